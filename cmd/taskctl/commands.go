@@ -0,0 +1,460 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/models/task"
+	"taskTracker/pkg/client"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// cli - общее состояние команд: клиент и формат вывода
+// создаются в PersistentPreRunE по флагам, окружению и файлу настроек
+type cli struct {
+	configPath string
+	server     string
+	token      string
+	output     string
+	timeout    time.Duration
+
+	client  *client.Client
+	printer *printer
+	out     io.Writer
+	now     func() time.Time
+	// started - команда прошла разбор аргументов и настройку
+	started bool
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	c := &cli{out: stdout, now: time.Now}
+	root := c.rootCommand()
+	root.SetArgs(args)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+
+	err := root.ExecuteContext(ctx)
+	// неизвестная команда и ошибки разбора до запуска - ошибки использования
+	if err != nil && !c.started {
+		err = &usageError{err: err}
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+	}
+	return exitCode(err)
+}
+
+func (c *cli) rootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "taskctl",
+		Short:         "Клиент TaskTracker для терминала",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.setup(cmd); err != nil {
+				return err
+			}
+			c.started = true
+			return nil
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&c.configPath, "config", "", "файл настроек (по умолчанию "+defaultConfigPath()+")")
+	flags.StringVar(&c.server, "server", "", "адрес сервера, $TASKCTL_SERVER (по умолчанию "+defaultServer+")")
+	flags.StringVar(&c.token, "token", "", "токен доступа, $TASKCTL_TOKEN")
+	flags.StringVarP(&c.output, "output", "o", "", "формат вывода: table, json, yaml")
+	flags.DurationVar(&c.timeout, "timeout", 30*time.Second, "таймаут запроса")
+	root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	// ошибки разбора флагов - ошибки использования
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+
+	root.AddCommand(
+		c.addCommand(),
+		c.listCommand(),
+		c.showCommand(),
+		c.editCommand(),
+		c.doneCommand(),
+		c.taskCommand("archive", "Архивировать задачу", (*client.Client).ArchiveTask),
+		c.taskCommand("unarchive", "Вернуть задачу из архива", (*client.Client).UnarchiveTask),
+		c.taskCommand("restore", "Восстановить удалённую задачу", (*client.Client).RestoreTask),
+		c.removeCommand("rm", "Удалить задачу (можно восстановить через restore)", (*client.Client).DeleteTask),
+		c.removeCommand("purge", "Удалить задачу безвозвратно", (*client.Client).PurgeTask),
+	)
+	return root
+}
+
+func (c *cli) setup(cmd *cobra.Command) error {
+	// скрипты автодополнения генерируются без обращения к серверу
+	if cmd.Parent() != nil && cmd.Parent().Name() == "completion" {
+		return nil
+	}
+	return c.connect()
+}
+
+func (c *cli) connect() error {
+	path, explicit := c.configPath, c.configPath != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	cfg, err := loadConfig(path, explicit)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	server := firstNonEmpty(c.server, os.Getenv("TASKCTL_SERVER"), cfg.Server, defaultServer)
+	token := firstNonEmpty(c.token, os.Getenv("TASKCTL_TOKEN"), cfg.Token)
+	output := firstNonEmpty(c.output, cfg.Output, outputTable)
+
+	c.printer, err = newPrinter(c.out, output)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	c.client, err = client.New(server, client.WithToken(token), client.WithUserAgent("taskctl"))
+	if err != nil {
+		return &usageError{err: err}
+	}
+	return nil
+}
+
+func (c *cli) context(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), c.timeout)
+}
+
+func parseID(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, &usageError{err: fmt.Errorf("неверный ID задачи %q", value)}
+	}
+	return id, nil
+}
+
+func exactArgs(n int, usage string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != n {
+			return &usageError{err: fmt.Errorf("использование: %s", cmd.CommandPath()+" "+usage)}
+		}
+		return nil
+	}
+}
+
+// completeTaskIDs подсказывает ID задач из списка view
+func (c *cli) completeTaskIDs(view client.View) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 || c.client == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ctx, cancel := c.context(cmd)
+		defer cancel()
+
+		tasks, err := c.client.ListTasks(ctx, client.ListOptions{View: view, Limit: 1000})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+		}
+		res := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			if strings.HasPrefix(t.UUID.String(), toComplete) {
+				res = append(res, t.UUID.String()+"\t"+t.Title)
+			}
+		}
+		return res, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func (c *cli) addCommand() *cobra.Command {
+	var (
+		due         string
+		description string
+		rrule       string
+		reminders   []string
+	)
+
+	cmd := &cobra.Command{
+		Use:               "add TITLE",
+		Short:             "Создать задачу",
+		Example:           `  taskctl add "Сдать отчёт" --due tomorrow --remind 2h`,
+		Args:              exactArgs(1, "TITLE"),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dueTime, err := parseDue(due, c.now())
+			if err != nil {
+				return &usageError{err: err}
+			}
+
+			ctx, cancel := c.context(cmd)
+			defer cancel()
+			created, err := c.client.CreateTask(ctx, dto.CreateTaskRequest{
+				Title:       args[0],
+				Description: description,
+				DueTime:     dueTime,
+				RRule:       rrule,
+				Reminders:   reminders,
+			})
+			if err != nil {
+				return err
+			}
+			return c.printer.task(created)
+		},
+	}
+
+	cmd.Flags().StringVar(&due, "due", "tomorrow", "срок: today, tomorrow, +3d, 2006-01-02, \"2006-01-02 15:04\", RFC 3339")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "описание")
+	cmd.Flags().StringVar(&rrule, "rrule", "", "правило повторения RFC 5545, например FREQ=WEEKLY;BYDAY=MO")
+	cmd.Flags().StringSliceVar(&reminders, "remind", nil, "напоминания до срока: 24h,1h")
+	return cmd
+}
+
+func (c *cli) listCommand() *cobra.Command {
+	var (
+		overdue, archived, all, deleted bool
+		page, limit                     int
+	)
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "Список задач (по умолчанию активные)",
+		Args:    exactArgs(0, ""),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if countTrue(overdue, archived, all, deleted) > 1 {
+				return &usageError{err: errors.New("флаги --overdue, --archived, --all и --deleted взаимоисключающие")}
+			}
+
+			view := client.ViewActive
+			switch {
+			case overdue:
+				view = client.ViewOverdue
+			case archived:
+				view = client.ViewArchived
+			case all:
+				view = client.ViewAll
+			case deleted:
+				view = client.ViewDeleted
+			}
+
+			ctx, cancel := c.context(cmd)
+			defer cancel()
+			tasks, err := c.client.ListTasks(ctx, client.ListOptions{View: view, Page: page, Limit: limit})
+			if err != nil {
+				return err
+			}
+			return c.printer.tasks(tasks)
+		},
+	}
+
+	cmd.Flags().BoolVar(&overdue, "overdue", false, "просроченные")
+	cmd.Flags().BoolVar(&archived, "archived", false, "архивные")
+	cmd.Flags().BoolVar(&all, "all", false, "все, кроме удалённых")
+	cmd.Flags().BoolVar(&deleted, "deleted", false, "удалённые")
+	cmd.Flags().IntVar(&page, "page", 1, "номер страницы")
+	cmd.Flags().IntVar(&limit, "limit", 50, "размер страницы")
+	return cmd
+}
+
+func countTrue(values ...bool) int {
+	n := 0
+	for _, v := range values {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *cli) showCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "show ID",
+		Short:             "Показать задачу",
+		Args:              exactArgs(1, "ID"),
+		ValidArgsFunction: c.completeTaskIDs(client.ViewAll),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := c.context(cmd)
+			defer cancel()
+			found, err := c.client.GetTask(ctx, id)
+			if err != nil {
+				return err
+			}
+			return c.printer.task(found)
+		},
+	}
+}
+
+func (c *cli) editCommand() *cobra.Command {
+	var (
+		title, description, status, due, rrule string
+		reminders                              []string
+	)
+
+	cmd := &cobra.Command{
+		Use:               "edit ID",
+		Short:             "Изменить задачу: меняются только переданные флаги",
+		Example:           `  taskctl edit 5f0c... --title "Новое название" --due +2d`,
+		Args:              exactArgs(1, "ID"),
+		ValidArgsFunction: c.completeTaskIDs(client.ViewAll),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			var request dto.UpdateTaskRequest
+			flags := cmd.Flags()
+			if flags.Changed("title") {
+				request.Title = &title
+			}
+			if flags.Changed("description") {
+				request.Description = &description
+			}
+			if flags.Changed("status") {
+				s := task.Status(status)
+				request.Status = &s
+			}
+			if flags.Changed("due") {
+				dueTime, err := parseDue(due, c.now())
+				if err != nil {
+					return &usageError{err: err}
+				}
+				request.DueTime = &dueTime
+			}
+			if flags.Changed("rrule") {
+				request.RRule = &rrule
+			}
+			if flags.Changed("remind") {
+				request.Reminders = &reminders
+			}
+			if request == (dto.UpdateTaskRequest{}) {
+				return &usageError{err: errors.New("не указано ни одного изменения")}
+			}
+
+			ctx, cancel := c.context(cmd)
+			defer cancel()
+			updated, err := c.client.UpdateTask(ctx, id, request)
+			if err != nil {
+				return err
+			}
+			return c.printer.task(updated)
+		},
+	}
+
+	// overdue ставит сервис, вручную его не выбрать
+	var statuses []string
+	for _, s := range task.Statuses {
+		if s != task.StatusOverdue {
+			statuses = append(statuses, string(s))
+		}
+	}
+	cmd.Flags().StringVar(&title, "title", "", "название")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "описание")
+	cmd.Flags().StringVar(&status, "status", "", "статус: "+strings.Join(statuses, ", "))
+	cmd.Flags().StringVar(&due, "due", "", "срок, как в add")
+	cmd.Flags().StringVar(&rrule, "rrule", "", "правило повторения, пустая строка - убрать")
+	cmd.Flags().StringSliceVar(&reminders, "remind", nil, "напоминания до срока, пустой список - убрать")
+	cmd.RegisterFlagCompletionFunc("status", cobra.FixedCompletions(statuses, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func (c *cli) doneCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "done ID",
+		Short:             "Отметить задачу выполненной",
+		Args:              exactArgs(1, "ID"),
+		ValidArgsFunction: c.completeTaskIDs(client.ViewActive),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := c.context(cmd)
+			defer cancel()
+			status := task.StatusDone
+			updated, err := c.client.UpdateTask(ctx, id, dto.UpdateTaskRequest{Status: &status})
+			if err != nil {
+				return err
+			}
+			return c.printer.task(updated)
+		},
+	}
+}
+
+// taskCommand - команда над одной задачей, которая возвращает задачу
+func (c *cli) taskCommand(name, short string,
+	fn func(*client.Client, context.Context, uuid.UUID) (*dto.TaskResponse, error)) *cobra.Command {
+	view := client.ViewAll
+	switch name {
+	case "unarchive":
+		view = client.ViewArchived
+	case "restore":
+		view = client.ViewDeleted
+	}
+
+	return &cobra.Command{
+		Use:               name + " ID",
+		Short:             short,
+		Args:              exactArgs(1, "ID"),
+		ValidArgsFunction: c.completeTaskIDs(view),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := c.context(cmd)
+			defer cancel()
+			res, err := fn(c.client, ctx, id)
+			if err != nil {
+				return err
+			}
+			return c.printer.task(res)
+		},
+	}
+}
+
+// removeCommand - удаление, у ответа нет тела
+func (c *cli) removeCommand(name, short string,
+	fn func(*client.Client, context.Context, uuid.UUID) error) *cobra.Command {
+	view := client.ViewAll
+	if name == "purge" {
+		view = client.ViewDeleted
+	}
+
+	return &cobra.Command{
+		Use:               name + " ID",
+		Short:             short,
+		Args:              exactArgs(1, "ID"),
+		ValidArgsFunction: c.completeTaskIDs(view),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := c.context(cmd)
+			defer cancel()
+			if err := fn(c.client, ctx, id); err != nil {
+				return err
+			}
+			return c.printer.message(fmt.Sprintf("Задача %s удалена", id), map[string]any{
+				"id":      id,
+				"deleted": true,
+				"purged":  name == "purge",
+			})
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// fileConfig - файл настроек, по умолчанию ~/.config/taskctl/config.yaml:
+//
+//	server: https://tasks.example.com
+//	token: secret
+//	output: table
+type fileConfig struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token"`
+	Output string `yaml:"output"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "taskctl", "config.yaml")
+}
+
+// loadConfig читает файл настроек. Отсутствие файла по умолчанию не ошибка,
+// явно указанный через --config файл должен существовать
+func loadConfig(path string, explicit bool) (fileConfig, error) {
+	var cfg fileConfig
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("чтение настроек: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("разбор настроек %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// firstNonEmpty - значение с наибольшим приоритетом: флаг, окружение, файл
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// конец дня для сроков, заданных только датой
+const endOfDayHour, endOfDayMinute = 23, 59
+
+// parseDue разбирает срок задачи относительно now:
+//
+//	today, tomorrow      - конец дня
+//	+30m, +2h, +3d, +1w  - через интервал
+//	2025-06-01           - конец указанного дня
+//	2025-06-01 18:00     - местное время
+//	2025-06-01T18:00:00Z - RFC 3339
+func parseDue(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+
+	endOfDay := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), endOfDayHour, endOfDayMinute, 0, 0, now.Location())
+	}
+
+	switch value {
+	case "":
+		return time.Time{}, fmt.Errorf("срок не задан")
+	case "today":
+		return endOfDay(now), nil
+	case "tomorrow":
+		return endOfDay(now.AddDate(0, 0, 1)), nil
+	}
+
+	if strings.HasPrefix(value, "+") {
+		return parseOffset(value[1:], now)
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(value)); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return endOfDay(t), nil
+	}
+
+	return time.Time{}, fmt.Errorf("неверный срок %q: ожидается today, tomorrow, +3d, 2006-01-02, "+
+		"\"2006-01-02 15:04\" или RFC 3339", value)
+}
+
+// parseOffset разбирает интервал с днями и неделями сверх time.ParseDuration
+func parseOffset(value string, now time.Time) (time.Time, error) {
+	if n := len(value); n > 1 {
+		count, err := strconv.Atoi(value[:n-1])
+		if err == nil && count > 0 {
+			switch value[n-1] {
+			case 'd':
+				return now.AddDate(0, 0, count), nil
+			case 'w':
+				return now.AddDate(0, 0, 7*count), nil
+			}
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("неверный интервал +%s: ожидается +30m, +2h, +3d или +1w", value)
+	}
+	return now.Add(d), nil
+}
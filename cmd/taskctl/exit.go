@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"taskTracker/pkg/client"
+)
+
+// Коды завершения. Скрипты могут отличать «не найдено» от конфликта
+// состояния, не разбирая текст ошибки
+const (
+	exitOK          = 0
+	exitError       = 1 // прочие ошибки, в том числе внутренняя ошибка сервера
+	exitUsage       = 2 // неверные аргументы или флаги
+	exitNotFound    = 3 // NOT_FOUND
+	exitInvalid     = 4 // VALIDATION_ERROR, NOT_RECURRING, BAD_REQUEST
+	exitConflict    = 5 // ALREADY_ARCHIVED, NOT_ARCHIVED, NOT_DELETED, IN_PROGRESS, VERSION_CONFLICT
+	exitGone        = 6 // TASK_DELETED, RESTORE_EXPIRED
+	exitUnavailable = 7 // сервер недоступен, перегружен или не ответил вовремя
+)
+
+// usageError - ошибка в аргументах командной строки
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return exitUsage
+	}
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		// ответа от сервера нет: сеть, таймаут
+		if errors.Is(err, context.Canceled) {
+			return exitError
+		}
+		return exitUnavailable
+	}
+
+	switch apiErr.Code {
+	case client.CodeNotFound:
+		return exitNotFound
+	case client.CodeValidation, client.CodeNotRecurring, client.CodeBadRequest:
+		return exitInvalid
+	case client.CodeAlreadyArchived, client.CodeNotArchived, client.CodeAlreadyDeleted, client.CodeNotDeleted,
+		client.CodeInvalidFlag, client.CodeInProgress, client.CodeVersionConflict, client.CodeInvalidTransition,
+		client.CodeWIPLimitExceeded, client.CodeTaskClosed, client.CodeTimerRunning, client.CodeTimerNotRunning:
+		return exitConflict
+	case client.CodeTaskDeleted, client.CodeRestoreExpired:
+		return exitGone
+	case client.CodeRateLimited, client.CodeUnavailable:
+		return exitUnavailable
+	default:
+		return exitError
+	}
+}
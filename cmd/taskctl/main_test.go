@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"taskTracker/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// newTestServer поднимает маршруты задач поверх inmemory-хранилища
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	svc := service.NewTaskService(inmemory.NewTaskStorage(), "inmemory")
+	h := handlers.NewTaskHandler(&svc)
+
+	r := chi.NewRouter()
+	r.Route("/tasks", func(r chi.Router) {
+		r.Get("/", h.GetActiveTasks)
+		r.Post("/", h.PostTask)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.GetTaskByID)
+			r.Put("/", h.UpdateTaskByID)
+			r.Delete("/", h.DeleteTaskByID)
+			r.Post("/archive", h.ArchiveTask)
+			r.Post("/unarchive", h.UnarchiveTask)
+		})
+		r.Get("/archived", h.GetArchivedTasks)
+		r.Get("/all", h.GetAllTasks)
+		r.Get("/overdue", h.GetOverdueTasks)
+	})
+	r.Route("/admin/tasks", func(r chi.Router) {
+		r.Get("/deleted", h.GetDeletedTasks)
+		r.Post("/{id}/restore", h.RestoreTask)
+		r.Delete("/{id}/purge", h.PurgeTask)
+	})
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// taskctl запускает команду без файла настроек и возвращает код и вывод
+func taskctl(t *testing.T, server string, args ...string) (int, string, string) {
+	t.Helper()
+	t.Setenv("TASKCTL_SERVER", "")
+	t.Setenv("TASKCTL_TOKEN", "")
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"--server", server, "--config", filepath.Join(t.TempDir(), "none.yaml")}, args...)
+	// отсутствующий явный файл - ошибка, поэтому создаём пустой
+	require.NoError(t, os.WriteFile(args[3], nil, 0o600))
+
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestParseDue тестирует разбор сроков задачи
+func TestParseDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "today", want: time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)},
+		{value: "Tomorrow", want: time.Date(2025, 6, 2, 23, 59, 0, 0, time.UTC)},
+		{value: "+30m", want: now.Add(30 * time.Minute)},
+		{value: "+3d", want: now.AddDate(0, 0, 3)},
+		{value: "+1w", want: now.AddDate(0, 0, 7)},
+		{value: "2025-07-01", want: time.Date(2025, 7, 1, 23, 59, 0, 0, time.UTC)},
+		{value: "2025-07-01 18:00", want: time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC)},
+		{value: "2025-07-01T18:00:00Z", want: time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC)},
+		{value: "", wantErr: true},
+		{value: "+0d", wantErr: true},
+		{value: "+-2h", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDue(tt.value, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "ожидалось %s, получено %s", tt.want, got)
+		})
+	}
+}
+
+// TestExitCode тестирует коды завершения для ошибок API и CLI
+func TestExitCode(t *testing.T) {
+	apiErr := func(code string) error { return &client.Error{StatusCode: 400, Code: code} }
+
+	assert.Equal(t, exitOK, exitCode(nil))
+	assert.Equal(t, exitUsage, exitCode(&usageError{err: errors.New("bad flag")}))
+	assert.Equal(t, exitNotFound, exitCode(apiErr("NOT_FOUND")))
+	assert.Equal(t, exitInvalid, exitCode(apiErr("VALIDATION_ERROR")))
+	assert.Equal(t, exitConflict, exitCode(apiErr("ALREADY_ARCHIVED")))
+	assert.Equal(t, exitGone, exitCode(apiErr("TASK_DELETED")))
+	assert.Equal(t, exitUnavailable, exitCode(apiErr(client.CodeRateLimited)))
+	assert.Equal(t, exitError, exitCode(apiErr(client.CodeInternal)))
+	assert.Equal(t, exitUnavailable, exitCode(errors.New("connection refused")))
+	assert.Equal(t, exitError, exitCode(context.Canceled))
+}
+
+// TestTaskctl_Lifecycle тестирует команды на живом сервере
+func TestTaskctl_Lifecycle(t *testing.T) {
+	srv := newTestServer(t)
+
+	code, out, stderr := taskctl(t, srv.URL, "add", "Сдать отчёт", "--due", "+2d", "-d", "квартальный", "-o", "json")
+	require.Equal(t, exitOK, code, stderr)
+
+	var created dto.TaskResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &created))
+	assert.Equal(t, "Сдать отчёт", created.Title)
+	id := created.UUID.String()
+
+	code, out, _ = taskctl(t, srv.URL, "ls")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "ID")
+	assert.Contains(t, out, id)
+
+	code, out, _ = taskctl(t, srv.URL, "show", id, "-o", "yaml")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "title: Сдать отчёт")
+
+	code, out, stderr = taskctl(t, srv.URL, "edit", id, "--title", "Сдать отчёт вовремя")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, out, "Сдать отчёт вовремя")
+
+	code, out, stderr = taskctl(t, srv.URL, "done", id)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, out, "done")
+
+	code, _, stderr = taskctl(t, srv.URL, "archive", id)
+	require.Equal(t, exitOK, code, stderr)
+	code, _, _ = taskctl(t, srv.URL, "archive", id)
+	assert.Equal(t, exitConflict, code)
+
+	code, out, _ = taskctl(t, srv.URL, "ls", "--archived")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, id)
+
+	code, _, stderr = taskctl(t, srv.URL, "rm", id)
+	require.Equal(t, exitOK, code, stderr)
+	code, _, stderr = taskctl(t, srv.URL, "restore", id)
+	require.Equal(t, exitOK, code, stderr)
+
+	code, _, stderr = taskctl(t, srv.URL, "rm", id)
+	require.Equal(t, exitOK, code, stderr)
+	code, out, stderr = taskctl(t, srv.URL, "purge", id, "-o", "json")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, out, `"purged": true`)
+
+	code, _, _ = taskctl(t, srv.URL, "show", id)
+	assert.Equal(t, exitNotFound, code)
+}
+
+// TestTaskctl_Usage тестирует ошибки использования
+func TestTaskctl_Usage(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "нет названия", args: []string{"add"}},
+		{name: "неверный срок", args: []string{"add", "title", "--due", "someday"}},
+		{name: "неверный ID", args: []string{"show", "42"}},
+		{name: "неизвестный флаг", args: []string{"ls", "--unknown"}},
+		{name: "взаимоисключающие флаги", args: []string{"ls", "--overdue", "--archived"}},
+		{name: "нет изменений", args: []string{"edit", "5f0c7c1e-0000-4000-8000-000000000000"}},
+		{name: "неверный формат", args: []string{"ls", "-o", "xml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := taskctl(t, srv.URL, tt.args...)
+			assert.Equal(t, exitUsage, code, stderr)
+			assert.True(t, strings.HasPrefix(stderr, "Ошибка:"), stderr)
+		})
+	}
+}
+
+// TestTaskctl_Completion тестирует генерацию скрипта автодополнения
+func TestTaskctl_Completion(t *testing.T) {
+	code, out, stderr := taskctl(t, "http://127.0.0.1:1", "completion", "bash")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, out, "taskctl")
+}
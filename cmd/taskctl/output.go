@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"taskTracker/internal/handlers/dto"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+type printer struct {
+	out    io.Writer
+	format string
+}
+
+func newPrinter(out io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON, outputYAML:
+		return &printer{out: out, format: format}, nil
+	}
+	return nil, fmt.Errorf("неверный формат вывода %q: ожидается %s", format, strings.Join(outputFormats, ", "))
+}
+
+func (p *printer) tasks(tasks []dto.TaskResponse) error {
+	if p.format != outputTable {
+		if tasks == nil {
+			tasks = []dto.TaskResponse{}
+		}
+		return p.encode(tasks)
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tDUE\tOVERDUE\tTITLE")
+	for _, t := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.UUID, t.Status, formatTime(t.DueDate), yesNo(t.IsOverdue), t.Title)
+	}
+	return w.Flush()
+}
+
+func (p *printer) task(t *dto.TaskResponse) error {
+	if p.format != outputTable {
+		return p.encode(t)
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", t.UUID)
+	fmt.Fprintf(w, "Title:\t%s\n", t.Title)
+	if t.Description != "" {
+		fmt.Fprintf(w, "Description:\t%s\n", t.Description)
+	}
+	fmt.Fprintf(w, "Status:\t%s\n", t.Status)
+	fmt.Fprintf(w, "Due:\t%s\n", formatTime(t.DueDate))
+	fmt.Fprintf(w, "Overdue:\t%s\n", yesNo(t.IsOverdue))
+	fmt.Fprintf(w, "Created:\t%s\n", formatTime(t.CreatedAt))
+	if t.UpdatedAt != nil {
+		fmt.Fprintf(w, "Updated:\t%s\n", formatTime(*t.UpdatedAt))
+	}
+	if t.RRule != "" {
+		fmt.Fprintf(w, "Repeat:\t%s\n", t.RRule)
+	}
+	if len(t.Reminders) > 0 {
+		befores := make([]string, len(t.Reminders))
+		for i, r := range t.Reminders {
+			befores[i] = r.Before.String()
+		}
+		fmt.Fprintf(w, "Reminders:\t%s\n", strings.Join(befores, ", "))
+	}
+	return w.Flush()
+}
+
+// message - итог команды без тела (удаление): в таблице - строка,
+// в JSON/YAML - объект, чтобы вывод можно было разобрать
+func (p *printer) message(text string, fields map[string]any) error {
+	if p.format == outputTable {
+		_, err := fmt.Fprintln(p.out, text)
+		return err
+	}
+	return p.encode(fields)
+}
+
+// encode выводит значение с именами полей из тегов json. YAML строится
+// из JSON, поэтому порядок и имена полей совпадают
+func (p *printer) encode(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if p.format == outputJSON {
+		_, err = fmt.Fprintln(p.out, string(data))
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+	encoder := yaml.NewEncoder(p.out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetStyle убирает JSON-стиль ({...}, кавычки), оставшийся после разбора
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
@@ -3,22 +3,49 @@ package app
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync/atomic"
 	"syscall"
+	"taskTracker/internal/board"
 	"taskTracker/internal/config"
+	"taskTracker/internal/events"
+	"taskTracker/internal/gql"
+	"taskTracker/internal/grpcapi"
 	"taskTracker/internal/handlers"
+	"taskTracker/internal/health"
+	"taskTracker/internal/history"
 	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
 	"taskTracker/internal/middleware"
+	"taskTracker/internal/notify"
+	"taskTracker/internal/openapi"
+	"taskTracker/internal/outbox"
+	"taskTracker/internal/ratelimit"
+	"taskTracker/internal/reminder"
+	"taskTracker/internal/repository/task/cache"
 	"taskTracker/internal/repository/task/inmemory"
 	"taskTracker/internal/repository/task/postgres"
+	"taskTracker/internal/repository/task/sqlite"
 	"taskTracker/internal/service"
+	"taskTracker/internal/stats"
+	"taskTracker/internal/stream"
+	"taskTracker/internal/tracing"
+	"taskTracker/internal/webhook"
+	"taskTracker/internal/workflow"
+	"taskTracker/internal/worklog"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 type App struct {
@@ -28,12 +55,43 @@ type App struct {
 	repository service.TaskRepository //
 	service    handlers.Service       //
 	shutdowns  []func()               //
+
+	cache     *cache.Repository
+	events    *events.Bus
+	outbox    outbox.Store
+	stream    *stream.Hub
+	webhooks  *webhook.Service
+	grpc      *grpc.Server
+	graphql   *gql.Executor
+	metrics   *prometheus.Registry
+	health    *health.Checker
+	limiter   *ratelimit.Limiter
+	workflows *workflow.Set
+	boards    *board.Boards
+	worklogs  *worklog.Service
+	history   *history.Service
+	stats     *stats.Service
+	// rateStore - общие счётчики лимитера в PostgreSQL
+	rateStore ratelimit.Store
+	// worklogStore - таймеры и записи времени, в том же хранилище, что и задачи
+	worklogStore worklog.Store
+	// historyStore - журнал состояний задач для отчётов
+	historyStore history.Store
+	// boardLocker - блокировка досок в PostgreSQL, общая для всех экземпляров
+	boardLocker service.BoardLocker
+	// webhookStore - подписки и недоставленные события вебхуков в PostgreSQL или SQLite
+	webhookStore webhook.Store
+
+	// handler - собранный роутер. До его появления сервер отвечает только на пробы
+	handler   atomic.Pointer[chi.Mux]
+	serverErr chan error
 }
 
 func New(cfg *config.Config) *App {
 	return &App{
 		config:    cfg,
 		shutdowns: make([]func(), 0),
+		serverErr: make(chan error, 2),
 	}
 }
 
@@ -46,7 +104,16 @@ func New(cfg *config.Config) *App {
 
 func (a *App) Init(ctx context.Context) error {
 	// логгер
-	if err := logger.Init(a.config.Logging.Development); err != nil {
+	if err := logger.Init(logger.Options{
+		Development: a.config.Logging.Development,
+		Level:       a.config.Logging.Level,
+		Format:      a.config.Logging.Format,
+		Output:      a.config.Logging.Output,
+		MaxSizeMB:   a.config.Logging.MaxSizeMB,
+		MaxBackups:  a.config.Logging.MaxBackups,
+		MaxAgeDays:  a.config.Logging.MaxAgeDays,
+		Compress:    a.config.Logging.Compress,
+	}); err != nil {
 		return fmt.Errorf("инициализация логгера: %w", err)
 	}
 	logger.Info("Успешная инициализация логгера")
@@ -56,6 +123,22 @@ func (a *App) Init(ctx context.Context) error {
 		logger.Sync()
 	})
 
+	// трассировка ставится до остальных компонентов, чтобы их спаны не терялись
+	if a.config.Tracing.Enabled {
+		if err := a.initTracing(ctx); err != nil {
+			return fmt.Errorf("инициализация трассировки: %w", err)
+		}
+		logger.Info("Успешная инициализация трассировки",
+			zap.String("exporter", a.config.Tracing.Exporter),
+			zap.Float64("sample_ratio", a.config.Tracing.SampleRatio))
+	}
+
+	// сервер слушает порт с самого начала: пока идут миграции, /livez
+	// отвечает 200, а /readyz - 503
+	a.initHealth()
+	a.initServer()
+	logger.Info("Успешная инициализация сервера")
+
 	// репозиторий
 	repo, err := a.initRepository(ctx)
 	if err != nil {
@@ -70,46 +153,132 @@ func (a *App) Init(ctx context.Context) error {
 	// 	}
 	// }
 
+	// шина событий задач
+	a.events = events.NewBus()
+
+	// статусы и переходы задач
+	if err := a.initWorkflow(); err != nil {
+		return fmt.Errorf("инициализация workflow: %w", err)
+	}
+	logger.Info("Успешная инициализация workflow",
+		zap.String("config", a.config.Workflow.ConfigPath),
+		zap.Strings("projects", a.workflows.Projects()))
+
+	// канбан-доски поверх статусов workflow
+	if err := a.initBoards(); err != nil {
+		return fmt.Errorf("инициализация досок: %w", err)
+	}
+	logger.Info("Успешная инициализация досок",
+		zap.String("config", a.config.Boards.ConfigPath))
+
 	// сервис
 	servi, err := a.initService()
 	if err != nil {
 		return fmt.Errorf("инициализация сервиса: %w", err)
 	}
 	a.service = servi
+	if a.config.Tracing.Enabled {
+		a.service = tracing.NewService(servi)
+	}
 	logger.Info("Успешная инициализация сервиса")
 
+	// учёт времени: таймеры останавливаются по событиям задач
+	a.initWorklogs()
+	logger.Info("Успешная инициализация учёта времени")
+
+	// журнал состояний задач для burndown и velocity
+	if err := a.initHistory(); err != nil {
+		return fmt.Errorf("инициализация истории задач: %w", err)
+	}
+	logger.Info("Успешная инициализация истории задач", zap.String("unit", a.config.Estimates.Unit))
+
+	// напоминания о сроках
+	if a.config.Reminder.Enabled {
+		if err := a.initReminders(); err != nil {
+			return fmt.Errorf("инициализация напоминаний: %w", err)
+		}
+		logger.Info("Успешная инициализация напоминаний",
+			zap.Strings("notifiers", a.config.Reminder.Notifiers))
+	}
+
+	// исходящие вебхуки
+	if a.config.Webhook.Enabled {
+		a.initWebhooks()
+		logger.Info("Успешная инициализация вебхуков")
+	}
+
+	// поток событий для клиентов
+	a.initStream()
+	logger.Info("Успешная инициализация потока событий")
+
+	// релей outbox запускается последним, чтобы все подписчики шины уже были на месте
+	if a.outbox != nil {
+		a.initOutboxRelay()
+		logger.Info("Успешная инициализация релея outbox")
+	}
+
+	// GraphQL поверх того же сервиса
+	if a.config.GraphQL.Enabled {
+		if err := a.initGraphQL(); err != nil {
+			return fmt.Errorf("инициализация GraphQL: %w", err)
+		}
+		logger.Info("Успешная инициализация GraphQL",
+			zap.Int("max_depth", a.config.GraphQL.MaxDepth),
+			zap.Int("max_complexity", a.config.GraphQL.MaxComplexity))
+	}
+
+	// лимитер запросов
+	if a.config.RateLimit.Enabled {
+		if err := a.initRateLimit(); err != nil {
+			return fmt.Errorf("инициализация лимитера: %w", err)
+		}
+		logger.Info("Успешная инициализация лимитера", zap.String("store", a.config.RateLimit.Store))
+	}
+
 	//хендлеры и роутинг
 	a.initRouter()
+	a.handler.Store(a.router)
 	logger.Info("Успешная инициализация роутера")
 
-	// сервер
-	a.initServer()
-	logger.Info("Успешная инициализация сервера")
+	// сервер останавливается раньше хранилища и воркеров
+	a.server.RegisterOnShutdown(a.stream.Close)
+	a.shutdowns = append(a.shutdowns, a.shutdownServer)
+
+	// gRPC API
+	if a.config.GRPC.Enabled {
+		a.initGRPC()
+		logger.Info("Успешная инициализация gRPC-сервера")
+	}
 
 	logger.Info("Приложение успешно инициализировано")
 	return nil
 }
 
 func (a *App) Run(ctx context.Context) error {
-
-	serverErr := make(chan error, 1)
-
-	go func() {
-		logger.Info("Запуск сервера", zap.String("addr", a.server.Addr))
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			serverErr <- err
-		}
-	}()
+	if a.grpc != nil {
+		go func() {
+			logger.Info("Запуск gRPC-сервера", zap.String("addr", a.config.GRPC.Addr))
+			listener, err := net.Listen("tcp", a.config.GRPC.Addr)
+			if err != nil {
+				a.serverErr <- fmt.Errorf("gRPC: %w", err)
+				return
+			}
+			if err := a.grpc.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+				a.serverErr <- fmt.Errorf("gRPC: %w", err)
+			}
+		}()
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	a.health.SetReady()
 	logger.Info("Приложение запущено. Ctrl+C для остановки.")
 
 	select {
 	case <-quit:
 		logger.Info("Получен сигнал завершения")
-	case err := <-serverErr:
+	case err := <-a.serverErr:
 		logger.Error("Ошибка сервера", err)
 	case <-ctx.Done():
 		logger.Info("Контекст отменен")
@@ -121,6 +290,14 @@ func (a *App) Run(ctx context.Context) error {
 func (a *App) Shutdown(ctx context.Context) error {
 	logger.Info("Начало graceful shutdown")
 
+	// /readyz отвечает 503, пока сервер ещё принимает запросы:
+	// балансировщик успевает перестать слать новый трафик
+	if a.health != nil && a.health.State() == health.StateReady {
+		a.health.SetDraining()
+		logger.Info("Ожидание снятия с балансировки", zap.Duration("delay", a.config.Health.DrainDelay))
+		time.Sleep(a.config.Health.DrainDelay)
+	}
+
 	// Выполняем в обратном порядке
 	for i := len(a.shutdowns) - 1; i >= 0; i-- {
 		a.shutdowns[i]()
@@ -131,6 +308,207 @@ func (a *App) Shutdown(ctx context.Context) error {
 }
 
 func (a *App) initRepository(ctx context.Context) (service.TaskRepository, error) {
+	repo, err := a.initStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.initHealthChecks(repo)
+	a.initStats(repo)
+	if a.worklogStore == nil {
+		a.worklogStore = worklog.NewMemoryStore()
+	}
+	if a.historyStore == nil {
+		a.historyStore = history.NewMemoryStore()
+	}
+
+	// метрики снимаются прямо с хранилища, под кэшем
+	if a.config.Metrics.Enabled {
+		a.initMetrics(repo)
+		repo = metrics.NewRepository(repo, a.config.Repository.Type)
+	}
+	if a.config.Tracing.Enabled {
+		repo = tracing.NewRepository(repo, a.config.Repository.Type)
+	}
+
+	if a.config.Cache.Enabled {
+		repo, err = a.initCache(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// итоги времени дописываются поверх кэша и не устаревают вместе с ним
+	return worklog.NewRepository(repo, a.worklogStore), nil
+}
+
+func (a *App) initMetrics(storage service.TaskRepository) {
+	var collectors []prometheus.Collector
+	if counter, ok := storage.(metrics.TaskCounter); ok {
+		collectors = append(collectors, metrics.NewTasksCollector(counter, a.config.Metrics.CountTimeout))
+	}
+	if pg, ok := storage.(*postgres.Storage); ok {
+		collectors = append(collectors, metrics.NewPoolCollector(pg.PoolStat))
+	}
+
+	a.metrics = metrics.NewRegistry(collectors...)
+	logger.Info("Успешная инициализация метрик", zap.Int("collectors", len(collectors)))
+}
+
+// initStats - статистика считается прямо в хранилище, мимо кэша задач:
+// у неё свой кэш с коротким TTL
+func (a *App) initStats(storage service.TaskRepository) {
+	source, ok := storage.(stats.Source)
+	if !ok {
+		logger.Warn("Хранилище не считает статистику, GET /stats недоступен",
+			zap.String("type", a.config.Repository.Type))
+		return
+	}
+	a.stats = stats.NewService(source, a.config.Stats.CacheTTL)
+	logger.Info("Успешная инициализация статистики", zap.Duration("cache_ttl", a.config.Stats.CacheTTL))
+}
+
+func (a *App) initHealth() {
+	a.health = health.New(health.Options{
+		Timeout:  a.config.Health.CheckTimeout,
+		CacheTTL: a.config.Health.CacheTTL,
+		Token:    a.config.Health.DetailsToken,
+	})
+	if a.config.Health.DetailsToken == "" {
+		logger.Warn("HEALTH_DETAILS_TOKEN не задан, /health/details недоступен")
+	}
+}
+
+// initHealthChecks регистрирует проверки зависимостей. storage - хранилище
+// без обёрток: проверка не должна попадать в кэш и метрики операций
+func (a *App) initHealthChecks(storage service.TaskRepository) {
+	cfg := a.config.Health
+
+	var poolStat func() *pgxpool.Stat
+	if pg, ok := storage.(*postgres.Storage); ok {
+		poolStat = pg.PoolStat
+	}
+	a.health.Register("database", health.Database(storage.HealthCheck, poolStat), true)
+
+	if counter, ok := a.outbox.(outbox.BacklogCounter); ok {
+		a.health.Register("outbox", health.Outbox(counter, int64(cfg.OutboxMaxBacklog)), false)
+		a.health.Register("worker:outbox", health.Worker("outbox", a.config.Outbox.Interval), false)
+	}
+	if a.config.Reminder.Enabled {
+		a.health.Register("worker:reminder", health.Worker("reminder", a.config.Reminder.Interval), false)
+	}
+
+	// файловые хранилища
+	minFree := uint64(cfg.DiskMinFreeMB) << 20
+	switch {
+	case a.config.Repository.Type == "inmemory" && a.config.Repository.Inmemory.DataDir != "":
+		a.health.Register("disk", health.DiskSpace(a.config.Repository.Inmemory.DataDir, minFree), false)
+	case a.config.Repository.Type == "sqlite":
+		a.health.Register("disk", health.DiskSpace(filepath.Dir(a.config.Repository.SQLitePath), minFree), false)
+	}
+}
+
+func (a *App) initRateLimit() error {
+	cfg := a.config.RateLimit
+
+	policies := ratelimit.Config{
+		Default: ratelimit.Policy{Algorithm: cfg.Algorithm, Limit: cfg.Limit, Window: cfg.Window},
+	}
+	if cfg.ConfigPath != "" {
+		loaded, err := ratelimit.LoadConfig(cfg.ConfigPath, policies.Default)
+		if err != nil {
+			return err
+		}
+		policies = loaded
+	}
+
+	var store ratelimit.Store
+	switch cfg.Store {
+	case "memory":
+		store = ratelimit.NewMemoryStore(cfg.MaxKeys)
+	case "postgres":
+		if a.rateStore == nil {
+			return fmt.Errorf("RATELIMIT_STORE=postgres требует REPOSITORY_TYPE=postgres")
+		}
+		store = a.rateStore
+	default:
+		return fmt.Errorf("неизвестное хранилище лимитера: %s", cfg.Store)
+	}
+
+	limiter, err := ratelimit.New(policies, ratelimit.Options{
+		Store:         store,
+		IdleTimeout:   cfg.IdleTimeout,
+		EvictInterval: cfg.EvictInterval,
+	})
+	if err != nil {
+		return err
+	}
+	limiter.Start()
+	a.limiter = limiter
+
+	a.shutdowns = append(a.shutdowns, func() {
+		logger.Info("Остановка лимитера...")
+		limiter.Stop()
+	})
+	return nil
+}
+
+func (a *App) initTracing(ctx context.Context) error {
+	cfg := a.config.Tracing
+
+	shutdown, err := tracing.Init(ctx, tracing.Options{
+		ServiceName: cfg.ServiceName,
+		Exporter:    cfg.Exporter,
+		Endpoint:    cfg.Endpoint,
+		Insecure:    cfg.Insecure,
+		SampleRatio: cfg.SampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+
+	a.shutdowns = append(a.shutdowns, func() {
+		logger.Info("Отправка оставшихся спанов...")
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logger.Error("Ошибка остановки трассировки", err)
+		}
+	})
+	return nil
+}
+
+func (a *App) initCache(ctx context.Context, repo service.TaskRepository) (service.TaskRepository, error) {
+	logger.Info("Попытка инициализации кэша", zap.String("backend", a.config.Cache.Backend))
+
+	var backend cache.Cache
+	switch a.config.Cache.Backend {
+	case "lru":
+		backend = cache.NewLRU(a.config.Cache.Size)
+
+	case "redis":
+		client := redis.NewClient(&redis.Options{Addr: a.config.Cache.RedisAddr})
+		if err := client.Ping(ctx).Err(); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("подключение к Redis: %w", err)
+		}
+
+		a.shutdowns = append(a.shutdowns, func() {
+			logger.Info("Закрытие соединения с Redis...")
+			client.Close()
+		})
+		backend = cache.NewRedis(client, "tasktracker:")
+
+	default:
+		return nil, fmt.Errorf("неизвестный тип кэша: %s", a.config.Cache.Backend)
+	}
+
+	a.cache = cache.NewRepository(repo, backend, a.config.Cache.TTL)
+	logger.Info("Успешная инициализация кэша", zap.Duration("ttl", a.config.Cache.TTL))
+	return a.cache, nil
+}
+
+func (a *App) initStorage(ctx context.Context) (service.TaskRepository, error) {
 	logger.Info("Попытка инициализации репозитория", zap.String("type", a.config.Repository.Type))
 
 	switch a.config.Repository.Type {
@@ -164,13 +542,161 @@ func (a *App) initRepository(ctx context.Context) (service.TaskRepository, error
 			return nil, fmt.Errorf("создание таблицы tasks: %w", err)
 		}
 
+		_, err = conn.Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS outbox (
+				seq          BIGSERIAL PRIMARY KEY,
+				event_id     UUID NOT NULL,
+				aggregate_id UUID NOT NULL,
+				event_type   VARCHAR(50) NOT NULL,
+				payload      JSONB NOT NULL,
+				created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				published_at TIMESTAMPTZ
+			)
+		`)
+		if err != nil {
+			conn.Close(ctx)
+			return nil, fmt.Errorf("создание таблицы outbox: %w", err)
+		}
+
+		_, err = conn.Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS rate_limits (
+				key        TEXT PRIMARY KEY,
+				value      DOUBLE PRECISION NOT NULL,
+				prev       DOUBLE PRECISION NOT NULL,
+				stamp      TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ NOT NULL
+			)
+		`)
+		if err != nil {
+			conn.Close(ctx)
+			return nil, fmt.Errorf("создание таблицы rate_limits: %w", err)
+		}
+
+		_, err = conn.Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS worklogs (
+				id               UUID PRIMARY KEY,
+				task_id          UUID NOT NULL,
+				user_id          TEXT NOT NULL,
+				started_at       TIMESTAMPTZ NOT NULL,
+				duration_seconds BIGINT NOT NULL,
+				note             TEXT NOT NULL DEFAULT '',
+				source           VARCHAR(20) NOT NULL,
+				created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+			CREATE TABLE IF NOT EXISTS timers (
+				task_id    UUID NOT NULL,
+				user_id    TEXT NOT NULL,
+				started_at TIMESTAMPTZ NOT NULL,
+				PRIMARY KEY (task_id, user_id)
+			)
+		`)
+		if err != nil {
+			conn.Close(ctx)
+			return nil, fmt.Errorf("создание таблиц учёта времени: %w", err)
+		}
+
+		_, err = conn.Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS task_history (
+				id                 UUID PRIMARY KEY,
+				task_id            UUID NOT NULL,
+				event_type         VARCHAR(50) NOT NULL,
+				status             VARCHAR(50) NOT NULL,
+				flag               VARCHAR(50) NOT NULL,
+				original_estimate  DOUBLE PRECISION,
+				remaining_estimate DOUBLE PRECISION,
+				occurred_at        TIMESTAMPTZ NOT NULL
+			)
+		`)
+		if err != nil {
+			conn.Close(ctx)
+			return nil, fmt.Errorf("создание таблицы task_history: %w", err)
+		}
+
+		_, err = conn.Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+				id         UUID PRIMARY KEY,
+				url        TEXT NOT NULL,
+				secret     TEXT NOT NULL,
+				events     JSONB NOT NULL DEFAULT '[]',
+				active     BOOLEAN NOT NULL DEFAULT TRUE,
+				created_at TIMESTAMPTZ NOT NULL,
+				updated_at TIMESTAMPTZ
+			);
+			CREATE TABLE IF NOT EXISTS webhook_deliveries (
+				seq             BIGSERIAL PRIMARY KEY,
+				id              UUID NOT NULL,
+				delivery_id     UUID NOT NULL,
+				subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+				event_id        UUID NOT NULL,
+				event_type      VARCHAR(50) NOT NULL,
+				attempt         INTEGER NOT NULL,
+				status_code     INTEGER NOT NULL DEFAULT 0,
+				error           TEXT NOT NULL DEFAULT '',
+				success         BOOLEAN NOT NULL,
+				duration_ns     BIGINT NOT NULL,
+				attempted_at    TIMESTAMPTZ NOT NULL,
+				next_retry_at   TIMESTAMPTZ
+			);
+			CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+				seq             BIGSERIAL PRIMARY KEY,
+				delivery_id     UUID NOT NULL,
+				subscription_id UUID NOT NULL,
+				event           JSONB NOT NULL,
+				attempts        INTEGER NOT NULL,
+				last_error      TEXT NOT NULL,
+				failed_at       TIMESTAMPTZ NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS webhook_pending (
+				delivery_id     UUID PRIMARY KEY,
+				subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+				event           JSONB NOT NULL,
+				attempt         INTEGER NOT NULL,
+				next_attempt_at TIMESTAMPTZ NOT NULL
+			)
+		`)
+		if err != nil {
+			conn.Close(ctx)
+			return nil, fmt.Errorf("создание таблиц вебхуков: %w", err)
+		}
+
+		// Колонки, добавленные после первой версии схемы
+		columns := []string{
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rrule TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS reminders JSONB NOT NULL DEFAULT '[]'`,
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS original_estimate DOUBLE PRECISION`,
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS remaining_estimate DOUBLE PRECISION`,
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ`,
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ`,
+			`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS next_reminder_at TIMESTAMPTZ`,
+		}
+
+		for i, col := range columns {
+			_, err = conn.Exec(ctx, col)
+			if err != nil {
+				conn.Close(ctx)
+				return nil, fmt.Errorf("добавление колонки %d: %w", i+1, err)
+			}
+		}
+
 		// Создаем индексы
 		indexes := []string{
 			`CREATE INDEX IF NOT EXISTS idx_tasks_flag ON tasks(flag)`,
 			`CREATE INDEX IF NOT EXISTS idx_tasks_active_created ON tasks(created_at DESC) WHERE flag = 'active'`,
-			`CREATE INDEX IF NOT EXISTS idx_tasks_overdue ON tasks(due_time, status) WHERE flag = 'active' AND status IN ('new', 'in progress')`,
+			`CREATE INDEX IF NOT EXISTS idx_tasks_overdue ON tasks(due_time, status) WHERE flag = 'active' AND status NOT IN ('done', 'cancelled', 'overdue')`,
 			`CREATE INDEX IF NOT EXISTS idx_tasks_archived_created ON tasks(created_at DESC) WHERE flag = 'archived'`,
 			`CREATE INDEX IF NOT EXISTS idx_tasks_deleted_created ON tasks(created_at DESC) WHERE flag = 'deleted'`,
+			`CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE published_at IS NULL`,
+			`CREATE INDEX IF NOT EXISTS idx_rate_limits_updated ON rate_limits(updated_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_tasks_active_status_rank ON tasks(status, rank) WHERE flag = 'active'`,
+			`CREATE INDEX IF NOT EXISTS idx_worklogs_task ON worklogs(task_id, started_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_worklogs_started ON worklogs(started_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_task_history_occurred ON task_history(occurred_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_task_history_task ON task_history(task_id, occurred_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_tasks_completed ON tasks(completed_at) WHERE completed_at IS NOT NULL`,
+			`CREATE INDEX IF NOT EXISTS idx_tasks_next_reminder ON tasks(next_reminder_at) WHERE next_reminder_at IS NOT NULL`,
+			`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_webhook_pending_next ON webhook_pending(next_attempt_at)`,
 		}
 
 		for i, idx := range indexes {
@@ -181,6 +707,19 @@ func (a *App) initRepository(ctx context.Context) (service.TaskRepository, error
 			}
 		}
 
+		// задачи без истории попадают в журнал с текущим состоянием,
+		// как в 010_task_history.up.sql
+		_, err = conn.Exec(ctx, `
+			INSERT INTO task_history (id, task_id, event_type, status, flag, original_estimate, remaining_estimate, occurred_at)
+			SELECT gen_random_uuid(), uuid, 'task.created', status, flag, original_estimate, remaining_estimate, NOW()
+			FROM tasks
+			WHERE NOT EXISTS (SELECT 1 FROM task_history h WHERE h.task_id = tasks.uuid)
+		`)
+		if err != nil {
+			conn.Close(ctx)
+			return nil, fmt.Errorf("заполнение истории задач: %w", err)
+		}
+
 		logger.Info("Миграции успешно применены")
 
 		// 3. Закрываем временное соединение
@@ -208,6 +747,17 @@ func (a *App) initRepository(ctx context.Context) (service.TaskRepository, error
 
 			// УДАЛЯЕМ ИНДЕКСЫ
 			dropIndexes := []string{
+				`DROP INDEX IF EXISTS idx_webhook_pending_next`,
+				`DROP INDEX IF EXISTS idx_webhook_deliveries_subscription`,
+				`DROP INDEX IF EXISTS idx_tasks_next_reminder`,
+				`DROP INDEX IF EXISTS idx_tasks_completed`,
+				`DROP INDEX IF EXISTS idx_task_history_task`,
+				`DROP INDEX IF EXISTS idx_task_history_occurred`,
+				`DROP INDEX IF EXISTS idx_worklogs_started`,
+				`DROP INDEX IF EXISTS idx_worklogs_task`,
+				`DROP INDEX IF EXISTS idx_tasks_active_status_rank`,
+				`DROP INDEX IF EXISTS idx_rate_limits_updated`,
+				`DROP INDEX IF EXISTS idx_outbox_pending`,
 				`DROP INDEX IF EXISTS idx_tasks_deleted_created`,
 				`DROP INDEX IF EXISTS idx_tasks_archived_created`,
 				`DROP INDEX IF EXISTS idx_tasks_overdue`,
@@ -222,7 +772,35 @@ func (a *App) initRepository(ctx context.Context) (service.TaskRepository, error
 				}
 			}
 
-			// УДАЛЯЕМ ТАБЛИЦУ
+			// УДАЛЯЕМ ТАБЛИЦЫ
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS webhook_pending`); err != nil {
+				logger.Error("Ошибка удаления таблицы webhook_pending", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS webhook_dead_letters`); err != nil {
+				logger.Error("Ошибка удаления таблицы webhook_dead_letters", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS webhook_deliveries`); err != nil {
+				logger.Error("Ошибка удаления таблицы webhook_deliveries", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS webhook_subscriptions`); err != nil {
+				logger.Error("Ошибка удаления таблицы webhook_subscriptions", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS task_history`); err != nil {
+				logger.Error("Ошибка удаления таблицы task_history", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS timers`); err != nil {
+				logger.Error("Ошибка удаления таблицы timers", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS worklogs`); err != nil {
+				logger.Error("Ошибка удаления таблицы worklogs", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS rate_limits`); err != nil {
+				logger.Error("Ошибка удаления таблицы rate_limits", err)
+			}
+			if _, err := conn.Exec(ctx, `DROP TABLE IF EXISTS outbox`); err != nil {
+				logger.Error("Ошибка удаления таблицы outbox", err)
+			}
+
 			_, err = conn.Exec(ctx, `DROP TABLE IF EXISTS tasks`)
 			if err != nil {
 				logger.Error("Ошибка удаления таблицы tasks", err)
@@ -236,10 +814,62 @@ func (a *App) initRepository(ctx context.Context) (service.TaskRepository, error
 			repo.Close()
 		})
 
+		a.outbox = repo
+		a.rateStore = repo
+		a.worklogStore = repo
+		a.historyStore = repo
+		a.boardLocker = repo
+		a.webhookStore = repo
 		return repo, nil
 
 	case "inmemory":
-		repo := inmemory.NewTaskStorage()
+		imCfg := a.config.Repository.Inmemory
+		if imCfg.DataDir == "" {
+			repo := inmemory.NewTaskStorage()
+			a.outbox = repo.EnableOutbox()
+			a.worklogStore = repo
+			a.historyStore = repo
+			return repo, nil
+		}
+
+		repo, err := inmemory.NewPersistentTaskStorage(inmemory.PersistenceOptions{
+			Dir:              imCfg.DataDir,
+			Fsync:            inmemory.FsyncPolicy(imCfg.FsyncPolicy),
+			FsyncInterval:    imCfg.FsyncInterval,
+			SnapshotInterval: imCfg.SnapshotInterval,
+			CompactThreshold: imCfg.CompactThreshold,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("восстановление хранилища в памяти: %w", err)
+		}
+
+		a.shutdowns = append(a.shutdowns, func() {
+			logger.Info("Запись финального снапшота хранилища...")
+			if err := repo.Close(); err != nil {
+				logger.Error("Ошибка записи финального снапшота", err)
+			}
+		})
+
+		a.outbox = repo.EnableOutbox()
+		a.worklogStore = repo
+		a.historyStore = repo
+		return repo, nil
+
+	case "sqlite":
+		// миграции SQLite встроены в бинарник и применяются при открытии базы
+		repo, err := sqlite.New(ctx, a.config.Repository.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+
+		a.shutdowns = append(a.shutdowns, func() {
+			logger.Info("Завершение работы БД...")
+			repo.Close()
+		})
+
+		a.worklogStore = repo
+		a.historyStore = repo
+		a.webhookStore = repo
 		return repo, nil
 
 	default:
@@ -247,16 +877,64 @@ func (a *App) initRepository(ctx context.Context) (service.TaskRepository, error
 	}
 }
 
+func (a *App) initWorkflow() error {
+	cfg := workflow.FileConfig{Config: workflow.DefaultConfig()}
+	if path := a.config.Workflow.ConfigPath; path != "" {
+		loaded, err := workflow.LoadConfig(path)
+		if err != nil {
+			return err
+		}
+		cfg = loaded
+	}
+
+	workflows, err := workflow.NewSet(cfg)
+	if err != nil {
+		return err
+	}
+	a.workflows = workflows
+	return nil
+}
+
+func (a *App) initBoards() error {
+	if a.config.Boards.ConfigPath == "" {
+		a.boards = board.Default(a.workflows.Default())
+		return nil
+	}
+
+	cfg, err := board.LoadConfig(a.config.Boards.ConfigPath)
+	if err != nil {
+		return err
+	}
+	boards, err := board.New(cfg, a.workflows.Default())
+	if err != nil {
+		return err
+	}
+	a.boards = boards
+	return nil
+}
+
 func (a *App) initService() (handlers.Service, error) {
 	logger.Info("Попытка инициализации сервиса")
 
+	// без outbox сервис публикует события сам сразу после мутации
+	options := []service.Option{service.WithWorkflows(a.workflows), service.WithBoards(a.boards)}
+	if a.outbox == nil {
+		options = append(options, service.WithPublisher(a.events))
+	}
+	if a.boardLocker != nil {
+		options = append(options, service.WithBoardLocker(a.boardLocker))
+	}
+
 	switch a.config.Repository.Type {
 	case "postgres":
-		ser := service.NewTaskService(a.repository, "postgres")
+		ser := service.NewTaskService(a.repository, "postgres", options...)
 
 		return &ser, nil
 	case "inmemory":
-		service := service.NewTaskService(a.repository, "inmemory")
+		service := service.NewTaskService(a.repository, "inmemory", options...)
+		return &service, nil
+	case "sqlite":
+		service := service.NewTaskService(a.repository, "sqlite", options...)
 		return &service, nil
 	default:
 		return nil, fmt.Errorf("неизвестный тип репозитория")
@@ -264,14 +942,173 @@ func (a *App) initService() (handlers.Service, error) {
 
 }
 
+func (a *App) initReminders() error {
+	cfg := a.config.Reminder
+
+	notifiers := make(notify.Multi, 0, len(cfg.Notifiers))
+	for _, name := range cfg.Notifiers {
+		switch name {
+		case "log":
+			notifiers = append(notifiers, notify.NewLog())
+
+		case "smtp":
+			smtpNotifier, err := notify.NewSMTP(notify.SMTPConfig{
+				Addr:     cfg.SMTP.Addr,
+				From:     cfg.SMTP.From,
+				To:       cfg.SMTP.To,
+				Username: cfg.SMTP.Username,
+				Password: cfg.SMTP.Password,
+			})
+			if err != nil {
+				return err
+			}
+			notifiers = append(notifiers, smtpNotifier)
+
+		case "webhook":
+			webhook, err := notify.NewWebhook(cfg.WebhookURL, cfg.WebhookTimeout)
+			if err != nil {
+				return err
+			}
+			notifiers = append(notifiers, webhook)
+
+		default:
+			return fmt.Errorf("неизвестный способ доставки напоминаний: %s", name)
+		}
+	}
+
+	scheduler := reminder.NewScheduler(a.repository, notifiers, cfg.Interval, cfg.BatchSize)
+	scheduler.Start()
+
+	a.shutdowns = append(a.shutdowns, func() {
+		logger.Info("Остановка планировщика напоминаний...")
+		scheduler.Stop()
+	})
+	return nil
+}
+
+func (a *App) initWebhooks() {
+	cfg := a.config.Webhook
+
+	store := a.webhookStore
+	if store == nil {
+		store = webhook.NewMemoryStore()
+	}
+	dispatcher := webhook.NewDispatcher(store, webhook.DispatcherOptions{
+		Workers:      cfg.Workers,
+		QueueSize:    cfg.QueueSize,
+		MaxAttempts:  cfg.MaxAttempts,
+		BaseDelay:    cfg.BaseDelay,
+		MaxDelay:     cfg.MaxDelay,
+		Timeout:      cfg.Timeout,
+		PollInterval: cfg.PollInterval,
+		Lease:        cfg.Lease,
+	})
+	dispatcher.Start()
+	a.events.Subscribe(dispatcher.Handle)
+
+	a.shutdowns = append(a.shutdowns, func() {
+		logger.Info("Остановка доставки вебхуков...")
+		dispatcher.Stop()
+	})
+
+	a.webhooks = webhook.NewService(store)
+}
+
+func (a *App) initWorklogs() {
+	a.worklogs = worklog.NewService(a.worklogStore, a.service)
+	a.events.Subscribe(a.worklogs.Handle)
+}
+
+// initHistory подписывает журнал на события задач до запуска релея outbox,
+// чтобы ни одно изменение не прошло мимо отчётов
+func (a *App) initHistory() error {
+	svc, err := history.NewService(a.historyStore, a.config.Estimates.Unit)
+	if err != nil {
+		return err
+	}
+	a.history = svc
+	a.events.Subscribe(a.history.Handle)
+	return nil
+}
+
+func (a *App) initStream() {
+	a.stream = stream.NewHub(stream.HubOptions{
+		ReplaySize:   a.config.Stream.ReplaySize,
+		ClientBuffer: a.config.Stream.ClientBuffer,
+	})
+	a.events.Subscribe(a.stream.Handle)
+}
+
+func (a *App) initOutboxRelay() {
+	relay := outbox.NewRelay(a.outbox, a.events, outbox.RelayOptions{
+		Interval:  a.config.Outbox.Interval,
+		BatchSize: a.config.Outbox.BatchSize,
+		Retention: a.config.Outbox.Retention,
+	})
+	relay.Start()
+
+	a.shutdowns = append(a.shutdowns, func() {
+		logger.Info("Остановка релея outbox...")
+		relay.Stop()
+	})
+}
+
+func (a *App) initGraphQL() error {
+	executor, err := gql.New(a.service, gql.Options{
+		MaxDepth:      a.config.GraphQL.MaxDepth,
+		MaxComplexity: a.config.GraphQL.MaxComplexity,
+	})
+	if err != nil {
+		return err
+	}
+	a.graphql = executor
+	return nil
+}
+
+func (a *App) initGRPC() {
+	a.grpc = grpcapi.NewGRPCServer(a.service)
+
+	a.shutdowns = append(a.shutdowns, func() {
+		logger.Info("Graceful shutdown gRPC-сервера")
+		stopped := make(chan struct{})
+		go func() {
+			a.grpc.GracefulStop()
+			close(stopped)
+		}()
+
+		// незавершённые потоки ListTasks не должны держать остановку бесконечно
+		select {
+		case <-stopped:
+		case <-time.After(a.config.GRPC.ShutdownTimeout):
+			logger.Warn("Таймаут graceful shutdown gRPC-сервера, принудительная остановка")
+			a.grpc.Stop()
+		}
+	})
+}
+
 func (a *App) initRouter() {
 	TaskHandler := handlers.NewTaskHandler(a.service)
+	WorklogHandler := handlers.NewWorklogHandler(a.worklogs)
 	r := chi.NewRouter()
 
+	if a.config.Tracing.Enabled {
+		r.Use(middleware.Tracing)
+	}
 	r.Use(middleware.RequestID)
+	if a.metrics != nil {
+		r.Use(middleware.Metrics)
+	}
 	r.Use(middleware.Logging)
 	// r.Use(middleware.Timeout(30 * time.Second))
-	r.Use(middleware.RateLimit(100))
+	if a.limiter != nil {
+		r.Use(middleware.RateLimiter(a.limiter))
+	}
+
+	spec := apiSpec(a.workflows)
+	r.Use(spec.Middleware(openapi.ValidatorOptions{
+		Requests:  a.config.OpenAPI.ValidateRequests,
+		Responses: a.config.OpenAPI.ValidateResponses,
+	}))
 
 	r.Route("/tasks", func(r chi.Router) {
 
@@ -285,6 +1122,15 @@ func (a *App) initRouter() {
 
 			r.Post("/archive", TaskHandler.ArchiveTask)     // POST /tasks/{id}/archive
 			r.Post("/unarchive", TaskHandler.UnarchiveTask) // POST /tasks/{id}/unarchive
+
+			r.Get("/occurrences", TaskHandler.GetTaskOccurrences) // GET /tasks/{id}/occurrences
+			r.Get("/transitions", TaskHandler.GetTaskTransitions) // GET /tasks/{id}/transitions
+			r.Post("/move", TaskHandler.MoveTask)                 // POST /tasks/{id}/move
+
+			r.Post("/timer/start", WorklogHandler.StartTimer)  // POST /tasks/{id}/timer/start
+			r.Post("/timer/stop", WorklogHandler.StopTimer)    // POST /tasks/{id}/timer/stop
+			r.Get("/worklogs", WorklogHandler.GetTaskWorklogs) // GET /tasks/{id}/worklogs
+			r.Post("/worklogs", WorklogHandler.CreateWorklog)  // POST /tasks/{id}/worklogs
 		})
 
 		r.Get("/archived", TaskHandler.GetArchivedTasks) // GET /tasks/archived
@@ -292,6 +1138,18 @@ func (a *App) initRouter() {
 		r.Get("/overdue", TaskHandler.GetOverdueTasks)   // GET /tasks/overdue
 	})
 
+	r.Get("/boards/{id}", TaskHandler.GetBoard)         // GET /boards/{id}
+	r.Get("/worklogs/report", WorklogHandler.GetReport) // GET /worklogs/report
+
+	ReportHandler := handlers.NewReportHandler(a.history)
+	r.Get("/reports/burndown", ReportHandler.GetBurndown) // GET /reports/burndown
+	r.Get("/reports/velocity", ReportHandler.GetVelocity) // GET /reports/velocity
+
+	if a.stats != nil {
+		StatsHandler := handlers.NewStatsHandler(a.stats)
+		r.Get("/stats", StatsHandler.GetStats) // GET /stats
+	}
+
 	r.Route("/admin/tasks", func(r chi.Router) {
 		r.Get("/deleted", TaskHandler.GetDeletedTasks) // GET /admin/tasks/deleted
 
@@ -301,27 +1159,95 @@ func (a *App) initRouter() {
 		})
 	})
 
+	StreamHandler := handlers.NewStreamHandler(a.stream, a.config.Stream.Heartbeat)
+	r.Get("/events/stream", StreamHandler.GetEventStream) // GET /events/stream
+
+	WSHandler := handlers.NewWSHandler(a.service, a.stream, handlers.WSOptions{
+		AllowedOrigins:   a.config.WebSocket.AllowedOrigins,
+		MaxSubscriptions: a.config.WebSocket.MaxSubscriptions,
+		PingInterval:     a.config.WebSocket.PingInterval,
+	})
+	r.Get("/ws", WSHandler.ServeWS) // GET /ws
+
+	if a.graphql != nil {
+		GraphQLHandler := handlers.NewGraphQLHandler(a.graphql)
+		r.Post("/graphql", GraphQLHandler.Query) // POST /graphql
+	}
+
+	if a.webhooks != nil {
+		WebhookHandler := handlers.NewWebhookHandler(a.webhooks)
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Get("/", WebhookHandler.GetWebhooks)                // GET /webhooks
+			r.Post("/", WebhookHandler.CreateWebhook)             // POST /webhooks
+			r.Get("/dead-letters", WebhookHandler.GetDeadLetters) // GET /webhooks/dead-letters
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.Get("/", WebhookHandler.GetWebhookByID)          // GET /webhooks/{id}
+				r.Put("/", WebhookHandler.UpdateWebhook)           // PUT /webhooks/{id}
+				r.Delete("/", WebhookHandler.DeleteWebhook)        // DELETE /webhooks/{id}
+				r.Get("/deliveries", WebhookHandler.GetDeliveries) // GET /webhooks/{id}/deliveries
+			})
+		})
+	}
+
+	if a.cache != nil {
+		CacheHandler := handlers.NewCacheHandler(a.cache)
+		r.Get("/admin/cache/stats", CacheHandler.GetStats) // GET /admin/cache/stats
+	}
+
+	LogHandler := handlers.NewLogHandler(a.config.Logging.AdminToken)
+	if a.config.Logging.AdminToken == "" {
+		logger.Warn("LOGGING_ADMIN_TOKEN не задан, /admin/log-level недоступен")
+	}
+	r.Get("/admin/log-level", LogHandler.GetLevel) // GET /admin/log-level
+	r.Put("/admin/log-level", LogHandler.SetLevel) // PUT /admin/log-level
+
 	r.Get("/health", TaskHandler.HealthCheck)
+	r.Get("/livez", a.health.Live)             // GET /livez
+	r.Get("/readyz", a.health.Ready)           // GET /readyz
+	r.Get("/health/details", a.health.Details) // GET /health/details
+	if a.metrics != nil {
+		r.Method(http.MethodGet, "/metrics", metrics.Handler(a.metrics)) // GET /metrics
+	}
+	r.Get("/openapi.json", spec.Handler())               // GET /openapi.json
+	r.Get("/docs", openapi.DocsHandler("/openapi.json")) // GET /docs
+
+	r.NotFound(handlers.NotFound)
+	r.MethodNotAllowed(handlers.MethodNotAllowed)
 
 	a.router = r
 }
 
 func (a *App) initServer() {
+	startup := a.health.StartupHandler()
 	a.server = &http.Server{
-		Addr:         a.config.GetServerAddr(),
-		Handler:      a.router,
+		Addr: a.config.GetServerAddr(),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if router := a.handler.Load(); router != nil {
+				router.ServeHTTP(w, r)
+				return
+			}
+			startup.ServeHTTP(w, r)
+		}),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
 
-	a.shutdowns = append(a.shutdowns,
-		func() {
-			logger.Info("Graceful shutdown сервера")
-			shutdownctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-			defer cancel()
-			if err := a.server.Shutdown(shutdownctx); err != nil {
-				logger.Error("Ошибка Shutdown сервера", err)
-			}
-		})
+	go func() {
+		logger.Info("Запуск сервера", zap.String("addr", a.server.Addr))
+		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			a.serverErr <- err
+		}
+	}()
+}
 
+// shutdownServer ждёт завершения активных запросов. Потоки событий
+// закрываются в момент начала Shutdown
+func (a *App) shutdownServer() {
+	logger.Info("Graceful shutdown сервера")
+	shutdownctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	if err := a.server.Shutdown(shutdownctx); err != nil {
+		logger.Error("Ошибка Shutdown сервера", err)
+	}
 }
@@ -0,0 +1,385 @@
+package app
+
+import (
+	"net/http"
+	"taskTracker/internal/events"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/health"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/openapi"
+	"taskTracker/internal/repository/task/cache"
+	"taskTracker/internal/webhook"
+	"taskTracker/internal/workflow"
+)
+
+const apiVersion = "1.0.0"
+
+// apiSpec описывает все маршруты initRouter. Схемы тел строятся по типам
+// dto, поэтому документ меняется вместе с обработчиками. Новый маршрут
+// нужно добавить и сюда, иначе упадёт TestAPISpec_CoversRouter
+// apiSpec - документ API. Перечисление статусов берётся из всех workflow
+// набора, nil - встроенный workflow
+func apiSpec(workflows *workflow.Set) *openapi.Spec {
+	if workflows == nil {
+		workflows = workflow.Single(workflow.Default())
+	}
+
+	spec := openapi.New("TaskTracker API", apiVersion)
+
+	// типы со своим MarshalJSON и перечисления
+	spec.Define(task.Reminder{}, "Reminder", openapi.Object(map[string]*openapi.Schema{
+		"before":     openapi.String().WithDescription("интервал до срока в формате Go: 24h, 1h30m"),
+		"sent_at":    openapi.DateTime(),
+		"claimed_at": openapi.DateTime().WithDescription("напоминание забрано на отправку"),
+	}, "before"))
+	var statuses []string
+	for _, status := range workflows.Statuses() {
+		statuses = append(statuses, string(status))
+	}
+	spec.Define(task.Status(""), "", openapi.Enum(statuses...))
+	spec.Define(task.Flag(""), "", openapi.Enum(
+		string(task.FlagActive), string(task.FlagArchived), string(task.FlagDeleted)))
+	spec.Define(events.Type(""), "", openapi.Enum(eventTypes()...))
+
+	// problem+json по RFC 7807; details ошибки - дополнительные поля верхнего уровня
+	problemSchema := openapi.Object(map[string]*openapi.Schema{
+		"type":     openapi.String().WithDescription("urn:task-tracker:problem:<код>"),
+		"title":    openapi.String(),
+		"status":   openapi.Integer(),
+		"detail":   openapi.String(),
+		"instance": openapi.String().WithDescription("ID запроса из X-Request-ID"),
+		"code":     openapi.String().WithDescription("код ошибки: NOT_FOUND, VALIDATION_ERROR..."),
+	}, "type", "title", "status", "code")
+	spec.Default("default", openapi.ProblemResponse("Ошибка", problemSchema))
+
+	taskSchema := spec.Schema(dto.TaskResponse{})
+	taskList := openapi.ArrayOf(taskSchema)
+	id := openapi.PathParam("id", openapi.UUID())
+	pagination := []openapi.Parameter{
+		openapi.QueryParam("page", openapi.Integer().WithMinimum(1), "номер страницы, по умолчанию 1"),
+		openapi.QueryParam("limit", openapi.Integer().WithMinimum(1), "размер страницы, по умолчанию 50, не больше 1000"),
+	}
+	limitOnly := pagination[1:]
+
+	list := func(operationID, summary string, tags ...string) openapi.Operation {
+		return openapi.Operation{
+			OperationID: operationID,
+			Summary:     summary,
+			Tags:        tags,
+			Parameters:  pagination,
+			Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Список задач", taskList)},
+		}
+	}
+	byID := func(operationID, summary string, response *openapi.Response, tags ...string) openapi.Operation {
+		return openapi.Operation{
+			OperationID: operationID,
+			Summary:     summary,
+			Tags:        tags,
+			Parameters:  []openapi.Parameter{id},
+			Responses:   map[string]*openapi.Response{statusCode(response): response},
+		}
+	}
+	taskResponse := openapi.JSONResponse("Задача", taskSchema)
+	noContent := openapi.EmptyResponse("Выполнено")
+
+	// задачи
+	spec.Add(http.MethodGet, "/tasks", list("getActiveTasks", "Активные задачи", "tasks"))
+	spec.Add(http.MethodPost, "/tasks", openapi.Operation{
+		OperationID: "createTask",
+		Summary:     "Создать задачу",
+		Tags:        []string{"tasks"},
+		RequestBody: openapi.JSONBody(spec.Schema(dto.CreateTaskRequest{})),
+		Responses:   map[string]*openapi.Response{"200": taskResponse},
+	})
+	spec.Add(http.MethodGet, "/tasks/{id}", byID("getTask", "Задача по ID", taskResponse, "tasks"))
+	update := byID("updateTask", "Частичное обновление задачи", taskResponse, "tasks")
+	update.RequestBody = openapi.JSONBody(spec.Schema(dto.UpdateTaskRequest{}))
+	spec.Add(http.MethodPut, "/tasks/{id}", update)
+	spec.Add(http.MethodDelete, "/tasks/{id}", byID("deleteTask", "Мягкое удаление задачи", noContent, "tasks"))
+	spec.Add(http.MethodPost, "/tasks/{id}/archive", byID("archiveTask", "Архивировать задачу", taskResponse, "tasks"))
+	spec.Add(http.MethodPost, "/tasks/{id}/unarchive", byID("unarchiveTask", "Вернуть задачу из архива", taskResponse, "tasks"))
+
+	occurrences := byID("getTaskOccurrences", "Повторения задачи в окне [from, to]",
+		openapi.JSONResponse("Повторения", spec.Schema(dto.OccurrencesResponse{})), "tasks")
+	occurrences.Parameters = append(occurrences.Parameters,
+		openapi.QueryParam("from", openapi.DateTime(), "начало окна, по умолчанию текущий момент"),
+		openapi.QueryParam("to", openapi.DateTime(), "конец окна, по умолчанию from + 90 дней"))
+	spec.Add(http.MethodGet, "/tasks/{id}/occurrences", occurrences)
+	spec.Add(http.MethodGet, "/tasks/{id}/transitions", byID("getTaskTransitions", "Переходы workflow из текущего статуса задачи",
+		openapi.JSONResponse("Переходы", spec.Schema(dto.TransitionsResponse{})), "tasks"))
+
+	move := byID("moveTask", "Переместить задачу на доске", taskResponse, "boards")
+	move.RequestBody = openapi.JSONBody(spec.Schema(dto.MoveTaskRequest{}))
+	spec.Add(http.MethodPost, "/tasks/{id}/move", move)
+	spec.Add(http.MethodGet, "/boards/{id}", openapi.Operation{
+		OperationID: "getBoard",
+		Summary:     "Доска с активными задачами по колонкам",
+		Tags:        []string{"boards"},
+		Parameters:  []openapi.Parameter{openapi.PathParam("id", openapi.String())},
+		Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Доска", spec.Schema(dto.BoardResponse{}))},
+	})
+
+	// учёт времени
+	timerBody := openapi.JSONBody(spec.Schema(dto.TimerRequest{}))
+	worklogResponse := openapi.JSONResponse("Запись времени", spec.Schema(dto.WorklogResponse{}))
+	startTimer := byID("startTimer", "Запустить таймер пользователя", openapi.JSONResponse("Таймер", spec.Schema(dto.TimerResponse{})), "worklogs")
+	startTimer.RequestBody = timerBody
+	startTimer.Responses = map[string]*openapi.Response{"201": startTimer.Responses["200"]}
+	spec.Add(http.MethodPost, "/tasks/{id}/timer/start", startTimer)
+	stopTimer := byID("stopTimer", "Остановить таймер и записать время", worklogResponse, "worklogs")
+	stopTimer.RequestBody = timerBody
+	spec.Add(http.MethodPost, "/tasks/{id}/timer/stop", stopTimer)
+	spec.Add(http.MethodGet, "/tasks/{id}/worklogs", byID("getTaskWorklogs", "Записи времени и запущенные таймеры задачи",
+		openapi.JSONResponse("Записи времени", spec.Schema(dto.TaskWorklogsResponse{})), "worklogs"))
+	createWorklog := byID("createWorklog", "Записать время вручную", worklogResponse, "worklogs")
+	createWorklog.RequestBody = openapi.JSONBody(spec.Schema(dto.CreateWorklogRequest{}))
+	createWorklog.Responses = map[string]*openapi.Response{"201": worklogResponse}
+	spec.Add(http.MethodPost, "/tasks/{id}/worklogs", createWorklog)
+	spec.Add(http.MethodGet, "/worklogs/report", openapi.Operation{
+		OperationID: "getWorklogReport",
+		Summary:     "Время по задачам, пользователям и дням за интервал",
+		Tags:        []string{"worklogs"},
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("from", openapi.DateTime(), "начало интервала, обязательный"),
+			openapi.QueryParam("to", openapi.DateTime(), "конец интервала, по умолчанию from + 7 дней"),
+			openapi.QueryParam("group_by", openapi.String(), "измерения через запятую: task, user, day; по умолчанию все"),
+			openapi.QueryParam("user", openapi.String(), "только записи пользователя"),
+			openapi.QueryParam("task_id", openapi.UUID(), "только записи задачи"),
+		},
+		Responses: map[string]*openapi.Response{"200": openapi.JSONResponse("Отчёт", spec.Schema(dto.WorklogReportResponse{}))},
+	})
+
+	// отчёты по оценкам
+	reportRange := []openapi.Parameter{
+		openapi.QueryParam("from", openapi.DateTime(), "начало интервала, обязательный"),
+		openapi.QueryParam("to", openapi.DateTime(), "конец интервала"),
+	}
+	spec.Add(http.MethodGet, "/reports/burndown", openapi.Operation{
+		OperationID: "getBurndown",
+		Summary:     "Burndown и burnup по дням из истории задач",
+		Tags:        []string{"reports"},
+		Parameters:  reportRange,
+		Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Серии по дням", spec.Schema(dto.BurndownResponse{}))},
+	})
+	spec.Add(http.MethodGet, "/reports/velocity", openapi.Operation{
+		OperationID: "getVelocity",
+		Summary:     "Выполненная работа по неделям",
+		Tags:        []string{"reports"},
+		Parameters:  reportRange,
+		Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Недели", spec.Schema(dto.VelocityResponse{}))},
+	})
+
+	// статистика
+	spec.Add(http.MethodGet, "/stats", openapi.Operation{
+		OperationID: "getStats",
+		Summary:     "Сводка по задачам: счётчики, ряды по дням, lead time и cycle time",
+		Tags:        []string{"stats"},
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("from", openapi.DateTime(), "начало интервала рядов, по умолчанию to минус 30 дней"),
+			openapi.QueryParam("to", openapi.DateTime(), "конец интервала рядов, по умолчанию текущий момент"),
+			openapi.QueryParam("top", openapi.Integer().WithMinimum(0), "сколько просроченных задач вернуть, по умолчанию 10, не больше 100"),
+		},
+		Responses: map[string]*openapi.Response{"200": openapi.JSONResponse("Статистика", spec.Schema(dto.StatsResponse{}))},
+	})
+
+	spec.Add(http.MethodGet, "/tasks/archived", list("getArchivedTasks", "Архивные задачи", "tasks"))
+	spec.Add(http.MethodGet, "/tasks/all", list("getAllTasks", "Все задачи, кроме удалённых", "tasks"))
+	spec.Add(http.MethodGet, "/tasks/overdue", list("getOverdueTasks", "Просроченные задачи", "tasks"))
+
+	// администрирование
+	spec.Add(http.MethodGet, "/admin/tasks/deleted", list("getDeletedTasks", "Удалённые задачи", "admin"))
+	spec.Add(http.MethodPost, "/admin/tasks/{id}/restore", byID("restoreTask", "Восстановить удалённую задачу", taskResponse, "admin"))
+	spec.Add(http.MethodDelete, "/admin/tasks/{id}/purge", byID("purgeTask", "Удалить задачу безвозвратно", noContent, "admin"))
+	spec.Add(http.MethodGet, "/admin/cache/stats", openapi.Operation{
+		OperationID: "getCacheStats",
+		Summary:     "Статистика кэша GetByID",
+		Tags:        []string{"admin"},
+		Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Статистика", spec.Schema(cache.Stats{}))},
+	})
+	logLevel := openapi.JSONResponse("Текущий уровень", spec.Schema(dto.LogLevel{}))
+	logToken := openapi.HeaderParam("Authorization", openapi.String(), "Bearer-токен LOGGING_ADMIN_TOKEN")
+	logUnauthorized := openapi.ProblemResponse("Нет токена или токен неверный", problemSchema)
+	spec.Add(http.MethodGet, "/admin/log-level", openapi.Operation{
+		OperationID: "getLogLevel",
+		Summary:     "Уровень логов",
+		Tags:        []string{"admin"},
+		Parameters:  []openapi.Parameter{logToken},
+		Responses:   map[string]*openapi.Response{"200": logLevel, "401": logUnauthorized},
+	})
+	spec.Add(http.MethodPut, "/admin/log-level", openapi.Operation{
+		OperationID: "setLogLevel",
+		Summary:     "Сменить уровень логов без перезапуска",
+		Tags:        []string{"admin"},
+		Parameters:  []openapi.Parameter{logToken},
+		RequestBody: openapi.JSONBody(spec.Schema(dto.LogLevel{})),
+		Responses:   map[string]*openapi.Response{"200": logLevel, "401": logUnauthorized},
+	})
+
+	// события
+	spec.Add(http.MethodGet, "/events/stream", openapi.Operation{
+		OperationID: "getEventStream",
+		Summary:     "Поток событий задач (Server-Sent Events)",
+		Tags:        []string{"events"},
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("flag", openapi.String(), "флаги задач через запятую"),
+			openapi.QueryParam("status", openapi.String(), "статусы задач через запятую"),
+			openapi.QueryParam("types", openapi.String(), "типы событий через запятую"),
+			openapi.QueryParam("last_event_id", openapi.String(), "точка возобновления, если нельзя передать заголовок"),
+			openapi.HeaderParam("Last-Event-ID", openapi.String(), "ID последнего полученного события"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.ContentResponse("Поток событий в формате text/event-stream", "text/event-stream",
+				spec.Schema(events.Event{})),
+		},
+	})
+	spec.Add(http.MethodGet, "/ws", openapi.Operation{
+		OperationID: "serveWebSocket",
+		Summary:     "WebSocket: подписка на задачи и мутации",
+		Tags:        []string{"events"},
+		Responses:   map[string]*openapi.Response{"101": openapi.EmptyResponse("Соединение переключено на WebSocket")},
+	})
+
+	// GraphQL
+	spec.Add(http.MethodPost, "/graphql", openapi.Operation{
+		OperationID: "graphql",
+		Summary:     "Запрос GraphQL",
+		Tags:        []string{"graphql"},
+		RequestBody: openapi.JSONBody(spec.Schema(handlers.GraphQLRequest{})),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.JSONResponse("Результат; ошибки GraphQL - в поле errors", openapi.Object(map[string]*openapi.Schema{
+				"data":   openapi.Any(),
+				"errors": openapi.ArrayOf(openapi.Any()),
+			})),
+		},
+	})
+
+	// вебхуки
+	webhookSchema := spec.Schema(dto.WebhookResponse{})
+	webhookResponse := openapi.JSONResponse("Подписка", webhookSchema)
+	spec.Add(http.MethodGet, "/webhooks", openapi.Operation{
+		OperationID: "getWebhooks",
+		Summary:     "Подписки на вебхуки",
+		Tags:        []string{"webhooks"},
+		Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Подписки", openapi.ArrayOf(webhookSchema))},
+	})
+	spec.Add(http.MethodPost, "/webhooks", openapi.Operation{
+		OperationID: "createWebhook",
+		Summary:     "Создать подписку",
+		Tags:        []string{"webhooks"},
+		RequestBody: openapi.JSONBody(spec.Schema(dto.CreateWebhookRequest{})),
+		Responses: map[string]*openapi.Response{
+			"201": openapi.JSONResponse("Подписка вместе с секретом", webhookSchema),
+		},
+	})
+	spec.Add(http.MethodGet, "/webhooks/dead-letters", openapi.Operation{
+		OperationID: "getWebhookDeadLetters",
+		Summary:     "Недоставленные события",
+		Tags:        []string{"webhooks"},
+		Parameters:  limitOnly,
+		Responses: map[string]*openapi.Response{
+			"200": openapi.JSONResponse("События", openapi.ArrayOf(spec.Schema(webhook.DeadLetter{}))),
+		},
+	})
+	spec.Add(http.MethodGet, "/webhooks/{id}", byID("getWebhook", "Подписка по ID", webhookResponse, "webhooks"))
+	updateWebhook := byID("updateWebhook", "Частичное обновление подписки", webhookResponse, "webhooks")
+	updateWebhook.RequestBody = openapi.JSONBody(spec.Schema(dto.UpdateWebhookRequest{}))
+	spec.Add(http.MethodPut, "/webhooks/{id}", updateWebhook)
+	spec.Add(http.MethodDelete, "/webhooks/{id}", byID("deleteWebhook", "Удалить подписку", noContent, "webhooks"))
+	deliveries := byID("getWebhookDeliveries", "Журнал доставок подписки",
+		openapi.JSONResponse("Доставки", openapi.ArrayOf(spec.Schema(webhook.Delivery{}))), "webhooks")
+	deliveries.Parameters = append(deliveries.Parameters, limitOnly...)
+	spec.Add(http.MethodGet, "/webhooks/{id}/deliveries", deliveries)
+
+	// служебные
+	spec.Add(http.MethodGet, "/health", openapi.Operation{
+		OperationID: "healthCheck",
+		Summary:     "Проверка доступности хранилища",
+		Tags:        []string{"service"},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.JSONResponse("Сервис доступен", healthSchema()),
+			"503": openapi.JSONResponse("Хранилище недоступно", healthSchema()),
+		},
+	})
+	probe := openapi.Object(map[string]*openapi.Schema{
+		"status": openapi.String(),
+		"state":  openapi.Enum("starting", "ready", "draining"),
+		"checks": {Type: openapi.SchemaType{"object"}, AdditionalProperties: openapi.Enum(health.StatusUp, health.StatusDown)},
+	}, "status", "state")
+	spec.Add(http.MethodGet, "/livez", openapi.Operation{
+		OperationID: "livez",
+		Summary:     "Процесс жив",
+		Tags:        []string{"service"},
+		Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Процесс обслуживает запросы", probe)},
+	})
+	spec.Add(http.MethodGet, "/readyz", openapi.Operation{
+		OperationID: "readyz",
+		Summary:     "Готовность принимать трафик: 503 при запуске, остановке и недоступности базы",
+		Tags:        []string{"service"},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.JSONResponse("Готов", probe),
+			"503": openapi.JSONResponse("Не готов", probe),
+		},
+	})
+	report := spec.Schema(health.Report{})
+	spec.Add(http.MethodGet, "/health/details", openapi.Operation{
+		OperationID: "healthDetails",
+		Summary:     "Подробная проверка зависимостей, по токену HEALTH_DETAILS_TOKEN",
+		Tags:        []string{"service"},
+		Parameters: []openapi.Parameter{
+			openapi.HeaderParam("Authorization", openapi.String(), "Bearer-токен подробной проверки"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.JSONResponse("Все критичные зависимости доступны", report),
+			"401": openapi.ProblemResponse("Нет токена или токен неверный", problemSchema),
+			"503": openapi.JSONResponse("Критичная зависимость недоступна", report),
+		},
+	})
+	spec.Add(http.MethodGet, "/metrics", openapi.Operation{
+		OperationID: "getMetrics",
+		Summary:     "Метрики Prometheus",
+		Tags:        []string{"service"},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.ContentResponse("Метрики в текстовом формате Prometheus", "text/plain", openapi.String()),
+		},
+	})
+	spec.Add(http.MethodGet, "/openapi.json", openapi.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "Этот документ",
+		Tags:        []string{"service"},
+		Responses:   map[string]*openapi.Response{"200": openapi.JSONResponse("Документ OpenAPI", openapi.Any())},
+	})
+	spec.Add(http.MethodGet, "/docs", openapi.Operation{
+		OperationID: "getDocs",
+		Summary:     "Swagger UI",
+		Tags:        []string{"service"},
+		Responses:   map[string]*openapi.Response{"200": openapi.ContentResponse("Страница документации", "text/html", openapi.String())},
+	})
+
+	return spec
+}
+
+// statusCode - код успешного ответа: 204 для ответа без тела
+func statusCode(response *openapi.Response) string {
+	if len(response.Content) == 0 {
+		return "204"
+	}
+	return "200"
+}
+
+func healthSchema() *openapi.Schema {
+	return openapi.Object(map[string]*openapi.Schema{
+		"status":    openapi.Enum("healthy", "unhealthy"),
+		"service":   openapi.String(),
+		"timestamp": openapi.DateTime(),
+		"error":     openapi.String(),
+	}, "status", "service", "timestamp")
+}
+
+func eventTypes() []string {
+	res := make([]string, len(events.Types))
+	for i, t := range events.Types {
+		res[i] = string(t)
+	}
+	return res
+}
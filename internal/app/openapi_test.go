@@ -0,0 +1,255 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"taskTracker/internal/config"
+	"taskTracker/internal/events"
+	"taskTracker/internal/gql"
+	"taskTracker/internal/health"
+	"taskTracker/internal/history"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
+	"taskTracker/internal/repository/task/cache"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"taskTracker/internal/stats"
+	"taskTracker/internal/stream"
+	"taskTracker/internal/webhook"
+	"taskTracker/internal/worklog"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// newTestApp собирает роутер со всеми необязательными маршрутами
+func newTestApp(t *testing.T, cfg config.OpenAPIConfig) *App {
+	t.Helper()
+
+	repo := inmemory.NewTaskStorage()
+	svc := service.NewTaskService(repo, "inmemory")
+	executor, err := gql.New(&svc, gql.Options{})
+	require.NoError(t, err)
+	reports, err := history.NewService(history.NewMemoryStore(), history.UnitPoints)
+	require.NoError(t, err)
+
+	a := &App{
+		config:   &config.Config{OpenAPI: cfg},
+		service:  &svc,
+		events:   events.NewBus(),
+		stream:   stream.NewHub(stream.HubOptions{}),
+		webhooks: webhook.NewService(webhook.NewMemoryStore()),
+		worklogs: worklog.NewService(worklog.NewMemoryStore(), &svc),
+		history:  reports,
+		stats:    stats.NewService(repo, time.Minute),
+		cache:    cache.NewRepository(repo, cache.NewLRU(10), time.Minute),
+		graphql:  executor,
+		metrics:  metrics.NewRegistry(metrics.NewTasksCollector(repo, time.Second)),
+		health:   health.New(health.Options{Timeout: time.Second}),
+	}
+	a.initRouter()
+	return a
+}
+
+func normalize(path string) string {
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// TestAPISpec_CoversRouter тестирует, что документ описывает ровно маршруты роутера
+func TestAPISpec_CoversRouter(t *testing.T) {
+	a := newTestApp(t, config.OpenAPIConfig{})
+	doc := apiSpec(nil).Document()
+
+	registered := make(map[string]bool)
+	err := chi.Walk(a.router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		key := method + " " + normalize(route)
+		registered[key] = true
+
+		item, ok := doc.Paths[normalize(route)]
+		if assert.True(t, ok, "маршрут %s не описан в OpenAPI", key) {
+			_, ok = (*item)[strings.ToLower(method)]
+			assert.True(t, ok, "метод %s не описан в OpenAPI", key)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	for path, item := range doc.Paths {
+		for method := range *item {
+			key := strings.ToUpper(method) + " " + path
+			assert.True(t, registered[key], "операция %s описана, но не зарегистрирована", key)
+		}
+	}
+}
+
+// TestAPISpec_Contract тестирует, что ответы обработчиков соответствуют документу
+func TestAPISpec_Contract(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = zap.NewNop() })
+
+	a := newTestApp(t, config.OpenAPIConfig{ValidateRequests: true, ValidateResponses: true})
+	server := httptest.NewServer(a.router)
+	t.Cleanup(server.Close)
+
+	do := func(method, path string, body any) (*http.Response, map[string]any) {
+		t.Helper()
+
+		var reader *bytes.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req, err := http.NewRequest(method, server.URL+path, reader)
+		require.NoError(t, err)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var decoded map[string]any
+		json.NewDecoder(resp.Body).Decode(&decoded)
+		return resp, decoded
+	}
+
+	resp, created := do(http.MethodPost, "/tasks", map[string]any{
+		"title":     "Задача",
+		"due_time":  time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+		"reminders": []string{"1h"},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	id := created["id"].(string)
+
+	for _, path := range []string{"/tasks", "/tasks/" + id, "/tasks/" + id + "/transitions", "/boards/default", "/tasks/all", "/tasks/archived", "/tasks/overdue",
+		"/admin/tasks/deleted", "/admin/cache/stats", "/health", "/livez", "/webhooks", "/openapi.json"} {
+		resp, _ := do(http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+	// Run не вызывался: приложение ещё запускается
+	resp, _ = do(http.MethodGet, "/readyz", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	resp, _ = do(http.MethodGet, "/health/details", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = do(http.MethodPut, "/tasks/"+id, map[string]any{"status": "in progress"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, body := do(http.MethodPut, "/tasks/"+id, map[string]any{"status": "new"})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "INVALID_TRANSITION", body["code"])
+	resp, body = do(http.MethodPost, "/tasks/"+id+"/move", map[string]any{"column": "blocked"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "blocked", body["status"])
+	assert.NotEmpty(t, body["rank"])
+	resp, _ = do(http.MethodGet, "/boards/missing", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = do(http.MethodPost, "/tasks/"+id+"/timer/start", map[string]any{"user": "alice"})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, body = do(http.MethodPost, "/tasks/"+id+"/timer/start", map[string]any{"user": "alice"})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "TIMER_RUNNING", body["code"])
+	resp, _ = do(http.MethodPost, "/tasks/"+id+"/timer/stop", map[string]any{"user": "alice", "note": "ревью"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	started := time.Now().Add(-2 * time.Hour).UTC()
+	resp, body = do(http.MethodPost, "/tasks/"+id+"/worklogs", map[string]any{
+		"user":       "bob",
+		"started_at": started.Format(time.RFC3339),
+		"duration":   "1h30m",
+	})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, float64(5400), body["duration_seconds"])
+	resp, body = do(http.MethodGet, "/tasks/"+id+"/worklogs", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, body["entries"], 2)
+	resp, body = do(http.MethodGet, "/worklogs/report?group_by=user&from="+started.Add(-time.Hour).Format(time.RFC3339), nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, body["rows"], 2)
+	resp, _ = do(http.MethodGet, "/worklogs/report", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, body = do(http.MethodPut, "/tasks/"+id, map[string]any{"original_estimate": 5, "remaining_estimate": 3})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, float64(3), body["remaining_estimate"])
+	resp, body = do(http.MethodPut, "/tasks/"+id, map[string]any{"original_estimate": -1})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "original_estimate", body["field"])
+	since := url.QueryEscape(time.Now().Add(-24 * time.Hour).Format(time.RFC3339))
+	resp, body = do(http.MethodGet, "/reports/burndown?from="+since, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "points", body["unit"])
+	resp, body = do(http.MethodGet, "/reports/velocity?from="+since, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, body["weeks"])
+	resp, body = do(http.MethodGet, "/stats?top=5", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, body["days"], 31)
+	assert.Equal(t, "private, max-age=60", resp.Header.Get("Cache-Control"))
+	resp, body = do(http.MethodGet, "/stats?top=500", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "top", body["field"])
+	resp, _ = do(http.MethodPost, "/tasks/"+id+"/archive", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, body = do(http.MethodPost, "/tasks/"+id+"/archive", nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "ALREADY_ARCHIVED", body["code"])
+	assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, resp.Header.Get("X-Request-ID"), body["instance"])
+	assert.Equal(t, id, body["task_id"])
+
+	// ни одного расхождения с документом
+	for _, entry := range logs.FilterMessage("HTTP: Ответ не соответствует OpenAPI").All() {
+		t.Errorf("ответ не соответствует документу: %v", entry.ContextMap())
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		resp, body := do(http.MethodGet, "/tasks/not-a-uuid", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "VALIDATION_ERROR", body["code"])
+		assert.Equal(t, "path.id", body["field"])
+
+		resp, body = do(http.MethodPost, "/tasks", map[string]any{"description": "без названия"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "body.title", body["field"])
+
+		resp, body = do(http.MethodPut, "/tasks/"+id, map[string]any{"status": "unknown"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "body.status", body["field"])
+
+		resp, body = do(http.MethodGet, "/tasks?page=0", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "query.page", body["field"])
+
+		resp, body = do(http.MethodGet, "/unknown", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "ROUTE_NOT_FOUND", body["code"])
+
+		resp, body = do(http.MethodPatch, "/tasks/"+id, nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		assert.Equal(t, "METHOD_NOT_ALLOWED", body["code"])
+	})
+}
@@ -0,0 +1,148 @@
+// Package board - канбан-доски: колонки по статусам workflow и ручной порядок
+// задач внутри колонки по лексикографическим рангам
+package board
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/workflow"
+)
+
+// DefaultID - доска без файла конфигурации, по колонке на статус workflow
+const DefaultID = "default"
+
+type Boards struct {
+	boards []Board
+}
+
+// New проверяет конфиг: у досок и колонок есть уникальные id, статусы
+// объявлены в workflow и каждый статус стоит не больше чем в одной колонке доски
+func New(cfg Config, wf *workflow.Workflow) (*Boards, error) {
+	b := &Boards{}
+	for _, board := range cfg.Boards {
+		if board.ID == "" {
+			return nil, fmt.Errorf("board: у доски %q пустой id", board.Name)
+		}
+		if _, ok := b.Get(board.ID); ok {
+			return nil, fmt.Errorf("board: доска %q объявлена дважды", board.ID)
+		}
+		if len(board.Columns) == 0 {
+			return nil, fmt.Errorf("board: у доски %s нет колонок", board.ID)
+		}
+
+		seen := make(map[task.Status]string)
+		for i, col := range board.Columns {
+			if col.ID == "" || slices.ContainsFunc(board.Columns[:i], func(c Column) bool { return c.ID == col.ID }) {
+				return nil, fmt.Errorf("board: у колонки %q доски %s пустой или повторный id", col.Name, board.ID)
+			}
+			if len(col.Statuses) == 0 {
+				return nil, fmt.Errorf("board: у колонки %s доски %s нет статусов", col.ID, board.ID)
+			}
+			if col.WIPLimit < 0 {
+				return nil, fmt.Errorf("board: отрицательный wip_limit у колонки %s доски %s", col.ID, board.ID)
+			}
+			for _, status := range col.Statuses {
+				if !wf.Known(status) {
+					return nil, fmt.Errorf("board: колонка %s доски %s ссылается на необъявленный статус %q", col.ID, board.ID, status)
+				}
+				if other, ok := seen[status]; ok {
+					return nil, fmt.Errorf("board: статус %q доски %s стоит в колонках %s и %s", status, board.ID, other, col.ID)
+				}
+				seen[status] = col.ID
+			}
+		}
+		b.boards = append(b.boards, board)
+	}
+	if len(b.boards) == 0 {
+		return nil, errors.New("board: не объявлено ни одной доски")
+	}
+	return b, nil
+}
+
+// Default - одна доска DefaultID с колонкой на каждый статус workflow
+func Default(wf *workflow.Workflow) *Boards {
+	board := Board{ID: DefaultID, Name: "Задачи"}
+	for _, status := range wf.Statuses() {
+		board.Columns = append(board.Columns, Column{
+			ID:       strings.ReplaceAll(string(status), " ", "_"),
+			Name:     string(status),
+			Statuses: []task.Status{status},
+		})
+	}
+	return &Boards{boards: []Board{board}}
+}
+
+// All - доски в порядке конфига
+func (b *Boards) All() []Board {
+	return slices.Clone(b.boards)
+}
+
+// First - доска по умолчанию для перемещений без явной доски
+func (b *Boards) First() Board {
+	return b.boards[0]
+}
+
+func (b *Boards) Get(id string) (Board, bool) {
+	for _, board := range b.boards {
+		if board.ID == id {
+			return board, true
+		}
+	}
+	return Board{}, false
+}
+
+func (b Board) Column(id string) (Column, bool) {
+	for _, col := range b.Columns {
+		if col.ID == id {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+func (c Column) Contains(status task.Status) bool {
+	return slices.Contains(c.Statuses, status)
+}
+
+// ColumnTasks - колонка с задачами в порядке рангов
+type ColumnTasks struct {
+	Column
+	Tasks []*task.Task
+}
+
+// Group раскладывает задачи по колонкам доски. Задачи со статусом, которого
+// нет ни в одной колонке, на доску не попадают
+func (b Board) Group(tasks []*task.Task) []ColumnTasks {
+	res := make([]ColumnTasks, len(b.Columns))
+	for i, col := range b.Columns {
+		res[i].Column = col
+		for _, t := range tasks {
+			if col.Contains(t.Status) {
+				res[i].Tasks = append(res[i].Tasks, t)
+			}
+		}
+		Sort(res[i].Tasks)
+	}
+	return res
+}
+
+// Rank - ранг задачи на доске. Задачи без ранга стоят по времени создания
+func Rank(t *task.Task) string {
+	if t.Rank != "" {
+		return t.Rank
+	}
+	return InitialRank(t.CreatedAt)
+}
+
+// Sort упорядочивает задачи по рангу, одинаковые ранги - по id
+func Sort(tasks []*task.Task) {
+	slices.SortFunc(tasks, func(a, b *task.Task) int {
+		if c := strings.Compare(Rank(a), Rank(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UUID.String(), b.UUID.String())
+	})
+}
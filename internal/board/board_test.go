@@ -0,0 +1,167 @@
+package board_test
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"taskTracker/internal/board"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/workflow"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBetween тестирует ранг между соседями
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "i"},
+		{"", "i", "9"},
+		{"i", "", "r"},
+		{"a", "b", "ai"},
+		{"az", "b", "azi"},
+		{"", "1", "0i"},
+		{"", "01", "00i"},
+		{"a1", "a2", "a1i"},
+		{"ai", "b1", "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			got, err := board.Between(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, bad := range [][2]string{{"b", "a"}, {"a", "a"}, {"a0", ""}, {"", "A"}} {
+		_, err := board.Between(bad[0], bad[1])
+		assert.ErrorIs(t, err, board.ErrInvalidRank, bad)
+	}
+}
+
+// TestBetween_Repeated тестирует вставки в одно место без перенумерации
+func TestBetween_Repeated(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for range 500 {
+		i := rnd.Intn(len(ranks) + 1)
+		var lo, hi string
+		if i > 0 {
+			lo = ranks[i-1]
+		}
+		if i < len(ranks) {
+			hi = ranks[i]
+		}
+		rank, err := board.Between(lo, hi)
+		require.NoError(t, err)
+		require.Greater(t, rank, lo)
+		if hi != "" {
+			require.Less(t, rank, hi)
+		}
+		ranks = append(ranks[:i], append([]string{rank}, ranks[i:]...)...)
+	}
+
+	// вставка всё время в начало удлиняет ранг медленно
+	rank := "i"
+	for range 100 {
+		var err error
+		rank, err = board.Between("", rank)
+		require.NoError(t, err)
+	}
+	assert.LessOrEqual(t, len(rank), 30)
+}
+
+// TestInitialRank тестирует порядок задач без ранга
+func TestInitialRank(t *testing.T) {
+	now := time.Now()
+	first, second := board.InitialRank(now), board.InitialRank(now.Add(time.Nanosecond))
+	assert.Less(t, first, second)
+
+	between, err := board.Between(first, second)
+	require.NoError(t, err)
+	assert.Less(t, first, between)
+	assert.Less(t, between, second)
+}
+
+// TestNew тестирует проверку конфига досок
+func TestNew(t *testing.T) {
+	wf := workflow.Default()
+	column := func(id string, statuses ...task.Status) board.Column {
+		return board.Column{ID: id, Statuses: statuses}
+	}
+
+	tests := []struct {
+		name   string
+		boards []board.Board
+		want   string
+	}{
+		{"empty id", []board.Board{{Columns: []board.Column{column("todo", task.StatusNew)}}}, "пустой id"},
+		{"duplicate board", []board.Board{
+			{ID: "dev", Columns: []board.Column{column("todo", task.StatusNew)}},
+			{ID: "dev", Columns: []board.Column{column("todo", task.StatusNew)}},
+		}, "дважды"},
+		{"no boards", nil, "ни одной доски"},
+		{"no columns", []board.Board{{ID: "dev"}}, "нет колонок"},
+		{"duplicate column", []board.Board{{ID: "dev", Columns: []board.Column{
+			column("todo", task.StatusNew), column("todo", task.StatusDone),
+		}}}, "повторный"},
+		{"unknown status", []board.Board{{ID: "dev", Columns: []board.Column{column("todo", "banana")}}}, "banana"},
+		{"status in two columns", []board.Board{{ID: "dev", Columns: []board.Column{
+			column("todo", task.StatusNew), column("later", task.StatusNew),
+		}}}, "todo и later"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := board.New(board.Config{Boards: tt.boards}, wf)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	def, ok := board.Default(wf).Get(board.DefaultID)
+	require.True(t, ok)
+	assert.Len(t, def.Columns, len(wf.Statuses()))
+	_, ok = def.Column("in_progress")
+	assert.True(t, ok)
+}
+
+// TestLoadConfig тестирует чтение YAML и раскладку задач по колонкам
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boards.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+boards:
+  - id: dev
+    name: Разработка
+    columns:
+      - {id: todo, name: К работе, statuses: [new, blocked]}
+      - {id: doing, name: В работе, statuses: [in progress], wip_limit: 3}
+`), 0o644))
+
+	cfg, err := board.LoadConfig(path)
+	require.NoError(t, err)
+	boards, err := board.New(cfg, workflow.Default())
+	require.NoError(t, err)
+
+	dev, ok := boards.Get("dev")
+	require.True(t, ok)
+	doing, ok := dev.Column("doing")
+	require.True(t, ok)
+	assert.Equal(t, 3, doing.WIPLimit)
+
+	now := time.Now()
+	older := &task.Task{UUID: uuid.New(), Status: task.StatusBlocked, CreatedAt: now.Add(-time.Hour)}
+	newer := &task.Task{UUID: uuid.New(), Status: task.StatusNew, CreatedAt: now}
+	moved := &task.Task{UUID: uuid.New(), Status: task.StatusNew, CreatedAt: now, Rank: "0i"}
+	done := &task.Task{UUID: uuid.New(), Status: task.StatusDone, CreatedAt: now}
+
+	columns := dev.Group([]*task.Task{newer, done, older, moved})
+	require.Len(t, columns, 2)
+	assert.Equal(t, []*task.Task{moved, older, newer}, columns[0].Tasks)
+	assert.Empty(t, columns[1].Tasks)
+}
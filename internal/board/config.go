@@ -0,0 +1,53 @@
+package board
+
+import (
+	"fmt"
+	"os"
+	"taskTracker/internal/models/task"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config - доски, файл BOARDS_CONFIG:
+//
+//	boards:
+//	  - id: dev
+//	    name: Разработка
+//	    columns:
+//	      - {id: todo, name: К работе, statuses: [new, blocked]}
+//	      - {id: doing, name: В работе, statuses: [in progress], wip_limit: 3}
+//	      - {id: review, name: Ревью, statuses: [in review], wip_limit: 2}
+//	      - {id: done, name: Готово, statuses: [done]}
+type Config struct {
+	Boards []Board `yaml:"boards"`
+}
+
+type Board struct {
+	ID      string   `yaml:"id"`
+	Name    string   `yaml:"name"`
+	Columns []Column `yaml:"columns"`
+}
+
+// Column - колонка доски. В колонку попадают активные задачи с любым из
+// Statuses, при перемещении в колонку задача получает первый из них.
+// WIPLimit 0 - без ограничения
+type Column struct {
+	ID       string        `yaml:"id"`
+	Name     string        `yaml:"name"`
+	Statuses []task.Status `yaml:"statuses"`
+	WIPLimit int           `yaml:"wip_limit"`
+}
+
+// LoadConfig читает доски из YAML
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("чтение %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("разбор %s: %w", path, err)
+	}
+	return cfg, nil
+}
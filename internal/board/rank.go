@@ -0,0 +1,95 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ранги - строки в base36, которые сравниваются лексикографически. Между
+// любыми двумя рангами есть третий, поэтому перемещение задачи меняет ранг
+// только у неё самой. Ранг не заканчивается на '0': иначе "a" и "a0" были бы
+// разными строками с одной позицией и между ними ничего не вставить
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var ErrInvalidRank = errors.New("некорректный ранг")
+
+// Between возвращает ранг строго между a и b. Пустой a - начало колонки,
+// пустой b - её конец
+func Between(a, b string) (string, error) {
+	if err := validate(a); err != nil {
+		return "", err
+	}
+	if err := validate(b); err != nil {
+		return "", err
+	}
+	if b != "" && a >= b {
+		return "", fmt.Errorf("%w: %q не меньше %q", ErrInvalidRank, a, b)
+	}
+	return midpoint(a, b), nil
+}
+
+func midpoint(a, b string) string {
+	if b != "" {
+		// общий префикс переносится как есть, a дополняется нулями
+		n := 0
+		for n < len(b) && digit(a, n) == digit(b, n) {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(tail(a, n), b[n:])
+		}
+	}
+
+	da, db := digit(a, 0), len(digits)
+	if b != "" {
+		db = digit(b, 0)
+	}
+	if db-da > 1 {
+		return string(digits[(da+db)/2])
+	}
+	// цифры соседние: хватает первой цифры b, если за ней что-то есть
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[da]) + midpoint(tail(a, 1), "")
+}
+
+// digit - значение i-й цифры, за концом строки - ноль
+func digit(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	return strings.IndexByte(digits, s[i])
+}
+
+func tail(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
+
+func validate(rank string) error {
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(digits, rank[i]) < 0 {
+			return fmt.Errorf("%w %q: допустимы 0-9 и a-z", ErrInvalidRank, rank)
+		}
+	}
+	if strings.HasSuffix(rank, "0") {
+		return fmt.Errorf("%w %q: заканчивается на 0", ErrInvalidRank, rank)
+	}
+	return nil
+}
+
+// InitialRank - ранг задачи, которую ещё не перемещали: по времени создания,
+// так что новые задачи оказываются в конце колонки
+func InitialRank(createdAt time.Time) string {
+	rank := strconv.FormatInt(createdAt.UnixNano(), 36)
+	if len(rank) < 12 {
+		rank = strings.Repeat("0", 12-len(rank)) + rank
+	}
+	return rank + "i"
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,6 +13,23 @@ type Config struct {
 	Logging    LoggingConfig
 	Worker     WorkerConfig
 	Repository RepositoryConfig
+	Cache      CacheConfig
+	Reminder   ReminderConfig
+	Webhook    WebhookConfig
+	Outbox     OutboxConfig
+	Stream     StreamConfig
+	WebSocket  WebSocketConfig
+	GRPC       GRPCConfig
+	GraphQL    GraphQLConfig
+	OpenAPI    OpenAPIConfig
+	Metrics    MetricsConfig
+	Tracing    TracingConfig
+	Health     HealthConfig
+	RateLimit  RateLimitConfig
+	Workflow   WorkflowConfig
+	Boards     BoardsConfig
+	Estimates  EstimatesConfig
+	Stats      StatsConfig
 }
 
 type ServerConfig struct {
@@ -28,6 +46,18 @@ type DatabaseConfig struct {
 
 type LoggingConfig struct {
 	Development bool
+	// Level - начальный уровень, меняется на лету через PUT /admin/log-level
+	Level string
+	// AdminToken открывает /admin/log-level. Пустой - эндпоинт всегда отвечает 401
+	AdminToken string
+	// Format - json или console
+	Format string
+	// Output - stdout, stderr или путь к файлу с ротацией
+	Output     string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
 }
 
 type WorkerConfig struct {
@@ -36,7 +66,179 @@ type WorkerConfig struct {
 }
 
 type RepositoryConfig struct {
-	Type string
+	Type       string
+	Inmemory   InmemoryConfig
+	SQLitePath string
+}
+
+// InmemoryConfig - хранение inmemory-репозитория на диске.
+// Пустой DataDir означает, что данные живут только в памяти
+type InmemoryConfig struct {
+	DataDir          string
+	FsyncPolicy      string
+	FsyncInterval    time.Duration
+	SnapshotInterval time.Duration
+	CompactThreshold int
+}
+
+// CacheConfig - кэш GetByID поверх репозитория.
+// Backend: "lru" (в памяти процесса) или "redis"
+type CacheConfig struct {
+	Enabled   bool
+	Backend   string
+	Size      int
+	TTL       time.Duration
+	RedisAddr string
+}
+
+// ReminderConfig - планировщик напоминаний о сроках.
+// Notifiers - список через запятую из "log", "smtp", "webhook"
+type ReminderConfig struct {
+	Enabled        bool
+	Interval       time.Duration
+	BatchSize      int
+	Notifiers      []string
+	SMTP           SMTPConfig
+	WebhookURL     string
+	WebhookTimeout time.Duration
+}
+
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	To       []string
+	Username string
+	Password string
+}
+
+// WebhookConfig - исходящие вебхуки о событиях задач
+type WebhookConfig struct {
+	Enabled     bool
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Timeout     time.Duration
+	// PollInterval - как часто забирать созревшие повторы из хранилища
+	PollInterval time.Duration
+	// Lease - через сколько доставка, взятая упавшим экземпляром, уходит повторно
+	Lease time.Duration
+}
+
+// OutboxConfig - релей событий из outbox в шину событий
+type OutboxConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	Retention time.Duration
+}
+
+// StreamConfig - поток событий для клиентов (SSE)
+type StreamConfig struct {
+	ReplaySize   int
+	ClientBuffer int
+	Heartbeat    time.Duration
+}
+
+// WebSocketConfig - двунаправленный канал для клиентов
+type WebSocketConfig struct {
+	AllowedOrigins   []string
+	MaxSubscriptions int
+	PingInterval     time.Duration
+}
+
+// GRPCConfig - gRPC API на отдельном порту
+type GRPCConfig struct {
+	Enabled         bool
+	Addr            string
+	ShutdownTimeout time.Duration
+}
+
+// GraphQLConfig - эндпоинт /graphql и ограничения запросов
+type GraphQLConfig struct {
+	Enabled       bool
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// OpenAPIConfig - проверка запросов и ответов по документу /openapi.json
+type OpenAPIConfig struct {
+	ValidateRequests  bool
+	ValidateResponses bool
+}
+
+// MetricsConfig - метрики Prometheus на /metrics
+type MetricsConfig struct {
+	Enabled bool
+	// CountTimeout ограничивает подсчёт задач для бизнес-метрик при сборе
+	CountTimeout time.Duration
+}
+
+// TracingConfig - трассировка OpenTelemetry
+type TracingConfig struct {
+	Enabled     bool
+	ServiceName string
+	// Exporter - otlp (gRPC-коллектор по Endpoint) или stdout
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+	// ShutdownTimeout ограничивает отправку оставшихся спанов при остановке
+	ShutdownTimeout time.Duration
+}
+
+// HealthConfig - пробы /livez, /readyz и отчёт /health/details
+type HealthConfig struct {
+	CheckTimeout time.Duration
+	CacheTTL     time.Duration
+	// DetailsToken открывает /health/details. Пустой - эндпоинт всегда отвечает 401
+	DetailsToken string
+	// DrainDelay - пауза между переводом /readyz в 503 и остановкой сервера,
+	// за которую балансировщик снимает экземпляр
+	DrainDelay time.Duration
+	// DiskMinFreeMB - порог свободного места для inmemory с диском и SQLite
+	DiskMinFreeMB int
+	// OutboxMaxBacklog - сколько неопубликованных событий outbox ещё не считается сбоем
+	OutboxMaxBacklog int
+}
+
+// RateLimitConfig - лимитер запросов. Политики маршрутов, клиентов
+// и доверенные прокси задаются в YAML-файле ConfigPath
+type RateLimitConfig struct {
+	Enabled    bool
+	ConfigPath string
+	// Store - memory или postgres (общие счётчики для всех реплик)
+	Store string
+	// общая политика, если в файле нет default
+	Algorithm string
+	Limit     int
+	Window    time.Duration
+	// MaxKeys ограничивает число счётчиков в памяти
+	MaxKeys int
+	// IdleTimeout должен быть не меньше самого длинного окна политик
+	IdleTimeout   time.Duration
+	EvictInterval time.Duration
+}
+
+// WorkflowConfig - статусы и переходы задач. Без файла - встроенный workflow
+type WorkflowConfig struct {
+	ConfigPath string
+}
+
+// BoardsConfig - канбан-доски. Без файла - доска default с колонкой на статус
+type BoardsConfig struct {
+	ConfigPath string
+}
+
+// EstimatesConfig - оценки задач. Unit - hours или points, одна единица на
+// весь трекер, чтобы отчёты складывали сравнимые величины
+type EstimatesConfig struct {
+	Unit string
+}
+
+// StatsConfig - GET /stats. CacheTTL 0 отключает кэш ответов
+type StatsConfig struct {
+	CacheTTL time.Duration
 }
 
 // ВАЖНО: Убираем ошибку, всегда возвращаем Config
@@ -61,6 +263,14 @@ func LoadFromEnv() *Config {
 		},
 		Logging: LoggingConfig{
 			Development: getEnvAsBool("LOGGING_DEVELOPMENT", true),
+			Level:       getEnv("LOGGING_LEVEL", ""),
+			AdminToken:  getEnv("LOGGING_ADMIN_TOKEN", ""),
+			Format:      getEnv("LOGGING_FORMAT", ""),
+			Output:      getEnv("LOGGING_OUTPUT", "stdout"),
+			MaxSizeMB:   getEnvAsInt("LOGGING_MAX_SIZE_MB", 100),
+			MaxBackups:  getEnvAsInt("LOGGING_MAX_BACKUPS", 5),
+			MaxAgeDays:  getEnvAsInt("LOGGING_MAX_AGE_DAYS", 30),
+			Compress:    getEnvAsBool("LOGGING_COMPRESS", false),
 		},
 		Worker: WorkerConfig{
 			Interval:  getEnvAsDuration("WORKER_INTERVAL", 5*time.Minute),
@@ -68,6 +278,120 @@ func LoadFromEnv() *Config {
 		},
 		Repository: RepositoryConfig{
 			Type: getEnv("REPOSITORY_TYPE", "postgres"),
+			Inmemory: InmemoryConfig{
+				DataDir:          getEnv("INMEMORY_DATA_DIR", ""),
+				FsyncPolicy:      getEnv("INMEMORY_FSYNC_POLICY", "always"),
+				FsyncInterval:    getEnvAsDuration("INMEMORY_FSYNC_INTERVAL", time.Second),
+				SnapshotInterval: getEnvAsDuration("INMEMORY_SNAPSHOT_INTERVAL", 5*time.Minute),
+				CompactThreshold: getEnvAsInt("INMEMORY_COMPACT_THRESHOLD", 10000),
+			},
+			SQLitePath: getEnv("SQLITE_PATH", "tasktracker.db"),
+		},
+		Cache: CacheConfig{
+			Enabled:   getEnvAsBool("CACHE_ENABLED", false),
+			Backend:   getEnv("CACHE_BACKEND", "lru"),
+			Size:      getEnvAsInt("CACHE_SIZE", 10000),
+			TTL:       getEnvAsDuration("CACHE_TTL", time.Minute),
+			RedisAddr: getEnv("REDIS_ADDR", "localhost:6379"),
+		},
+		Reminder: ReminderConfig{
+			Enabled:   getEnvAsBool("REMINDERS_ENABLED", false),
+			Interval:  getEnvAsDuration("REMINDER_INTERVAL", 30*time.Second),
+			BatchSize: getEnvAsInt("REMINDER_BATCH_SIZE", 1000),
+			Notifiers: getEnvAsList("REMINDER_NOTIFIERS", []string{"log"}),
+			SMTP: SMTPConfig{
+				Addr:     getEnv("SMTP_ADDR", "localhost:25"),
+				From:     getEnv("SMTP_FROM", ""),
+				To:       getEnvAsList("SMTP_TO", nil),
+				Username: getEnv("SMTP_USERNAME", ""),
+				Password: getEnv("SMTP_PASSWORD", ""),
+			},
+			WebhookURL:     getEnv("REMINDER_WEBHOOK_URL", ""),
+			WebhookTimeout: getEnvAsDuration("REMINDER_WEBHOOK_TIMEOUT", 10*time.Second),
+		},
+		Webhook: WebhookConfig{
+			Enabled:      getEnvAsBool("WEBHOOKS_ENABLED", false),
+			Workers:      getEnvAsInt("WEBHOOK_WORKERS", 4),
+			QueueSize:    getEnvAsInt("WEBHOOK_QUEUE_SIZE", 1000),
+			MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 5),
+			BaseDelay:    getEnvAsDuration("WEBHOOK_RETRY_BASE_DELAY", time.Second),
+			MaxDelay:     getEnvAsDuration("WEBHOOK_RETRY_MAX_DELAY", 5*time.Minute),
+			Timeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			PollInterval: getEnvAsDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+			Lease:        getEnvAsDuration("WEBHOOK_LEASE", time.Minute),
+		},
+		Outbox: OutboxConfig{
+			Interval:  getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize: getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			Retention: getEnvAsDuration("OUTBOX_RETENTION", 24*time.Hour),
+		},
+		Stream: StreamConfig{
+			ReplaySize:   getEnvAsInt("STREAM_REPLAY_SIZE", 1000),
+			ClientBuffer: getEnvAsInt("STREAM_CLIENT_BUFFER", 64),
+			Heartbeat:    getEnvAsDuration("STREAM_HEARTBEAT", 15*time.Second),
+		},
+		WebSocket: WebSocketConfig{
+			AllowedOrigins:   getEnvAsList("WS_ALLOWED_ORIGINS", nil),
+			MaxSubscriptions: getEnvAsInt("WS_MAX_SUBSCRIPTIONS", 1000),
+			PingInterval:     getEnvAsDuration("WS_PING_INTERVAL", 30*time.Second),
+		},
+		GRPC: GRPCConfig{
+			Enabled:         getEnvAsBool("GRPC_ENABLED", false),
+			Addr:            getEnv("GRPC_ADDRESS", ":9090"),
+			ShutdownTimeout: getEnvAsDuration("GRPC_SHUTDOWN_TIMEOUT", 20*time.Second),
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:       getEnvAsBool("GRAPHQL_ENABLED", true),
+			MaxDepth:      getEnvAsInt("GRAPHQL_MAX_DEPTH", 8),
+			MaxComplexity: getEnvAsInt("GRAPHQL_MAX_COMPLEXITY", 5000),
+		},
+		OpenAPI: OpenAPIConfig{
+			ValidateRequests:  getEnvAsBool("OPENAPI_VALIDATE_REQUESTS", true),
+			ValidateResponses: getEnvAsBool("OPENAPI_VALIDATE_RESPONSES", false),
+		},
+		Metrics: MetricsConfig{
+			Enabled:      getEnvAsBool("METRICS_ENABLED", true),
+			CountTimeout: getEnvAsDuration("METRICS_COUNT_TIMEOUT", 5*time.Second),
+		},
+		Tracing: TracingConfig{
+			Enabled:         getEnvAsBool("TRACING_ENABLED", false),
+			ServiceName:     getEnv("TRACING_SERVICE_NAME", "task-tracker"),
+			Exporter:        getEnv("TRACING_EXPORTER", "otlp"),
+			Endpoint:        getEnv("TRACING_ENDPOINT", "localhost:4317"),
+			Insecure:        getEnvAsBool("TRACING_INSECURE", true),
+			SampleRatio:     getEnvAsFloat("TRACING_SAMPLE_RATIO", 1.0),
+			ShutdownTimeout: getEnvAsDuration("TRACING_SHUTDOWN_TIMEOUT", 5*time.Second),
+		},
+		Health: HealthConfig{
+			CheckTimeout:     getEnvAsDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			CacheTTL:         getEnvAsDuration("HEALTH_CACHE_TTL", 5*time.Second),
+			DetailsToken:     getEnv("HEALTH_DETAILS_TOKEN", ""),
+			DrainDelay:       getEnvAsDuration("HEALTH_DRAIN_DELAY", 5*time.Second),
+			DiskMinFreeMB:    getEnvAsInt("HEALTH_DISK_MIN_FREE_MB", 100),
+			OutboxMaxBacklog: getEnvAsInt("HEALTH_OUTBOX_MAX_BACKLOG", 10000),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:       getEnvAsBool("RATELIMIT_ENABLED", true),
+			ConfigPath:    getEnv("RATELIMIT_CONFIG", ""),
+			Store:         getEnv("RATELIMIT_STORE", "memory"),
+			Algorithm:     getEnv("RATELIMIT_ALGORITHM", "sliding_window"),
+			Limit:         getEnvAsInt("RATELIMIT_LIMIT", 100),
+			Window:        getEnvAsDuration("RATELIMIT_WINDOW", time.Minute),
+			MaxKeys:       getEnvAsInt("RATELIMIT_MAX_KEYS", 100000),
+			IdleTimeout:   getEnvAsDuration("RATELIMIT_IDLE_TIMEOUT", 10*time.Minute),
+			EvictInterval: getEnvAsDuration("RATELIMIT_EVICT_INTERVAL", time.Minute),
+		},
+		Workflow: WorkflowConfig{
+			ConfigPath: getEnv("WORKFLOW_CONFIG", ""),
+		},
+		Boards: BoardsConfig{
+			ConfigPath: getEnv("BOARDS_CONFIG", ""),
+		},
+		Estimates: EstimatesConfig{
+			Unit: getEnv("ESTIMATE_UNIT", "hours"),
+		},
+		Stats: StatsConfig{
+			CacheTTL: getEnvAsDuration("STATS_CACHE_TTL", 30*time.Second),
 		},
 	}
 }
@@ -93,6 +417,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -110,3 +443,18 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var res []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"taskTracker/internal/models/task"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Type string
+
+const TaskCreated Type = "task.created"
+const TaskUpdated Type = "task.updated"
+const TaskArchived Type = "task.archived"
+const TaskUnarchived Type = "task.unarchived"
+const TaskDeleted Type = "task.deleted"
+const TaskRestored Type = "task.restored"
+const TaskPurged Type = "task.purged"
+
+var Types = []Type{
+	TaskCreated,
+	TaskUpdated,
+	TaskArchived,
+	TaskUnarchived,
+	TaskDeleted,
+	TaskRestored,
+	TaskPurged,
+}
+
+func (t Type) Valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event - доменное событие жизненного цикла задачи.
+// Task - снимок задачи на момент события
+type Event struct {
+	ID         uuid.UUID  `json:"id"`
+	Type       Type       `json:"type"`
+	TaskID     uuid.UUID  `json:"task_id"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	Task       *task.Task `json:"task,omitempty"`
+}
+
+func New(eventType Type, t *task.Task) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		TaskID:     t.UUID,
+		OccurredAt: time.Now().UTC(),
+	}.WithTask(t)
+}
+
+// WithTask возвращает копию события со снимком t
+func (e Event) WithTask(t *task.Task) Event {
+	snapshot := *t
+	snapshot.Reminders = t.Reminders.Clone()
+	e.Task = &snapshot
+	return e
+}
+
+type pendingKey struct{}
+
+// WithPending прикладывает к ctx события готовящейся мутации. Хранилище
+// с outbox сохраняет их в той же транзакции, что и саму мутацию
+func WithPending(ctx context.Context, pending ...Event) context.Context {
+	return context.WithValue(ctx, pendingKey{}, pending)
+}
+
+func Pending(ctx context.Context) []Event {
+	pending, _ := ctx.Value(pendingKey{}).([]Event)
+	return pending
+}
+
+// Publisher публикует события для подписчиков
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Handler обрабатывает событие. Ошибка означает, что событие нужно
+// доставить ещё раз: релей outbox оставит запись неопубликованной.
+// Поэтому обработчик должен быть идемпотентен по ID события
+type Handler func(ctx context.Context, e Event) error
+
+// Bus - синхронная шина событий внутри процесса.
+// Обработчики вызываются в порядке подписки и не должны блокироваться надолго
+type Bus struct {
+	mtx      sync.RWMutex
+	handlers []Handler
+
+	// failed - события, которые обработаны не всеми: при повторной
+	// доставке вызываются только обработчики с ошибкой, чтобы остальные
+	// (поток событий, вебхуки) не получали дубли
+	failedMtx sync.Mutex
+	failed    map[uuid.UUID]map[int]bool
+}
+
+// maxFailed ограничивает память под недоставленные события. Сверх него
+// повтор снова вызывает все обработчики
+const maxFailed = 10000
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(h Handler) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish вызывает обработчики и возвращает их ошибки вместе
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	b.mtx.RLock()
+	handlers := b.handlers
+	b.mtx.RUnlock()
+
+	b.failedMtx.Lock()
+	retry, isRetry := b.failed[e.ID]
+	b.failedMtx.Unlock()
+
+	var errs []error
+	failed := make(map[int]bool)
+	for i, h := range handlers {
+		if isRetry && !retry[i] {
+			continue
+		}
+		if err := h(ctx, e); err != nil {
+			errs = append(errs, err)
+			failed[i] = true
+		}
+	}
+
+	b.failedMtx.Lock()
+	switch {
+	case len(failed) > 0 && (isRetry || len(b.failed) < maxFailed):
+		if b.failed == nil {
+			b.failed = make(map[uuid.UUID]map[int]bool)
+		}
+		b.failed[e.ID] = failed
+	case len(failed) == 0:
+		delete(b.failed, e.ID)
+	}
+	b.failedMtx.Unlock()
+
+	return errors.Join(errs...)
+}
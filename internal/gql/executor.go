@@ -0,0 +1,112 @@
+package gql
+
+import (
+	"context"
+	"taskTracker/internal/handlers"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type Options struct {
+	// MaxDepth - максимальная вложенность выборки, 0 - без ограничения
+	MaxDepth int
+	// MaxComplexity - максимальная оценка сложности, 0 - без ограничения
+	MaxComplexity int
+}
+
+// Executor выполняет запросы GraphQL с проверкой ограничений
+// и загрузчиком задач на каждый запрос
+type Executor struct {
+	schema  graphql.Schema
+	service handlers.Service
+	options Options
+}
+
+func New(taskService handlers.Service, opts Options) (*Executor, error) {
+	schema, err := NewSchema(taskService)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Executor{
+		schema:  schema,
+		service: taskService,
+		options: opts,
+	}, nil
+}
+
+// errorResult - ответ с ошибкой до выполнения запроса. FormatError
+// сам переносит extensions только у ошибок выполнения, поэтому здесь вручную
+func errorResult(err error) *graphql.Result {
+	formatted := gqlerrors.FormatError(err)
+	if extended, ok := err.(gqlerrors.ExtendedError); ok {
+		formatted.Extensions = extended.Extensions()
+	}
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{formatted}}
+}
+
+// Execute разбирает запрос, проверяет глубину и сложность до выполнения
+// и выполняет его
+func (e *Executor) Execute(ctx context.Context, query, operationName string, variables map[string]any) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return errorResult(err)
+	}
+
+	depth, complexity, err := analyze(doc, operationName, variables)
+	if err != nil {
+		return errorResult(err)
+	}
+	if e.options.MaxDepth > 0 && depth > e.options.MaxDepth {
+		return errorResult(&LimitError{Code: "QUERY_TOO_DEEP", Limit: e.options.MaxDepth, Actual: depth})
+	}
+	if e.options.MaxComplexity > 0 && complexity > e.options.MaxComplexity {
+		return errorResult(&LimitError{Code: "QUERY_TOO_COMPLEX", Limit: e.options.MaxComplexity, Actual: complexity})
+	}
+
+	validation := graphql.ValidateDocument(&e.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	loader := newTaskLoader(e.service.GetTasksByIDs)
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: operationName,
+		Args:          variables,
+		Context:       withLoader(ctx, loader),
+	})
+	for i := range result.Errors {
+		if result.Errors[i].Extensions == nil {
+			result.Errors[i].Extensions = extensions(result.Errors[i].OriginalError())
+		}
+	}
+	return result
+}
+
+// extensions ищет extensions в цепочке исходных ошибок. Ошибки из thunk
+// graphql-go дважды оборачивает в FormattedError и теряет их
+func extensions(err error) map[string]any {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			return e.Extensions()
+		case gqlerrors.FormattedError:
+			if e.Extensions != nil {
+				return e.Extensions
+			}
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
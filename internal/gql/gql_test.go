@@ -0,0 +1,185 @@
+package gql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"taskTracker/internal/gql"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// countingService считает партии GetTasksByIDs и загруженные в них ID
+type countingService struct {
+	handlers.Service
+	batches atomic.Int32
+	loaded  atomic.Int32
+}
+
+func (s *countingService) GetTasksByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, []error) {
+	s.batches.Add(1)
+	s.loaded.Add(int32(len(ids)))
+	return s.Service.GetTasksByIDs(ctx, ids)
+}
+
+func newExecutor(t *testing.T, opts gql.Options) (*gql.Executor, *countingService) {
+	t.Helper()
+
+	svc := service.NewTaskService(inmemory.NewTaskStorage(), "inmemory")
+	counting := &countingService{Service: &svc}
+	executor, err := gql.New(counting, opts)
+	require.NoError(t, err)
+	return executor, counting
+}
+
+func createTask(t *testing.T, svc handlers.Service, title string) *task.Task {
+	t.Helper()
+
+	created, err := svc.CreateTask(context.Background(), title, "", time.Now().Add(48*time.Hour))
+	require.NoError(t, err)
+	return created
+}
+
+// data раскладывает результат через JSON, как его увидит клиент
+func data(t *testing.T, result *graphql.Result) map[string]any {
+	t.Helper()
+
+	raw, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	var res map[string]any
+	require.NoError(t, json.Unmarshal(raw, &res))
+	return res
+}
+
+// TestExecutor_Batching тестирует объединение загрузок задач по ID в одну партию
+func TestExecutor_Batching(t *testing.T) {
+	executor, svc := newExecutor(t, gql.Options{})
+	first := createTask(t, svc, "Первая")
+	second := createTask(t, svc, "Вторая")
+
+	query := fmt.Sprintf(`{
+		a: task(id: "%[1]s") { title }
+		b: task(id: "%[2]s") { title }
+		c: task(id: "%[1]s") { id }
+		list: tasks(ids: ["%[1]s", "%[2]s"]) { title }
+	}`, first.UUID, second.UUID)
+
+	result := executor.Execute(context.Background(), query, "", nil)
+	require.Empty(t, result.Errors)
+
+	res := data(t, result)
+	assert.Equal(t, "Первая", res["a"].(map[string]any)["title"])
+	assert.Equal(t, "Вторая", res["b"].(map[string]any)["title"])
+	assert.Len(t, res["list"], 2)
+
+	// все ID уровня загружаются одним вызовом, повторные - один раз
+	assert.EqualValues(t, 1, svc.batches.Load())
+	assert.EqualValues(t, 2, svc.loaded.Load())
+}
+
+// TestExecutor_Limits тестирует отказ по глубине и сложности до выполнения
+func TestExecutor_Limits(t *testing.T) {
+	executor, svc := newExecutor(t, gql.Options{MaxDepth: 3, MaxComplexity: 100})
+
+	t.Run("depth", func(t *testing.T) {
+		result := executor.Execute(context.Background(),
+			`{ taskList { items { reminders { before } } } }`, "", nil)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_DEEP", result.Errors[0].Extensions["code"])
+	})
+
+	t.Run("complexity", func(t *testing.T) {
+		result := executor.Execute(context.Background(),
+			`query($limit: Int) { taskList(limit: $limit) { items { id title } } }`, "",
+			map[string]any{"limit": 500})
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_COMPLEX", result.Errors[0].Extensions["code"])
+	})
+
+	t.Run("huge limit", func(t *testing.T) {
+		// limit считается не больше maxLimit и не переполняет сложность
+		result := executor.Execute(context.Background(),
+			`query($limit: Int) { taskList(limit: $limit) { items { id } } }`, "",
+			map[string]any{"limit": 1e300})
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_COMPLEX", result.Errors[0].Extensions["code"])
+		assert.Equal(t, 1+1000*2, result.Errors[0].Extensions["actual"])
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		createTask(t, svc, "Задача")
+		result := executor.Execute(context.Background(),
+			`{ taskList(limit: 10) { items { id title } page limit } }`, "", nil)
+		require.Empty(t, result.Errors)
+		assert.Len(t, data(t, result)["taskList"].(map[string]any)["items"], 1)
+	})
+}
+
+// TestExecutor_BusinessErrors тестирует передачу BusinessError в extensions
+func TestExecutor_BusinessErrors(t *testing.T) {
+	executor, _ := newExecutor(t, gql.Options{})
+
+	missing := uuid.New()
+	result := executor.Execute(context.Background(),
+		fmt.Sprintf(`{ task(id: "%s") { id } }`, missing), "", nil)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "NOT_FOUND", result.Errors[0].Extensions["code"])
+	assert.Equal(t, []any{"task"}, result.Errors[0].Path)
+
+	result = executor.Execute(context.Background(), `{ task(id: "not-a-uuid") { id } }`, "", nil)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "VALIDATION_ERROR", result.Errors[0].Extensions["code"])
+}
+
+// TestExecutor_Mutations тестирует мутации над задачами
+func TestExecutor_Mutations(t *testing.T) {
+	executor, _ := newExecutor(t, gql.Options{})
+	ctx := context.Background()
+
+	result := executor.Execute(ctx, `mutation($input: CreateTaskInput!) {
+		createTask(input: $input) { id title status reminders { before } }
+	}`, "", map[string]any{"input": map[string]any{
+		"title":     "Новая",
+		"dueTime":   time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+		"reminders": []any{"1h"},
+	}})
+	require.Empty(t, result.Errors)
+	created := data(t, result)["createTask"].(map[string]any)
+	assert.Equal(t, "NEW", created["status"])
+	assert.Equal(t, "1h0m0s", created["reminders"].([]any)[0].(map[string]any)["before"])
+	id := created["id"].(string)
+
+	result = executor.Execute(ctx, fmt.Sprintf(`mutation {
+		updateTask(id: "%s", input: {title: "Обновлённая", status: IN_PROGRESS}) { title status description }
+	}`, id), "", nil)
+	require.Empty(t, result.Errors)
+	updated := data(t, result)["updateTask"].(map[string]any)
+	assert.Equal(t, "Обновлённая", updated["title"])
+	assert.Equal(t, "IN_PROGRESS", updated["status"])
+
+	result = executor.Execute(ctx, fmt.Sprintf(`mutation { archiveTask(id: "%s") { flag } }`, id), "", nil)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, "ARCHIVED", data(t, result)["archiveTask"].(map[string]any)["flag"])
+
+	result = executor.Execute(ctx, fmt.Sprintf(`mutation { archiveTask(id: "%s") { flag } }`, id), "", nil)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "ALREADY_ARCHIVED", result.Errors[0].Extensions["code"])
+}
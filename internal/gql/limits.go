@@ -0,0 +1,188 @@
+package gql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// defaultListCost - множитель для списка без явного limit
+const defaultListCost = 50
+
+// analyzer считает глубину и сложность запроса до выполнения. Каждое поле
+// стоит 1, вложенная выборка списка умножается на его limit (или число ids),
+// но не больше maxLimit - больше резолверы не вернут. Сложение и умножение
+// насыщаются на math.MaxInt, поэтому огромный limit не переполняет счётчик
+// и не проходит проверку отрицательной сложностью
+type analyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	visiting  map[string]bool
+}
+
+// analyze возвращает глубину и сложность операции operationName
+// (или единственной операции документа)
+func analyze(doc *ast.Document, operationName string, variables map[string]any) (depth, complexity int, err error) {
+	a := &analyzer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		visiting:  make(map[string]bool),
+	}
+
+	var operations []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.FragmentDefinition:
+			a.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			operations = append(operations, d)
+		}
+	}
+
+	for _, op := range operations {
+		if operationName != "" && (op.Name == nil || op.Name.Value != operationName) {
+			continue
+		}
+		d, c, err := a.selectionSet(op.SelectionSet)
+		if err != nil {
+			return 0, 0, err
+		}
+		depth = max(depth, d)
+		complexity = addCost(complexity, c)
+	}
+	return depth, complexity, nil
+}
+
+func (a *analyzer) selectionSet(set *ast.SelectionSet) (depth, complexity int, err error) {
+	if set == nil {
+		return 0, 0, nil
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c, err = a.field(s)
+		case *ast.InlineFragment:
+			d, c, err = a.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || a.visiting[name] {
+				// неизвестные и циклические фрагменты отклонит валидация схемы
+				continue
+			}
+			a.visiting[name] = true
+			d, c, err = a.selectionSet(fragment.SelectionSet)
+			a.visiting[name] = false
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		depth = max(depth, d)
+		complexity = addCost(complexity, c)
+	}
+	return depth, complexity, nil
+}
+
+func (a *analyzer) field(f *ast.Field) (depth, complexity int, err error) {
+	childDepth, childComplexity, err := a.selectionSet(f.SelectionSet)
+	if err != nil {
+		return 0, 0, err
+	}
+	if f.SelectionSet == nil {
+		return 1, 1, nil
+	}
+	return childDepth + 1, addCost(1, mulCost(a.multiplier(f), childComplexity)), nil
+}
+
+// multiplier - сколько элементов может вернуть поле, от 1 до maxLimit
+func (a *analyzer) multiplier(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		switch arg.Name.Value {
+		case "limit":
+			if n, ok := a.intValue(arg.Value); ok {
+				return min(max(n, 1), maxLimit)
+			}
+			return defaultListCost
+		case "ids":
+			if n, ok := a.listLen(arg.Value); ok {
+				return min(max(n, 1), maxLimit)
+			}
+			return defaultListCost
+		}
+	}
+	return 1
+}
+
+// addCost и mulCost складывают и умножают неотрицательные стоимости
+// с насыщением на math.MaxInt
+func addCost(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+func mulCost(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+func (a *analyzer) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := a.variables[v.Name.Value].(type) {
+		case float64:
+			// переменные приходят из JSON; перевод огромного float64 в int
+			// не определён, поэтому сначала ограничиваем
+			return int(min(max(n, 0), maxLimit)), true
+		case int:
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+func (a *analyzer) listLen(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.ListValue:
+		return len(v.Values), true
+	case *ast.Variable:
+		if list, ok := a.variables[v.Name.Value].([]any); ok {
+			return len(list), true
+		}
+	}
+	return 0, false
+}
+
+// LimitError - запрос превысил ограничения глубины или сложности
+type LimitError struct {
+	Code   string
+	Limit  int
+	Actual int
+}
+
+func (e *LimitError) Error() string {
+	switch e.Code {
+	case "QUERY_TOO_DEEP":
+		return fmt.Sprintf("глубина запроса %d превышает допустимую %d", e.Actual, e.Limit)
+	default:
+		return fmt.Sprintf("сложность запроса %d превышает допустимую %d", e.Actual, e.Limit)
+	}
+}
+
+func (e *LimitError) Extensions() map[string]any {
+	return map[string]any{
+		"code":   e.Code,
+		"limit":  e.Limit,
+		"actual": e.Actual,
+	}
+}
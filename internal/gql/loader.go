@@ -0,0 +1,100 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"taskTracker/internal/models/task"
+
+	"github.com/google/uuid"
+)
+
+type loadEntry struct {
+	done chan struct{}
+	task *task.Task
+	err  error
+}
+
+// taskLoader собирает обращения к задачам по ID за время разрешения одного
+// уровня запроса и загружает их одним вызовом fetch (одним запросом к хранилищу).
+// Повторные ID загружаются один раз. Живёт в пределах одного запроса
+type taskLoader struct {
+	fetch func(context.Context, []uuid.UUID) ([]*task.Task, []error)
+
+	mtx     sync.Mutex
+	pending []uuid.UUID
+	entries map[uuid.UUID]*loadEntry
+	batches int
+}
+
+func newTaskLoader(fetch func(context.Context, []uuid.UUID) ([]*task.Task, []error)) *taskLoader {
+	return &taskLoader{
+		fetch:   fetch,
+		entries: make(map[uuid.UUID]*loadEntry),
+	}
+}
+
+// Load регистрирует ID и возвращает thunk, который graphql-go вызовет
+// после разрешения соседних полей
+func (l *taskLoader) Load(ctx context.Context, id uuid.UUID) func() (any, error) {
+	l.mtx.Lock()
+	entry, ok := l.entries[id]
+	if !ok {
+		entry = &loadEntry{done: make(chan struct{})}
+		l.entries[id] = entry
+		l.pending = append(l.pending, id)
+	}
+	l.mtx.Unlock()
+
+	return func() (any, error) {
+		l.dispatch(ctx)
+		<-entry.done
+
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return entry.task, nil
+	}
+}
+
+// Batches - число выполненных партий
+func (l *taskLoader) Batches() int {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.batches
+}
+
+// dispatch загружает все накопленные ID
+func (l *taskLoader) dispatch(ctx context.Context) {
+	l.mtx.Lock()
+	batch := l.pending
+	l.pending = nil
+	entries := make([]*loadEntry, len(batch))
+	for i, id := range batch {
+		entries[i] = l.entries[id]
+	}
+	if len(batch) > 0 {
+		l.batches++
+	}
+	l.mtx.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	tasks, errs := l.fetch(ctx, batch)
+	for i, entry := range entries {
+		entry.task, entry.err = tasks[i], errs[i]
+		close(entry.done)
+	}
+}
+
+type loaderKey struct{}
+
+func withLoader(ctx context.Context, l *taskLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+func loaderFrom(ctx context.Context) *taskLoader {
+	l, _ := ctx.Value(loaderKey{}).(*taskLoader)
+	return l
+}
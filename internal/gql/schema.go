@@ -0,0 +1,525 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 1000
+	// окно повторений по умолчанию, как в GET /tasks/{id}/occurrences
+	defaultOccurrencesWindow = 90 * 24 * time.Hour
+)
+
+// Error - ошибка резолвера с кодом в extensions. Для бизнес-ошибок
+// code и details берутся из BusinessError
+type Error struct {
+	Message string
+	Code    string
+	Details map[string]any
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	ext := map[string]any{"code": e.Code}
+	if len(e.Details) > 0 {
+		ext["details"] = e.Details
+	}
+	return ext
+}
+
+func wrapError(err error, operation string) error {
+	var businessErr *service.BusinessError
+	if errors.As(err, &businessErr) {
+		logger.Warn("GraphQL: Бизнес-ошибка",
+			zap.String("operation", operation),
+			zap.String("error_code", businessErr.Code))
+		return &Error{Message: businessErr.Message, Code: businessErr.Code, Details: businessErr.Details}
+	}
+
+	logger.Error("GraphQL: ошибка в Service", err, zap.String("operation", operation))
+	return &Error{Message: "внутренняя ошибка сервера", Code: "INTERNAL_ERROR"}
+}
+
+func validationError(field, reason string) error {
+	return wrapError(service.NewValidationError(field, reason), "validate")
+}
+
+func parseID(value any) (uuid.UUID, error) {
+	raw, _ := value.(string)
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, validationError("id", "неверный формат идентификатора задачи")
+	}
+	return id, nil
+}
+
+func pagination(args map[string]any) (page, limit int, err error) {
+	page, _ = args["page"].(int)
+	limit, _ = args["limit"].(int)
+	if page <= 0 {
+		return 0, 0, validationError("page", "должен быть положительным числом")
+	}
+	if limit <= 0 {
+		return 0, 0, validationError("limit", "должен быть положительным числом")
+	}
+	return page, min(limit, maxLimit), nil
+}
+
+type schemaBuilder struct {
+	service handlers.Service
+}
+
+// NewSchema строит схему GraphQL поверх того же сервиса, что и HTTP API
+func NewSchema(taskService handlers.Service) (graphql.Schema, error) {
+	b := &schemaBuilder{service: taskService}
+
+	taskType := b.taskType()
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    b.queryType(taskType),
+		Mutation: b.mutationType(taskType),
+	})
+}
+
+var statusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "TaskStatus",
+	Values: graphql.EnumValueConfigMap{
+		"NEW":         {Value: task.StatusNew},
+		"IN_PROGRESS": {Value: task.StatusInProgress},
+		"BLOCKED":     {Value: task.StatusBlocked},
+		"IN_REVIEW":   {Value: task.StatusInReview},
+		"DONE":        {Value: task.StatusDone},
+		"CANCELLED":   {Value: task.StatusCancelled},
+		"OVERDUE":     {Value: task.StatusOverdue},
+	},
+})
+
+var flagEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "TaskFlag",
+	Values: graphql.EnumValueConfigMap{
+		"ACTIVE":   {Value: task.FlagActive},
+		"ARCHIVED": {Value: task.FlagArchived},
+		"DELETED":  {Value: task.FlagDeleted},
+	},
+})
+
+var listFilterEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "TaskListFilter",
+	Values: graphql.EnumValueConfigMap{
+		"ACTIVE":   {Value: "active"},
+		"ALL":      {Value: "all"},
+		"ARCHIVED": {Value: "archived"},
+		"OVERDUE":  {Value: "overdue"},
+		"DELETED":  {Value: "deleted"},
+	},
+})
+
+var reminderType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Reminder",
+	Fields: graphql.Fields{
+		"before": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(task.Reminder).Before.String(), nil
+			},
+		},
+		"sentAt": &graphql.Field{
+			Type: graphql.DateTime,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if sentAt := p.Source.(task.Reminder).SentAt; sentAt != nil {
+					return *sentAt, nil
+				}
+				return nil, nil
+			},
+		},
+	},
+})
+
+// taskField - поле задачи со своим резолвером
+func taskField(fieldType graphql.Output, get func(t *task.Task) any) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(*task.Task)), nil
+		},
+	}
+}
+
+func optionalTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+func (b *schemaBuilder) taskType() *graphql.Object {
+	nonNull := graphql.NewNonNull
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Task",
+		Fields: graphql.Fields{
+			"id":          taskField(nonNull(graphql.ID), func(t *task.Task) any { return t.UUID.String() }),
+			"title":       taskField(nonNull(graphql.String), func(t *task.Task) any { return t.Title }),
+			"description": taskField(nonNull(graphql.String), func(t *task.Task) any { return t.Description }),
+			"status":      taskField(nonNull(statusEnum), func(t *task.Task) any { return t.Status }),
+			"flag":        taskField(nonNull(flagEnum), func(t *task.Task) any { return t.Flag }),
+			"dueTime":     taskField(nonNull(graphql.DateTime), func(t *task.Task) any { return t.DueTime }),
+			"createdAt":   taskField(nonNull(graphql.DateTime), func(t *task.Task) any { return t.CreatedAt }),
+			"updatedAt":   taskField(graphql.DateTime, func(t *task.Task) any { return optionalTime(t.UpdatedAt) }),
+			"deletedAt":   taskField(graphql.DateTime, func(t *task.Task) any { return optionalTime(t.DeletedAt) }),
+			"version":     taskField(nonNull(graphql.Int), func(t *task.Task) any { return t.Version }),
+			"rrule":       taskField(graphql.String, func(t *task.Task) any { return t.RRule }),
+			"isOverdue": taskField(nonNull(graphql.Boolean), func(t *task.Task) any {
+				return t.Status == task.StatusOverdue ||
+					(!t.Status.Closed() && t.DueTime.Before(time.Now()))
+			}),
+			"reminders": taskField(nonNull(graphql.NewList(nonNull(reminderType))), func(t *task.Task) any {
+				return []task.Reminder(t.Reminders)
+			}),
+			"occurrences": &graphql.Field{
+				Type:        nonNull(graphql.NewList(nonNull(graphql.DateTime))),
+				Description: "Повторения задачи в окне [from, to], по умолчанию 90 дней",
+				Args: graphql.FieldConfigArgument{
+					"from": {Type: graphql.DateTime},
+					"to":   {Type: graphql.DateTime},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					t := p.Source.(*task.Task)
+					if t.RRule == "" {
+						return []time.Time{}, nil
+					}
+
+					from := time.Now()
+					if v, ok := p.Args["from"].(time.Time); ok {
+						from = v
+					}
+					to := from.Add(defaultOccurrencesWindow)
+					if v, ok := p.Args["to"].(time.Time); ok {
+						to = v
+					}
+					if to.Before(from) {
+						return nil, validationError("to", "не может быть раньше from")
+					}
+
+					occurrences, err := b.service.GetTaskOccurrences(p.Context, t.UUID, from, to)
+					if err != nil {
+						return nil, wrapError(err, "get_task_occurrences")
+					}
+					return occurrences, nil
+				},
+			},
+		},
+	})
+}
+
+// loadTask загружает задачу через загрузчик запроса, если он есть
+func (b *schemaBuilder) loadTask(ctx context.Context, id uuid.UUID) func() (any, error) {
+	if l := loaderFrom(ctx); l != nil {
+		thunk := l.Load(ctx, id)
+		return func() (any, error) {
+			t, err := thunk()
+			if err != nil {
+				return nil, wrapError(err, "get_task")
+			}
+			return t, nil
+		}
+	}
+
+	return func() (any, error) {
+		t, err := b.service.GetTaskByID(ctx, id)
+		if err != nil {
+			return nil, wrapError(err, "get_task")
+		}
+		return t, nil
+	}
+}
+
+func (b *schemaBuilder) queryType(taskType *graphql.Object) *graphql.Object {
+	nonNull := graphql.NewNonNull
+
+	pageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaskPage",
+		Fields: graphql.Fields{
+			"items": &graphql.Field{Type: nonNull(graphql.NewList(nonNull(taskType)))},
+			"page":  &graphql.Field{Type: nonNull(graphql.Int)},
+			"limit": &graphql.Field{Type: nonNull(graphql.Int)},
+		},
+	})
+
+	paginationArgs := func(extra graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args := graphql.FieldConfigArgument{
+			"page":  {Type: graphql.Int, DefaultValue: 1},
+			"limit": {Type: graphql.Int, DefaultValue: defaultLimit},
+		}
+		for name, arg := range extra {
+			args[name] = arg
+		}
+		return args
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"task": &graphql.Field{
+				Type: taskType,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: nonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, err
+					}
+					return b.loadTask(p.Context, id), nil
+				},
+			},
+			"tasks": &graphql.Field{
+				Type:        nonNull(graphql.NewList(taskType)),
+				Description: "Задачи по списку ID, загружаются одной партией",
+				Args: graphql.FieldConfigArgument{
+					"ids": {Type: nonNull(graphql.NewList(nonNull(graphql.ID)))},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					raw, _ := p.Args["ids"].([]any)
+					if len(raw) > maxLimit {
+						return nil, validationError("ids", "слишком много идентификаторов")
+					}
+
+					thunks := make([]func() (any, error), len(raw))
+					for i, value := range raw {
+						id, err := parseID(value)
+						if err != nil {
+							return nil, err
+						}
+						thunks[i] = b.loadTask(p.Context, id)
+					}
+
+					return func() (any, error) {
+						res := make([]any, len(thunks))
+						for i, thunk := range thunks {
+							t, err := thunk()
+							if err != nil {
+								return nil, err
+							}
+							res[i] = t
+						}
+						return res, nil
+					}, nil
+				},
+			},
+			"taskList": &graphql.Field{
+				Type: nonNull(pageType),
+				Args: paginationArgs(graphql.FieldConfigArgument{
+					"filter": {Type: listFilterEnum, DefaultValue: "active"},
+				}),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					page, limit, err := pagination(p.Args)
+					if err != nil {
+						return nil, err
+					}
+
+					var list func(context.Context, int, int) ([]*task.Task, error)
+					switch p.Args["filter"] {
+					case "all":
+						list = b.service.GetAllTasks
+					case "archived":
+						list = b.service.GetArchivedTasks
+					case "overdue":
+						list = b.service.GetOverdueTasks
+					case "deleted":
+						list = b.service.GetDeletedTasks
+					default:
+						list = b.service.GetActiveTasks
+					}
+
+					tasks, err := list(p.Context, page, limit)
+					if err != nil {
+						return nil, wrapError(err, "list_tasks")
+					}
+					return map[string]any{"items": tasks, "page": page, "limit": limit}, nil
+				},
+			},
+			"overdueTasks": &graphql.Field{
+				Type: nonNull(graphql.NewList(nonNull(taskType))),
+				Args: paginationArgs(nil),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					page, limit, err := pagination(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					tasks, err := b.service.GetOverdueTasks(p.Context, page, limit)
+					if err != nil {
+						return nil, wrapError(err, "get_overdue_tasks")
+					}
+					return tasks, nil
+				},
+			},
+		},
+	})
+}
+
+func (b *schemaBuilder) mutationType(taskType *graphql.Object) *graphql.Object {
+	nonNull := graphql.NewNonNull
+
+	createInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateTaskInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       {Type: nonNull(graphql.String)},
+			"description": {Type: graphql.String, DefaultValue: ""},
+			"dueTime":     {Type: nonNull(graphql.DateTime)},
+			"rrule":       {Type: graphql.String},
+			"reminders":   {Type: graphql.NewList(nonNull(graphql.String))},
+		},
+	})
+
+	updateInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateTaskInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       {Type: graphql.String},
+			"description": {Type: graphql.String},
+			"status":      {Type: statusEnum},
+			"dueTime":     {Type: graphql.DateTime},
+			"rrule":       {Type: graphql.String},
+			"reminders":   {Type: graphql.NewList(nonNull(graphql.String))},
+		},
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": {Type: nonNull(graphql.ID)},
+	}
+
+	byID := func(operation string, fn func(context.Context, uuid.UUID) (*task.Task, error)) *graphql.Field {
+		return &graphql.Field{
+			Type: taskType,
+			Args: idArgs,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				id, err := parseID(p.Args["id"])
+				if err != nil {
+					return nil, err
+				}
+				t, err := fn(p.Context, id)
+				if err != nil {
+					return nil, wrapError(err, operation)
+				}
+				return t, nil
+			},
+		}
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTask": &graphql.Field{
+				Type: taskType,
+				Args: graphql.FieldConfigArgument{
+					"input": {Type: nonNull(createInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					input := p.Args["input"].(map[string]any)
+					title, _ := input["title"].(string)
+					description, _ := input["description"].(string)
+					dueTime, _ := input["dueTime"].(time.Time)
+
+					opts, err := taskOptions(input)
+					if err != nil {
+						return nil, err
+					}
+
+					t, err := b.service.CreateTask(p.Context, title, description, dueTime, opts...)
+					if err != nil {
+						return nil, wrapError(err, "create_task")
+					}
+					return t, nil
+				},
+			},
+			"updateTask": &graphql.Field{
+				Type: taskType,
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: nonNull(graphql.ID)},
+					"input": {Type: nonNull(updateInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, err
+					}
+					opts, err := taskOptions(p.Args["input"].(map[string]any))
+					if err != nil {
+						return nil, err
+					}
+
+					t, err := b.service.UpdateTask(p.Context, id, opts...)
+					if err != nil {
+						return nil, wrapError(err, "update_task")
+					}
+					return t, nil
+				},
+			},
+			"archiveTask":   byID("archive_task", b.service.ArchiveTask),
+			"unarchiveTask": byID("unarchive_task", b.service.UnarchiveTask),
+			"restoreTask":   byID("restore_task", b.service.RestoreTask),
+			"deleteTask": &graphql.Field{
+				Type: nonNull(graphql.Boolean),
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, err
+					}
+					if err := b.service.DeleteTask(p.Context, id); err != nil {
+						return nil, wrapError(err, "delete_task")
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+}
+
+// taskOptions переводит поля input в опции задачи. Отсутствующие поля
+// не меняются, как и в PUT /tasks/{id}
+func taskOptions(input map[string]any) ([]task.TaskOption, error) {
+	opts := []task.TaskOption{}
+
+	if v, ok := input["title"].(string); ok {
+		opts = append(opts, task.WithTitle(v))
+	}
+	if v, ok := input["description"].(string); ok {
+		opts = append(opts, task.WithDescription(v))
+	}
+	if v, ok := input["status"].(task.Status); ok {
+		opts = append(opts, task.WithStatus(v))
+	}
+	if v, ok := input["dueTime"].(time.Time); ok {
+		opts = append(opts, task.WithDueTime(v))
+	}
+	if v, ok := input["rrule"].(string); ok {
+		opts = append(opts, task.WithRRule(v))
+	}
+	if raw, ok := input["reminders"].([]any); ok {
+		values := make([]string, len(raw))
+		for i, v := range raw {
+			values[i], _ = v.(string)
+		}
+		reminders, err := task.ParseReminders(values)
+		if err != nil {
+			return nil, validationError("reminders", err.Error())
+		}
+		opts = append(opts, task.WithReminders(reminders))
+	}
+
+	return opts, nil
+}
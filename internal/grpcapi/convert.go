@@ -0,0 +1,76 @@
+package grpcapi
+
+import (
+	"taskTracker/internal/models/task"
+	"time"
+
+	taskv1 "taskTracker/internal/grpcapi/gen/tasktracker/v1"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var statusToProto = map[task.Status]taskv1.TaskStatus{
+	task.StatusNew:        taskv1.TaskStatus_TASK_STATUS_NEW,
+	task.StatusInProgress: taskv1.TaskStatus_TASK_STATUS_IN_PROGRESS,
+	task.StatusDone:       taskv1.TaskStatus_TASK_STATUS_DONE,
+	task.StatusOverdue:    taskv1.TaskStatus_TASK_STATUS_OVERDUE,
+	task.StatusBlocked:    taskv1.TaskStatus_TASK_STATUS_BLOCKED,
+	task.StatusInReview:   taskv1.TaskStatus_TASK_STATUS_IN_REVIEW,
+	task.StatusCancelled:  taskv1.TaskStatus_TASK_STATUS_CANCELLED,
+}
+
+var flagToProto = map[task.Flag]taskv1.TaskFlag{
+	task.FlagActive:   taskv1.TaskFlag_TASK_FLAG_ACTIVE,
+	task.FlagArchived: taskv1.TaskFlag_TASK_FLAG_ARCHIVED,
+	task.FlagDeleted:  taskv1.TaskFlag_TASK_FLAG_DELETED,
+}
+
+func statusFromProto(s taskv1.TaskStatus) (task.Status, bool) {
+	for status, value := range statusToProto {
+		if value == s {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+func toProtoTask(t *task.Task) *taskv1.Task {
+	res := &taskv1.Task{
+		Id:          t.UUID.String(),
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      statusToProto[t.Status],
+		DueTime:     timestamppb.New(t.DueTime),
+		CreatedAt:   timestamppb.New(t.CreatedAt),
+		UpdatedAt:   optionalTimestamp(t.UpdatedAt),
+		Version:     int64(t.Version),
+		Flag:        flagToProto[t.Flag],
+		DeletedAt:   optionalTimestamp(t.DeletedAt),
+		Rrule:       t.RRule,
+	}
+
+	for _, r := range t.Reminders {
+		res.Reminders = append(res.Reminders, &taskv1.Reminder{
+			Before: durationpb.New(r.Before),
+			SentAt: optionalTimestamp(r.SentAt),
+		})
+	}
+	return res
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// remindersFromProto переиспользует разбор и проверки HTTP API
+func remindersFromProto(values []*durationpb.Duration) (task.Reminders, error) {
+	raw := make([]string, len(values))
+	for i, v := range values {
+		raw[i] = v.AsDuration().String()
+	}
+	return task.ParseReminders(raw)
+}
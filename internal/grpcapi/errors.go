@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/service"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain - домен google.rpc.ErrorInfo для бизнес-ошибок
+const ErrorDomain = "tasktracker"
+
+// Code - код gRPC для кода бизнес-ошибки, аналог problem.Status для HTTP.
+// Выводится из HTTP-статуса в problem, поэтому новый код ошибки достаточно
+// зарегистрировать там.
+// VERSION_CONFLICT - 409, как и остальные конфликты, но в gRPC это Aborted:
+// клиенту нужно перечитать задачу и повторить
+func Code(code string) codes.Code {
+	if code == service.CodeVersionConflict {
+		return codes.Aborted
+	}
+
+	switch httpStatus := problem.Status(code); httpStatus {
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		if httpStatus >= http.StatusInternalServerError {
+			return codes.Internal
+		}
+		// 409 и 410: задача не в том состоянии
+		return codes.FailedPrecondition
+	}
+}
+
+// toStatus переводит ошибку сервиса в статус gRPC. Код и details бизнес-ошибки
+// передаются в google.rpc.ErrorInfo
+func toStatus(err error, operation string) error {
+	if err == nil {
+		return nil
+	}
+
+	var businessErr *service.BusinessError
+	if errors.As(err, &businessErr) {
+		logger.Warn("gRPC: Бизнес-ошибка",
+			zap.String("operation", operation),
+			zap.String("error_code", businessErr.Code))
+
+		metadata := make(map[string]string, len(businessErr.Details))
+		for key, value := range businessErr.Details {
+			metadata[key] = fmt.Sprint(value)
+		}
+
+		st := status.New(Code(businessErr.Code), businessErr.Message)
+		detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason:   businessErr.Code,
+			Domain:   ErrorDomain,
+			Metadata: metadata,
+		})
+		if detailsErr != nil {
+			return st.Err()
+		}
+		return detailed.Err()
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	logger.Error("gRPC: ошибка в Service", err, zap.String("operation", operation))
+	return status.Error(codes.Internal, "внутренняя ошибка сервера")
+}
+
+func invalidArgument(field, reason string) error {
+	return toStatus(service.NewValidationError(field, reason), "validate")
+}
@@ -0,0 +1,975 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: tasktracker/v1/task.proto
+
+package taskv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TaskStatus int32
+
+const (
+	TaskStatus_TASK_STATUS_UNSPECIFIED TaskStatus = 0
+	TaskStatus_TASK_STATUS_NEW         TaskStatus = 1
+	TaskStatus_TASK_STATUS_IN_PROGRESS TaskStatus = 2
+	TaskStatus_TASK_STATUS_DONE        TaskStatus = 3
+	TaskStatus_TASK_STATUS_OVERDUE     TaskStatus = 4
+	TaskStatus_TASK_STATUS_BLOCKED     TaskStatus = 5
+	TaskStatus_TASK_STATUS_IN_REVIEW   TaskStatus = 6
+	TaskStatus_TASK_STATUS_CANCELLED   TaskStatus = 7
+)
+
+// Enum value maps for TaskStatus.
+var (
+	TaskStatus_name = map[int32]string{
+		0: "TASK_STATUS_UNSPECIFIED",
+		1: "TASK_STATUS_NEW",
+		2: "TASK_STATUS_IN_PROGRESS",
+		3: "TASK_STATUS_DONE",
+		4: "TASK_STATUS_OVERDUE",
+		5: "TASK_STATUS_BLOCKED",
+		6: "TASK_STATUS_IN_REVIEW",
+		7: "TASK_STATUS_CANCELLED",
+	}
+	TaskStatus_value = map[string]int32{
+		"TASK_STATUS_UNSPECIFIED": 0,
+		"TASK_STATUS_NEW":         1,
+		"TASK_STATUS_IN_PROGRESS": 2,
+		"TASK_STATUS_DONE":        3,
+		"TASK_STATUS_OVERDUE":     4,
+		"TASK_STATUS_BLOCKED":     5,
+		"TASK_STATUS_IN_REVIEW":   6,
+		"TASK_STATUS_CANCELLED":   7,
+	}
+)
+
+func (x TaskStatus) Enum() *TaskStatus {
+	p := new(TaskStatus)
+	*p = x
+	return p
+}
+
+func (x TaskStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_tasktracker_v1_task_proto_enumTypes[0].Descriptor()
+}
+
+func (TaskStatus) Type() protoreflect.EnumType {
+	return &file_tasktracker_v1_task_proto_enumTypes[0]
+}
+
+func (x TaskStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskStatus.Descriptor instead.
+func (TaskStatus) EnumDescriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{0}
+}
+
+type TaskFlag int32
+
+const (
+	TaskFlag_TASK_FLAG_UNSPECIFIED TaskFlag = 0
+	TaskFlag_TASK_FLAG_ACTIVE      TaskFlag = 1
+	TaskFlag_TASK_FLAG_ARCHIVED    TaskFlag = 2
+	TaskFlag_TASK_FLAG_DELETED     TaskFlag = 3
+)
+
+// Enum value maps for TaskFlag.
+var (
+	TaskFlag_name = map[int32]string{
+		0: "TASK_FLAG_UNSPECIFIED",
+		1: "TASK_FLAG_ACTIVE",
+		2: "TASK_FLAG_ARCHIVED",
+		3: "TASK_FLAG_DELETED",
+	}
+	TaskFlag_value = map[string]int32{
+		"TASK_FLAG_UNSPECIFIED": 0,
+		"TASK_FLAG_ACTIVE":      1,
+		"TASK_FLAG_ARCHIVED":    2,
+		"TASK_FLAG_DELETED":     3,
+	}
+)
+
+func (x TaskFlag) Enum() *TaskFlag {
+	p := new(TaskFlag)
+	*p = x
+	return p
+}
+
+func (x TaskFlag) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskFlag) Descriptor() protoreflect.EnumDescriptor {
+	return file_tasktracker_v1_task_proto_enumTypes[1].Descriptor()
+}
+
+func (TaskFlag) Type() protoreflect.EnumType {
+	return &file_tasktracker_v1_task_proto_enumTypes[1]
+}
+
+func (x TaskFlag) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskFlag.Descriptor instead.
+func (TaskFlag) EnumDescriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{1}
+}
+
+type TaskView int32
+
+const (
+	TaskView_TASK_VIEW_UNSPECIFIED TaskView = 0 // то же, что TASK_VIEW_ACTIVE
+	TaskView_TASK_VIEW_ACTIVE      TaskView = 1
+	TaskView_TASK_VIEW_ALL         TaskView = 2
+	TaskView_TASK_VIEW_ARCHIVED    TaskView = 3
+	TaskView_TASK_VIEW_OVERDUE     TaskView = 4
+	TaskView_TASK_VIEW_DELETED     TaskView = 5
+)
+
+// Enum value maps for TaskView.
+var (
+	TaskView_name = map[int32]string{
+		0: "TASK_VIEW_UNSPECIFIED",
+		1: "TASK_VIEW_ACTIVE",
+		2: "TASK_VIEW_ALL",
+		3: "TASK_VIEW_ARCHIVED",
+		4: "TASK_VIEW_OVERDUE",
+		5: "TASK_VIEW_DELETED",
+	}
+	TaskView_value = map[string]int32{
+		"TASK_VIEW_UNSPECIFIED": 0,
+		"TASK_VIEW_ACTIVE":      1,
+		"TASK_VIEW_ALL":         2,
+		"TASK_VIEW_ARCHIVED":    3,
+		"TASK_VIEW_OVERDUE":     4,
+		"TASK_VIEW_DELETED":     5,
+	}
+)
+
+func (x TaskView) Enum() *TaskView {
+	p := new(TaskView)
+	*p = x
+	return p
+}
+
+func (x TaskView) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskView) Descriptor() protoreflect.EnumDescriptor {
+	return file_tasktracker_v1_task_proto_enumTypes[2].Descriptor()
+}
+
+func (TaskView) Type() protoreflect.EnumType {
+	return &file_tasktracker_v1_task_proto_enumTypes[2]
+}
+
+func (x TaskView) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskView.Descriptor instead.
+func (TaskView) EnumDescriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{2}
+}
+
+type Reminder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Before        *durationpb.Duration   `protobuf:"bytes,1,opt,name=before,proto3" json:"before,omitempty"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reminder) Reset() {
+	*x = Reminder{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reminder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reminder) ProtoMessage() {}
+
+func (x *Reminder) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reminder.ProtoReflect.Descriptor instead.
+func (*Reminder) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Reminder) GetBefore() *durationpb.Duration {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *Reminder) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+type Task struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status        TaskStatus             `protobuf:"varint,4,opt,name=status,proto3,enum=tasktracker.v1.TaskStatus" json:"status,omitempty"`
+	DueTime       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=due_time,json=dueTime,proto3" json:"due_time,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version       int64                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	Flag          TaskFlag               `protobuf:"varint,9,opt,name=flag,proto3,enum=tasktracker.v1.TaskFlag" json:"flag,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Rrule         string                 `protobuf:"bytes,11,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	Reminders     []*Reminder            `protobuf:"bytes,12,rep,name=reminders,proto3" json:"reminders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *Task) GetDueTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueTime
+	}
+	return nil
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Task) GetFlag() TaskFlag {
+	if x != nil {
+		return x.Flag
+	}
+	return TaskFlag_TASK_FLAG_UNSPECIFIED
+}
+
+func (x *Task) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *Task) GetReminders() []*Reminder {
+	if x != nil {
+		return x.Reminders
+	}
+	return nil
+}
+
+type CreateTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	DueTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=due_time,json=dueTime,proto3" json:"due_time,omitempty"`
+	Rrule         string                 `protobuf:"bytes,4,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	Reminders     []*durationpb.Duration `protobuf:"bytes,5,rep,name=reminders,proto3" json:"reminders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTaskRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetDueTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueTime
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetReminders() []*durationpb.Duration {
+	if x != nil {
+		return x.Reminders
+	}
+	return nil
+}
+
+type GetTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type TaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskRequest) Reset() {
+	*x = TaskRequest{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRequest) ProtoMessage() {}
+
+func (x *TaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRequest.ProtoReflect.Descriptor instead.
+func (*TaskRequest) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// task.id - идентификатор обновляемой задачи.
+	Task *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// Поддерживаются title, description, status, due_time, rrule, reminders.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateTaskRequest) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type ListTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	View  TaskView               `protobuf:"varint,1,opt,name=view,proto3,enum=tasktracker.v1.TaskView" json:"view,omitempty"`
+	// Размер страницы чтения из хранилища, по умолчанию 100, не больше 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Первая страница, по умолчанию 1.
+	Page int32 `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	// Сколько задач отдать всего, 0 - до конца списка.
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTasksRequest) GetView() TaskView {
+	if x != nil {
+		return x.View
+	}
+	return TaskView_TASK_VIEW_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetTaskOccurrencesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskOccurrencesRequest) Reset() {
+	*x = GetTaskOccurrencesRequest{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskOccurrencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskOccurrencesRequest) ProtoMessage() {}
+
+func (x *GetTaskOccurrencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskOccurrencesRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskOccurrencesRequest) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTaskOccurrencesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetTaskOccurrencesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetTaskOccurrencesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type GetTaskOccurrencesResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	TaskId        string                   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Occurrences   []*timestamppb.Timestamp `protobuf:"bytes,2,rep,name=occurrences,proto3" json:"occurrences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskOccurrencesResponse) Reset() {
+	*x = GetTaskOccurrencesResponse{}
+	mi := &file_tasktracker_v1_task_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskOccurrencesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskOccurrencesResponse) ProtoMessage() {}
+
+func (x *GetTaskOccurrencesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tasktracker_v1_task_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskOccurrencesResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskOccurrencesResponse) Descriptor() ([]byte, []int) {
+	return file_tasktracker_v1_task_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTaskOccurrencesResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *GetTaskOccurrencesResponse) GetOccurrences() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.Occurrences
+	}
+	return nil
+}
+
+var File_tasktracker_v1_task_proto protoreflect.FileDescriptor
+
+const file_tasktracker_v1_task_proto_rawDesc = "" +
+	"\n" +
+	"\x19tasktracker/v1/task.proto\x12\x0etasktracker.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"r\n" +
+	"\bReminder\x121\n" +
+	"\x06before\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x06before\x123\n" +
+	"\asent_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\"\x80\x04\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x122\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1a.tasktracker.v1.TaskStatusR\x06status\x125\n" +
+	"\bdue_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\adueTime\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\b \x01(\x03R\aversion\x12,\n" +
+	"\x04flag\x18\t \x01(\x0e2\x18.tasktracker.v1.TaskFlagR\x04flag\x129\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x14\n" +
+	"\x05rrule\x18\v \x01(\tR\x05rrule\x126\n" +
+	"\treminders\x18\f \x03(\v2\x18.tasktracker.v1.ReminderR\treminders\"\xd1\x01\n" +
+	"\x11CreateTaskRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x125\n" +
+	"\bdue_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\adueTime\x12\x14\n" +
+	"\x05rrule\x18\x04 \x01(\tR\x05rrule\x127\n" +
+	"\treminders\x18\x05 \x03(\v2\x19.google.protobuf.DurationR\treminders\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1d\n" +
+	"\vTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"z\n" +
+	"\x11UpdateTaskRequest\x12(\n" +
+	"\x04task\x18\x01 \x01(\v2\x14.tasktracker.v1.TaskR\x04task\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"\x87\x01\n" +
+	"\x10ListTasksRequest\x12,\n" +
+	"\x04view\x18\x01 \x01(\x0e2\x18.tasktracker.v1.TaskViewR\x04view\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\x87\x01\n" +
+	"\x19GetTaskOccurrencesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"s\n" +
+	"\x1aGetTaskOccurrencesResponse\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12<\n" +
+	"\voccurrences\x18\x02 \x03(\v2\x1a.google.protobuf.TimestampR\voccurrences*\xd9\x01\n" +
+	"\n" +
+	"TaskStatus\x12\x1b\n" +
+	"\x17TASK_STATUS_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fTASK_STATUS_NEW\x10\x01\x12\x1b\n" +
+	"\x17TASK_STATUS_IN_PROGRESS\x10\x02\x12\x14\n" +
+	"\x10TASK_STATUS_DONE\x10\x03\x12\x17\n" +
+	"\x13TASK_STATUS_OVERDUE\x10\x04\x12\x17\n" +
+	"\x13TASK_STATUS_BLOCKED\x10\x05\x12\x19\n" +
+	"\x15TASK_STATUS_IN_REVIEW\x10\x06\x12\x19\n" +
+	"\x15TASK_STATUS_CANCELLED\x10\a*j\n" +
+	"\bTaskFlag\x12\x19\n" +
+	"\x15TASK_FLAG_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10TASK_FLAG_ACTIVE\x10\x01\x12\x16\n" +
+	"\x12TASK_FLAG_ARCHIVED\x10\x02\x12\x15\n" +
+	"\x11TASK_FLAG_DELETED\x10\x03*\x94\x01\n" +
+	"\bTaskView\x12\x19\n" +
+	"\x15TASK_VIEW_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10TASK_VIEW_ACTIVE\x10\x01\x12\x11\n" +
+	"\rTASK_VIEW_ALL\x10\x02\x12\x16\n" +
+	"\x12TASK_VIEW_ARCHIVED\x10\x03\x12\x15\n" +
+	"\x11TASK_VIEW_OVERDUE\x10\x04\x12\x15\n" +
+	"\x11TASK_VIEW_DELETED\x10\x052\xdd\x05\n" +
+	"\vTaskService\x12E\n" +
+	"\n" +
+	"CreateTask\x12!.tasktracker.v1.CreateTaskRequest\x1a\x14.tasktracker.v1.Task\x12?\n" +
+	"\aGetTask\x12\x1e.tasktracker.v1.GetTaskRequest\x1a\x14.tasktracker.v1.Task\x12E\n" +
+	"\n" +
+	"UpdateTask\x12!.tasktracker.v1.UpdateTaskRequest\x1a\x14.tasktracker.v1.Task\x12@\n" +
+	"\vArchiveTask\x12\x1b.tasktracker.v1.TaskRequest\x1a\x14.tasktracker.v1.Task\x12B\n" +
+	"\rUnarchiveTask\x12\x1b.tasktracker.v1.TaskRequest\x1a\x14.tasktracker.v1.Task\x12A\n" +
+	"\n" +
+	"DeleteTask\x12\x1b.tasktracker.v1.TaskRequest\x1a\x16.google.protobuf.Empty\x12@\n" +
+	"\vRestoreTask\x12\x1b.tasktracker.v1.TaskRequest\x1a\x14.tasktracker.v1.Task\x12@\n" +
+	"\tPurgeTask\x12\x1b.tasktracker.v1.TaskRequest\x1a\x16.google.protobuf.Empty\x12E\n" +
+	"\tListTasks\x12 .tasktracker.v1.ListTasksRequest\x1a\x14.tasktracker.v1.Task0\x01\x12k\n" +
+	"\x12GetTaskOccurrences\x12).tasktracker.v1.GetTaskOccurrencesRequest\x1a*.tasktracker.v1.GetTaskOccurrencesResponseB8Z6taskTracker/internal/grpcapi/gen/tasktracker/v1;taskv1b\x06proto3"
+
+var (
+	file_tasktracker_v1_task_proto_rawDescOnce sync.Once
+	file_tasktracker_v1_task_proto_rawDescData []byte
+)
+
+func file_tasktracker_v1_task_proto_rawDescGZIP() []byte {
+	file_tasktracker_v1_task_proto_rawDescOnce.Do(func() {
+		file_tasktracker_v1_task_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tasktracker_v1_task_proto_rawDesc), len(file_tasktracker_v1_task_proto_rawDesc)))
+	})
+	return file_tasktracker_v1_task_proto_rawDescData
+}
+
+var file_tasktracker_v1_task_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_tasktracker_v1_task_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_tasktracker_v1_task_proto_goTypes = []any{
+	(TaskStatus)(0),                    // 0: tasktracker.v1.TaskStatus
+	(TaskFlag)(0),                      // 1: tasktracker.v1.TaskFlag
+	(TaskView)(0),                      // 2: tasktracker.v1.TaskView
+	(*Reminder)(nil),                   // 3: tasktracker.v1.Reminder
+	(*Task)(nil),                       // 4: tasktracker.v1.Task
+	(*CreateTaskRequest)(nil),          // 5: tasktracker.v1.CreateTaskRequest
+	(*GetTaskRequest)(nil),             // 6: tasktracker.v1.GetTaskRequest
+	(*TaskRequest)(nil),                // 7: tasktracker.v1.TaskRequest
+	(*UpdateTaskRequest)(nil),          // 8: tasktracker.v1.UpdateTaskRequest
+	(*ListTasksRequest)(nil),           // 9: tasktracker.v1.ListTasksRequest
+	(*GetTaskOccurrencesRequest)(nil),  // 10: tasktracker.v1.GetTaskOccurrencesRequest
+	(*GetTaskOccurrencesResponse)(nil), // 11: tasktracker.v1.GetTaskOccurrencesResponse
+	(*durationpb.Duration)(nil),        // 12: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),      // 13: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),      // 14: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),              // 15: google.protobuf.Empty
+}
+var file_tasktracker_v1_task_proto_depIdxs = []int32{
+	12, // 0: tasktracker.v1.Reminder.before:type_name -> google.protobuf.Duration
+	13, // 1: tasktracker.v1.Reminder.sent_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: tasktracker.v1.Task.status:type_name -> tasktracker.v1.TaskStatus
+	13, // 3: tasktracker.v1.Task.due_time:type_name -> google.protobuf.Timestamp
+	13, // 4: tasktracker.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	13, // 5: tasktracker.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 6: tasktracker.v1.Task.flag:type_name -> tasktracker.v1.TaskFlag
+	13, // 7: tasktracker.v1.Task.deleted_at:type_name -> google.protobuf.Timestamp
+	3,  // 8: tasktracker.v1.Task.reminders:type_name -> tasktracker.v1.Reminder
+	13, // 9: tasktracker.v1.CreateTaskRequest.due_time:type_name -> google.protobuf.Timestamp
+	12, // 10: tasktracker.v1.CreateTaskRequest.reminders:type_name -> google.protobuf.Duration
+	4,  // 11: tasktracker.v1.UpdateTaskRequest.task:type_name -> tasktracker.v1.Task
+	14, // 12: tasktracker.v1.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
+	2,  // 13: tasktracker.v1.ListTasksRequest.view:type_name -> tasktracker.v1.TaskView
+	13, // 14: tasktracker.v1.GetTaskOccurrencesRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 15: tasktracker.v1.GetTaskOccurrencesRequest.to:type_name -> google.protobuf.Timestamp
+	13, // 16: tasktracker.v1.GetTaskOccurrencesResponse.occurrences:type_name -> google.protobuf.Timestamp
+	5,  // 17: tasktracker.v1.TaskService.CreateTask:input_type -> tasktracker.v1.CreateTaskRequest
+	6,  // 18: tasktracker.v1.TaskService.GetTask:input_type -> tasktracker.v1.GetTaskRequest
+	8,  // 19: tasktracker.v1.TaskService.UpdateTask:input_type -> tasktracker.v1.UpdateTaskRequest
+	7,  // 20: tasktracker.v1.TaskService.ArchiveTask:input_type -> tasktracker.v1.TaskRequest
+	7,  // 21: tasktracker.v1.TaskService.UnarchiveTask:input_type -> tasktracker.v1.TaskRequest
+	7,  // 22: tasktracker.v1.TaskService.DeleteTask:input_type -> tasktracker.v1.TaskRequest
+	7,  // 23: tasktracker.v1.TaskService.RestoreTask:input_type -> tasktracker.v1.TaskRequest
+	7,  // 24: tasktracker.v1.TaskService.PurgeTask:input_type -> tasktracker.v1.TaskRequest
+	9,  // 25: tasktracker.v1.TaskService.ListTasks:input_type -> tasktracker.v1.ListTasksRequest
+	10, // 26: tasktracker.v1.TaskService.GetTaskOccurrences:input_type -> tasktracker.v1.GetTaskOccurrencesRequest
+	4,  // 27: tasktracker.v1.TaskService.CreateTask:output_type -> tasktracker.v1.Task
+	4,  // 28: tasktracker.v1.TaskService.GetTask:output_type -> tasktracker.v1.Task
+	4,  // 29: tasktracker.v1.TaskService.UpdateTask:output_type -> tasktracker.v1.Task
+	4,  // 30: tasktracker.v1.TaskService.ArchiveTask:output_type -> tasktracker.v1.Task
+	4,  // 31: tasktracker.v1.TaskService.UnarchiveTask:output_type -> tasktracker.v1.Task
+	15, // 32: tasktracker.v1.TaskService.DeleteTask:output_type -> google.protobuf.Empty
+	4,  // 33: tasktracker.v1.TaskService.RestoreTask:output_type -> tasktracker.v1.Task
+	15, // 34: tasktracker.v1.TaskService.PurgeTask:output_type -> google.protobuf.Empty
+	4,  // 35: tasktracker.v1.TaskService.ListTasks:output_type -> tasktracker.v1.Task
+	11, // 36: tasktracker.v1.TaskService.GetTaskOccurrences:output_type -> tasktracker.v1.GetTaskOccurrencesResponse
+	27, // [27:37] is the sub-list for method output_type
+	17, // [17:27] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_tasktracker_v1_task_proto_init() }
+func file_tasktracker_v1_task_proto_init() {
+	if File_tasktracker_v1_task_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tasktracker_v1_task_proto_rawDesc), len(file_tasktracker_v1_task_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tasktracker_v1_task_proto_goTypes,
+		DependencyIndexes: file_tasktracker_v1_task_proto_depIdxs,
+		EnumInfos:         file_tasktracker_v1_task_proto_enumTypes,
+		MessageInfos:      file_tasktracker_v1_task_proto_msgTypes,
+	}.Build()
+	File_tasktracker_v1_task_proto = out.File
+	file_tasktracker_v1_task_proto_goTypes = nil
+	file_tasktracker_v1_task_proto_depIdxs = nil
+}
@@ -0,0 +1,99 @@
+package grpcapi
+
+import (
+	"context"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/logger"
+	"time"
+
+	taskv1 "taskTracker/internal/grpcapi/gen/tasktracker/v1"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewGRPCServer собирает gRPC-сервер с TaskService, стандартным health
+// и reflection для grpcurl
+func NewGRPCServer(taskService handlers.Service) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(recoveryUnary, loggingUnary),
+		grpc.ChainStreamInterceptor(recoveryStream, loggingStream),
+	)
+
+	taskv1.RegisterTaskServiceServer(server, NewServer(taskService))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(taskv1.TaskService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
+
+func logCall(method string, start time.Time, err error) {
+	code := status.Code(err)
+
+	logLevel := zap.InfoLevel
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		logLevel = zap.ErrorLevel
+	default:
+		logLevel = zap.WarnLevel
+	}
+
+	logger.Log(
+		logLevel,
+		"GRPC_OUT: Завершение вызова",
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("ms", time.Since(start)),
+	)
+}
+
+func loggingUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	logger.Info("GRPC_IN: Начало вызова", zap.String("method", info.FullMethod))
+
+	resp, err := handler(ctx, req)
+	logCall(info.FullMethod, start, err)
+	return resp, err
+}
+
+func loggingStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	logger.Info("GRPC_IN: Начало потока", zap.String("method", info.FullMethod))
+
+	err := handler(srv, ss)
+	logCall(info.FullMethod, start, err)
+	return err
+}
+
+func recoveryUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("gRPC: паника в обработчике", nil,
+				zap.String("method", info.FullMethod),
+				zap.Any("panic", r))
+			err = status.Error(codes.Internal, "внутренняя ошибка сервера")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoveryStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("gRPC: паника в обработчике", nil,
+				zap.String("method", info.FullMethod),
+				zap.Any("panic", r))
+			err = status.Error(codes.Internal, "внутренняя ошибка сервера")
+		}
+	}()
+	return handler(srv, ss)
+}
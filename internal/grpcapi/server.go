@@ -0,0 +1,277 @@
+package grpcapi
+
+//go:generate protoc -I ../../api/proto --go_out=gen --go_opt=paths=source_relative --go-grpc_out=gen --go-grpc_opt=paths=source_relative tasktracker/v1/task.proto
+
+import (
+	"context"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/models/task"
+	"time"
+
+	taskv1 "taskTracker/internal/grpcapi/gen/tasktracker/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+	// окно повторений по умолчанию, как в GET /tasks/{id}/occurrences
+	defaultOccurrencesWindow = 90 * 24 * time.Hour
+)
+
+// Server реализует taskv1.TaskServiceServer поверх того же сервиса, что и HTTP API
+type Server struct {
+	taskv1.UnimplementedTaskServiceServer
+
+	TaskService handlers.Service
+}
+
+func NewServer(taskService handlers.Service) *Server {
+	return &Server{TaskService: taskService}
+}
+
+func parseID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, invalidArgument("id", "неверный формат идентификатора задачи")
+	}
+	return parsed, nil
+}
+
+func (s *Server) CreateTask(ctx context.Context, req *taskv1.CreateTaskRequest) (*taskv1.Task, error) {
+	if req.GetDueTime() == nil {
+		return nil, invalidArgument("due_time", "обязательное поле")
+	}
+
+	opts := []task.TaskOption{}
+	if req.GetRrule() != "" {
+		opts = append(opts, task.WithRRule(req.GetRrule()))
+	}
+	if len(req.GetReminders()) > 0 {
+		reminders, err := remindersFromProto(req.GetReminders())
+		if err != nil {
+			return nil, invalidArgument("reminders", err.Error())
+		}
+		opts = append(opts, task.WithReminders(reminders))
+	}
+
+	created, err := s.TaskService.CreateTask(ctx, req.GetTitle(), req.GetDescription(), req.GetDueTime().AsTime(), opts...)
+	if err != nil {
+		return nil, toStatus(err, "create_task")
+	}
+	return toProtoTask(created), nil
+}
+
+func (s *Server) GetTask(ctx context.Context, req *taskv1.GetTaskRequest) (*taskv1.Task, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.TaskService.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(err, "get_task")
+	}
+	return toProtoTask(found), nil
+}
+
+func (s *Server) UpdateTask(ctx context.Context, req *taskv1.UpdateTaskRequest) (*taskv1.Task, error) {
+	patch := req.GetTask()
+	if patch == nil {
+		return nil, invalidArgument("task", "обязательное поле")
+	}
+	id, err := parseID(patch.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return nil, invalidArgument("update_mask", "не указаны обновляемые поля")
+	}
+
+	opts := []task.TaskOption{}
+	for _, path := range paths {
+		switch path {
+		case "title":
+			opts = append(opts, task.WithTitle(patch.GetTitle()))
+		case "description":
+			opts = append(opts, task.WithDescription(patch.GetDescription()))
+		case "status":
+			status, ok := statusFromProto(patch.GetStatus())
+			if !ok {
+				return nil, invalidArgument("status", "неизвестный статус")
+			}
+			opts = append(opts, task.WithStatus(status))
+		case "due_time":
+			if patch.GetDueTime() == nil {
+				return nil, invalidArgument("due_time", "обязательное поле")
+			}
+			opts = append(opts, task.WithDueTime(patch.GetDueTime().AsTime()))
+		case "rrule":
+			opts = append(opts, task.WithRRule(patch.GetRrule()))
+		case "reminders":
+			befores := make([]*durationpb.Duration, len(patch.GetReminders()))
+			for i, r := range patch.GetReminders() {
+				befores[i] = r.GetBefore()
+			}
+			reminders, err := remindersFromProto(befores)
+			if err != nil {
+				return nil, invalidArgument("reminders", err.Error())
+			}
+			opts = append(opts, task.WithReminders(reminders))
+		default:
+			return nil, invalidArgument("update_mask", "поле "+path+" нельзя обновить")
+		}
+	}
+
+	updated, err := s.TaskService.UpdateTask(ctx, id, opts...)
+	if err != nil {
+		return nil, toStatus(err, "update_task")
+	}
+	return toProtoTask(updated), nil
+}
+
+func (s *Server) ArchiveTask(ctx context.Context, req *taskv1.TaskRequest) (*taskv1.Task, error) {
+	return s.mutate(ctx, req, "archive_task", s.TaskService.ArchiveTask)
+}
+
+func (s *Server) UnarchiveTask(ctx context.Context, req *taskv1.TaskRequest) (*taskv1.Task, error) {
+	return s.mutate(ctx, req, "unarchive_task", s.TaskService.UnarchiveTask)
+}
+
+func (s *Server) RestoreTask(ctx context.Context, req *taskv1.TaskRequest) (*taskv1.Task, error) {
+	return s.mutate(ctx, req, "restore_task", s.TaskService.RestoreTask)
+}
+
+func (s *Server) DeleteTask(ctx context.Context, req *taskv1.TaskRequest) (*emptypb.Empty, error) {
+	return s.remove(ctx, req, "delete_task", s.TaskService.DeleteTask)
+}
+
+func (s *Server) PurgeTask(ctx context.Context, req *taskv1.TaskRequest) (*emptypb.Empty, error) {
+	return s.remove(ctx, req, "purge_task", s.TaskService.PurgeTask)
+}
+
+func (s *Server) mutate(ctx context.Context, req *taskv1.TaskRequest, operation string,
+	fn func(context.Context, uuid.UUID) (*task.Task, error)) (*taskv1.Task, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := fn(ctx, id)
+	if err != nil {
+		return nil, toStatus(err, operation)
+	}
+	return toProtoTask(res), nil
+}
+
+func (s *Server) remove(ctx context.Context, req *taskv1.TaskRequest, operation string,
+	fn func(context.Context, uuid.UUID) error) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(ctx, id); err != nil {
+		return nil, toStatus(err, operation)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListTasks(req *taskv1.ListTasksRequest, stream grpc.ServerStreamingServer[taskv1.Task]) error {
+	var list func(context.Context, int, int) ([]*task.Task, error)
+	switch req.GetView() {
+	case taskv1.TaskView_TASK_VIEW_UNSPECIFIED, taskv1.TaskView_TASK_VIEW_ACTIVE:
+		list = s.TaskService.GetActiveTasks
+	case taskv1.TaskView_TASK_VIEW_ALL:
+		list = s.TaskService.GetAllTasks
+	case taskv1.TaskView_TASK_VIEW_ARCHIVED:
+		list = s.TaskService.GetArchivedTasks
+	case taskv1.TaskView_TASK_VIEW_OVERDUE:
+		list = s.TaskService.GetOverdueTasks
+	case taskv1.TaskView_TASK_VIEW_DELETED:
+		list = s.TaskService.GetDeletedTasks
+	default:
+		return invalidArgument("view", "неизвестное представление")
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize < 0 {
+		return invalidArgument("page_size", "должен быть неотрицательным")
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	page := int(req.GetPage())
+	if page < 0 {
+		return invalidArgument("page", "должен быть неотрицательным")
+	}
+	page = max(page, 1)
+
+	limit := int(req.GetLimit())
+	if limit < 0 {
+		return invalidArgument("limit", "должен быть неотрицательным")
+	}
+
+	ctx := stream.Context()
+	sent := 0
+	for {
+		tasks, err := list(ctx, page, pageSize)
+		if err != nil {
+			return toStatus(err, "list_tasks")
+		}
+
+		for _, t := range tasks {
+			if limit > 0 && sent >= limit {
+				return nil
+			}
+			if err := stream.Send(toProtoTask(t)); err != nil {
+				return err
+			}
+			sent++
+		}
+
+		if len(tasks) < pageSize {
+			return nil
+		}
+		page++
+	}
+}
+
+func (s *Server) GetTaskOccurrences(ctx context.Context, req *taskv1.GetTaskOccurrencesRequest) (*taskv1.GetTaskOccurrencesResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now()
+	if req.GetFrom() != nil {
+		from = req.GetFrom().AsTime()
+	}
+	to := from.Add(defaultOccurrencesWindow)
+	if req.GetTo() != nil {
+		to = req.GetTo().AsTime()
+	}
+	if to.Before(from) {
+		return nil, invalidArgument("to", "не может быть раньше from")
+	}
+
+	occurrences, err := s.TaskService.GetTaskOccurrences(ctx, id, from, to)
+	if err != nil {
+		return nil, toStatus(err, "get_task_occurrences")
+	}
+
+	res := &taskv1.GetTaskOccurrencesResponse{TaskId: id.String()}
+	for _, o := range occurrences {
+		res.Occurrences = append(res.Occurrences, timestamppb.New(o))
+	}
+	return res, nil
+}
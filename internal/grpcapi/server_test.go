@@ -0,0 +1,241 @@
+package grpcapi_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"taskTracker/internal/grpcapi"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"testing"
+	"time"
+
+	taskv1 "taskTracker/internal/grpcapi/gen/tasktracker/v1"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func newClient(t *testing.T) (taskv1.TaskServiceClient, *grpc.ClientConn) {
+	t.Helper()
+
+	svc := service.NewTaskService(inmemory.NewTaskStorage(), "inmemory")
+	server := grpcapi.NewGRPCServer(&svc)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return taskv1.NewTaskServiceClient(conn), conn
+}
+
+func createTask(t *testing.T, client taskv1.TaskServiceClient, title string) *taskv1.Task {
+	t.Helper()
+
+	created, err := client.CreateTask(context.Background(), &taskv1.CreateTaskRequest{
+		Title:   title,
+		DueTime: timestamppb.New(time.Now().Add(48 * time.Hour)),
+	})
+	require.NoError(t, err)
+	return created
+}
+
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("нет ErrorInfo в деталях: %v", err)
+	return nil
+}
+
+// TestServer_CreateAndGet тестирует создание и получение задачи
+func TestServer_CreateAndGet(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateTask(ctx, &taskv1.CreateTaskRequest{
+		Title:     "Задача",
+		DueTime:   timestamppb.New(time.Now().Add(48 * time.Hour)),
+		Reminders: []*durationpb.Duration{durationpb.New(time.Hour)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, taskv1.TaskFlag_TASK_FLAG_ACTIVE, created.GetFlag())
+	require.Len(t, created.GetReminders(), 1)
+	assert.Equal(t, time.Hour, created.GetReminders()[0].GetBefore().AsDuration())
+
+	found, err := client.GetTask(ctx, &taskv1.GetTaskRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, "Задача", found.GetTitle())
+
+	_, err = client.CreateTask(ctx, &taskv1.CreateTaskRequest{Title: "Без срока"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestServer_UpdateTask тестирует обновление по маске полей
+func TestServer_UpdateTask(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+	created := createTask(t, client, "Исходное")
+
+	updated, err := client.UpdateTask(ctx, &taskv1.UpdateTaskRequest{
+		Task: &taskv1.Task{
+			Id:          created.GetId(),
+			Title:       "Новое",
+			Description: "не попадёт в маску",
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Новое", updated.GetTitle())
+	assert.Empty(t, updated.GetDescription())
+
+	_, err = client.UpdateTask(ctx, &taskv1.UpdateTaskRequest{
+		Task:       &taskv1.Task{Id: created.GetId()},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"flag"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.UpdateTask(ctx, &taskv1.UpdateTaskRequest{Task: &taskv1.Task{Id: created.GetId()}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestServer_BusinessErrors тестирует перевод BusinessError в статусы gRPC
+func TestServer_BusinessErrors(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+	created := createTask(t, client, "Задача")
+
+	t.Run("not found", func(t *testing.T) {
+		missing := uuid.New().String()
+		_, err := client.GetTask(ctx, &taskv1.GetTaskRequest{Id: missing})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		info := errorInfo(t, err)
+		assert.Equal(t, "NOT_FOUND", info.GetReason())
+		assert.Equal(t, grpcapi.ErrorDomain, info.GetDomain())
+		assert.Equal(t, missing, info.GetMetadata()["id"])
+	})
+
+	t.Run("already archived", func(t *testing.T) {
+		_, err := client.ArchiveTask(ctx, &taskv1.TaskRequest{Id: created.GetId()})
+		require.NoError(t, err)
+
+		_, err = client.ArchiveTask(ctx, &taskv1.TaskRequest{Id: created.GetId()})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Equal(t, "ALREADY_ARCHIVED", errorInfo(t, err).GetReason())
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := client.DeleteTask(ctx, &taskv1.TaskRequest{Id: "not-a-uuid"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, "VALIDATION_ERROR", errorInfo(t, err).GetReason())
+	})
+}
+
+// TestCode тестирует вывод кода gRPC из HTTP-статуса кода ошибки
+func TestCode(t *testing.T) {
+	cases := map[string]codes.Code{
+		service.CodeNotFound:         codes.NotFound,
+		service.CodeValidation:       codes.InvalidArgument,
+		service.CodeVersionConflict:  codes.Aborted,
+		service.CodeAlreadyArchived:  codes.FailedPrecondition,
+		service.CodeWIPLimitExceeded: codes.FailedPrecondition,
+		service.CodeTaskDeleted:      codes.FailedPrecondition,
+		problem.CodeUnauthorized:     codes.Unauthenticated,
+		problem.CodeRateLimited:      codes.ResourceExhausted,
+		problem.CodeTimeout:          codes.DeadlineExceeded,
+		problem.CodeUnavailable:      codes.Unavailable,
+		problem.CodeInternal:         codes.Internal,
+		"UNREGISTERED":               codes.InvalidArgument,
+	}
+	for code, want := range cases {
+		assert.Equal(t, want, grpcapi.Code(code), code)
+	}
+}
+
+// TestServer_ListTasks тестирует потоковую выдачу списка по страницам
+func TestServer_ListTasks(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		createTask(t, client, "Задача")
+	}
+
+	collect := func(req *taskv1.ListTasksRequest) ([]*taskv1.Task, error) {
+		stream, err := client.ListTasks(ctx, req)
+		require.NoError(t, err)
+
+		var res []*taskv1.Task
+		for {
+			task, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			if err != nil {
+				return res, err
+			}
+			res = append(res, task)
+		}
+	}
+
+	all, err := collect(&taskv1.ListTasksRequest{PageSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	limited, err := collect(&taskv1.ListTasksRequest{PageSize: 2, Limit: 3})
+	require.NoError(t, err)
+	assert.Len(t, limited, 3)
+
+	archived, err := collect(&taskv1.ListTasksRequest{View: taskv1.TaskView_TASK_VIEW_ARCHIVED})
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+
+	_, err = collect(&taskv1.ListTasksRequest{PageSize: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestServer_Health тестирует стандартный health-сервис
+func TestServer_Health(t *testing.T) {
+	_, conn := newClient(t)
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: taskv1.TaskService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GET /boards/{id}
+func (s *TaskHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	b, columns, err := s.TaskService.GetBoard(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "get_board")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromBoard(b, columns))
+}
+
+// POST /tasks/{id}/move
+func (s *TaskHandler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	if !checkContentType(r, "application/json") {
+		writeError(w, r, errUnsupportedMediaType(), "move_task")
+		return
+	}
+
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var request dto.MoveTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errBadBody(err), "move_task")
+		return
+	}
+	if request.Column == "" {
+		writeError(w, r, service.NewValidationError("column", "обязательное поле"), "move_task")
+		return
+	}
+
+	logger.Info("HTTP: Перемещение задачи на доске",
+		zap.String("task_id", id.String()),
+		zap.String("board", request.Board),
+		zap.String("column", request.Column))
+
+	moved, err := s.TaskService.MoveTask(r.Context(), id, service.MoveRequest{
+		Board:  request.Board,
+		Column: request.Column,
+		Before: request.Before,
+		After:  request.After,
+	})
+	if err != nil {
+		writeError(w, r, err, "move_task")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromTask(moved))
+}
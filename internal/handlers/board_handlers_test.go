@@ -0,0 +1,60 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"taskTracker/internal/board"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"taskTracker/internal/workflow"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaskHandler_UpdateTaskWIPLimit тестирует, что PUT со сменой статуса не обходит WIP-лимит колонки
+func TestTaskHandler_UpdateTaskWIPLimit(t *testing.T) {
+	ctx := context.Background()
+	boards, err := board.New(board.Config{Boards: []board.Board{{
+		ID: "dev",
+		Columns: []board.Column{
+			{ID: "todo", Statuses: []task.Status{task.StatusNew}},
+			{ID: "doing", Statuses: []task.Status{task.StatusInProgress}, WIPLimit: 1},
+		},
+	}}}, workflow.Default())
+	require.NoError(t, err)
+
+	svc := service.NewTaskService(inmemory.NewTaskStorage(), service.InMemoryType, service.WithBoards(boards))
+	handler := handlers.NewTaskHandler(&svc)
+	router := chi.NewRouter()
+	router.Put("/tasks/{id}", handler.UpdateTaskByID)
+
+	busy, err := svc.CreateTask(ctx, "busy", "", time.Now().Add(72*time.Hour))
+	require.NoError(t, err)
+	_, err = svc.UpdateTask(ctx, busy.UUID, task.WithStatus(task.StatusInProgress))
+	require.NoError(t, err)
+
+	waiting, err := svc.CreateTask(ctx, "waiting", "", time.Now().Add(72*time.Hour))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, "/tasks/"+waiting.UUID.String(),
+		bytes.NewBufferString(`{"status": "in progress"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "WIP_LIMIT_EXCEEDED")
+
+	stored, err := svc.GetTaskByID(ctx, waiting.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusNew, stored.Status)
+}
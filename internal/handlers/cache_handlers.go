@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"taskTracker/internal/repository/task/cache"
+)
+
+type CacheStats interface {
+	Stats() cache.Stats
+}
+
+type CacheHandler struct {
+	Cache CacheStats
+}
+
+func NewCacheHandler(cache CacheStats) CacheHandler {
+	return CacheHandler{
+		Cache: cache,
+	}
+}
+
+// GET /admin/cache/stats
+func (h *CacheHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats := h.Cache.Stats()
+
+	responseWithJSON(w, http.StatusOK,
+		toPayload("hits", stats.Hits),
+		toPayload("misses", stats.Misses),
+		toPayload("invalidations", stats.Invalidations),
+		toPayload("errors", stats.Errors),
+		toPayload("hit_ratio", stats.HitRatio),
+	)
+}
@@ -1,7 +1,14 @@
 package dto
 
 import (
+	"math"
+	"taskTracker/internal/board"
+	"taskTracker/internal/history"
 	"taskTracker/internal/models/task"
+	"taskTracker/internal/stats"
+	"taskTracker/internal/webhook"
+	"taskTracker/internal/workflow"
+	"taskTracker/internal/worklog"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,8 +16,13 @@ import (
 
 type CreateTaskRequest struct {
 	Title       string    `json:"title"`
-	Description string    `json:"description"`
+	Description string    `json:"description,omitempty"`
 	DueTime     time.Time `json:"due_time"`
+	RRule       string    `json:"rrule,omitempty"`
+	Reminders   []string  `json:"reminders,omitempty"`
+	// оценки в единицах трекера (ESTIMATE_UNIT)
+	OriginalEstimate  *float64 `json:"original_estimate,omitempty"`
+	RemainingEstimate *float64 `json:"remaining_estimate,omitempty"`
 }
 
 type UpdateTaskRequest struct {
@@ -18,6 +30,10 @@ type UpdateTaskRequest struct {
 	Description *string      `json:"description,omitempty"`
 	Status      *task.Status `json:"status,omitempty"`
 	DueTime     *time.Time   `json:"due_time,omitempty"`
+	RRule       *string      `json:"rrule,omitempty"`
+	Reminders   *[]string    `json:"reminders,omitempty"`
+	OriginalEstimate  *float64 `json:"original_estimate,omitempty"`
+	RemainingEstimate *float64 `json:"remaining_estimate,omitempty"`
 }
 
 type TaskResponse struct {
@@ -29,6 +45,21 @@ type TaskResponse struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 	IsOverdue   bool       `json:"is_overdue"` 
+	RRule       string     `json:"rrule,omitempty"`
+	Reminders   task.Reminders `json:"reminders,omitempty"`
+	Rank        string     `json:"rank,omitempty"`
+	TimeSpent   int64      `json:"time_spent_seconds"`
+	OriginalEstimate  *float64 `json:"original_estimate,omitempty"`
+	RemainingEstimate *float64 `json:"remaining_estimate,omitempty"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+}
+
+type OccurrencesResponse struct {
+	TaskID      uuid.UUID   `json:"task_id"`
+	From        time.Time   `json:"from"`
+	To          time.Time   `json:"to"`
+	Occurrences []time.Time `json:"occurrences"`
 }
 
 func FromTask(t *task.Task) TaskResponse {
@@ -41,10 +72,327 @@ func FromTask(t *task.Task) TaskResponse {
 		CreatedAt:   t.CreatedAt,
 		UpdatedAt:   t.UpdatedAt,
 		IsOverdue: t.Status == task.StatusOverdue ||
-			(t.Status != task.StatusDone && t.DueTime.Before(time.Now())),
+			(!t.Status.Closed() && t.DueTime.Before(time.Now())),
+		RRule:     t.RRule,
+		Reminders: t.Reminders,
+		Rank:      t.Rank,
+		TimeSpent: int64(t.TimeSpent / time.Second),
+		OriginalEstimate:  t.OriginalEstimate,
+		RemainingEstimate: t.RemainingEstimate,
+		StartedAt:         t.StartedAt,
+		CompletedAt:       t.CompletedAt,
 	}
 }
 
+// TransitionsResponse - переходы из текущего статуса задачи
+type TransitionsResponse struct {
+	TaskID      uuid.UUID    `json:"task_id"`
+	Status      task.Status  `json:"status"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// Transition - переход workflow. Allowed false - условие перехода не
+// выполнено, причина в reason
+type Transition struct {
+	Name    string      `json:"name"`
+	To      task.Status `json:"to"`
+	Allowed bool        `json:"allowed"`
+	Reason  string      `json:"reason,omitempty"`
+}
+
+func FromTransitions(t *task.Task, options []workflow.Option) TransitionsResponse {
+	res := TransitionsResponse{TaskID: t.UUID, Status: t.Status, Transitions: make([]Transition, len(options))}
+	for i, opt := range options {
+		res.Transitions[i] = Transition{Name: opt.Name, To: opt.To, Allowed: opt.Allowed, Reason: opt.Reason}
+	}
+	return res
+}
+
+// BoardResponse - доска с активными задачами по колонкам
+type BoardResponse struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Columns []ColumnResponse `json:"columns"`
+}
+
+// ColumnResponse - колонка доски, задачи в порядке рангов. WIPLimit 0 - без ограничения
+type ColumnResponse struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Statuses []task.Status  `json:"statuses"`
+	WIPLimit int            `json:"wip_limit"`
+	Tasks    []TaskResponse `json:"tasks"`
+}
+
+func FromBoard(b board.Board, columns []board.ColumnTasks) BoardResponse {
+	res := BoardResponse{ID: b.ID, Name: b.Name, Columns: make([]ColumnResponse, len(columns))}
+	for i, col := range columns {
+		res.Columns[i] = ColumnResponse{
+			ID:       col.ID,
+			Name:     col.Name,
+			Statuses: col.Statuses,
+			WIPLimit: col.WIPLimit,
+			Tasks:    FromTaskList(col.Tasks),
+		}
+	}
+	return res
+}
+
+// MoveTaskRequest - перемещение задачи на доске. After - задача, которая
+// окажется прямо над перемещаемой, Before - прямо под ней. Без board
+// используется первая доска конфига, без соседей задача встаёт в конец колонки
+type MoveTaskRequest struct {
+	Board  string     `json:"board,omitempty"`
+	Column string     `json:"column"`
+	Before *uuid.UUID `json:"before,omitempty"`
+	After  *uuid.UUID `json:"after,omitempty"`
+}
+
+// TimerRequest - запуск или остановка таймера. Note сохраняется в запись
+// времени при остановке
+type TimerRequest struct {
+	User string `json:"user"`
+	Note string `json:"note,omitempty"`
+}
+
+// CreateWorklogRequest - ручная запись времени. Duration в формате Go:
+// "1h30m", "45m"
+type CreateWorklogRequest struct {
+	User      string    `json:"user"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+	Note      string    `json:"note,omitempty"`
+}
+
+type WorklogResponse struct {
+	ID        uuid.UUID `json:"id"`
+	TaskID    uuid.UUID `json:"task_id"`
+	User      string    `json:"user"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  int64     `json:"duration_seconds"`
+	Note      string    `json:"note,omitempty"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TimerResponse - запущенный таймер. Elapsed - время с запуска на момент ответа
+type TimerResponse struct {
+	TaskID    uuid.UUID `json:"task_id"`
+	User      string    `json:"user"`
+	StartedAt time.Time `json:"started_at"`
+	Elapsed   int64     `json:"elapsed_seconds"`
+}
+
+// TaskWorklogsResponse - записи времени задачи. В TotalSeconds не входят
+// запущенные таймеры
+type TaskWorklogsResponse struct {
+	TaskID       uuid.UUID         `json:"task_id"`
+	TotalSeconds int64             `json:"total_seconds"`
+	Entries      []WorklogResponse `json:"entries"`
+	Timers       []TimerResponse   `json:"timers"`
+}
+
+// WorklogReportResponse - отчёт по времени. В строках заполнены только
+// измерения из group_by
+type WorklogReportResponse struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	GroupBy      []string           `json:"group_by"`
+	TotalSeconds int64              `json:"total_seconds"`
+	Rows         []WorklogReportRow `json:"rows"`
+}
+
+type WorklogReportRow struct {
+	TaskID       *uuid.UUID `json:"task_id,omitempty"`
+	User         string     `json:"user,omitempty"`
+	Day          string     `json:"day,omitempty"`
+	TotalSeconds int64      `json:"total_seconds"`
+	Entries      int        `json:"entries"`
+}
+
+func FromWorklog(e *worklog.Entry) WorklogResponse {
+	return WorklogResponse{
+		ID:        e.ID,
+		TaskID:    e.TaskID,
+		User:      e.User,
+		StartedAt: e.StartedAt,
+		Duration:  int64(e.Duration / time.Second),
+		Note:      e.Note,
+		Source:    string(e.Source),
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func FromTimer(t *worklog.Timer) TimerResponse {
+	return TimerResponse{
+		TaskID:    t.TaskID,
+		User:      t.User,
+		StartedAt: t.StartedAt,
+		Elapsed:   int64(time.Since(t.StartedAt) / time.Second),
+	}
+}
+
+func FromTaskWorklogs(taskID uuid.UUID, entries []worklog.Entry, timers []worklog.Timer) TaskWorklogsResponse {
+	res := TaskWorklogsResponse{
+		TaskID:  taskID,
+		Entries: make([]WorklogResponse, len(entries)),
+		Timers:  make([]TimerResponse, len(timers)),
+	}
+	for i := range entries {
+		res.Entries[i] = FromWorklog(&entries[i])
+		res.TotalSeconds += res.Entries[i].Duration
+	}
+	for i := range timers {
+		res.Timers[i] = FromTimer(&timers[i])
+	}
+	return res
+}
+
+func FromWorklogReport(from, to time.Time, groupBy []string, rows []worklog.Row) WorklogReportResponse {
+	res := WorklogReportResponse{From: from, To: to, GroupBy: groupBy, Rows: make([]WorklogReportRow, len(rows))}
+	for i, row := range rows {
+		res.Rows[i] = WorklogReportRow{
+			User:         row.User,
+			Day:          row.Day,
+			TotalSeconds: int64(row.Duration / time.Second),
+			Entries:      row.Entries,
+		}
+		if row.TaskID != uuid.Nil {
+			id := row.TaskID
+			res.Rows[i].TaskID = &id
+		}
+		res.TotalSeconds += res.Rows[i].TotalSeconds
+	}
+	return res
+}
+
+// BurndownResponse - burndown (remaining, ideal) и burnup (scope, completed)
+// по дням, значения на конец дня в единицах unit
+type BurndownResponse struct {
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Unit   string          `json:"unit"`
+	Points []BurndownPoint `json:"points"`
+}
+
+type BurndownPoint struct {
+	Day       string  `json:"day"`
+	Remaining float64 `json:"remaining"`
+	Ideal     float64 `json:"ideal"`
+	Scope     float64 `json:"scope"`
+	Completed float64 `json:"completed"`
+	OpenTasks int     `json:"open_tasks"`
+	DoneTasks int     `json:"done_tasks"`
+}
+
+// VelocityResponse - выполненная работа по неделям с понедельника
+type VelocityResponse struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Unit    string         `json:"unit"`
+	Average float64        `json:"average"`
+	Weeks   []VelocityWeek `json:"weeks"`
+}
+
+type VelocityWeek struct {
+	WeekStart string  `json:"week_start"`
+	Completed float64 `json:"completed"`
+	Tasks     int     `json:"tasks"`
+}
+
+func FromBurndown(r *history.BurndownReport) BurndownResponse {
+	res := BurndownResponse{From: r.From, To: r.To, Unit: r.Unit, Points: make([]BurndownPoint, len(r.Points))}
+	for i, p := range r.Points {
+		res.Points[i] = BurndownPoint{
+			Day:       p.Day.Format(time.DateOnly),
+			Remaining: round(p.Remaining),
+			Ideal:     round(p.Ideal),
+			Scope:     round(p.Scope),
+			Completed: round(p.Completed),
+			OpenTasks: p.Open,
+			DoneTasks: p.Done,
+		}
+	}
+	return res
+}
+
+func FromVelocity(r *history.VelocityReport) VelocityResponse {
+	res := VelocityResponse{From: r.From, To: r.To, Unit: r.Unit, Average: round(r.Average), Weeks: make([]VelocityWeek, len(r.Weeks))}
+	for i, w := range r.Weeks {
+		res.Weeks[i] = VelocityWeek{
+			WeekStart: w.Start.Format(time.DateOnly),
+			Completed: round(w.Completed),
+			Tasks:     w.Tasks,
+		}
+	}
+	return res
+}
+
+// StatsResponse - сводка по задачам. by_status - задачи без удалённых,
+// by_flag - все; days, lead_time и cycle_time - за интервал [from, to)
+type StatsResponse struct {
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	GeneratedAt  time.Time      `json:"generated_at"`
+	ByStatus     map[string]int `json:"by_status"`
+	ByFlag       map[string]int `json:"by_flag"`
+	Overdue      int            `json:"overdue"`
+	OverdueRatio float64        `json:"overdue_ratio"`
+	LeadTime     DurationStats  `json:"lead_time"`
+	CycleTime    DurationStats  `json:"cycle_time"`
+	Days         []StatsDay     `json:"days"`
+	TopOverdue   []TaskResponse `json:"top_overdue"`
+}
+
+// DurationStats - средняя длительность по Tasks выполненным задачам
+type DurationStats struct {
+	AverageSeconds float64 `json:"average_seconds"`
+	Tasks          int     `json:"tasks"`
+}
+
+type StatsDay struct {
+	Day       string `json:"day"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+}
+
+func FromStats(r *stats.Report) StatsResponse {
+	res := StatsResponse{
+		From:         r.From,
+		To:           r.To,
+		GeneratedAt:  r.GeneratedAt,
+		ByStatus:     map[string]int{},
+		ByFlag:       map[string]int{},
+		Overdue:      r.Counts.Overdue,
+		OverdueRatio: math.Round(r.OverdueRatio*10000) / 10000,
+		LeadTime:     fromDurations(r.LeadTime),
+		CycleTime:    fromDurations(r.CycleTime),
+		Days:         make([]StatsDay, len(r.Days)),
+		TopOverdue:   FromTaskList(r.TopOverdue),
+	}
+	for flag, byStatus := range r.Counts.ByFlagStatus {
+		for status, n := range byStatus {
+			res.ByFlag[string(flag)] += n
+			if flag != task.FlagDeleted {
+				res.ByStatus[string(status)] += n
+			}
+		}
+	}
+	for i, d := range r.Days {
+		res.Days[i] = StatsDay{Day: d.Day.Format(time.DateOnly), Created: d.Created, Completed: d.Completed}
+	}
+	return res
+}
+
+func fromDurations(d stats.Durations) DurationStats {
+	return DurationStats{AverageSeconds: round(d.Average().Seconds()), Tasks: d.Tasks}
+}
+
+// round оставляет два знака: оценки не точнее сотых
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
 func FromTaskList(tasks []*task.Task) []TaskResponse {
 	result := make([]TaskResponse, len(tasks))
 	for i, t := range tasks {
@@ -52,3 +400,82 @@ func FromTaskList(tasks []*task.Task) []TaskResponse {
 	}
 	return result
 }
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url,omitempty"`
+	Secret *string   `json:"secret,omitempty"`
+	Events *[]string `json:"events,omitempty"`
+	Active *bool     `json:"active,omitempty"`
+}
+
+// WebhookResponse - подписка без секрета. Secret заполняется только
+// в ответе на создание, чтобы клиент мог сохранить сгенерированный секрет
+type WebhookResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	URL       string     `json:"url"`
+	Events    []string   `json:"events"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Secret    string     `json:"secret,omitempty"`
+}
+
+func FromSubscription(s *webhook.Subscription) WebhookResponse {
+	types := make([]string, len(s.Events))
+	for i, t := range s.Events {
+		types[i] = string(t)
+	}
+	return WebhookResponse{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    types,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func FromSubscriptionList(subscriptions []*webhook.Subscription) []WebhookResponse {
+	result := make([]WebhookResponse, len(subscriptions))
+	for i, s := range subscriptions {
+		result[i] = FromSubscription(s)
+	}
+	return result
+}
+
+// WSRequest - кадр клиента WebSocket. ID возвращается в ответе без изменений,
+// чтобы клиент мог сопоставить ответ с запросом
+type WSRequest struct {
+	ID      string             `json:"id,omitempty"`
+	Type    string             `json:"type"`
+	TaskID  uuid.UUID          `json:"task_id,omitempty"`
+	TaskIDs []uuid.UUID        `json:"task_ids,omitempty"`
+	Update  *UpdateTaskRequest `json:"update,omitempty"`
+}
+
+// WSResponse - кадр сервера: ответ на запрос (result или error)
+// либо событие подписки (event)
+type WSResponse struct {
+	ID     string   `json:"id,omitempty"`
+	Type   string   `json:"type"`
+	Result any      `json:"result,omitempty"`
+	Error  *WSError `json:"error,omitempty"`
+	Event  any      `json:"event,omitempty"`
+}
+
+type WSError struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// LogLevel - уровень логов: debug, info, warn, error
+type LogLevel struct {
+	Level string `json:"level"`
+}
@@ -1,45 +1,41 @@
 package handlers
 
 import (
-    "net/http"
-    "taskTracker/internal/logger"
-    "taskTracker/internal/service"
-    "go.uber.org/zap"
+	"net/http"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/service"
+
+	"go.uber.org/zap"
 )
 
+// writeError отвечает ошибкой в формате problem+json. Бизнес-ошибка отдаётся
+// со статусом своего кода, остальные логируются и отдаются как 500
+func writeError(w http.ResponseWriter, r *http.Request, err error, operation string) {
+	problem.Write(w, r, err,
+		zap.String("operation", operation),
+		zap.String("client_ip", r.RemoteAddr))
+}
+
+func errUnsupportedMediaType() *service.BusinessError {
+	return service.NewBusinessError(problem.CodeUnsupportedMediaType,
+		"Content-Type должен быть application/json",
+		service.ToDetail("expected", "application/json"))
+}
 
-func handleBusinessError(w http.ResponseWriter, err error, defaultMessage string) bool {
-    if businessErr, ok := err.(*service.BusinessError); ok {
-        statusCode := mapBusinessErrorToHTTP(businessErr.Code)
-        
-        logger.Warn("HTTP: Бизнес-ошибка",
-            zap.String("error_code", businessErr.Code),
-            zap.Int("http_status", statusCode))
-        
-        responseWithJSON(w, statusCode, 
-            toPayload("error",   businessErr.Code),
-            toPayload("message", businessErr.Message),
-            toPayload("details", businessErr.Details),
-        )
-        return true
-    }
-    return false
+func errBadBody(err error) *service.BusinessError {
+	return service.NewBusinessError(problem.CodeBadRequest, "неверное тело запроса: "+err.Error())
 }
 
+// NotFound - ответ роутера на неизвестный маршрут
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, service.NewBusinessError(problem.CodeRouteNotFound, "Маршрут не найден",
+		service.ToDetail("method", r.Method),
+		service.ToDetail("path", r.URL.Path)), "route")
+}
 
-func mapBusinessErrorToHTTP(code string) int {
-    switch code {
-    case "NOT_FOUND":
-        return http.StatusNotFound
-    case "VALIDATION_ERROR":
-        return http.StatusBadRequest
-    case "ALREADY_ARCHIVED", "NOT_ARCHIVED", "VERSION_CONFLICT":
-        return http.StatusConflict
-    case "TASK_DELETED", "RESTORE_EXPIRED":
-        return http.StatusGone
-    case "IN_PROGRESS", "NOT_DELETED":
-        return http.StatusConflict
-    default:
-        return http.StatusBadRequest
-    }
-}
\ No newline at end of file
+// MethodNotAllowed - ответ роутера на известный маршрут с чужим методом
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, service.NewBusinessError(problem.CodeMethodNotAllowed, "Метод не поддерживается",
+		service.ToDetail("method", r.Method),
+		service.ToDetail("path", r.URL.Path)), "route")
+}
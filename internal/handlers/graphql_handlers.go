@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"taskTracker/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// maxGraphQLBody - ограничение размера тела запроса GraphQL
+const maxGraphQLBody = 1 << 20
+
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type GraphQLHandler struct {
+	Executor GraphQLExecutor
+}
+
+func NewGraphQLHandler(executor GraphQLExecutor) GraphQLHandler {
+	return GraphQLHandler{Executor: executor}
+}
+
+// POST /graphql
+// Ошибки GraphQL, в том числе бизнес-ошибки, отдаются со статусом 200
+// в поле errors, как принято в GraphQL over HTTP
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var request GraphQLRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBody))
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, r, errBadBody(err), "graphql")
+		return
+	}
+	if request.Query == "" {
+		writeInvalid(w, r, "query", "не передан query", "")
+		return
+	}
+
+	result := h.Executor.Execute(r.Context(), request.Query, request.OperationName, request.Variables)
+	if result.HasErrors() {
+		logger.Info("HTTP: Запрос GraphQL завершился с ошибками",
+			zap.String("operation", request.OperationName),
+			zap.Int("errors", len(result.Errors)))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		logger.Error("HTTP: Ошибка записи ответа GraphQL", err)
+	}
+}
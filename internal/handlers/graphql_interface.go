@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+)
+
+type GraphQLExecutor interface {
+	Execute(ctx context.Context, query, operationName string, variables map[string]any) *graphql.Result
+}
@@ -11,10 +11,12 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"taskTracker/internal/board"
 	"taskTracker/internal/handlers"
 	"taskTracker/internal/handlers/dto"
 	"taskTracker/internal/models/task"
 	"taskTracker/internal/service"
+	"taskTracker/internal/workflow"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -32,7 +34,7 @@ func (m *MockTaskService) HealthCheck(ctx context.Context) error {
 	return args.Error(0)
 }
 
-func (m *MockTaskService) CreateTask(ctx context.Context, title, description string, dueTime time.Time) (*task.Task, error) {
+func (m *MockTaskService) CreateTask(ctx context.Context, title, description string, dueTime time.Time, options ...task.TaskOption) (*task.Task, error) {
 	args := m.Called(ctx, title, description, dueTime)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -48,6 +50,11 @@ func (m *MockTaskService) GetTaskByID(ctx context.Context, id uuid.UUID) (*task.
 	return args.Get(0).(*task.Task), args.Error(1)
 }
 
+func (m *MockTaskService) GetTasksByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, []error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]*task.Task), args.Get(1).([]error)
+}
+
 func (m *MockTaskService) UpdateTask(ctx context.Context, id uuid.UUID, options ...task.TaskOption) (*task.Task, error) {
 	args := m.Called(ctx, id, options)
 	if args.Get(0) == nil {
@@ -130,6 +137,38 @@ func (m *MockTaskService) PurgeTask(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockTaskService) GetTaskTransitions(ctx context.Context, id uuid.UUID) (*task.Task, []workflow.Option, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*task.Task), args.Get(1).([]workflow.Option), args.Error(2)
+}
+
+func (m *MockTaskService) GetBoard(ctx context.Context, id string) (board.Board, []board.ColumnTasks, error) {
+	args := m.Called(ctx, id)
+	if args.Get(1) == nil {
+		return board.Board{}, nil, args.Error(2)
+	}
+	return args.Get(0).(board.Board), args.Get(1).([]board.ColumnTasks), args.Error(2)
+}
+
+func (m *MockTaskService) MoveTask(ctx context.Context, id uuid.UUID, req service.MoveRequest) (*task.Task, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*task.Task), args.Error(1)
+}
+
+func (m *MockTaskService) GetTaskOccurrences(ctx context.Context, id uuid.UUID, from, to time.Time) ([]time.Time, error) {
+	args := m.Called(ctx, id, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
 var _ handlers.Service = (*MockTaskService)(nil)
 
 // TestTaskHandler_HealthCheck тестирует HealthCheck
@@ -621,7 +660,7 @@ func TestTaskHandler_ArchiveTask(t *testing.T) {
 				var response map[string]interface{}
 				err := json.NewDecoder(w.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedError, response["error"])
+				assert.Equal(t, tt.expectedError, response["code"])
 			}
 			
 			mockService.AssertExpectations(t)
@@ -1135,9 +1174,9 @@ func TestTaskHandler_ErrorResponses(t *testing.T) {
 		err := json.NewDecoder(w.Body).Decode(&response)
 		require.NoError(t, err)
 		
-		assert.Equal(t, "TEST_ERROR", response["error"])
-		assert.Equal(t, "Test error message", response["message"])
-		assert.NotNil(t, response["details"])
+		assert.Equal(t, "TEST_ERROR", response["code"])
+		assert.Equal(t, "Test error message", response["detail"])
+		assert.Equal(t, "value", response["field"])
 	})
 
 	t.Run("validation error response", func(t *testing.T) {
@@ -1148,7 +1187,8 @@ func TestTaskHandler_ErrorResponses(t *testing.T) {
 		handler.PostTask(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "неверное тело запроса")
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"field":"title"`)
 	})
 }
 
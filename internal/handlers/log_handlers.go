@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// LogHandler меняет уровень логов на лету. Пустой token закрывает эндпоинты
+type LogHandler struct {
+	token string
+}
+
+func NewLogHandler(token string) LogHandler {
+	return LogHandler{token: token}
+}
+
+// GET /admin/log-level
+func (h *LogHandler) GetLevel(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(w, r, "get_log_level") {
+		return
+	}
+
+	responseWithJSON(w, http.StatusOK, toPayload("level", logger.Level().String()))
+}
+
+// PUT /admin/log-level
+func (h *LogHandler) SetLevel(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(w, r, "set_log_level") {
+		return
+	}
+
+	if !checkContentType(r, "application/json") {
+		writeError(w, r, errUnsupportedMediaType(), "set_log_level")
+		return
+	}
+
+	var request dto.LogLevel
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errBadBody(err), "set_log_level")
+		return
+	}
+
+	previous := logger.Level()
+	if err := logger.SetLevel(request.Level); err != nil {
+		writeInvalid(w, r, "level", "неизвестный уровень логов, допустимы debug, info, warn, error", request.Level)
+		return
+	}
+
+	// пишется на warn, чтобы смена уровня была видна при любом новом уровне, кроме error
+	logger.WarnCtx(r.Context(), "HTTP: Уровень логов изменён",
+		zap.String("from", previous.String()),
+		zap.String("to", logger.Level().String()))
+
+	responseWithJSON(w, http.StatusOK, toPayload("level", logger.Level().String()))
+}
+
+// authorized проверяет Bearer-токен и сам отвечает 401, если он не подошёл
+func (h *LogHandler) authorized(w http.ResponseWriter, r *http.Request, operation string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if h.token != "" && ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1 {
+		return true
+	}
+
+	w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+	writeError(w, r, service.NewBusinessError(problem.CodeUnauthorized,
+		"Нужен токен управления уровнем логов"), operation)
+	return false
+}
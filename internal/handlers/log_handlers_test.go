@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLogHandler_Unauthorized тестирует, что без верного токена уровень логов не читается и не меняется
+func TestLogHandler_Unauthorized(t *testing.T) {
+	before := logger.Level()
+	t.Cleanup(func() { _ = logger.SetLevel(before.String()) })
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+	}{
+		{name: "no header", token: "secret"},
+		{name: "wrong token", token: "secret", authorization: "Bearer wrong"},
+		{name: "not bearer", token: "secret", authorization: "secret"},
+		{name: "token not configured", token: "", authorization: "Bearer "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := handlers.NewLogHandler(tt.token)
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"debug"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.SetLevel(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+			assert.Contains(t, w.Body.String(), "UNAUTHORIZED")
+			assert.Equal(t, before, logger.Level())
+
+			req = httptest.NewRequest(http.MethodGet, "/admin/log-level", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w = httptest.NewRecorder()
+			handler.GetLevel(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		handler := handlers.NewLogHandler("secret")
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"error"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		handler.SetLevel(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "error", logger.Level().String())
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"taskTracker/internal/handlers/dto"
+	"time"
+)
+
+type ReportHandler struct {
+	ReportService ReportService
+}
+
+func NewReportHandler(reportService ReportService) ReportHandler {
+	return ReportHandler{
+		ReportService: reportService,
+	}
+}
+
+// GET /reports/burndown?from=...&to=...
+func (h *ReportHandler) GetBurndown(w http.ResponseWriter, r *http.Request) {
+	// без to - двухнедельный спринт от from
+	from, to, ok := reportRange(w, r, 14*24*time.Hour)
+	if !ok {
+		return
+	}
+
+	report, err := h.ReportService.Burndown(r.Context(), from, to)
+	if err != nil {
+		writeError(w, r, err, "burndown_report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromBurndown(report))
+}
+
+// GET /reports/velocity?from=...&to=...
+func (h *ReportHandler) GetVelocity(w http.ResponseWriter, r *http.Request) {
+	// без to - двенадцать недель от from
+	from, to, ok := reportRange(w, r, 12*7*24*time.Hour)
+	if !ok {
+		return
+	}
+
+	report, err := h.ReportService.Velocity(r.Context(), from, to)
+	if err != nil {
+		writeError(w, r, err, "velocity_report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromVelocity(report))
+}
+
+// reportRange читает интервал отчёта: from обязателен, to по умолчанию
+// from + window
+func reportRange(w http.ResponseWriter, r *http.Request, window time.Duration) (time.Time, time.Time, bool) {
+	if r.URL.Query().Get("from") == "" {
+		writeInvalid(w, r, "from", "обязательный параметр", "")
+		return time.Time{}, time.Time{}, false
+	}
+	return validateTimeRange(w, r, window)
+}
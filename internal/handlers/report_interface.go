@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"context"
+	"taskTracker/internal/history"
+	"time"
+)
+
+type ReportService interface {
+	Burndown(context.Context, time.Time, time.Time) (*history.BurndownReport, error)
+	Velocity(context.Context, time.Time, time.Time) (*history.VelocityReport, error)
+}
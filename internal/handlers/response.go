@@ -27,10 +27,3 @@ func responseWithJSON(w http.ResponseWriter, code int, payload ...Payload){
 	}
 	json.NewEncoder(w).Encode(storage)
 }
-
-func responseWithError(w http.ResponseWriter, code int, message string){
-	responseWithJSON(w,code, toPayload("error", message))
-}
-
-
-
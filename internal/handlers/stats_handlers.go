@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/stats"
+	"time"
+)
+
+// интервал рядов и размер списка просроченных по умолчанию
+const (
+	defaultStatsWindow = 30 * 24 * time.Hour
+	defaultStatsTop    = 10
+)
+
+type StatsHandler struct {
+	StatsService StatsService
+}
+
+func NewStatsHandler(statsService StatsService) StatsHandler {
+	return StatsHandler{
+		StatsService: statsService,
+	}
+}
+
+// GET /stats?from=...&to=...&top=...
+// Без параметров - последние 30 дней и десять самых давних просроченных задач
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	q := stats.Query{To: time.Now(), Top: defaultStatsTop}
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeInvalid(w, r, "to", "должен быть в формате RFC 3339", value)
+			return
+		}
+		q.To = parsed
+	}
+	q.From = q.To.Add(-defaultStatsWindow)
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeInvalid(w, r, "from", "должен быть в формате RFC 3339", value)
+			return
+		}
+		q.From = parsed
+	}
+	if value := query.Get("top"); value != "" {
+		top, err := strconv.Atoi(value)
+		if err != nil {
+			writeInvalid(w, r, "top", "должен быть числом", value)
+			return
+		}
+		q.Top = top
+	}
+
+	report, err := h.StatsService.Stats(r.Context(), q)
+	if err != nil {
+		writeError(w, r, err, "stats")
+		return
+	}
+
+	// ответ может быть из кэша сервиса: клиенту незачем спрашивать раньше,
+	// чем он устареет
+	if ttl := h.StatsService.TTL(); ttl > 0 {
+		left := ttl - time.Since(report.GeneratedAt)
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(max(left, 0).Round(time.Second).Seconds())))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromStats(report))
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"context"
+	"taskTracker/internal/stats"
+	"time"
+)
+
+type StatsService interface {
+	Stats(context.Context, stats.Query) (*stats.Report, error)
+	TTL() time.Duration
+}
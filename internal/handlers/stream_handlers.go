@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/stream"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ResetEvent отправляется клиенту, если пропущенные события уже вытеснены
+// из буфера и состояние нужно перечитать через REST
+const ResetEvent = "stream.reset"
+
+// retryMillis - пауза перед переподключением для EventSource
+const retryMillis = 3000
+
+type StreamHandler struct {
+	Hub       StreamHub
+	Heartbeat time.Duration
+}
+
+func NewStreamHandler(hub StreamHub, heartbeat time.Duration) StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return StreamHandler{
+		Hub:       hub,
+		Heartbeat: heartbeat,
+	}
+}
+
+// GET /events/stream
+func (h *StreamHandler) GetEventStream(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseStreamFilter(w, r)
+	if !ok {
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var lastID uint64
+	resumable := true
+	if lastEventID != "" {
+		lastID, resumable = h.Hub.ParseEventID(lastEventID)
+	}
+
+	// поток живёт дольше WriteTimeout сервера, снимаем дедлайн для этого ответа
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.Warn("HTTP: Не удалось снять дедлайн записи для потока", zap.Error(err))
+	}
+
+	sub, replay, complete := h.Hub.Subscribe(lastID, filter)
+	defer sub.Close()
+
+	logger.Info("HTTP: Клиент подключился к потоку событий",
+		zap.String("last_event_id", lastEventID),
+		zap.Int("replay", len(replay)),
+		zap.String("client_ip", r.RemoteAddr))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", retryMillis); err != nil {
+		return
+	}
+
+	if lastEventID != "" && (!resumable || !complete) {
+		if err := h.writeReset(w, sub.From()); err != nil {
+			return
+		}
+	}
+	for _, msg := range replay {
+		if err := h.writeMessage(w, msg); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Warn("HTTP: Поток событий не поддерживает Flush", zap.Error(err))
+		return
+	}
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			logger.Info("HTTP: Клиент отключился от потока событий",
+				zap.String("client_ip", r.RemoteAddr))
+			return
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				// хаб отключил клиента: отстал или сервер останавливается
+				logger.Info("HTTP: Поток событий закрыт сервером",
+					zap.String("client_ip", r.RemoteAddr))
+				return
+			}
+			if err := h.writeMessage(w, msg); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (h *StreamHandler) writeMessage(w http.ResponseWriter, msg stream.Message) error {
+	data, err := json.Marshal(msg.Event)
+	if err != nil {
+		logger.Error("HTTP: Ошибка сериализации события", err,
+			zap.String("event_id", msg.Event.ID.String()))
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", h.Hub.EventID(msg.ID), msg.Event.Type, data)
+	return err
+}
+
+func (h *StreamHandler) writeReset(w http.ResponseWriter, from uint64) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: {}\n\n", h.Hub.EventID(from), ResetEvent)
+	return err
+}
+
+// parseStreamFilter разбирает фильтры flag, status и types (значения через запятую).
+// Фильтры применяются к состоянию задачи после события
+func parseStreamFilter(w http.ResponseWriter, r *http.Request) (stream.Filter, bool) {
+	query := r.URL.Query()
+
+	if query.Get("project") != "" {
+		writeInvalid(w, r, "project", "фильтр не поддерживается: у задач нет проекта", query.Get("project"))
+		return nil, false
+	}
+
+	flags := make(map[task.Flag]bool)
+	for _, value := range splitQueryList(query.Get("flag")) {
+		flag := task.Flag(value)
+		if flag != task.FlagActive && flag != task.FlagArchived && flag != task.FlagDeleted {
+			writeInvalid(w, r, "flag", "неизвестный флаг: "+value, value)
+			return nil, false
+		}
+		flags[flag] = true
+	}
+
+	statuses := make(map[task.Status]bool)
+	for _, value := range splitQueryList(query.Get("status")) {
+		status := task.Status(value)
+		if !slices.Contains(task.Statuses, status) {
+			writeInvalid(w, r, "status", "неизвестный статус: "+value, value)
+			return nil, false
+		}
+		statuses[status] = true
+	}
+
+	types := make(map[events.Type]bool)
+	for _, value := range splitQueryList(query.Get("types")) {
+		eventType := events.Type(value)
+		if !eventType.Valid() {
+			writeInvalid(w, r, "types", "неизвестный тип события: "+value, value)
+			return nil, false
+		}
+		types[eventType] = true
+	}
+
+	if len(flags) == 0 && len(statuses) == 0 && len(types) == 0 {
+		return nil, true
+	}
+
+	return func(e events.Event) bool {
+		if len(types) > 0 && !types[e.Type] {
+			return false
+		}
+		if len(flags) == 0 && len(statuses) == 0 {
+			return true
+		}
+		if e.Task == nil {
+			return false
+		}
+		if len(flags) > 0 && !flags[e.Task.Flag] {
+			return false
+		}
+		if len(statuses) > 0 && !statuses[e.Task.Status] {
+			return false
+		}
+		return true
+	}, true
+}
+
+func splitQueryList(value string) []string {
+	var res []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			res = append(res, part)
+		}
+	}
+	return res
+}
@@ -0,0 +1,9 @@
+package handlers
+
+import "taskTracker/internal/stream"
+
+type StreamHub interface {
+	Subscribe(lastID uint64, filter stream.Filter) (*stream.Subscription, []stream.Message, bool)
+	EventID(id uint64) string
+	ParseEventID(value string) (uint64, bool)
+}
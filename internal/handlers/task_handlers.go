@@ -34,8 +34,7 @@ func (s *TaskHandler) GetActiveTasks(w http.ResponseWriter, r *http.Request) {
     
     tasks, err := s.TaskService.GetActiveTasks(r.Context(), page, limit)
     if err != nil {
-        logger.Error("HTTP: Ошибка получения активных задач", err)
-        responseWithError(w, http.StatusInternalServerError, err.Error())
+        writeError(w, r, err, "get_active_tasks")
         return
     }
     
@@ -47,37 +46,47 @@ func (s *TaskHandler) GetActiveTasks(w http.ResponseWriter, r *http.Request) {
 func (s *TaskHandler) PostTask(w http.ResponseWriter, r *http.Request) {
     start := time.Now()
     if !checkContentType(r, "application/json") {
-        logger.Warn("HTTP: Неверный тип контента",
-            zap.String("expected", "application/json"),
-            zap.String("received", r.Header.Get("Content-Type")),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusUnsupportedMediaType, "Content-Type должен быть application/json")
+        writeError(w, r, errUnsupportedMediaType(), "create_task")
         return
     }
 
     var request dto.CreateTaskRequest
     if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-        logger.Warn("HTTP: ошибка чтения JSON",
-            zap.Error(err),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusBadRequest, "неверное тело запроса:"+err.Error())
+        writeError(w, r, errBadBody(err), "create_task")
         return
     }
 
-    if msg, ok := validateCreateRequest(r,w,request); !ok{
-        responseWithError(w, http.StatusBadRequest, msg)
+    if err := validateCreateRequest(request); err != nil {
+        writeError(w, r, err, "create_task")
         return
     }
 
     logger.Info("HTTP: Вызов сервиса создания задачи")
     
-    createdTask, err := s.TaskService.CreateTask(r.Context(), request.Title, request.Description, request.DueTime)
+    opts := []task.TaskOption{}
+    if request.RRule != "" {
+        opts = append(opts, task.WithRRule(request.RRule))
+    }
+
+    if len(request.Reminders) > 0 {
+        reminders, err := task.ParseReminders(request.Reminders)
+        if err != nil {
+            writeError(w, r, service.NewValidationError("reminders", err.Error()), "create_task")
+            return
+        }
+        opts = append(opts, task.WithReminders(reminders))
+    }
+
+    if request.OriginalEstimate != nil {
+        opts = append(opts, task.WithOriginalEstimate(*request.OriginalEstimate))
+    }
+    if request.RemainingEstimate != nil {
+        opts = append(opts, task.WithRemainingEstimate(*request.RemainingEstimate))
+    }
+
+    createdTask, err := s.TaskService.CreateTask(r.Context(), request.Title, request.Description, request.DueTime, opts...)
     if err != nil {
-        logger.Error("HTTP: Ошибка Service", err,
-            zap.String("operation", "create_task"),
-            zap.String("client_ip", r.RemoteAddr),
-            zap.Duration("ms", time.Since(start)))
-        responseWithError(w, http.StatusInternalServerError, err.Error())
+        writeError(w, r, err, "create_task")
         return
     }
     
@@ -127,14 +136,7 @@ func (s *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 
     task, err := s.TaskService.GetTaskByID(r.Context(), id)
     if err != nil {
-        if handleBusinessError(w, err, "ошибка получения задачи") {
-            return
-        }
-        
-        logger.Error("HTTP: Системная ошибка в Service", err,
-            zap.String("operation", "get_task"),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "get_task")
         return
     }
 
@@ -145,11 +147,7 @@ func (s *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 
 func (s *TaskHandler) UpdateTaskByID(w http.ResponseWriter, r *http.Request) {
     if !checkContentType(r, "application/json") {
-        logger.Warn("HTTP: Неверный тип контента",
-            zap.String("expected", "application/json"),
-            zap.String("received", r.Header.Get("Content-Type")),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusUnsupportedMediaType, "Content-Type должен быть application/json")
+        writeError(w, r, errUnsupportedMediaType(), "update_task")
         return
     }
 
@@ -164,13 +162,33 @@ func (s *TaskHandler) UpdateTaskByID(w http.ResponseWriter, r *http.Request) {
 
     err := decoder.Decode(&request)
     if err != nil {
-        logger.Warn("HTTP: ошибка чтения JSON",
-            zap.Error(err),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusBadRequest, "неверно переданы параметры обновления:"+err.Error())
+        writeError(w, r, errBadBody(err), "update_task")
         return
     }
     
+    opts, err := updateOptions(request)
+    if err != nil {
+        writeError(w, r, service.NewValidationError("reminders", err.Error()), "update_task")
+        return
+    }
+
+    logger.Info("HTTP: запрос к сервису обновления данных",
+        zap.String("task_id", id.String()))
+
+    updatedTask, err := s.TaskService.UpdateTask(r.Context(), id, opts...)
+    if err != nil {
+        writeError(w, r, err, "update_task")
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(dto.FromTask(updatedTask))
+}
+
+// updateOptions переводит запрос на обновление в опции задачи,
+// общие для HTTP и WebSocket
+func updateOptions(request dto.UpdateTaskRequest) ([]task.TaskOption, error) {
     opts := []task.TaskOption{}
 
     if request.Status != nil {
@@ -189,25 +207,27 @@ func (s *TaskHandler) UpdateTaskByID(w http.ResponseWriter, r *http.Request) {
         opts = append(opts, task.WithDueTime(*request.DueTime))
     }
 
-    logger.Info("HTTP: запрос к сервису обновления данных",
-        zap.String("task_id", id.String()))
+    if request.RRule != nil {
+        opts = append(opts, task.WithRRule(*request.RRule))
+    }
 
-    updatedTask, err := s.TaskService.UpdateTask(r.Context(), id, opts...)
-    if err != nil {
-        if handleBusinessError(w, err, "ошибка обновления задачи") {
-            return
+    if request.Reminders != nil {
+        reminders, err := task.ParseReminders(*request.Reminders)
+        if err != nil {
+            return nil, err
         }
-        
-        logger.Error("HTTP: ошибка в Service", err,
-            zap.String("operation", "update_task"),
-            zap.String("client_addr", r.RemoteAddr))
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
-        return
+        opts = append(opts, task.WithReminders(reminders))
     }
 
-    w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-    json.NewEncoder(w).Encode(dto.FromTask(updatedTask))
+    if request.OriginalEstimate != nil {
+        opts = append(opts, task.WithOriginalEstimate(*request.OriginalEstimate))
+    }
+
+    if request.RemainingEstimate != nil {
+        opts = append(opts, task.WithRemainingEstimate(*request.RemainingEstimate))
+    }
+
+    return opts, nil
 }
 
 func (s *TaskHandler) DeleteTaskByID(w http.ResponseWriter, r *http.Request) {
@@ -221,14 +241,7 @@ func (s *TaskHandler) DeleteTaskByID(w http.ResponseWriter, r *http.Request) {
 
     err := s.TaskService.DeleteTask(r.Context(), id)
     if err != nil {
-        if handleBusinessError(w, err, "ошибка удаления задачи") {
-            return
-        }
-        
-        logger.Error("HTTP: ошибка в Service", err,
-            zap.String("operation", "delete_task"),
-            zap.String("client_addr", r.RemoteAddr))
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "delete_task")
         return
     }
 
@@ -247,8 +260,7 @@ func (s *TaskHandler) GetArchivedTasks(w http.ResponseWriter, r *http.Request) {
     
     tasks, err := s.TaskService.GetArchivedTasks(r.Context(), page, limit)
     if err != nil {
-        logger.Error("HTTP: Ошибка получения архивных задач", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "get_archived_tasks")
         return
     }
     
@@ -269,8 +281,7 @@ func (s *TaskHandler) GetAllTasks(w http.ResponseWriter, r *http.Request) {
     
     tasks, err := s.TaskService.GetAllTasks(r.Context(), page, limit)
     if err != nil {
-        logger.Error("HTTP: Ошибка получения всех задач", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "get_all_tasks")
         return
     }
     
@@ -291,8 +302,7 @@ func (s *TaskHandler) GetOverdueTasks(w http.ResponseWriter, r *http.Request) {
     
     tasks, err := s.TaskService.GetOverdueTasks(r.Context(), page, limit)
     if err != nil {
-        logger.Error("HTTP: Ошибка получения просроченных задач", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "get_overdue_tasks")
         return
     }
   
@@ -313,8 +323,7 @@ func (s *TaskHandler) GetDeletedTasks(w http.ResponseWriter, r *http.Request) {
     
     tasks, err := s.TaskService.GetDeletedTasks(r.Context(), page, limit)
     if err != nil {
-        logger.Error("HTTP: Ошибка получения удаленных задач", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "get_deleted_tasks")
         return
     }
     
@@ -334,32 +343,7 @@ func (s *TaskHandler) ArchiveTask(w http.ResponseWriter, r *http.Request) {
     
     archivedTask, err := s.TaskService.ArchiveTask(r.Context(), id)
     if err != nil {
-        if businessErr, ok := err.(*service.BusinessError); ok {
-            statusCode := http.StatusBadRequest
-            switch businessErr.Code {
-            case "ALREADY_ARCHIVED":
-                statusCode = http.StatusConflict
-            case "TASK_DELETED":
-                statusCode = http.StatusGone
-            case "VERSION_CONFLICT":
-                statusCode = http.StatusConflict
-            }
-            
-            logger.Warn("HTTP: Бизнес-ошибка при архивации",
-                zap.String("error_code", businessErr.Code),
-                zap.String("task_id", id.String()),
-                zap.Int("http_status", statusCode))
-            
-            responseWithJSON(w, statusCode, 
-                toPayload("error", businessErr.Code),
-                toPayload("message", businessErr.Message),
-                toPayload("details", businessErr.Details),
-            )
-            return
-        }
-        
-        logger.Error("HTTP: Системная ошибка при архивации", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "archive_task")
         return
     }
 
@@ -379,32 +363,7 @@ func (s *TaskHandler) UnarchiveTask(w http.ResponseWriter, r *http.Request) {
 
     unarchivedTask, err := s.TaskService.UnarchiveTask(r.Context(), id)
     if err != nil {
-        if businessErr, ok := err.(*service.BusinessError); ok {
-            statusCode := http.StatusBadRequest
-            switch businessErr.Code {
-            case "NOT_ARCHIVED":
-                statusCode = http.StatusConflict
-            case "TASK_DELETED":
-                statusCode = http.StatusGone
-            case "VERSION_CONFLICT":
-                statusCode = http.StatusConflict
-            }
-            
-            logger.Warn("HTTP: Бизнес-ошибка при разархивации",
-                zap.String("error_code", businessErr.Code),
-                zap.String("task_id", id.String()),
-                zap.Int("http_status", statusCode))
-            
-            responseWithJSON(w, statusCode, 
-                toPayload("error", businessErr.Code),
-                toPayload("message", businessErr.Message),
-                toPayload("details", businessErr.Details),
-            )
-            return
-        }
-        
-        logger.Error("HTTP: Системная ошибка при разархивации", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "unarchive_task")
         return
     }
     
@@ -421,10 +380,7 @@ func (s *TaskHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
     }
 
     if !checkContentType(r, "application/json") && r.ContentLength > 0 {
-        logger.Warn("HTTP: Неверный тип контента",
-            zap.String("expected", "application/json"),
-            zap.String("received", r.Header.Get("Content-Type")))
-        responseWithError(w, http.StatusUnsupportedMediaType, "Content-Type должен быть application/json")
+        writeError(w, r, errUnsupportedMediaType(), "restore_task")
         return
     }
     
@@ -433,32 +389,7 @@ func (s *TaskHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
 
     restoredTask, err := s.TaskService.RestoreTask(r.Context(), id)
     if err != nil {
-        if businessErr, ok := err.(*service.BusinessError); ok {
-            statusCode := http.StatusBadRequest
-            switch businessErr.Code {
-            case "NOT_DELETED":
-                statusCode = http.StatusConflict
-            case "RESTORE_EXPIRED":
-                statusCode = http.StatusGone
-            case "VERSION_CONFLICT":
-                statusCode = http.StatusConflict
-            }
-            
-            logger.Warn("HTTP: Бизнес-ошибка при восстановлении",
-                zap.String("error_code", businessErr.Code),
-                zap.String("task_id", id.String()),
-                zap.Int("http_status", statusCode))
-            
-            responseWithJSON(w, statusCode, 
-                toPayload("error", businessErr.Code),
-                toPayload("message", businessErr.Message),
-                toPayload("details", businessErr.Details),
-            )
-            return
-        }
-        
-        logger.Error("HTTP: Системная ошибка при восстановлении", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "restore_task")
         return
     }
 
@@ -476,29 +407,58 @@ func (s *TaskHandler) PurgeTask(w http.ResponseWriter, r *http.Request) {
     
     err := s.TaskService.PurgeTask(r.Context(), id)
     if err != nil {
-        if businessErr, ok := err.(*service.BusinessError); ok {
-            statusCode := http.StatusBadRequest
-            if businessErr.Code == "NOT_DELETED" {
-                statusCode = http.StatusConflict
-            }
-            
-            logger.Warn("HTTP: Бизнес-ошибка при полном удалении",
-                zap.String("error_code", businessErr.Code),
-                zap.String("task_id", id.String()),
-                zap.Int("http_status", statusCode))
-            
-            responseWithJSON(w, statusCode, 
-                toPayload("error", businessErr.Code),
-                toPayload("message", businessErr.Message),
-                toPayload("details", businessErr.Details),
-            )
-            return
-        }
-        
-        logger.Error("HTTP: Системная ошибка при полном удалении", err)
-        responseWithError(w, http.StatusInternalServerError, "внутренняя ошибка сервера")
+        writeError(w, r, err, "purge_task")
         return
     }
     
     w.WriteHeader(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+func (s *TaskHandler) GetTaskTransitions(w http.ResponseWriter, r *http.Request) {
+    id, ok := validateUUID(w, r, "id")
+    if !ok {
+        return
+    }
+
+    t, options, err := s.TaskService.GetTaskTransitions(r.Context(), id)
+    if err != nil {
+        writeError(w, r, err, "get_transitions")
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(dto.FromTransitions(t, options))
+}
+
+func (s *TaskHandler) GetTaskOccurrences(w http.ResponseWriter, r *http.Request) {
+    id, ok := validateUUID(w, r, "id")
+    if !ok {
+        return
+    }
+
+    from, to, ok := validateTimeRange(w, r, defaultOccurrencesWindow)
+    if !ok {
+        return
+    }
+
+    logger.Info("HTTP: Предпросмотр повторений задачи",
+        zap.String("task_id", id.String()),
+        zap.Time("from", from),
+        zap.Time("to", to))
+
+    occurrences, err := s.TaskService.GetTaskOccurrences(r.Context(), id, from, to)
+    if err != nil {
+        writeError(w, r, err, "get_occurrences")
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(dto.OccurrencesResponse{
+        TaskID:      id,
+        From:        from,
+        To:          to,
+        Occurrences: occurrences,
+    })
+}
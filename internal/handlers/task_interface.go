@@ -4,20 +4,28 @@ import "context"
 import "github.com/google/uuid"
 import "time"
 import "taskTracker/internal/models/task"
+import "taskTracker/internal/workflow"
+import "taskTracker/internal/board"
+import "taskTracker/internal/service"
 
 type Service interface {
-    CreateTask(context.Context, string, string, time.Time) (*task.Task, error)
+    CreateTask(context.Context, string, string, time.Time, ...task.TaskOption) (*task.Task, error)
     GetActiveTasks(context.Context, int, int) ([]*task.Task, error)
     GetAllTasks(context.Context, int, int) ([]*task.Task, error)
     GetArchivedTasks(context.Context, int, int) ([]*task.Task, error)
     GetOverdueTasks(context.Context, int, int) ([]*task.Task, error)
     GetDeletedTasks(context.Context, int, int) ([]*task.Task, error)
     GetTaskByID(context.Context, uuid.UUID) (*task.Task, error)
+    GetTasksByIDs(context.Context, []uuid.UUID) ([]*task.Task, []error)
     UpdateTask(context.Context, uuid.UUID, ...task.TaskOption) (*task.Task, error)
     DeleteTask(context.Context, uuid.UUID) error
     ArchiveTask(context.Context, uuid.UUID) (*task.Task, error)
     UnarchiveTask(context.Context, uuid.UUID) (*task.Task, error)
     RestoreTask(context.Context, uuid.UUID) (*task.Task, error)
     PurgeTask(context.Context, uuid.UUID) error
+    GetTaskOccurrences(context.Context, uuid.UUID, time.Time, time.Time) ([]time.Time, error)
+    GetTaskTransitions(context.Context, uuid.UUID) (*task.Task, []workflow.Option, error)
+    GetBoard(context.Context, string) (board.Board, []board.ColumnTasks, error)
+    MoveTask(context.Context, uuid.UUID, service.MoveRequest) (*task.Task, error)
 	HealthCheck(context.Context) error
 }
\ No newline at end of file
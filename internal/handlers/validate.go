@@ -5,8 +5,9 @@ import (
     "mime"
     "strconv"
     "github.com/google/uuid"
-    "taskTracker/internal/logger"
     "taskTracker/internal/handlers/dto"
+    "taskTracker/internal/problem"
+    "taskTracker/internal/service"
     "time"
     "go.uber.org/zap"
 	"github.com/go-chi/chi/v5"
@@ -42,21 +43,13 @@ func validatePagination(w http.ResponseWriter, r *http.Request) (page, limit int
     
     page, err := strconv.Atoi(pageStr)
     if err != nil || page <= 0 {
-        logger.Warn("HTTP: Неверное значение параметра",
-            zap.String("query", "page"),
-            zap.String("value", pageStr),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusBadRequest, "параметр page должен быть положительным числом")
+        writeInvalid(w, r, "page", "должен быть положительным числом", pageStr)
         return 0, 0, false
     }
     
     limit, err = strconv.Atoi(limitStr)
     if err != nil || limit <= 0 {
-        logger.Warn("HTTP: Неверное значение параметра",
-            zap.String("query", "limit"),
-            zap.String("value", limitStr),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusBadRequest, "параметр limit должен быть положительным числом")
+        writeInvalid(w, r, "limit", "должен быть положительным числом", limitStr)
         return 0, 0, false
     }
     
@@ -72,49 +65,72 @@ func validatePagination(w http.ResponseWriter, r *http.Request) (page, limit int
 func validateUUID(w http.ResponseWriter, r *http.Request, paramName string) (uuid.UUID, bool) {
     idParam := chi.URLParam(r, paramName)
     if idParam == "" {
-        logger.Warn("HTTP: Отсутствует параметр ID",
-            zap.String("param", paramName),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusBadRequest, "отсутствует идентификатор задачи")
+        writeInvalid(w, r, paramName, "отсутствует идентификатор", idParam)
         return uuid.Nil, false
     }
     
     id, err := uuid.Parse(idParam)
     if err != nil {
-        logger.Warn("HTTP: Неверный формат UUID",
-            zap.String("param", paramName),
-            zap.String("value", idParam),
-            zap.String("client_ip", r.RemoteAddr))
-        responseWithError(w, http.StatusBadRequest, "неверный формат идентификатора задачи")
+        writeInvalid(w, r, paramName, "неверный формат идентификатора", idParam)
         return uuid.Nil, false
     }
     
     return id, true
 }
 
-func validateCreateRequest(r *http.Request, w http.ResponseWriter,request dto.CreateTaskRequest) (string,bool){
-       if request.Title == "" {
-        logger.Warn("HTTP: Ошибка валидации",
-            zap.String("field", "title"),
-            zap.String("error", "empty_field"),
-            zap.String("client_ip", r.RemoteAddr))
-        return "название не может быть пустым",false
+// validateCreateRequest возвращает ошибку валидации или nil
+func validateCreateRequest(request dto.CreateTaskRequest) error {
+    if request.Title == "" {
+        return service.NewValidationError("title", "название не может быть пустым")
     }
 
     if request.DueTime.IsZero() {
-        logger.Warn("HTTP: Ошибка валидации",
-            zap.String("field", "due_time"),
-            zap.String("error", "empty_field"),
-            zap.String("client_ip", r.RemoteAddr))
-        return "дедлайн должен быть задан",false
+        return service.NewValidationError("due_time", "дедлайн должен быть задан")
     }
 
     if time.Now().After(request.DueTime) {
-        logger.Warn("HTTP: Ошибка валидации",
-            zap.String("field", "due_time"),
-            zap.String("error", "wrong_value"),
-            zap.String("client_ip", r.RemoteAddr))
-        return "дедлайн не может быть в прошлом",false
+        return service.NewValidationError("due_time", "дедлайн не может быть в прошлом")
     }
-    return "",true
-}
\ No newline at end of file
+    return nil
+}
+
+// окно предпросмотра повторений, если to не задан
+const defaultOccurrencesWindow = 90 * 24 * time.Hour
+
+// validateTimeRange читает from/to в RFC 3339. По умолчанию from - текущий момент,
+// to - from + window
+func validateTimeRange(w http.ResponseWriter, r *http.Request, window time.Duration) (from, to time.Time, ok bool) {
+    from = time.Now()
+    if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+        parsed, err := time.Parse(time.RFC3339, fromStr)
+        if err != nil {
+            writeInvalid(w, r, "from", "должен быть в формате RFC 3339", fromStr)
+            return time.Time{}, time.Time{}, false
+        }
+        from = parsed
+    }
+
+    to = from.Add(window)
+    if toStr := r.URL.Query().Get("to"); toStr != "" {
+        parsed, err := time.Parse(time.RFC3339, toStr)
+        if err != nil {
+            writeInvalid(w, r, "to", "должен быть в формате RFC 3339", toStr)
+            return time.Time{}, time.Time{}, false
+        }
+        to = parsed
+    }
+
+    if to.Before(from) {
+        writeInvalid(w, r, "to", "не может быть раньше from", r.URL.Query().Get("to"))
+        return time.Time{}, time.Time{}, false
+    }
+
+    return from, to, true
+}
+
+// writeInvalid отвечает ошибкой валидации параметра запроса
+func writeInvalid(w http.ResponseWriter, r *http.Request, field, reason, value string) {
+    problem.Write(w, r, service.NewValidationError(field, reason),
+        zap.String("value", value),
+        zap.String("client_ip", r.RemoteAddr))
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"taskTracker/internal/events"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	WebhookService WebhookService
+}
+
+func NewWebhookHandler(webhookService WebhookService) WebhookHandler {
+	return WebhookHandler{
+		WebhookService: webhookService,
+	}
+}
+
+func toEventTypes(values []string) []events.Type {
+	types := make([]events.Type, len(values))
+	for i, v := range values {
+		types[i] = events.Type(v)
+	}
+	return types
+}
+
+// POST /webhooks
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if !checkContentType(r, "application/json") {
+		writeError(w, r, errUnsupportedMediaType(), "create_webhook")
+		return
+	}
+
+	var request dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errBadBody(err), "create_webhook")
+		return
+	}
+
+	subscription, err := h.WebhookService.CreateSubscription(r.Context(), request.URL, request.Secret, toEventTypes(request.Events))
+	if err != nil {
+		writeError(w, r, err, "create_webhook")
+		return
+	}
+
+	logger.Info("HTTP_OUT: Подписка создана",
+		zap.String("webhook_id", subscription.ID.String()),
+		zap.String("url", subscription.URL))
+
+	response := dto.FromSubscription(subscription)
+	response.Secret = subscription.Secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GET /webhooks
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.WebhookService.ListSubscriptions(r.Context())
+	if err != nil {
+		writeError(w, r, err, "list_webhooks")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromSubscriptionList(subscriptions))
+}
+
+// GET /webhooks/{id}
+func (h *WebhookHandler) GetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	subscription, err := h.WebhookService.GetSubscription(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "get_webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromSubscription(subscription))
+}
+
+// PUT /webhooks/{id}
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	if !checkContentType(r, "application/json") {
+		writeError(w, r, errUnsupportedMediaType(), "update_webhook")
+		return
+	}
+
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var request dto.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errBadBody(err), "update_webhook")
+		return
+	}
+
+	opts := []webhook.SubscriptionOption{}
+	if request.URL != nil {
+		opts = append(opts, webhook.WithURL(*request.URL))
+	}
+	if request.Secret != nil {
+		opts = append(opts, webhook.WithSecret(*request.Secret))
+	}
+	if request.Events != nil {
+		opts = append(opts, webhook.WithEvents(toEventTypes(*request.Events)))
+	}
+	if request.Active != nil {
+		opts = append(opts, webhook.WithActive(*request.Active))
+	}
+
+	subscription, err := h.WebhookService.UpdateSubscription(r.Context(), id, opts...)
+	if err != nil {
+		writeError(w, r, err, "update_webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromSubscription(subscription))
+}
+
+// DELETE /webhooks/{id}
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.WebhookService.DeleteSubscription(r.Context(), id); err != nil {
+		writeError(w, r, err, "delete_webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /webhooks/{id}/deliveries
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	_, limit, ok := validatePagination(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.WebhookService.ListDeliveries(r.Context(), id, limit)
+	if err != nil {
+		writeError(w, r, err, "list_deliveries")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// GET /webhooks/dead-letters
+func (h *WebhookHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	_, limit, ok := validatePagination(w, r)
+	if !ok {
+		return
+	}
+
+	deadLetters, err := h.WebhookService.ListDeadLetters(r.Context(), limit)
+	if err != nil {
+		writeError(w, r, err, "list_dead_letters")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(deadLetters)
+}
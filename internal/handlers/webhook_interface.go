@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+	"taskTracker/internal/events"
+	"taskTracker/internal/webhook"
+
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	CreateSubscription(context.Context, string, string, []events.Type) (*webhook.Subscription, error)
+	ListSubscriptions(context.Context) ([]*webhook.Subscription, error)
+	GetSubscription(context.Context, uuid.UUID) (*webhook.Subscription, error)
+	UpdateSubscription(context.Context, uuid.UUID, ...webhook.SubscriptionOption) (*webhook.Subscription, error)
+	DeleteSubscription(context.Context, uuid.UUID) error
+	ListDeliveries(context.Context, uuid.UUID, int) ([]webhook.Delivery, error)
+	ListDeadLetters(context.Context, int) ([]webhook.DeadLetter, error)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/service"
+	"taskTracker/internal/worklog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type WorklogHandler struct {
+	WorklogService WorklogService
+}
+
+func NewWorklogHandler(worklogService WorklogService) WorklogHandler {
+	return WorklogHandler{
+		WorklogService: worklogService,
+	}
+}
+
+// POST /tasks/{id}/timer/start
+func (h *WorklogHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	id, request, ok := h.timerRequest(w, r, "start_timer")
+	if !ok {
+		return
+	}
+
+	timer, err := h.WorklogService.StartTimer(r.Context(), id, request.User)
+	if err != nil {
+		writeError(w, r, err, "start_timer")
+		return
+	}
+
+	logger.Info("HTTP_OUT: Таймер запущен",
+		zap.String("task_id", id.String()),
+		zap.String("user", timer.User))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.FromTimer(timer))
+}
+
+// POST /tasks/{id}/timer/stop
+func (h *WorklogHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	id, request, ok := h.timerRequest(w, r, "stop_timer")
+	if !ok {
+		return
+	}
+
+	entry, err := h.WorklogService.StopTimer(r.Context(), id, request.User, request.Note)
+	if err != nil {
+		writeError(w, r, err, "stop_timer")
+		return
+	}
+
+	logger.Info("HTTP_OUT: Таймер остановлен",
+		zap.String("task_id", id.String()),
+		zap.String("user", entry.User),
+		zap.Duration("duration", entry.Duration))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromWorklog(entry))
+}
+
+func (h *WorklogHandler) timerRequest(w http.ResponseWriter, r *http.Request, op string) (uuid.UUID, dto.TimerRequest, bool) {
+	var request dto.TimerRequest
+	if !checkContentType(r, "application/json") {
+		writeError(w, r, errUnsupportedMediaType(), op)
+		return uuid.Nil, request, false
+	}
+
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return uuid.Nil, request, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errBadBody(err), op)
+		return uuid.Nil, request, false
+	}
+	return id, request, true
+}
+
+// GET /tasks/{id}/worklogs
+func (h *WorklogHandler) GetTaskWorklogs(w http.ResponseWriter, r *http.Request) {
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	entries, timers, err := h.WorklogService.TaskWorklogs(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "get_worklogs")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromTaskWorklogs(id, entries, timers))
+}
+
+// POST /tasks/{id}/worklogs
+func (h *WorklogHandler) CreateWorklog(w http.ResponseWriter, r *http.Request) {
+	if !checkContentType(r, "application/json") {
+		writeError(w, r, errUnsupportedMediaType(), "create_worklog")
+		return
+	}
+
+	id, ok := validateUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var request dto.CreateWorklogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errBadBody(err), "create_worklog")
+		return
+	}
+	duration, err := time.ParseDuration(request.Duration)
+	if err != nil {
+		writeError(w, r, service.NewValidationError("duration", "должна быть в формате 1h30m"), "create_worklog")
+		return
+	}
+
+	entry, err := h.WorklogService.AddWorklog(r.Context(), id, request.User, request.StartedAt, duration, request.Note)
+	if err != nil {
+		writeError(w, r, err, "create_worklog")
+		return
+	}
+
+	logger.Info("HTTP_OUT: Время записано",
+		zap.String("task_id", id.String()),
+		zap.String("user", entry.User),
+		zap.Duration("duration", entry.Duration))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.FromWorklog(entry))
+}
+
+// GET /worklogs/report?from=...&to=...&group_by=task,user,day
+func (h *WorklogHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	// без to отчёт строится за неделю от from
+	from, to, ok := reportRange(w, r, 7*24*time.Hour)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := worklog.Filter{User: query.Get("user"), From: from, To: to}
+	if taskID := query.Get("task_id"); taskID != "" {
+		id, err := uuid.Parse(taskID)
+		if err != nil {
+			writeInvalid(w, r, "task_id", "должен быть UUID", taskID)
+			return
+		}
+		filter.TaskID = id
+	}
+
+	groupBy := worklog.Dimensions
+	if value := query.Get("group_by"); value != "" {
+		groupBy = strings.Split(value, ",")
+	}
+
+	rows, err := h.WorklogService.Report(r.Context(), filter, groupBy)
+	if err != nil {
+		writeError(w, r, err, "worklog_report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromWorklogReport(from, to, groupBy, rows))
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"context"
+	"taskTracker/internal/worklog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorklogService interface {
+	StartTimer(context.Context, uuid.UUID, string) (*worklog.Timer, error)
+	StopTimer(context.Context, uuid.UUID, string, string) (*worklog.Entry, error)
+	AddWorklog(context.Context, uuid.UUID, string, time.Time, time.Duration, string) (*worklog.Entry, error)
+	TaskWorklogs(context.Context, uuid.UUID) ([]worklog.Entry, []worklog.Timer, error)
+	Report(context.Context, worklog.Filter, []string) ([]worklog.Row, error)
+}
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"taskTracker/internal/events"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// типы кадров WebSocket
+const (
+	WSSubscribe   = "subscribe"
+	WSUnsubscribe = "unsubscribe"
+	WSGet         = "get"
+	WSUpdate      = "update"
+	WSArchive     = "archive"
+	WSUnarchive   = "unarchive"
+	WSDelete      = "delete"
+	WSPing        = "ping"
+
+	WSResult = "result"
+	WSError  = "error"
+	WSEvent  = "event"
+	WSPong   = "pong"
+)
+
+const (
+	wsMaxMessageSize = 64 << 10
+	wsSendBuffer     = 64
+	wsWriteWait      = 10 * time.Second
+	wsRequestTimeout = 10 * time.Second
+)
+
+type WSOptions struct {
+	// AllowedOrigins - разрешённые Origin; пусто - только тот же хост, "*" - любые
+	AllowedOrigins []string
+	// MaxSubscriptions - сколько задач может отслеживать одно соединение
+	MaxSubscriptions int
+	PingInterval     time.Duration
+}
+
+type WSHandler struct {
+	TaskService Service
+	Hub         StreamHub
+	Options     WSOptions
+	upgrader    websocket.Upgrader
+}
+
+func NewWSHandler(taskService Service, hub StreamHub, opts WSOptions) *WSHandler {
+	if opts.MaxSubscriptions <= 0 {
+		opts.MaxSubscriptions = 1000
+	}
+	if opts.PingInterval <= 0 {
+		opts.PingInterval = 30 * time.Second
+	}
+
+	h := &WSHandler{
+		TaskService: taskService,
+		Hub:         hub,
+		Options:     opts,
+	}
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+		CheckOrigin:     checkOrigin(opts.AllowedOrigins),
+	}
+	return h
+}
+
+// checkOrigin возвращает nil для проверки по умолчанию (тот же хост)
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	set := make(map[string]bool, len(allowed))
+	for _, origin := range allowed {
+		if origin == "*" {
+			return func(r *http.Request) bool { return true }
+		}
+		set[origin] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || set[origin]
+	}
+}
+
+// wsConn - состояние одного соединения
+type wsConn struct {
+	handler *WSHandler
+	conn    *websocket.Conn
+	send    chan dto.WSResponse
+	done    chan struct{}
+	once    sync.Once
+
+	mtx        sync.RWMutex
+	subscribed map[uuid.UUID]bool
+}
+
+// GET /ws
+func (h *WSHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade уже ответил клиенту ошибкой
+		logger.Warn("WS: Ошибка установки соединения",
+			zap.Error(err),
+			zap.String("client_ip", r.RemoteAddr))
+		return
+	}
+
+	c := &wsConn{
+		handler:    h,
+		conn:       conn,
+		send:       make(chan dto.WSResponse, wsSendBuffer),
+		done:       make(chan struct{}),
+		subscribed: make(map[uuid.UUID]bool),
+	}
+
+	sub, _, _ := h.Hub.Subscribe(0, c.matches)
+	defer sub.Close()
+
+	logger.Info("WS: Клиент подключился", zap.String("client_ip", r.RemoteAddr))
+
+	go c.writeLoop()
+	go func() {
+		for msg := range sub.Messages() {
+			c.push(dto.WSResponse{Type: WSEvent, Event: msg.Event})
+		}
+		// хаб отключил клиента: отстал или сервер останавливается
+		c.close(websocket.CloseGoingAway, "поток событий закрыт")
+	}()
+
+	c.readLoop()
+	c.close(websocket.CloseNormalClosure, "")
+
+	logger.Info("WS: Клиент отключился", zap.String("client_ip", r.RemoteAddr))
+}
+
+func (c *wsConn) matches(e events.Event) bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.subscribed[e.TaskID]
+}
+
+// push ставит кадр в очередь отправки. Клиент, который не успевает
+// читать, отключается, чтобы не копить кадры без ограничения
+func (c *wsConn) push(frame dto.WSResponse) {
+	select {
+	case <-c.done:
+	case c.send <- frame:
+	default:
+		logger.Warn("WS: Клиент не успевает читать, соединение закрыто")
+		c.close(websocket.ClosePolicyViolation, "клиент не успевает читать")
+	}
+}
+
+func (c *wsConn) close(code int, reason string) {
+	c.once.Do(func() {
+		close(c.done)
+		deadline := time.Now().Add(wsWriteWait)
+		c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+		c.conn.Close()
+	})
+}
+
+func (c *wsConn) writeLoop() {
+	ping := time.NewTicker(c.handler.Options.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case frame := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteJSON(frame); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
+
+func (c *wsConn) readLoop() {
+	// клиент обязан отвечать на ping, иначе соединение считается оборванным
+	pongWait := 2 * c.handler.Options.PingInterval
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Warn("WS: Соединение оборвано", zap.Error(err))
+			}
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		// невалидный кадр не рвёт соединение
+		var request dto.WSRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			c.push(wsError("", "BAD_REQUEST", "неверный кадр: "+err.Error()))
+			continue
+		}
+
+		c.push(c.handle(request))
+	}
+}
+
+// handle выполняет запрос клиента. Мутации идут через Service,
+// поэтому бизнес-ошибки те же, что и в HTTP API
+func (c *wsConn) handle(request dto.WSRequest) dto.WSResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), wsRequestTimeout)
+	defer cancel()
+
+	switch request.Type {
+	case WSPing:
+		return dto.WSResponse{ID: request.ID, Type: WSPong}
+
+	case WSSubscribe:
+		ids := request.TaskIDs
+		if request.TaskID != uuid.Nil {
+			ids = append(ids, request.TaskID)
+		}
+		if len(ids) == 0 {
+			return wsError(request.ID, "VALIDATION_ERROR", "не переданы task_ids")
+		}
+		return c.subscribe(request.ID, ids)
+
+	case WSUnsubscribe:
+		ids := request.TaskIDs
+		if request.TaskID != uuid.Nil {
+			ids = append(ids, request.TaskID)
+		}
+		c.mtx.Lock()
+		for _, id := range ids {
+			delete(c.subscribed, id)
+		}
+		c.mtx.Unlock()
+		return dto.WSResponse{ID: request.ID, Type: WSResult, Result: map[string]any{"unsubscribed": ids}}
+	}
+
+	if request.TaskID == uuid.Nil {
+		switch request.Type {
+		case WSGet, WSUpdate, WSArchive, WSUnarchive, WSDelete:
+			return wsError(request.ID, "VALIDATION_ERROR", "не передан task_id")
+		}
+	}
+
+	svc := c.handler.TaskService
+	var (
+		result *task.Task
+		err    error
+	)
+
+	switch request.Type {
+	case WSGet:
+		result, err = svc.GetTaskByID(ctx, request.TaskID)
+	case WSUpdate:
+		if request.Update == nil {
+			return wsError(request.ID, "VALIDATION_ERROR", "не передано поле update")
+		}
+		opts, optsErr := updateOptions(*request.Update)
+		if optsErr != nil {
+			return wsError(request.ID, "VALIDATION_ERROR", optsErr.Error())
+		}
+		result, err = svc.UpdateTask(ctx, request.TaskID, opts...)
+	case WSArchive:
+		result, err = svc.ArchiveTask(ctx, request.TaskID)
+	case WSUnarchive:
+		result, err = svc.UnarchiveTask(ctx, request.TaskID)
+	case WSDelete:
+		err = svc.DeleteTask(ctx, request.TaskID)
+	default:
+		return wsError(request.ID, "BAD_REQUEST", "неизвестный тип кадра: "+request.Type)
+	}
+
+	if err != nil {
+		return wsBusinessError(request.ID, request.Type, err)
+	}
+	if result == nil {
+		return dto.WSResponse{ID: request.ID, Type: WSResult, Result: map[string]any{"task_id": request.TaskID}}
+	}
+	return dto.WSResponse{ID: request.ID, Type: WSResult, Result: dto.FromTask(result)}
+}
+
+func (c *wsConn) subscribe(requestID string, ids []uuid.UUID) dto.WSResponse {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	added := 0
+	for _, id := range ids {
+		if !c.subscribed[id] {
+			added++
+		}
+	}
+	if len(c.subscribed)+added > c.handler.Options.MaxSubscriptions {
+		return wsError(requestID, "VALIDATION_ERROR", "превышено число подписок на соединение")
+	}
+
+	for _, id := range ids {
+		c.subscribed[id] = true
+	}
+	return dto.WSResponse{ID: requestID, Type: WSResult, Result: map[string]any{"subscribed": ids}}
+}
+
+func wsError(requestID, code, message string) dto.WSResponse {
+	return dto.WSResponse{
+		ID:    requestID,
+		Type:  WSError,
+		Error: &dto.WSError{Code: code, Message: message},
+	}
+}
+
+func wsBusinessError(requestID, operation string, err error) dto.WSResponse {
+	var businessErr *service.BusinessError
+	if errors.As(err, &businessErr) {
+		logger.Warn("WS: Бизнес-ошибка",
+			zap.String("operation", operation),
+			zap.String("error_code", businessErr.Code))
+		return dto.WSResponse{
+			ID:   requestID,
+			Type: WSError,
+			Error: &dto.WSError{
+				Code:    businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			},
+		}
+	}
+
+	logger.Error("WS: ошибка в Service", err, zap.String("operation", operation))
+	return wsError(requestID, "INTERNAL_ERROR", "внутренняя ошибка сервера")
+}
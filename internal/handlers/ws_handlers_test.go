@@ -0,0 +1,171 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"taskTracker/internal/events"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/middleware"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"taskTracker/internal/stream"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+type wsFixture struct {
+	server  *httptest.Server
+	service *service.TaskService
+	hub     *stream.Hub
+}
+
+func newWSFixture(t *testing.T) *wsFixture {
+	t.Helper()
+
+	bus := events.NewBus()
+	hub := stream.NewHub(stream.HubOptions{})
+	bus.Subscribe(hub.Handle)
+
+	svc := service.NewTaskService(inmemory.NewTaskStorage(), "inmemory", service.WithPublisher(bus))
+	handler := handlers.NewWSHandler(&svc, hub, handlers.WSOptions{MaxSubscriptions: 2})
+
+	server := httptest.NewServer(middleware.Logging(http.HandlerFunc(handler.ServeWS)))
+	t.Cleanup(server.Close)
+
+	return &wsFixture{server: server, service: &svc, hub: hub}
+}
+
+func (f *wsFixture) dial(t *testing.T) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(f.server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func roundTrip(t *testing.T, conn *websocket.Conn, request dto.WSRequest) map[string]any {
+	t.Helper()
+
+	require.NoError(t, conn.WriteJSON(request))
+	return readFrame(t, conn)
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var frame map[string]any
+	require.NoError(t, conn.ReadJSON(&frame))
+	return frame
+}
+
+// TestWSHandler_Mutations тестирует мутации через WebSocket с корреляцией ответов
+func TestWSHandler_Mutations(t *testing.T) {
+	f := newWSFixture(t)
+	conn := f.dial(t)
+
+	created, err := f.service.CreateTask(t.Context(), "Задача", "", time.Now().Add(48*time.Hour))
+	require.NoError(t, err)
+
+	t.Run("update", func(t *testing.T) {
+		title := "Новое название"
+		frame := roundTrip(t, conn, dto.WSRequest{
+			ID:     "req-1",
+			Type:   handlers.WSUpdate,
+			TaskID: created.UUID,
+			Update: &dto.UpdateTaskRequest{Title: &title},
+		})
+
+		assert.Equal(t, "req-1", frame["id"])
+		assert.Equal(t, handlers.WSResult, frame["type"])
+		result := frame["result"].(map[string]any)
+		assert.Equal(t, title, result["title"])
+	})
+
+	t.Run("archive twice returns business error", func(t *testing.T) {
+		frame := roundTrip(t, conn, dto.WSRequest{ID: "req-2", Type: handlers.WSArchive, TaskID: created.UUID})
+		assert.Equal(t, handlers.WSResult, frame["type"])
+
+		frame = roundTrip(t, conn, dto.WSRequest{ID: "req-3", Type: handlers.WSArchive, TaskID: created.UUID})
+		assert.Equal(t, "req-3", frame["id"])
+		assert.Equal(t, handlers.WSError, frame["type"])
+		assert.Equal(t, "ALREADY_ARCHIVED", frame["error"].(map[string]any)["code"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		frame := roundTrip(t, conn, dto.WSRequest{ID: "req-4", Type: handlers.WSDelete, TaskID: uuid.New()})
+		assert.Equal(t, handlers.WSError, frame["type"])
+		assert.Equal(t, "NOT_FOUND", frame["error"].(map[string]any)["code"])
+	})
+
+	t.Run("bad frames keep connection open", func(t *testing.T) {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+		frame := readFrame(t, conn)
+		assert.Equal(t, "BAD_REQUEST", frame["error"].(map[string]any)["code"])
+
+		frame = roundTrip(t, conn, dto.WSRequest{ID: "req-5", Type: "explode"})
+		assert.Equal(t, "BAD_REQUEST", frame["error"].(map[string]any)["code"])
+
+		frame = roundTrip(t, conn, dto.WSRequest{ID: "req-6", Type: handlers.WSPing})
+		assert.Equal(t, handlers.WSPong, frame["type"])
+	})
+}
+
+// TestWSHandler_Subscriptions тестирует доставку событий только по подписанным задачам
+func TestWSHandler_Subscriptions(t *testing.T) {
+	f := newWSFixture(t)
+	watcher := f.dial(t)
+
+	watched, err := f.service.CreateTask(t.Context(), "Отслеживаемая", "", time.Now().Add(48*time.Hour))
+	require.NoError(t, err)
+	other, err := f.service.CreateTask(t.Context(), "Другая", "", time.Now().Add(48*time.Hour))
+	require.NoError(t, err)
+
+	frame := roundTrip(t, watcher, dto.WSRequest{ID: "sub", Type: handlers.WSSubscribe, TaskIDs: []uuid.UUID{watched.UUID}})
+	require.Equal(t, handlers.WSResult, frame["type"])
+
+	frame = roundTrip(t, watcher, dto.WSRequest{ID: "too-many", Type: handlers.WSSubscribe,
+		TaskIDs: []uuid.UUID{uuid.New(), uuid.New()}})
+	assert.Equal(t, "VALIDATION_ERROR", frame["error"].(map[string]any)["code"])
+
+	// мутация другого клиента приходит подписчику событием
+	actor := f.dial(t)
+	roundTrip(t, actor, dto.WSRequest{ID: "a-1", Type: handlers.WSArchive, TaskID: other.UUID})
+	roundTrip(t, actor, dto.WSRequest{ID: "a-2", Type: handlers.WSArchive, TaskID: watched.UUID})
+
+	frame = readFrame(t, watcher)
+	assert.Equal(t, handlers.WSEvent, frame["type"])
+	event := frame["event"].(map[string]any)
+	assert.Equal(t, string(events.TaskArchived), event["type"])
+	assert.Equal(t, watched.UUID.String(), event["task_id"])
+}
+
+// TestWSHandler_HubClose тестирует закрытие соединений при остановке сервера
+func TestWSHandler_HubClose(t *testing.T) {
+	f := newWSFixture(t)
+	conn := f.dial(t)
+
+	require.Eventually(t, func() bool { return f.hub.Clients() == 1 }, time.Second, 10*time.Millisecond)
+	f.hub.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "ошибка: %v", err)
+}
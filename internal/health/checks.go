@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/metrics"
+	"taskTracker/internal/outbox"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Database проверяет хранилище через ping. stat - статистика пула pgx,
+// nil для хранилищ без пула
+func Database(ping func(context.Context) error, stat func() *pgxpool.Stat) Check {
+	return func(ctx context.Context) (map[string]any, error) {
+		var details map[string]any
+		if stat != nil {
+			s := stat()
+			details = map[string]any{
+				"total_conns":    s.TotalConns(),
+				"idle_conns":     s.IdleConns(),
+				"acquired_conns": s.AcquiredConns(),
+				"max_conns":      s.MaxConns(),
+				"wait_count":     s.EmptyAcquireCount(),
+			}
+		}
+		return details, ping(ctx)
+	}
+}
+
+// Worker проверяет последний запуск периодического воркера. Воркер считается
+// зависшим, если не запускался дольше трёх интервалов
+func Worker(name string, interval time.Duration) Check {
+	return func(ctx context.Context) (map[string]any, error) {
+		run, ok := metrics.LastWorkerRun(name)
+		if !ok {
+			return map[string]any{"started": false}, nil
+		}
+
+		details := map[string]any{
+			"last_run":     run.At,
+			"last_success": run.LastSuccess,
+		}
+		if run.Err != nil {
+			details["last_error"] = run.Err.Error()
+			return details, fmt.Errorf("последний запуск завершился ошибкой")
+		}
+		if since := time.Since(run.At); since > 3*interval {
+			return details, fmt.Errorf("воркер не запускался %s", since.Round(time.Second))
+		}
+		return details, nil
+	}
+}
+
+// Outbox проверяет число неопубликованных событий. Растущий хвост значит,
+// что релей не успевает или публикация падает
+func Outbox(counter outbox.BacklogCounter, maxBacklog int64) Check {
+	return func(ctx context.Context) (map[string]any, error) {
+		pending, err := counter.OutboxBacklog(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		details := map[string]any{"pending": pending, "max_pending": maxBacklog}
+		if pending > maxBacklog {
+			return details, fmt.Errorf("неопубликованных событий %d, порог %d", pending, maxBacklog)
+		}
+		return details, nil
+	}
+}
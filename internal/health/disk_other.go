@@ -0,0 +1,12 @@
+//go:build !unix
+
+package health
+
+import "context"
+
+// DiskSpace на этой платформе только сообщает путь: statfs недоступен
+func DiskSpace(path string, minFree uint64) Check {
+	return func(ctx context.Context) (map[string]any, error) {
+		return map[string]any{"path": path, "supported": false}, nil
+	}
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// DiskSpace проверяет свободное место в файловой системе с path:
+// снапшоты и журнал inmemory, файл SQLite
+func DiskSpace(path string, minFree uint64) Check {
+	return func(ctx context.Context) (map[string]any, error) {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(path, &stat); err != nil {
+			return nil, fmt.Errorf("statfs %s: %w", path, err)
+		}
+
+		free := uint64(stat.Bavail) * uint64(stat.Bsize)
+		total := uint64(stat.Blocks) * uint64(stat.Bsize)
+		details := map[string]any{
+			"path":        path,
+			"free_bytes":  free,
+			"total_bytes": total,
+			"min_free":    minFree,
+		}
+		if free < minFree {
+			return details, fmt.Errorf("свободно %d байт, нужно не меньше %d", free, minFree)
+		}
+		return details, nil
+	}
+}
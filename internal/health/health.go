@@ -0,0 +1,302 @@
+// Package health - пробы для балансировщика и оркестратора:
+//   - /livez - процесс жив и обслуживает HTTP, зависимости не проверяются;
+//   - /readyz - приложение готово принимать трафик: запуск завершён,
+//     остановка не началась и критичные проверки (база) проходят;
+//   - /health/details - подробный отчёт по каждой зависимости, только по токену.
+//
+// Результаты проверок кэшируются, поэтому частые пробы не нагружают базу
+package health
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/service"
+	"time"
+)
+
+// Check проверяет одну зависимость. details попадают в подробный отчёт
+// и при ошибке, например статистика пула рядом с ошибкой ping
+type Check func(ctx context.Context) (details map[string]any, err error)
+
+// Состояние приложения
+type State int32
+
+const (
+	StateStarting State = iota
+	StateReady
+	StateDraining
+)
+
+func (s State) String() string {
+	switch s {
+	case StateReady:
+		return "ready"
+	case StateDraining:
+		return "draining"
+	default:
+		return "starting"
+	}
+}
+
+// Статус одной проверки
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Общий статус отчёта: degraded - упала некритичная проверка
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
+var ErrTimeout = errors.New("проверка не уложилась в таймаут")
+
+type Options struct {
+	// Timeout - таймаут одной проверки
+	Timeout time.Duration
+	// CacheTTL - сколько переиспользуется результат проверки
+	CacheTTL time.Duration
+	// Token открывает /health/details. Пустой токен закрывает эндпоинт
+	Token string
+}
+
+type Result struct {
+	Status    string         `json:"status"`
+	Critical  bool           `json:"critical"`
+	Error     string         `json:"error,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+	Duration  float64        `json:"duration_ms"`
+	CheckedAt time.Time      `json:"checked_at"`
+}
+
+type Report struct {
+	Status    string            `json:"status"`
+	State     string            `json:"state"`
+	Checks    map[string]Result `json:"checks"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+type check struct {
+	name     string
+	fn       Check
+	critical bool
+
+	// mtx держится на время проверки: одновременные пробы ждут одну проверку
+	mtx     sync.Mutex
+	result  Result
+	expires time.Time
+}
+
+type Checker struct {
+	opts  Options
+	state atomic.Int32
+
+	mtx    sync.RWMutex
+	checks []*check
+}
+
+func New(opts Options) *Checker {
+	return &Checker{opts: opts}
+}
+
+// Register добавляет проверку. Критичные проверки влияют на /readyz
+func (c *Checker) Register(name string, fn Check, critical bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.checks = append(c.checks, &check{name: name, fn: fn, critical: critical})
+}
+
+func (c *Checker) State() State {
+	return State(c.state.Load())
+}
+
+// SetReady вызывается, когда приложение готово принимать трафик
+func (c *Checker) SetReady() {
+	c.state.Store(int32(StateReady))
+}
+
+// SetDraining переводит /readyz в 503 в начале остановки, чтобы балансировщик
+// успел снять экземпляр до закрытия соединений
+func (c *Checker) SetDraining() {
+	c.state.Store(int32(StateDraining))
+}
+
+// Run выполняет проверки параллельно. criticalOnly - только критичные, для /readyz
+func (c *Checker) Run(ctx context.Context, criticalOnly bool) Report {
+	c.mtx.RLock()
+	checks := make([]*check, 0, len(c.checks))
+	for _, ch := range c.checks {
+		if ch.critical || !criticalOnly {
+			checks = append(checks, ch)
+		}
+	}
+	c.mtx.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, ch)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:    StatusHealthy,
+		State:     c.State().String(),
+		Checks:    make(map[string]Result, len(checks)),
+		Timestamp: time.Now(),
+	}
+	for i, ch := range checks {
+		result := results[i]
+		report.Checks[ch.name] = result
+		if result.Status == StatusUp {
+			continue
+		}
+		if ch.critical {
+			report.Status = StatusUnhealthy
+		} else if report.Status == StatusHealthy {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, ch *check) Result {
+	ch.mtx.Lock()
+	defer ch.mtx.Unlock()
+
+	now := time.Now()
+	if now.Before(ch.expires) {
+		return ch.result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	type outcome struct {
+		details map[string]any
+		err     error
+	}
+	// буфер, чтобы зависшая проверка не держала горутину после таймаута
+	done := make(chan outcome, 1)
+	go func() {
+		details, err := ch.fn(ctx)
+		done <- outcome{details, err}
+	}()
+
+	var out outcome
+	select {
+	case out = <-done:
+	case <-ctx.Done():
+		out.err = ErrTimeout
+	}
+
+	result := Result{
+		Status:    StatusUp,
+		Critical:  ch.critical,
+		Details:   out.details,
+		Duration:  float64(time.Since(now).Microseconds()) / 1000,
+		CheckedAt: now,
+	}
+	if out.err != nil {
+		result.Status = StatusDown
+		result.Error = out.err.Error()
+	}
+
+	ch.result = result
+	ch.expires = now.Add(c.opts.CacheTTL)
+	return result
+}
+
+// Live - /livez: ответ означает, что процесс жив
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status": "alive",
+		"state":  c.State().String(),
+	})
+}
+
+// Ready - /readyz: 503 при запуске, при остановке и при падении критичных проверок
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	state := c.State()
+	if state != StateReady {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{
+			"status": "not_ready",
+			"state":  state.String(),
+		})
+		return
+	}
+
+	report := c.Run(r.Context(), true)
+	checks := make(map[string]string, len(report.Checks))
+	for name, result := range report.Checks {
+		checks[name] = result.Status
+	}
+
+	status, code := "ready", http.StatusOK
+	if report.Status == StatusUnhealthy {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]any{
+		"status": status,
+		"state":  report.State,
+		"checks": checks,
+	})
+}
+
+// Details - /health/details: полный отчёт по токену из заголовка Authorization
+func (c *Checker) Details(w http.ResponseWriter, r *http.Request) {
+	if !c.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="health"`)
+		problem.Write(w, r, service.NewBusinessError(problem.CodeUnauthorized,
+			"Нужен токен подробной проверки здоровья"))
+		return
+	}
+
+	report := c.Run(r.Context(), false)
+	code := http.StatusOK
+	if report.Status == StatusUnhealthy || c.State() != StateReady {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+func (c *Checker) authorized(r *http.Request) bool {
+	if c.opts.Token == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(c.opts.Token)) == 1
+}
+
+// StartupHandler отвечает на пробы, пока роутер приложения не собран.
+// Остальные запросы получают 503
+func (c *Checker) StartupHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /livez", c.Live)
+	mux.HandleFunc("GET /readyz", c.Ready)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		problem.Write(w, r, service.NewBusinessError(problem.CodeUnavailable, "Сервис запускается"))
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
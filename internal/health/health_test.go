@@ -0,0 +1,169 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"taskTracker/internal/health"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
+	"taskTracker/internal/outbox"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func get(t *testing.T, handler http.HandlerFunc, token string) (int, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	return rec.Code, body
+}
+
+// TestChecker_Ready тестирует /readyz при запуске, работе, сбое базы и остановке
+func TestChecker_Ready(t *testing.T) {
+	var dbDown atomic.Bool
+	c := health.New(health.Options{Timeout: time.Second})
+	c.Register("database", func(ctx context.Context) (map[string]any, error) {
+		if dbDown.Load() {
+			return nil, errors.New("connection refused")
+		}
+		return nil, nil
+	}, true)
+	c.Register("disk", func(ctx context.Context) (map[string]any, error) {
+		return nil, errors.New("мало места")
+	}, false)
+
+	code, body := get(t, c.Ready, "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "starting", body["state"])
+
+	c.SetReady()
+	code, body = get(t, c.Ready, "")
+	assert.Equal(t, http.StatusOK, code, "некритичная проверка не влияет на готовность")
+	assert.Equal(t, map[string]any{"database": "up"}, body["checks"])
+
+	dbDown.Store(true)
+	code, _ = get(t, c.Ready, "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	dbDown.Store(false)
+	c.SetDraining()
+	code, body = get(t, c.Ready, "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "draining", body["state"])
+
+	code, _ = get(t, c.Live, "")
+	assert.Equal(t, http.StatusOK, code, "livez не зависит от состояния")
+}
+
+// TestChecker_Details тестирует токен и содержимое подробного отчёта
+func TestChecker_Details(t *testing.T) {
+	c := health.New(health.Options{Timeout: time.Second, Token: "secret"})
+	c.Register("database", health.Database(func(context.Context) error { return nil }, nil), true)
+	c.Register("outbox", health.Outbox(outbox.NewMemory(), 10), false)
+	c.Register("disk", health.DiskSpace(t.TempDir(), 1<<62), false)
+	c.SetReady()
+
+	code, _ := get(t, c.Details, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = get(t, c.Details, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, body := get(t, c.Details, "secret")
+	assert.Equal(t, http.StatusOK, code, "упала только некритичная проверка")
+	assert.Equal(t, health.StatusDegraded, body["status"])
+
+	checks := body["checks"].(map[string]any)
+	outboxCheck := checks["outbox"].(map[string]any)
+	assert.Equal(t, health.StatusUp, outboxCheck["status"])
+	assert.EqualValues(t, 0, outboxCheck["details"].(map[string]any)["pending"])
+
+	disk := checks["disk"].(map[string]any)
+	assert.Equal(t, health.StatusDown, disk["status"])
+	assert.Contains(t, disk["details"], "free_bytes")
+
+	// пустой токен закрывает эндпоинт
+	closed := health.New(health.Options{Timeout: time.Second})
+	code, _ = get(t, closed.Details, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+// TestChecker_TimeoutAndCache тестирует таймаут проверки и кэширование результата
+func TestChecker_TimeoutAndCache(t *testing.T) {
+	var calls atomic.Int32
+	c := health.New(health.Options{Timeout: 50 * time.Millisecond, CacheTTL: time.Minute})
+	c.Register("slow", func(ctx context.Context) (map[string]any, error) {
+		calls.Add(1)
+		time.Sleep(time.Second)
+		return nil, nil
+	}, true)
+
+	start := time.Now()
+	report := c.Run(context.Background(), false)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, health.StatusUnhealthy, report.Status)
+	assert.Equal(t, health.ErrTimeout.Error(), report.Checks["slow"].Error)
+
+	for i := 0; i < 5; i++ {
+		c.Run(context.Background(), true)
+	}
+	assert.EqualValues(t, 1, calls.Load(), "результат берётся из кэша")
+}
+
+// TestWorker тестирует проверку последнего запуска воркера
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+
+	details, err := health.Worker("health-test-idle", time.Second)(ctx)
+	assert.NoError(t, err, "воркер ещё не запускался")
+	assert.Equal(t, false, details["started"])
+
+	metrics.ObserveWorkerRun("health-test", time.Now(), 1, nil)
+	_, err = health.Worker("health-test", time.Second)(ctx)
+	assert.NoError(t, err)
+
+	metrics.ObserveWorkerRun("health-test", time.Now(), 0, errors.New("db down"))
+	details, err = health.Worker("health-test", time.Second)(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "db down", details["last_error"])
+
+	metrics.ObserveWorkerRun("health-test-stale", time.Now().Add(-time.Hour), 1, nil)
+	_, err = health.Worker("health-test-stale", time.Second)(ctx)
+	assert.Error(t, err, "воркер завис")
+}
+
+// TestStartupHandler тестирует ответы до сборки роутера
+func TestStartupHandler(t *testing.T) {
+	handler := health.New(health.Options{Timeout: time.Second}).StartupHandler()
+
+	for path, want := range map[string]int{
+		"/livez":  http.StatusOK,
+		"/readyz": http.StatusServiceUnavailable,
+		"/tasks":  http.StatusServiceUnavailable,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, rec.Code, path)
+	}
+}
@@ -0,0 +1,79 @@
+// Package history - журнал состояний задач для отчётов. Каждое событие задачи
+// оставляет запись о статусе, флаге и оценках после него, поэтому графики за
+// прошлые даты строятся по тому, что было тогда, а не по текущему состоянию
+package history
+
+import (
+	"taskTracker/internal/events"
+	"taskTracker/internal/models/task"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Record - состояние задачи после события. EventID делает запись
+// идемпотентной: повторная доставка события её не дублирует
+type Record struct {
+	EventID           uuid.UUID
+	TaskID            uuid.UUID
+	Event             events.Type
+	Status            task.Status
+	Flag              task.Flag
+	OriginalEstimate  *float64
+	RemainingEstimate *float64
+	OccurredAt        time.Time
+}
+
+// FromEvent снимает запись с события. Безвозвратно удалённая задача
+// считается удалённой, какой бы флаг ни был в снимке
+func FromEvent(e events.Event) Record {
+	r := Record{
+		EventID:    e.ID,
+		TaskID:     e.TaskID,
+		Event:      e.Type,
+		OccurredAt: e.OccurredAt,
+	}
+	if e.Task != nil {
+		r.Status = e.Task.Status
+		r.Flag = e.Task.Flag
+		r.OriginalEstimate = e.Task.OriginalEstimate
+		r.RemainingEstimate = e.Task.RemainingEstimate
+	}
+	if e.Type == events.TaskPurged {
+		r.Flag = task.FlagDeleted
+	}
+	return r
+}
+
+// InScope - задача входит в объём работ: не удалена, не отменена и не
+// отложена в архив невыполненной
+func (r Record) InScope() bool {
+	return r.Flag != task.FlagDeleted && r.Status != task.StatusCancelled &&
+		(r.Flag == task.FlagActive || r.Done())
+}
+
+func (r Record) Done() bool {
+	return r.Status == task.StatusDone && r.Flag != task.FlagDeleted
+}
+
+// Size - объём задачи: исходная оценка, а без неё - оставшаяся
+func (r Record) Size() float64 {
+	switch {
+	case r.OriginalEstimate != nil:
+		return *r.OriginalEstimate
+	case r.RemainingEstimate != nil:
+		return *r.RemainingEstimate
+	}
+	return 0
+}
+
+// Remaining - оставшаяся работа, у закрытой задачи ноль
+func (r Record) Remaining() float64 {
+	if r.Status.Closed() {
+		return 0
+	}
+	if r.RemainingEstimate != nil {
+		return *r.RemainingEstimate
+	}
+	return r.Size()
+}
@@ -0,0 +1,183 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"taskTracker/internal/events"
+	"taskTracker/internal/history"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func estimate(v float64) *float64 {
+	return &v
+}
+
+// journal публикует события задач в сервис, как шина событий
+type journal struct {
+	svc *history.Service
+}
+
+func (j journal) emit(at time.Time, eventType events.Type, tsk task.Task) {
+	e := events.New(eventType, &tsk)
+	e.OccurredAt = at
+	j.svc.Handle(context.Background(), e)
+}
+
+func newJournal(t *testing.T) journal {
+	svc, err := history.NewService(history.NewMemoryStore(), history.UnitPoints)
+	require.NoError(t, err)
+	return journal{svc: svc}
+}
+
+// TestService_Burndown тестирует серии burndown и burnup по истории, а не по текущему состоянию
+func TestService_Burndown(t *testing.T) {
+	j := newJournal(t)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return from.Add(time.Duration(day*24+hour) * time.Hour) }
+
+	first := task.Task{UUID: uuid.New(), Status: task.StatusNew, Flag: task.FlagActive, OriginalEstimate: estimate(5)}
+	second := task.Task{UUID: uuid.New(), Status: task.StatusNew, Flag: task.FlagActive, OriginalEstimate: estimate(3)}
+	// до начала интервала
+	j.emit(at(-1, 10), events.TaskCreated, first)
+	j.emit(at(-1, 11), events.TaskCreated, second)
+
+	// день 0: остаток первой задачи пересмотрен
+	first.Status, first.RemainingEstimate = task.StatusInProgress, estimate(2)
+	j.emit(at(0, 12), events.TaskUpdated, first)
+	// день 1: вторая выполнена, добавлена третья
+	second.Status = task.StatusDone
+	j.emit(at(1, 9), events.TaskUpdated, second)
+	third := task.Task{UUID: uuid.New(), Status: task.StatusNew, Flag: task.FlagActive, RemainingEstimate: estimate(4)}
+	j.emit(at(1, 15), events.TaskCreated, third)
+	// день 2: третью удалили, отменённая задача в объём не входит
+	third.Flag = task.FlagDeleted
+	j.emit(at(2, 8), events.TaskDeleted, third)
+	cancelled := task.Task{UUID: uuid.New(), Status: task.StatusCancelled, Flag: task.FlagActive, OriginalEstimate: estimate(10)}
+	j.emit(at(2, 9), events.TaskCreated, cancelled)
+
+	report, err := j.svc.Burndown(context.Background(), from.Add(3*time.Hour), at(3, 20))
+	require.NoError(t, err)
+	assert.Equal(t, from, report.From)
+	assert.Equal(t, at(4, 0), report.To)
+	assert.Equal(t, history.UnitPoints, report.Unit)
+
+	require.Len(t, report.Points, 4)
+	got := make([][4]float64, len(report.Points))
+	for i, p := range report.Points {
+		got[i] = [4]float64{p.Remaining, p.Ideal, p.Scope, p.Completed}
+	}
+	assert.Equal(t, [][4]float64{
+		{5, 6, 8, 0},  // 2 + 3
+		{6, 4, 12, 3}, // 2 + 4, вторая выполнена
+		{2, 2, 8, 3},  // третья удалена
+		{2, 0, 8, 3},
+	}, got)
+	assert.Equal(t, 1, report.Points[3].Open)
+	assert.Equal(t, 1, report.Points[3].Done)
+
+	// будущие дни не строятся
+	report, err = j.svc.Burndown(context.Background(), time.Now().Add(-24*time.Hour), time.Now().Add(5*24*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, report.Points, 2)
+}
+
+// TestService_Velocity тестирует выполненную работу по неделям с учётом переоткрытия
+func TestService_Velocity(t *testing.T) {
+	j := newJournal(t)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(day int) time.Time { return monday.Add(time.Duration(day)*24*time.Hour + 12*time.Hour) }
+
+	newTask := func(size float64) task.Task {
+		return task.Task{UUID: uuid.New(), Status: task.StatusInProgress, Flag: task.FlagActive, OriginalEstimate: estimate(size)}
+	}
+	done, reopened, finishedLater, deleted := newTask(3), newTask(5), newTask(8), newTask(2)
+	for _, tsk := range []task.Task{done, reopened, finishedLater, deleted} {
+		j.emit(at(-3), events.TaskCreated, tsk)
+	}
+
+	complete := func(day int, tsk *task.Task) {
+		tsk.Status = task.StatusDone
+		j.emit(at(day), events.TaskUpdated, *tsk)
+	}
+	complete(1, &done)
+	complete(2, &reopened)
+	reopened.Status = task.StatusInProgress
+	j.emit(at(3), events.TaskUpdated, reopened)
+	complete(4, &finishedLater)
+	complete(10, &finishedLater) // уже выполненная задача не засчитывается снова
+	complete(5, &deleted)
+	deleted.Flag = task.FlagDeleted
+	j.emit(at(9), events.TaskDeleted, deleted)
+
+	report, err := j.svc.Velocity(context.Background(), at(2), at(12))
+	require.NoError(t, err)
+	assert.Equal(t, monday, report.From)
+	assert.Equal(t, monday.Add(14*24*time.Hour), report.To)
+	require.Len(t, report.Weeks, 2)
+	assert.Equal(t, history.Week{Start: monday, Completed: 11, Tasks: 2}, report.Weeks[0])
+	assert.Equal(t, history.Week{Start: monday.Add(7 * 24 * time.Hour), Completed: 0, Tasks: 0}, report.Weeks[1])
+	assert.Equal(t, 5.5, report.Average)
+}
+
+// TestMemoryStore_Idempotent тестирует повторную доставку события
+func TestMemoryStore_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	store := history.NewMemoryStore()
+	now := time.Now()
+
+	r := history.Record{EventID: uuid.New(), TaskID: uuid.New(), Status: task.StatusNew, Flag: task.FlagActive, OccurredAt: now}
+	require.NoError(t, store.AppendHistory(ctx, r))
+	require.NoError(t, store.AppendHistory(ctx, r))
+	// событие из outbox может прийти позже более нового
+	earlier := r
+	earlier.EventID, earlier.Status, earlier.OccurredAt = uuid.New(), task.StatusBlocked, now.Add(-time.Minute)
+	require.NoError(t, store.AppendHistory(ctx, earlier))
+
+	records, err := store.ListHistory(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, task.StatusBlocked, records[0].Status)
+
+	state, err := store.HistoryState(ctx, now.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+	assert.Equal(t, task.StatusNew, state[0].Status)
+}
+
+// TestNewService_Unit тестирует проверку единицы оценок
+func TestNewService_Unit(t *testing.T) {
+	_, err := history.NewService(history.NewMemoryStore(), "days")
+	assert.Error(t, err)
+}
+
+// failingStore не принимает записи, как недоступная база
+type failingStore struct {
+	*history.MemoryStore
+}
+
+func (failingStore) AppendHistory(ctx context.Context, r history.Record) error {
+	return errors.New("connection refused")
+}
+
+// TestService_HandleError тестирует возврат ошибки записи шине для повторной доставки
+func TestService_HandleError(t *testing.T) {
+	svc, err := history.NewService(failingStore{history.NewMemoryStore()}, history.UnitPoints)
+	require.NoError(t, err)
+
+	e := events.New(events.TaskCreated, &task.Task{UUID: uuid.New(), Status: task.StatusNew, Flag: task.FlagActive})
+	assert.ErrorContains(t, svc.Handle(context.Background(), e), "connection refused")
+}
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"taskTracker/internal/stats"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Point - состояние объёма работ на конец дня Day (UTC).
+// Remaining и Ideal - линии burndown, Scope и Completed - линии burnup
+type Point struct {
+	Day       time.Time
+	Remaining float64
+	Ideal     float64
+	Scope     float64
+	Completed float64
+	Open      int
+	Done      int
+}
+
+// Week - выполненная за неделю работа. Задача засчитывается в неделю
+// последнего выполнения, переоткрытая и не выполненная снова не считается
+type Week struct {
+	Start     time.Time
+	Completed float64
+	Tasks     int
+}
+
+// Burndown строит точки по дням [from, to) по состояниям задач на from и
+// изменениям после него. Дни после now не строятся, но идеальная линия
+// доходит до нуля к концу to
+func Burndown(state, changes []Record, from, to, now time.Time) []Point {
+	current := make(map[uuid.UUID]Record, len(state))
+	for _, r := range state {
+		current[r.TaskID] = r
+	}
+
+	var initial float64
+	for _, r := range current {
+		if r.InScope() {
+			initial += r.Remaining()
+		}
+	}
+
+	days := int(to.Sub(from) / stats.DayLength)
+	points := []Point{}
+	next := 0
+	for i := range days {
+		start := from.Add(time.Duration(i) * stats.DayLength)
+		if start.After(now) {
+			break
+		}
+		cutoff := start.Add(stats.DayLength)
+		if cutoff.After(now) {
+			cutoff = now
+		}
+		for next < len(changes) && changes[next].OccurredAt.Before(cutoff) {
+			current[changes[next].TaskID] = changes[next]
+			next++
+		}
+
+		p := Point{Day: start, Ideal: initial * float64(days-i-1) / float64(days)}
+		for _, r := range current {
+			if !r.InScope() {
+				continue
+			}
+			p.Scope += r.Size()
+			if r.Done() {
+				p.Completed += r.Size()
+				p.Done++
+			} else {
+				p.Remaining += r.Remaining()
+				p.Open++
+			}
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// Velocity считает выполненную работу по неделям [from, to). from должен
+// быть началом недели
+func Velocity(state, changes []Record, from, to time.Time) []Week {
+	done := make(map[uuid.UUID]bool, len(state))
+	for _, r := range state {
+		done[r.TaskID] = r.Done()
+	}
+
+	type completion struct {
+		at   time.Time
+		size float64
+	}
+	completions := make(map[uuid.UUID]completion)
+	for _, r := range changes {
+		switch {
+		case r.Done() && !done[r.TaskID]:
+			completions[r.TaskID] = completion{r.OccurredAt, r.Size()}
+		case !r.Done() && done[r.TaskID]:
+			// переоткрыли или удалили: прежнее выполнение не в счёт
+			delete(completions, r.TaskID)
+		}
+		done[r.TaskID] = r.Done()
+	}
+
+	weeks := make([]Week, int(to.Sub(from)/(7*stats.DayLength)))
+	for i := range weeks {
+		weeks[i].Start = from.Add(time.Duration(i) * 7 * stats.DayLength)
+	}
+	for _, c := range completions {
+		i := int(c.at.Sub(from) / (7 * stats.DayLength))
+		if i >= 0 && i < len(weeks) {
+			weeks[i].Completed += c.size
+			weeks[i].Tasks++
+		}
+	}
+	return weeks
+}
+
+// StartOfWeek - понедельник недели t в UTC
+func StartOfWeek(t time.Time) time.Time {
+	d := t.UTC().Truncate(stats.DayLength)
+	offset := (int(d.Weekday()) + 6) % 7
+	return d.Add(-time.Duration(offset) * stats.DayLength)
+}
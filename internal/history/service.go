@@ -0,0 +1,139 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/service"
+	"taskTracker/internal/stats"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Единицы оценок
+const (
+	UnitHours  = "hours"
+	UnitPoints = "points"
+)
+
+// самый длинный интервал отчёта
+const maxReportRange = 366 * stats.DayLength
+
+// Service пишет журнал по событиям задач и строит по нему отчёты
+type Service struct {
+	store Store
+	unit  string
+}
+
+func NewService(store Store, unit string) (*Service, error) {
+	if unit != UnitHours && unit != UnitPoints {
+		return nil, fmt.Errorf("неизвестная единица оценок '%s', допустимы %s и %s", unit, UnitHours, UnitPoints)
+	}
+	return &Service{store: store, unit: unit}, nil
+}
+
+// Handle записывает состояние задачи после события. Ошибка возвращается
+// шине, и релей outbox доставит событие повторно. Запись идемпотентна:
+// id записи - id события, повтор ничего не дублирует
+func (s *Service) Handle(ctx context.Context, e events.Event) error {
+	if err := s.store.AppendHistory(ctx, FromEvent(e)); err != nil {
+		logger.ErrorCtx(ctx, "History: Не удалось записать изменение задачи", err,
+			zap.String("task_id", e.TaskID.String()),
+			zap.String("event", string(e.Type)))
+		return fmt.Errorf("запись истории задачи: %w", err)
+	}
+	return nil
+}
+
+// BurndownReport - серии burndown и burnup за [From, To)
+type BurndownReport struct {
+	From   time.Time
+	To     time.Time
+	Unit   string
+	Points []Point
+}
+
+// VelocityReport - выполненная работа по неделям [From, To). Average -
+// среднее по неделям, которые уже начались
+type VelocityReport struct {
+	From    time.Time
+	To      time.Time
+	Unit    string
+	Weeks   []Week
+	Average float64
+}
+
+// GET /reports/burndown
+// Интервал расширяется до целых суток UTC
+func (s *Service) Burndown(ctx context.Context, from, to time.Time) (*BurndownReport, error) {
+	from, to = stats.WholeDays(from, to)
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	state, changes, err := s.load(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &BurndownReport{
+		From:   from,
+		To:     to,
+		Unit:   s.unit,
+		Points: Burndown(state, changes, from, to, time.Now()),
+	}, nil
+}
+
+// GET /reports/velocity
+// Интервал расширяется до целых недель, неделя начинается в понедельник UTC
+func (s *Service) Velocity(ctx context.Context, from, to time.Time) (*VelocityReport, error) {
+	from = StartOfWeek(from)
+	if end := StartOfWeek(to); end.Before(to) {
+		to = end.Add(7 * stats.DayLength)
+	}
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	state, changes, err := s.load(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VelocityReport{From: from, To: to, Unit: s.unit, Weeks: Velocity(state, changes, from, to)}
+	now, started := time.Now(), 0
+	for _, w := range report.Weeks {
+		if w.Start.After(now) {
+			break
+		}
+		report.Average += w.Completed
+		started++
+	}
+	if started > 0 {
+		report.Average /= float64(started)
+	}
+	return report, nil
+}
+
+func (s *Service) load(ctx context.Context, from, to time.Time) ([]Record, []Record, error) {
+	state, err := s.store.HistoryState(ctx, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("получение состояния задач: %w", err)
+	}
+	changes, err := s.store.ListHistory(ctx, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("получение истории задач: %w", err)
+	}
+	return state, changes, nil
+}
+
+func validateRange(from, to time.Time) error {
+	if !to.After(from) {
+		return service.NewValidationError("to", "должен быть позже from")
+	}
+	if to.Sub(from) > maxReportRange {
+		return service.NewValidationError("to", fmt.Sprintf("интервал отчёта не больше %d дней", maxReportRange/stats.DayLength))
+	}
+	return nil
+}
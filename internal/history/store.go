@@ -0,0 +1,89 @@
+package history
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store - журнал состояний задач
+type Store interface {
+	// AppendHistory добавляет запись, повтор того же EventID игнорируется
+	AppendHistory(context.Context, Record) error
+	// HistoryState - последняя запись каждой задачи раньше at
+	HistoryState(ctx context.Context, at time.Time) ([]Record, error)
+	// ListHistory - записи в [from, to) по возрастанию времени
+	ListHistory(ctx context.Context, from, to time.Time) ([]Record, error)
+}
+
+// MemoryStore хранит журнал в памяти процесса
+type MemoryStore struct {
+	mtx     sync.RWMutex
+	seen    map[uuid.UUID]struct{}
+	records []Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{seen: make(map[uuid.UUID]struct{})}
+}
+
+func (m *MemoryStore) AppendHistory(ctx context.Context, r Record) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.seen[r.EventID]; ok {
+		return nil
+	}
+	m.seen[r.EventID] = struct{}{}
+
+	// события приходят почти по порядку, поэтому вставка обычно в конец
+	i := len(m.records)
+	for i > 0 && m.records[i-1].OccurredAt.After(r.OccurredAt) {
+		i--
+	}
+	m.records = slices.Insert(m.records, i, r)
+	return nil
+}
+
+func (m *MemoryStore) HistoryState(ctx context.Context, at time.Time) ([]Record, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	latest := make(map[uuid.UUID]Record)
+	for _, r := range m.records {
+		if !r.OccurredAt.Before(at) {
+			break
+		}
+		latest[r.TaskID] = r
+	}
+
+	res := make([]Record, 0, len(latest))
+	for _, r := range latest {
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+func (m *MemoryStore) ListHistory(ctx context.Context, from, to time.Time) ([]Record, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	res := []Record{}
+	for _, r := range m.records {
+		if !r.OccurredAt.Before(from) && r.OccurredAt.Before(to) {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+// Records - все записи по возрастанию времени, например для снапшота хранилища
+func (m *MemoryStore) Records() []Record {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	return slices.Clone(m.records)
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type fieldsKey struct{}
+
+// WithFields добавляет поля ко всем записям, сделанным с этим контекстом:
+// middleware кладёт сюда request_id, маршрут и клиента, и они попадают
+// в логи сервиса и хранилищ без передачи вручную
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, slices.Concat(Fields(ctx), fields))
+}
+
+// Fields - поля, добавленные в контекст через WithFields
+func Fields(ctx context.Context) []zap.Field {
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	return fields
+}
+
+// FromContext - логгер с полями контекста. Поля применяются к текущему
+// глобальному Logger, поэтому подмена Logger в тестах тоже работает
+func FromContext(ctx context.Context) *zap.Logger {
+	fields := Fields(ctx)
+	if len(fields) == 0 {
+		return Logger
+	}
+	return Logger.With(fields...)
+}
+
+func InfoCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	Logger.Info(msg, slices.Concat(Fields(ctx), fields)...)
+}
+
+func WarnCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	Logger.Warn(msg, slices.Concat(Fields(ctx), fields)...)
+}
+
+func ErrorCtx(ctx context.Context, msg string, err error, fields ...zap.Field) {
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	Logger.Error(msg, slices.Concat(Fields(ctx), fields)...)
+}
+
+func LogCtx(ctx context.Context, lvl zapcore.Level, msg string, fields ...zap.Field) {
+	Logger.Log(lvl, msg, slices.Concat(Fields(ctx), fields)...)
+}
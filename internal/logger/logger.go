@@ -1,32 +1,130 @@
 package logger
 
 import (
+	"fmt"
 	"net/http"
+	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var Logger *zap.Logger
 
-func Init(development bool) error {
-	var err error
-	if development {
-		config := zap.NewDevelopmentConfig()
-		config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006/01/02 15:04:05")
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		Logger, err = config.Build()
+// level - общий уровень всех записей, меняется на лету через SetLevel
+var level = zap.NewAtomicLevel()
 
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// Options - уровень, формат и вывод логов
+type Options struct {
+	Development bool
+	// Level - debug, info, warn, error. Пустой: debug в режиме разработки, иначе info
+	Level string
+	// Format - json или console. Пустой: console в режиме разработки, иначе json
+	Format string
+	// Output - stdout, stderr или путь к файлу. Файл ротируется по размеру
+	Output string
+
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+}
+
+func Init(opts Options) error {
+	lvl := zapcore.InfoLevel
+	if opts.Development {
+		lvl = zapcore.DebugLevel
+	}
+	if opts.Level != "" {
+		parsed, err := zapcore.ParseLevel(opts.Level)
+		if err != nil {
+			return fmt.Errorf("уровень логов %q: %w", opts.Level, err)
+		}
+		lvl = parsed
+	}
+	level.SetLevel(lvl)
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	if opts.Development {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006/01/02 15:04:05")
+
+	format := opts.Format
+	if format == "" {
+		format = FormatJSON
+		if opts.Development {
+			format = FormatConsole
+		}
+	}
+
+	output, toFile := outputSyncer(opts)
+
+	var encoder zapcore.Encoder
+	switch format {
+	case FormatConsole:
+		// цвета только в терминал: в файле они превратились бы в escape-коды
+		if opts.Development && !toFile {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case FormatJSON:
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	default:
+		return fmt.Errorf("неизвестный формат логов %q", format)
+	}
+
+	core := zapcore.NewCore(encoder, output, level)
+	options := []zap.Option{zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr))}
+	if opts.Development {
+		options = append(options, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
 	} else {
-		config := zap.NewProductionConfig()
-		config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006/01/02 15:04:05")
-		Logger, err = config.Build()
+		core = zapcore.NewSamplerWithOptions(core, time.Second, 100, 100)
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
 	}
 
-	if err != nil {
-		panic(err)
+	Logger = zap.New(core, options...)
+	return nil
+}
+
+func outputSyncer(opts Options) (zapcore.WriteSyncer, bool) {
+	switch opts.Output {
+	case "", OutputStdout:
+		return zapcore.Lock(os.Stdout), false
+	case OutputStderr:
+		return zapcore.Lock(os.Stderr), false
 	}
+	return zapcore.AddSync(&lumberjack.Logger{
+		Filename:   opts.Output,
+		MaxSize:    opts.MaxSizeMB,
+		MaxBackups: opts.MaxBackups,
+		MaxAge:     opts.MaxAgeDays,
+		Compress:   opts.Compress,
+	}), true
+}
+
+// Level - текущий уровень логов
+func Level() zapcore.Level {
+	return level.Level()
+}
 
+// SetLevel меняет уровень логов без перезапуска
+func SetLevel(lvl string) error {
+	parsed, err := zapcore.ParseLevel(lvl)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(parsed)
 	return nil
 }
 
@@ -42,7 +140,7 @@ func Log(lvl zapcore.Level, msg string, fields ...zap.Field){
 }
 
 func HttpRequestInfo(r *http.Request, msg string, fields ...zap.Field) {
-	
+
 	allFields := []zap.Field{
 		zap.String("method", r.Method),
 		zap.String("path", r.URL.Path),
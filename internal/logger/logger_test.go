@@ -0,0 +1,78 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"taskTracker/internal/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// TestWithFields тестирует поля контекста в записях хелперов *Ctx и FromContext
+func TestWithFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = zap.NewNop() })
+
+	ctx := logger.WithFields(context.Background(), zap.String("request_id", "req-1"))
+	child := logger.WithFields(ctx, zap.String("principal", "ci"))
+
+	logger.WarnCtx(ctx, "конфликт", zap.Int("version", 2))
+	logger.ErrorCtx(child, "ошибка", assert.AnError)
+	logger.FromContext(child).Info("напрямую")
+	logger.InfoCtx(context.Background(), "без полей")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 4)
+	assert.Equal(t, map[string]any{"request_id": "req-1", "version": int64(2)}, entries[0].ContextMap())
+	assert.Equal(t, "req-1", entries[1].ContextMap()["request_id"])
+	assert.Equal(t, "ci", entries[1].ContextMap()["principal"])
+	assert.Equal(t, assert.AnError.Error(), entries[1].ContextMap()["error"])
+	assert.Equal(t, "ci", entries[2].ContextMap()["principal"])
+	assert.Empty(t, entries[3].ContextMap())
+	assert.Len(t, logger.Fields(ctx), 1, "дочерний контекст не меняет родительский")
+}
+
+// TestInit_File тестирует JSON в файл и смену уровня на лету
+func TestInit_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, logger.Init(logger.Options{Level: "warn", Format: logger.FormatJSON, Output: path, MaxSizeMB: 1}))
+	t.Cleanup(func() { logger.Logger = zap.NewNop() })
+
+	logger.Info("не пишется")
+	logger.Warn("пишется", zap.String("key", "value"))
+	require.NoError(t, logger.SetLevel("debug"))
+	assert.Equal(t, zapcore.DebugLevel, logger.Level())
+	logger.Log(zapcore.DebugLevel, "отладка")
+	logger.Sync()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var lines []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		lines = append(lines, entry)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "пишется", lines[0]["msg"])
+	assert.Equal(t, "value", lines[0]["key"])
+	assert.Equal(t, "отладка", lines[1]["msg"])
+
+	assert.Error(t, logger.SetLevel("verbose"))
+	assert.Equal(t, zapcore.DebugLevel, logger.Level(), "неверный уровень не меняет текущий")
+	assert.Error(t, logger.Init(logger.Options{Format: "xml"}))
+}
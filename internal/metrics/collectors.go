@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TaskCounter - хранилище, умеющее считать задачи одним запросом
+type TaskCounter interface {
+	CountTasks(ctx context.Context, now time.Time) (task.Counts, error)
+}
+
+var knownFlags = []task.Flag{task.FlagActive, task.FlagArchived, task.FlagDeleted}
+
+var knownStatuses = task.Statuses
+
+// TasksCollector считает задачи при каждом сборе метрик. Нулевые комбинации
+// флага и статуса тоже отдаются, чтобы ряды не пропадали из графиков
+type TasksCollector struct {
+	counter TaskCounter
+	timeout time.Duration
+
+	tasks   *prometheus.Desc
+	overdue *prometheus.Desc
+	errors  prometheus.Counter
+}
+
+func NewTasksCollector(counter TaskCounter, timeout time.Duration) *TasksCollector {
+	return &TasksCollector{
+		counter: counter,
+		timeout: timeout,
+		tasks: prometheus.NewDesc(namespace+"_tasks",
+			"Число задач по флагу и статусу", []string{"flag", "status"}, nil),
+		overdue: prometheus.NewDesc(namespace+"_tasks_overdue",
+			"Активные невыполненные задачи с прошедшим сроком", nil, nil),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tasks_count_errors_total",
+			Help:      "Ошибки подсчёта задач при сборе метрик",
+		}),
+	}
+}
+
+func (c *TasksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tasks
+	ch <- c.overdue
+	c.errors.Describe(ch)
+}
+
+func (c *TasksCollector) Collect(ch chan<- prometheus.Metric) {
+	defer c.errors.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	counts, err := c.counter.CountTasks(ctx, time.Now())
+	if err != nil {
+		// без значений: устаревшие числа хуже пропуска в графике
+		logger.Error("Metrics: Не удалось посчитать задачи", err)
+		c.errors.Inc()
+		return
+	}
+
+	for _, flag := range knownFlags {
+		for _, status := range knownStatuses {
+			ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.GaugeValue,
+				float64(counts.ByFlagStatus[flag][status]), string(flag), string(status))
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(c.overdue, prometheus.GaugeValue, float64(counts.Overdue))
+}
+
+// PoolCollector отдаёт статистику пула соединений pgx
+type PoolCollector struct {
+	stat func() *pgxpool.Stat
+
+	acquired, idle, constructing, total, max           *prometheus.Desc
+	acquires, acquireDuration, emptyAcquires, canceled *prometheus.Desc
+}
+
+func NewPoolCollector(stat func() *pgxpool.Stat) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(namespace+"_db_pool_"+name, help, nil, nil)
+	}
+	return &PoolCollector{
+		stat:            stat,
+		acquired:        desc("acquired_conns", "Соединения, занятые запросами"),
+		idle:            desc("idle_conns", "Свободные соединения"),
+		constructing:    desc("constructing_conns", "Соединения в процессе открытия"),
+		total:           desc("total_conns", "Все соединения пула"),
+		max:             desc("max_conns", "Максимальный размер пула"),
+		acquires:        desc("acquires_total", "Выдачи соединения из пула"),
+		acquireDuration: desc("acquire_duration_seconds_total", "Суммарное время ожидания соединения"),
+		emptyAcquires:   desc("empty_acquires_total", "Выдачи, которым пришлось ждать соединение"),
+		canceled:        desc("canceled_acquires_total", "Ожидания соединения, отменённые контекстом"),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.acquired, c.idle, c.constructing, c.total, c.max,
+		c.acquires, c.acquireDuration, c.emptyAcquires, c.canceled,
+	} {
+		ch <- d
+	}
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquired, float64(s.AcquiredConns()))
+	gauge(c.idle, float64(s.IdleConns()))
+	gauge(c.constructing, float64(s.ConstructingConns()))
+	gauge(c.total, float64(s.TotalConns()))
+	gauge(c.max, float64(s.MaxConns()))
+	counter(c.acquires, float64(s.AcquireCount()))
+	counter(c.acquireDuration, s.AcquireDuration().Seconds())
+	counter(c.emptyAcquires, float64(s.EmptyAcquireCount()))
+	counter(c.canceled, float64(s.CanceledAcquireCount()))
+}
@@ -0,0 +1,182 @@
+// Package metrics - метрики Prometheus. Счётчики и гистограммы - переменные
+// пакета: их обновляют middleware, репозиторий и воркеры. Реестр собирает
+// приложение в NewRegistry вместе со своими коллекторами (пул БД, бизнес-метрики),
+// поэтому тесты могут собирать приложение несколько раз
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"taskTracker/internal/logger"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const namespace = "tasktracker"
+
+// Метка route для запросов, не попавших ни в один маршрут: сырые пути
+// в метках раздули бы число рядов
+const UnmatchedRoute = "unmatched"
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Число HTTP-запросов по маршруту chi, методу и коду ответа",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Время обработки HTTP-запроса по маршруту chi и методу",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Запросы в обработке, включая открытые потоки SSE и WebSocket",
+	})
+
+	RateLimitRejections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limit_rejections_total",
+		Help:      "Запросы, отклонённые лимитером с ответом 429",
+	})
+
+	RepositoryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "operation_duration_seconds",
+		Help:      "Время операции хранилища по методу и результату",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"backend", "method", "result"})
+
+	WorkerRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "runs_total",
+		Help:      "Запуски фоновых воркеров по результату",
+	}, []string{"worker", "result"})
+
+	WorkerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "run_duration_seconds",
+		Help:      "Время одного запуска фонового воркера",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"worker"})
+
+	WorkerItems = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "items_total",
+		Help:      "Обработанные воркером элементы: напоминания, события, доставки",
+	}, []string{"worker"})
+
+	WorkerLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix-время последнего успешного запуска воркера",
+	}, []string{"worker"})
+)
+
+// Результат операции в метках result
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultOK
+}
+
+// NewRegistry создаёт реестр с метриками пакета, метриками рантайма Go
+// и процесса и коллекторами приложения extra
+func NewRegistry(extra ...prometheus.Collector) *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		HTTPInFlight,
+		RateLimitRejections,
+		RepositoryDuration,
+		WorkerRuns,
+		WorkerDuration,
+		WorkerItems,
+		WorkerLastSuccess,
+	)
+	reg.MustRegister(extra...)
+	return reg
+}
+
+// Handler отдаёт метрики реестра в формате Prometheus
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{
+		ErrorLog:      errorLog{},
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+type errorLog struct{}
+
+func (errorLog) Println(v ...any) {
+	logger.Warn("Metrics: Ошибка сбора метрик", zap.Any("error", v))
+}
+
+// ObserveWorkerRun учитывает один запуск воркера, начатый в start,
+// обработавший items элементов
+func ObserveWorkerRun(worker string, start time.Time, items int, err error) {
+	WorkerRuns.WithLabelValues(worker, result(err)).Inc()
+	WorkerDuration.WithLabelValues(worker).Observe(time.Since(start).Seconds())
+	if items > 0 {
+		WorkerItems.WithLabelValues(worker).Add(float64(items))
+	}
+	if err == nil {
+		WorkerLastSuccess.WithLabelValues(worker).SetToCurrentTime()
+	}
+
+	lastRunsMtx.Lock()
+	defer lastRunsMtx.Unlock()
+	run := lastRuns[worker]
+	run.At = start
+	run.Err = err
+	if err == nil {
+		run.LastSuccess = start
+	}
+	lastRuns[worker] = run
+}
+
+// WorkerRun - последний запуск воркера, для подробной проверки здоровья
+type WorkerRun struct {
+	At          time.Time
+	Err         error
+	LastSuccess time.Time
+}
+
+var (
+	lastRunsMtx sync.Mutex
+	lastRuns    = make(map[string]WorkerRun)
+)
+
+// LastWorkerRun - последний запуск воркера. false, если воркер ещё не запускался
+func LastWorkerRun(worker string) (WorkerRun, bool) {
+	lastRunsMtx.Lock()
+	defer lastRunsMtx.Unlock()
+	run, ok := lastRuns[worker]
+	return run, ok
+}
@@ -0,0 +1,202 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
+	"taskTracker/internal/middleware"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository/task/inmemory"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// sampleCount возвращает число наблюдений гистограммы с метками labels
+func sampleCount(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) uint64 {
+	t.Helper()
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metric:
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if want, ok := labels[label.GetName()]; ok && want != label.GetValue() {
+					continue metric
+				}
+			}
+			return m.GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+// TestMiddleware_RoutePattern тестирует метку route из шаблона chi
+func TestMiddleware_RoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(middleware.Metrics)
+	// отказ до роутинга, как у лимитера и проверки OpenAPI
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Reject") != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Get("/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/tasks/{id}", "404"))
+	unmatched := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, metrics.UnmatchedRoute, "404"))
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/"+uuid.NewString(), nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope/"+uuid.NewString(), nil))
+
+	rejected := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/tasks/{id}", "400"))
+	req := httptest.NewRequest(http.MethodGet, "/tasks/not-a-uuid", nil)
+	req.Header.Set("X-Reject", "1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, rejected+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/tasks/{id}", "400")))
+
+	assert.Equal(t, before+3, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/tasks/{id}", "404")))
+	assert.Equal(t, unmatched+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, metrics.UnmatchedRoute, "404")))
+	assert.Zero(t, testutil.ToFloat64(metrics.HTTPInFlight))
+}
+
+// TestMiddleware_RateLimit тестирует счётчик отказов лимитера
+func TestMiddleware_RateLimit(t *testing.T) {
+	handler := middleware.RateLimit(1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	before := testutil.ToFloat64(metrics.RateLimitRejections)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.RemoteAddr = "10.0.0.42:1234"
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, before+2, testutil.ToFloat64(metrics.RateLimitRejections))
+}
+
+type failingRepo struct {
+	*inmemory.TaskStorage
+}
+
+func (failingRepo) HealthCheck(context.Context) error { return errors.New("db down") }
+
+// TestRepository тестирует гистограммы операций хранилища по методу и результату
+func TestRepository(t *testing.T) {
+	reg := metrics.NewRegistry()
+	repo := metrics.NewRepository(failingRepo{inmemory.NewTaskStorage()}, "test")
+	ctx := context.Background()
+
+	created := &task.Task{UUID: uuid.New(), Title: "Метрики", Status: task.StatusNew,
+		DueTime: time.Now().Add(time.Hour), Flag: task.FlagActive, Version: 1}
+	require.NoError(t, repo.Create(ctx, created))
+	_, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, uuid.New())
+	require.Error(t, err)
+	require.Error(t, repo.HealthCheck(ctx))
+
+	const name = "tasktracker_repository_operation_duration_seconds"
+	assert.EqualValues(t, 1, sampleCount(t, reg, name, map[string]string{"backend": "test", "method": "Create", "result": "ok"}))
+	assert.EqualValues(t, 1, sampleCount(t, reg, name, map[string]string{"backend": "test", "method": "GetByID", "result": "ok"}))
+	assert.EqualValues(t, 1, sampleCount(t, reg, name, map[string]string{"backend": "test", "method": "GetByID", "result": "error"}))
+	assert.EqualValues(t, 1, sampleCount(t, reg, name, map[string]string{"backend": "test", "method": "HealthCheck", "result": "error"}))
+}
+
+// TestTasksCollector тестирует бизнес-метрики по данным хранилища
+func TestTasksCollector(t *testing.T) {
+	storage := inmemory.NewTaskStorage()
+	ctx := context.Background()
+
+	add := func(due time.Time, status task.Status, flag task.Flag) {
+		t.Helper()
+		created := &task.Task{UUID: uuid.New(), Title: "Задача", Status: status, DueTime: due, Version: 1}
+		require.NoError(t, storage.Create(ctx, created))
+		if flag != task.FlagActive {
+			created.Flag = flag
+			require.NoError(t, storage.Update(ctx, created))
+		}
+	}
+	future, past := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+	add(future, task.StatusNew, task.FlagActive)
+	add(past, task.StatusNew, task.FlagActive)
+	add(past, task.StatusOverdue, task.FlagActive)
+	add(past, task.StatusDone, task.FlagActive)
+	add(past, task.StatusNew, task.FlagArchived)
+
+	collector := metrics.NewTasksCollector(storage, time.Second)
+	assert.Equal(t, 23, testutil.CollectAndCount(collector), "21 комбинация флага и статуса, overdue и счётчик ошибок")
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "tasktracker_tasks_overdue", "tasktracker_tasks_count_errors_total"))
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(collector)
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range m.GetLabel() {
+				key += " " + label.GetValue()
+			}
+			values[key] = m.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, 2.0, values["tasktracker_tasks active new"])
+	assert.Equal(t, 1.0, values["tasktracker_tasks active overdue"])
+	assert.Equal(t, 1.0, values["tasktracker_tasks archived new"])
+	assert.Equal(t, 0.0, values["tasktracker_tasks deleted done"])
+	assert.Equal(t, 2.0, values["tasktracker_tasks_overdue"])
+}
+
+// TestHandler тестирует выдачу метрик в формате Prometheus
+func TestHandler(t *testing.T) {
+	metrics.ObserveWorkerRun("test-worker", time.Now(), 3, nil)
+	metrics.ObserveWorkerRun("test-worker", time.Now(), 0, errors.New("fail"))
+
+	srv := httptest.NewServer(metrics.Handler(metrics.NewRegistry()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `tasktracker_worker_runs_total{result="ok",worker="test-worker"} 1`)
+	assert.Contains(t, string(body), `tasktracker_worker_runs_total{result="error",worker="test-worker"} 1`)
+	assert.Contains(t, string(body), `tasktracker_worker_items_total{worker="test-worker"} 3`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository замеряет время каждого метода service.TaskRepository.
+// Ставится прямо над хранилищем, под кэшем: попадания в кэш
+// не смешиваются с задержками БД
+type Repository struct {
+	repo    service.TaskRepository
+	backend string
+}
+
+func NewRepository(repo service.TaskRepository, backend string) *Repository {
+	return &Repository{repo: repo, backend: backend}
+}
+
+func (r *Repository) observe(method string, start time.Time, err error) {
+	RepositoryDuration.WithLabelValues(r.backend, method, result(err)).Observe(time.Since(start).Seconds())
+}
+
+func (r *Repository) Create(ctx context.Context, t *task.Task) (err error) {
+	defer func(start time.Time) { r.observe("Create", start, err) }(time.Now())
+	return r.repo.Create(ctx, t)
+}
+
+func (r *Repository) Update(ctx context.Context, t *task.Task) (err error) {
+	defer func(start time.Time) { r.observe("Update", start, err) }(time.Now())
+	return r.repo.Update(ctx, t)
+}
+
+func (r *Repository) GetAllWithLimit(ctx context.Context, page, limit int) (tasks []*task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetAllWithLimit", start, err) }(time.Now())
+	return r.repo.GetAllWithLimit(ctx, page, limit)
+}
+
+func (r *Repository) GetStatusedWithLimit(ctx context.Context, page, limit int, status task.Status) (tasks []*task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetStatusedWithLimit", start, err) }(time.Now())
+	return r.repo.GetStatusedWithLimit(ctx, page, limit, status)
+}
+
+func (r *Repository) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag task.Flag) (tasks []*task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetFlaggedWithLimit", start, err) }(time.Now())
+	return r.repo.GetFlaggedWithLimit(ctx, page, limit, flag)
+}
+
+func (r *Repository) GetTasksDueBefore(ctx context.Context, deadline time.Time, limit int) (tasks []*task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetTasksDueBefore", start, err) }(time.Now())
+	return r.repo.GetTasksDueBefore(ctx, deadline, limit)
+}
+
+func (r *Repository) GetTasksWithDueReminders(ctx context.Context, now time.Time, limit int) (tasks []*task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetTasksWithDueReminders", start, err) }(time.Now())
+	return r.repo.GetTasksWithDueReminders(ctx, now, limit)
+}
+
+func (r *Repository) GetActiveByStatuses(ctx context.Context, statuses []task.Status) (tasks []*task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetActiveByStatuses", start, err) }(time.Now())
+	return r.repo.GetActiveByStatuses(ctx, statuses)
+}
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (t *task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetByID", start, err) }(time.Now())
+	return r.repo.GetByID(ctx, id)
+}
+
+func (r *Repository) GetByIDs(ctx context.Context, ids []uuid.UUID) (tasks []*task.Task, err error) {
+	defer func(start time.Time) { r.observe("GetByIDs", start, err) }(time.Now())
+	return r.repo.GetByIDs(ctx, ids)
+}
+
+func (r *Repository) DeleteSoft(ctx context.Context, t *task.Task) (err error) {
+	defer func(start time.Time) { r.observe("DeleteSoft", start, err) }(time.Now())
+	return r.repo.DeleteSoft(ctx, t)
+}
+
+func (r *Repository) DeleteFull(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { r.observe("DeleteFull", start, err) }(time.Now())
+	return r.repo.DeleteFull(ctx, id)
+}
+
+func (r *Repository) HealthCheck(ctx context.Context) (err error) {
+	defer func(start time.Time) { r.observe("HealthCheck", start, err) }(time.Now())
+	return r.repo.HealthCheck(ctx)
+}
@@ -1,16 +1,26 @@
 package middleware
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
 	"net"
 	"net/http"
 	"strconv"
-	"sync"
+	"strings"
 	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/ratelimit"
+	"taskTracker/internal/service"
+	"taskTracker/internal/tracing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -26,8 +36,15 @@ func RequestID(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("X-Request-ID", requestId)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request_id", requestId))
 
 		ctx := context.WithValue(r.Context(), RequestIdKey, requestId)
+		// маршрут известен только после роутинга, поэтому он вычисляется
+		// в момент записи лога
+		ctx = logger.WithFields(ctx, append([]zap.Field{
+			zap.String("request_id", requestId),
+			zap.Stringer("route", requestRoute{r}),
+		}, tracing.LogFields(ctx)...)...)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
@@ -60,14 +77,28 @@ func (lw *loggingWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap нужен http.ResponseController, чтобы добраться до Flush
+// и SetWriteDeadline исходного writer
+func (lw *loggingWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
+// Hijack нужен для перехода на WebSocket
+func (lw *loggingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(lw.ResponseWriter).Hijack()
+	if err == nil && !lw.wroteHeader {
+		lw.status = http.StatusSwitchingProtocols
+		lw.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
 func Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		requesId := GetRequestID(r.Context())
 
-		logger.Info(
+		logger.InfoCtx(r.Context(),
 			"HTTP_IN: Начало зароса",
-			zap.String("request_id", requesId),
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
 			zap.String("query", r.URL.RawQuery),
@@ -88,10 +119,9 @@ func Logging(next http.Handler) http.Handler {
 		} else if lw.status >= 500 {
 			logLevel = zap.ErrorLevel
 		}
-		logger.Log(
+		logger.LogCtx(r.Context(),
 			logLevel,
 			"HTTP_OUT: Завершение запроса",
-			zap.String("request_id", requesId),
 			zap.Int("status", lw.status),
 			zap.Int("bytes_written", lw.size),
 			zap.Duration("ms", time.Since(start)),
@@ -100,6 +130,71 @@ func Logging(next http.Handler) http.Handler {
 	})
 }
 
+// Metrics считает запросы по шаблону маршрута chi (/tasks/{id}), а не по
+// сырому пути: иначе каждый ID задачи давал бы новый ряд
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		metrics.HTTPInFlight.Inc()
+		defer metrics.HTTPInFlight.Dec()
+
+		lw := &loggingWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		next.ServeHTTP(lw, r)
+
+		route := routePattern(r)
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(lw.status)).Inc()
+		metrics.HTTPDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// Tracing открывает серверный спан на запрос и продолжает трассу
+// из заголовка traceparent. Имя спана - метод и шаблон маршрута
+// (GET /tasks/{id}), известный только после роутинга
+func Tracing(next http.Handler) http.Handler {
+	named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		route := routePattern(r)
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	})
+	return otelhttp.NewHandler(named, "http.request",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}))
+}
+
+// routePattern - шаблон маршрута запроса. Если запрос отклонён до роутинга
+// (лимитер, проверка OpenAPI), шаблон ищется в роутере по пути
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return metrics.UnmatchedRoute
+	}
+	if pattern := rctx.RoutePattern(); pattern != "" {
+		return pattern
+	}
+	if rctx.Routes != nil {
+		if pattern := rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path); pattern != "" {
+			return pattern
+		}
+	}
+	return metrics.UnmatchedRoute
+}
+
+// requestRoute - шаблон маршрута для полей лога
+type requestRoute struct {
+	r *http.Request
+}
+
+func (rr requestRoute) String() string {
+	return routePattern(rr.r)
+}
+
 func GetRequestID(ctx context.Context) string {
 	if id, ok := ctx.Value(RequestIdKey).(string); ok {
 		return id
@@ -110,8 +205,6 @@ func GetRequestID(ctx context.Context) string {
 func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			requestId := GetRequestID(r.Context())
-
 			ctx, cancel := context.WithTimeout(r.Context(), timeout)
 			defer cancel()
 
@@ -129,22 +222,10 @@ func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
 				return
 			case <-ctx.Done():
 				if ctx.Err() == context.DeadlineExceeded {
-					logger.Warn(
-						"HTTP: таймаут запроса",
-						zap.String("request_id", requestId),
-						zap.String("method", r.Method),
-						zap.String("path", r.URL.Path),
+					problem.Write(w, r, service.NewBusinessError(problem.CodeTimeout,
+						"Запрос выполнялся слишком долго"),
 						zap.String("client_ip", r.RemoteAddr),
-						zap.Duration("ms", timeout),
-					)
-					w.WriteHeader(http.StatusGatewayTimeout)
-					w.Header().Set("Content-Type", "application/json")
-
-					w.Write([]byte(`{
-                        "error": "request timeout",
-                        "request_id": "` + requestId + `",
-                        "message": "the request took too long to process"
-                    }`))
+						zap.Duration("ms", timeout))
 
 					if hijacker, ok := w.(http.Hijacker); ok {
 						if conn, _, err := hijacker.Hijack(); err == nil {
@@ -157,82 +238,72 @@ func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
 	}
 }
 
-type clientInfo struct {
-	count   int
-	resetAt time.Time
-}
+// maxMemoryClients ограничивает память лимитера RateLimit
+const maxMemoryClients = 100000
 
+// RateLimit - не больше rpm запросов в минуту с одного IP, счётчики в памяти
 func RateLimit(rpm int) func(http.Handler) http.Handler {
-	clients := make(map[string]*clientInfo)
-	var mtx sync.Mutex
-	window := time.Minute
+	limiter, err := ratelimit.New(ratelimit.Config{
+		Default: ratelimit.Policy{Algorithm: ratelimit.SlidingWindow, Limit: rpm, Window: time.Minute},
+	}, ratelimit.Options{Store: ratelimit.NewMemoryStore(maxMemoryClients)})
+	if err != nil {
+		panic(err)
+	}
+	return RateLimiter(limiter)
+}
 
+// RateLimiter отклоняет запросы сверх политик лимитера ответом 429.
+// Остаток сообщается заголовками RateLimit-* (draft-ietf-httpapi-ratelimit-headers)
+func RateLimiter(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := getIp(r)
-			now := time.Now()
-
-			mtx.Lock()
-
-			// Всегда работаем с одной переменной info
-			info, exists := clients[ip]
-
-			if !exists {
-				// Создаем новую запись
-				info = &clientInfo{
-					count:   1,
-					resetAt: now.Add(window),
-				}
-				clients[ip] = info
-			} else if now.After(info.resetAt) {
-				// Сброс счетчика
-				info.count = 1
-				info.resetAt = now.Add(window)
-			} else {
-				// Проверяем лимит
-				if info.count >= rpm {
-					mtx.Unlock() // разблокируем перед возвратом
-
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusTooManyRequests)
-
-					json.NewEncoder(w).Encode(map[string]any{
-						"error":       "rate_limit_exceeded",
-						"message":     "Слишком много запросов. Попробуйте позже.",
-						"retry_after": int(info.resetAt.Sub(now).Seconds()),
-						"request_id":  GetRequestID(r.Context()),
-					})
-					return
-				}
-
-				// Увеличиваем счетчик
-				info.count++
+			req := ratelimit.Request{
+				IP:     limiter.ClientIP(r),
+				Token:  bearerToken(r),
+				Method: r.Method,
+				Route:  routePattern(r),
 			}
+			if principal := limiter.Principal(req); principal != "" {
+				r = r.WithContext(logger.WithFields(r.Context(), zap.String("principal", principal)))
+			}
+			decision := limiter.Allow(r.Context(), req)
 
-			// Сохраняем значения ДО разблокировки
-			remaining := rpm - info.count
-			resetUnix := info.resetAt.Unix()
-
-			mtx.Unlock() // разблокируем здесь
-
-			// Устанавливаем заголовки
-			if remaining < 0 {
-				remaining = 0
+			policies := limiter.Policies(req)
+			described := make([]string, len(policies))
+			for i, p := range policies {
+				described[i] = p.Header()
+			}
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Policy.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+			w.Header().Set("RateLimit-Policy", strings.Join(described, ", "))
+
+			if !decision.Allowed {
+				metrics.RateLimitRejections.Inc()
+				retryAfter := ceilSeconds(decision.RetryAfter)
+
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				problem.Write(w, r, service.NewBusinessError(problem.CodeRateLimited,
+					"Слишком много запросов. Попробуйте позже.",
+					service.ToDetail("retry_after", retryAfter),
+					service.ToDetail("policy", decision.Policy.Name)))
+				return
 			}
-			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rpm))
-			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
-			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetUnix, 10))
 
-			// Вызываем следующий handler
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-func getIp(r *http.Request) string {
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
 	}
-	return host
+	return token
+}
+
+// ceilSeconds округляет вверх: Retry-After: 0 клиент понял бы как «сразу»
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
 }
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS reminders JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_overdue;
+CREATE INDEX idx_tasks_overdue ON tasks(due_time, status)
+WHERE flag = 'active' AND status IN ('new', 'in progress');
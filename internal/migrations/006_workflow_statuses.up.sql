@@ -0,0 +1,4 @@
+-- индекс просроченных совпадает с условием GetTasksDueBefore и учитывает статусы workflow
+DROP INDEX IF EXISTS idx_tasks_overdue;
+CREATE INDEX idx_tasks_overdue ON tasks(due_time, status)
+WHERE flag = 'active' AND status NOT IN ('done', 'cancelled', 'overdue');
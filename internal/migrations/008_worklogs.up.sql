@@ -0,0 +1,21 @@
+-- учёт времени: записи о потраченном времени и запущенные таймеры
+CREATE TABLE IF NOT EXISTS worklogs (
+    id               UUID PRIMARY KEY,
+    task_id          UUID NOT NULL,
+    user_id          TEXT NOT NULL,
+    started_at       TIMESTAMPTZ NOT NULL,
+    duration_seconds BIGINT NOT NULL,
+    note             TEXT NOT NULL DEFAULT '',
+    source           VARCHAR(20) NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_worklogs_task ON worklogs(task_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_worklogs_started ON worklogs(started_at);
+
+CREATE TABLE IF NOT EXISTS timers (
+    task_id    UUID NOT NULL,
+    user_id    TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (task_id, user_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS remaining_estimate;
+ALTER TABLE tasks DROP COLUMN IF EXISTS original_estimate;
@@ -0,0 +1,3 @@
+-- оценки задачи в единицах трекера (ESTIMATE_UNIT): исходная и оставшаяся
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS original_estimate DOUBLE PRECISION;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS remaining_estimate DOUBLE PRECISION;
@@ -0,0 +1,22 @@
+-- история задачи: состояние после каждого события, id - id события,
+-- поэтому повторная доставка из outbox не дублирует запись
+CREATE TABLE IF NOT EXISTS task_history (
+    id                 UUID PRIMARY KEY,
+    task_id            UUID NOT NULL,
+    event_type         VARCHAR(50) NOT NULL,
+    status             VARCHAR(50) NOT NULL,
+    flag               VARCHAR(50) NOT NULL,
+    original_estimate  DOUBLE PRECISION,
+    remaining_estimate DOUBLE PRECISION,
+    occurred_at        TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_history_occurred ON task_history(occurred_at);
+CREATE INDEX IF NOT EXISTS idx_task_history_task ON task_history(task_id, occurred_at);
+
+-- задачи, созданные до журнала, попадают в него с состоянием на момент миграции.
+-- Задачи, у которых история уже есть, пропускаются, поэтому повторный запуск безопасен
+INSERT INTO task_history (id, task_id, event_type, status, flag, original_estimate, remaining_estimate, occurred_at)
+SELECT gen_random_uuid(), uuid, 'task.created', status, flag, original_estimate, remaining_estimate, NOW()
+FROM tasks
+WHERE NOT EXISTS (SELECT 1 FROM task_history h WHERE h.task_id = tasks.uuid);
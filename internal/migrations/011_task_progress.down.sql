@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_completed;
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS started_at;
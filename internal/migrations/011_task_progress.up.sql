@@ -0,0 +1,5 @@
+-- начало работы и выполнение задачи для lead time и cycle time
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_tasks_completed ON tasks(completed_at)
+WHERE completed_at IS NOT NULL;
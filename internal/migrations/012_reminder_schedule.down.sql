@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_next_reminder;
+ALTER TABLE tasks DROP COLUMN IF EXISTS next_reminder_at;
@@ -0,0 +1,9 @@
+-- ближайший момент отправки напоминания, см. task.Reminders.NextAt
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS next_reminder_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_tasks_next_reminder ON tasks(next_reminder_at)
+WHERE next_reminder_at IS NOT NULL;
+-- интервалы хранятся строками Go, поэтому существующим задачам ставится
+-- самая ранняя граница окна: планировщик уточнит момент, когда впервые выберет задачу
+UPDATE tasks SET next_reminder_at = due_time - INTERVAL '30 days'
+WHERE next_reminder_at IS NULL
+AND EXISTS (SELECT 1 FROM jsonb_array_elements(reminders) r WHERE r->>'sent_at' IS NULL);
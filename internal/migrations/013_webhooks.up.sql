@@ -0,0 +1,50 @@
+-- подписки на вебхуки и их доставки; попытки и отложенные доставки
+-- удаляются вместе с подпиской, dead-letter остаётся для разбора
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         UUID PRIMARY KEY,
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    events     JSONB NOT NULL DEFAULT '[]',
+    active     BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    seq             BIGSERIAL PRIMARY KEY,
+    id              UUID NOT NULL,
+    delivery_id     UUID NOT NULL,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id        UUID NOT NULL,
+    event_type      VARCHAR(50) NOT NULL,
+    attempt         INTEGER NOT NULL,
+    status_code     INTEGER NOT NULL DEFAULT 0,
+    error           TEXT NOT NULL DEFAULT '',
+    success         BOOLEAN NOT NULL,
+    duration_ns     BIGINT NOT NULL,
+    attempted_at    TIMESTAMPTZ NOT NULL,
+    next_retry_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, seq);
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    seq             BIGSERIAL PRIMARY KEY,
+    delivery_id     UUID NOT NULL,
+    subscription_id UUID NOT NULL,
+    event           JSONB NOT NULL,
+    attempts        INTEGER NOT NULL,
+    last_error      TEXT NOT NULL,
+    failed_at       TIMESTAMPTZ NOT NULL
+);
+
+-- доставки, ещё не закончившиеся успехом или dead-letter
+CREATE TABLE IF NOT EXISTS webhook_pending (
+    delivery_id     UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event           JSONB NOT NULL,
+    attempt         INTEGER NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_pending_next ON webhook_pending(next_attempt_at);
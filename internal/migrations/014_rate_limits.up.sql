@@ -0,0 +1,11 @@
+-- общие счётчики лимитера запросов для RATELIMIT_STORE=postgres;
+-- строки, не обновлявшиеся дольше окна, удаляются по updated_at
+CREATE TABLE IF NOT EXISTS rate_limits (
+    key        TEXT PRIMARY KEY,
+    value      DOUBLE PRECISION NOT NULL,
+    prev       DOUBLE PRECISION NOT NULL,
+    stamp      TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_updated ON rate_limits(updated_at);
@@ -0,0 +1,262 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const FreqDaily Frequency = "DAILY"
+const FreqWeekly Frequency = "WEEKLY"
+const FreqMonthly Frequency = "MONTHLY"
+
+// защита от правил, которые никогда не дают подходящей даты
+const maxRecurrenceIterations = 100000
+
+// Recurrence - подмножество RRULE из RFC 5545: FREQ=DAILY|WEEKLY|MONTHLY,
+// INTERVAL, BYDAY (без порядковых номеров), COUNT и UNTIL.
+// Первым повторением серии всегда считается DueTime задачи (DTSTART).
+type Recurrence struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    *time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var untilLayouts = []string{
+	"20060102T150405Z",
+	"20060102",
+	time.RFC3339,
+}
+
+func ParseRRule(rule string) (*Recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, errors.New("пустое правило повторения")
+	}
+
+	r := &Recurrence{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("неверная часть правила: %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(value))
+			if r.Freq != FreqDaily && r.Freq != FreqWeekly && r.Freq != FreqMonthly {
+				return nil, fmt.Errorf("неподдерживаемая частота: %s", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval <= 0 {
+				return nil, fmt.Errorf("INTERVAL должен быть положительным числом: %s", value)
+			}
+			r.Interval = interval
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := weekdayCodes[strings.ToUpper(code)]
+				if !ok {
+					return nil, fmt.Errorf("неподдерживаемый день недели в BYDAY: %s", code)
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count <= 0 {
+				return nil, fmt.Errorf("COUNT должен быть положительным числом: %s", value)
+			}
+			r.Count = count
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		default:
+			return nil, fmt.Errorf("неподдерживаемая часть правила: %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("FREQ обязателен")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT и UNTIL нельзя задавать одновременно")
+	}
+	if len(r.ByDay) > 0 && r.Freq == FreqDaily {
+		return nil, errors.New("BYDAY не поддерживается для FREQ=DAILY")
+	}
+
+	sort.Slice(r.ByDay, func(i, j int) bool {
+		return isoWeekday(r.ByDay[i]) < isoWeekday(r.ByDay[j])
+	})
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range untilLayouts {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// дата без времени включает весь день
+				until = until.Add(24*time.Hour - time.Nanosecond)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("неверный формат UNTIL: %s", value)
+}
+
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = strings.ToUpper(day.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает первое повторение серии с началом в dtstart строго после after
+// и его порядковый номер в серии (dtstart - номер 0)
+func (r *Recurrence) Next(dtstart, after time.Time) (time.Time, int, bool) {
+	var next time.Time
+	index := -1
+	r.iterate(dtstart, func(i int, t time.Time) bool {
+		if t.After(after) {
+			next, index = t, i
+			return false
+		}
+		return true
+	})
+	return next, index, index >= 0
+}
+
+// Between возвращает не больше limit повторений серии, попадающих в [from, to]
+func (r *Recurrence) Between(dtstart, from, to time.Time, limit int) []time.Time {
+	res := []time.Time{}
+	r.iterate(dtstart, func(_ int, t time.Time) bool {
+		if t.After(to) || len(res) >= limit {
+			return false
+		}
+		if !t.Before(from) {
+			res = append(res, t)
+		}
+		return true
+	})
+	return res
+}
+
+// iterate перебирает повторения по возрастанию с учётом COUNT и UNTIL,
+// пока yield возвращает true
+func (r *Recurrence) iterate(dtstart time.Time, yield func(int, time.Time) bool) {
+	index := 0
+	emit := func(t time.Time) bool {
+		if r.Count > 0 && index >= r.Count {
+			return false
+		}
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		if !yield(index, t) {
+			return false
+		}
+		index++
+		return true
+	}
+
+	if !emit(dtstart) {
+		return
+	}
+
+	for period := 0; period < maxRecurrenceIterations; period++ {
+		for _, t := range r.candidates(dtstart, period) {
+			if !t.After(dtstart) {
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// candidates - повторения в period-м по счёту периоде (дне, неделе, месяце) серии
+func (r *Recurrence) candidates(dtstart time.Time, period int) []time.Time {
+	step := period * r.Interval
+
+	switch r.Freq {
+	case FreqDaily:
+		return []time.Time{dtstart.AddDate(0, 0, step)}
+
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{dtstart.AddDate(0, 0, 7*step)}
+		}
+		monday := dtstart.AddDate(0, 0, -int(isoWeekday(dtstart.Weekday()))+7*step)
+		res := make([]time.Time, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			res = append(res, monday.AddDate(0, 0, int(isoWeekday(day))))
+		}
+		return res
+
+	case FreqMonthly:
+		year, month, day := dtstart.Date()
+		hour, min, sec := dtstart.Clock()
+		loc := dtstart.Location()
+		first := time.Date(year, month+time.Month(step), 1, hour, min, sec, dtstart.Nanosecond(), loc)
+
+		if len(r.ByDay) == 0 {
+			// несуществующие даты (31 февраля) пропускаются, как в RFC 5545
+			candidate := first.AddDate(0, 0, day-1)
+			if candidate.Month() != first.Month() {
+				return nil
+			}
+			return []time.Time{candidate}
+		}
+
+		res := []time.Time{}
+		for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+			for _, wd := range r.ByDay {
+				if d.Weekday() == wd {
+					res = append(res, d)
+				}
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// isoWeekday нумерует дни недели с понедельника: Monday = 0, Sunday = 6
+func isoWeekday(day time.Weekday) time.Weekday {
+	return (day + 6) % 7
+}
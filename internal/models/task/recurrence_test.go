@@ -0,0 +1,151 @@
+package task_test
+
+import (
+	"taskTracker/internal/models/task"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+// TestParseRRule тестирует разбор и сериализацию правил
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		expected  string
+		expectErr bool
+	}{
+		{name: "daily", rule: "FREQ=DAILY", expected: "FREQ=DAILY"},
+		{name: "with prefix and interval", rule: "RRULE:FREQ=WEEKLY;INTERVAL=2", expected: "FREQ=WEEKLY;INTERVAL=2"},
+		{name: "byday sorted", rule: "FREQ=WEEKLY;BYDAY=FR,MO", expected: "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{name: "count", rule: "freq=monthly;count=3", expected: "FREQ=MONTHLY;COUNT=3"},
+		{name: "until", rule: "FREQ=DAILY;UNTIL=20300101T000000Z", expected: "FREQ=DAILY;UNTIL=20300101T000000Z"},
+		{name: "empty", rule: "", expectErr: true},
+		{name: "no freq", rule: "INTERVAL=2", expectErr: true},
+		{name: "yearly unsupported", rule: "FREQ=YEARLY", expectErr: true},
+		{name: "bad interval", rule: "FREQ=DAILY;INTERVAL=0", expectErr: true},
+		{name: "ordinal byday unsupported", rule: "FREQ=MONTHLY;BYDAY=1MO", expectErr: true},
+		{name: "count and until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20300101", expectErr: true},
+		{name: "byday with daily", rule: "FREQ=DAILY;BYDAY=MO", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := task.ParseRRule(tt.rule)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule.String())
+		})
+	}
+}
+
+// TestRecurrence_Between тестирует генерацию повторений
+func TestRecurrence_Between(t *testing.T) {
+	// понедельник
+	start := date(2030, time.January, 7, 9)
+
+	tests := []struct {
+		name     string
+		rule     string
+		from     time.Time
+		to       time.Time
+		expected []time.Time
+	}{
+		{
+			name: "daily interval",
+			rule: "FREQ=DAILY;INTERVAL=2",
+			from: start,
+			to:   date(2030, time.January, 12, 0),
+			expected: []time.Time{
+				date(2030, time.January, 7, 9),
+				date(2030, time.January, 9, 9),
+				date(2030, time.January, 11, 9),
+			},
+		},
+		{
+			name: "weekly byday every second week",
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			from: start,
+			to:   date(2030, time.January, 31, 0),
+			expected: []time.Time{
+				date(2030, time.January, 7, 9),
+				date(2030, time.January, 10, 9),
+				date(2030, time.January, 21, 9),
+				date(2030, time.January, 24, 9),
+			},
+		},
+		{
+			name: "monthly skips short months",
+			rule: "FREQ=MONTHLY",
+			from: date(2030, time.January, 1, 0),
+			to:   date(2030, time.May, 1, 0),
+			expected: []time.Time{
+				date(2030, time.January, 31, 9),
+				date(2030, time.March, 31, 9),
+			},
+		},
+		{
+			name: "count limits series",
+			rule: "FREQ=DAILY;COUNT=2",
+			from: start,
+			to:   date(2030, time.February, 1, 0),
+			expected: []time.Time{
+				date(2030, time.January, 7, 9),
+				date(2030, time.January, 8, 9),
+			},
+		},
+		{
+			name: "until is inclusive",
+			rule: "FREQ=WEEKLY;UNTIL=20300121",
+			from: date(2030, time.January, 8, 0),
+			to:   date(2030, time.February, 1, 0),
+			expected: []time.Time{
+				date(2030, time.January, 14, 9),
+				date(2030, time.January, 21, 9),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := task.ParseRRule(tt.rule)
+			require.NoError(t, err)
+
+			dtstart := start
+			if tt.name == "monthly skips short months" {
+				dtstart = date(2030, time.January, 31, 9)
+			}
+			assert.Equal(t, tt.expected, rule.Between(dtstart, tt.from, tt.to, 100))
+		})
+	}
+}
+
+// TestRecurrence_Next тестирует поиск следующего повторения и его номера
+func TestRecurrence_Next(t *testing.T) {
+	start := date(2030, time.January, 7, 9)
+	rule, err := task.ParseRRule("FREQ=DAILY;COUNT=5")
+	require.NoError(t, err)
+
+	next, index, ok := rule.Next(start, start)
+	require.True(t, ok)
+	assert.Equal(t, date(2030, time.January, 8, 9), next)
+	assert.Equal(t, 1, index)
+
+	// пропущенные повторения расходуют COUNT
+	next, index, ok = rule.Next(start, date(2030, time.January, 9, 12))
+	require.True(t, ok)
+	assert.Equal(t, date(2030, time.January, 10, 9), next)
+	assert.Equal(t, 3, index)
+
+	_, _, ok = rule.Next(start, date(2030, time.January, 11, 9))
+	assert.False(t, ok)
+}
@@ -0,0 +1,189 @@
+package task
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// сколько напоминаний можно повесить на одну задачу
+const MaxReminders = 10
+
+// насколько заранее можно напомнить о сроке. Планировщик просматривает
+// задачи со сроком в пределах этого окна
+const MaxReminderBefore = 30 * 24 * time.Hour
+
+// сколько планировщик держит напоминание за собой на время доставки.
+// Если за это время отправка не подтверждена (например, процесс упал),
+// напоминание снова становится доступным и будет доставлено повторно
+const ReminderLease = 5 * time.Minute
+
+// Reminder - напоминание за Before до DueTime задачи.
+// ClaimedAt выставляет планировщик, когда забирает напоминание на отправку,
+// SentAt - когда доставка подтверждена. После SentAt напоминание больше
+// не срабатывает, в том числе после перезапуска
+type Reminder struct {
+	Before    time.Duration
+	SentAt    *time.Time
+	ClaimedAt *time.Time
+}
+
+type reminderJSON struct {
+	Before    string     `json:"before"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+}
+
+func (r Reminder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(reminderJSON{Before: r.Before.String(), SentAt: r.SentAt, ClaimedAt: r.ClaimedAt})
+}
+
+func (r *Reminder) UnmarshalJSON(data []byte) error {
+	var raw reminderJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	before, err := time.ParseDuration(raw.Before)
+	if err != nil {
+		return fmt.Errorf("неверный интервал напоминания: %w", err)
+	}
+	r.Before = before
+	r.SentAt = raw.SentAt
+	r.ClaimedAt = raw.ClaimedAt
+	return nil
+}
+
+// FireAt - момент срабатывания напоминания для срока due
+func (r Reminder) FireAt(due time.Time) time.Time {
+	return due.Add(-r.Before)
+}
+
+// NextAt - когда напоминание нужно отправить: момент срабатывания или
+// истечение аренды, если его уже забрали. false для отправленных
+func (r Reminder) NextAt(due time.Time) (time.Time, bool) {
+	if r.SentAt != nil {
+		return time.Time{}, false
+	}
+	at := r.FireAt(due)
+	if r.ClaimedAt != nil {
+		if expires := r.ClaimedAt.Add(ReminderLease); expires.After(at) {
+			at = expires
+		}
+	}
+	return at, true
+}
+
+// Reminders хранится в БД одной JSON-колонкой
+type Reminders []Reminder
+
+// ParseReminders разбирает интервалы вида "24h", "1h30m". Повторы схлопываются,
+// результат отсортирован от самого раннего напоминания к самому позднему
+func ParseReminders(values []string) (Reminders, error) {
+	if len(values) > MaxReminders {
+		return nil, fmt.Errorf("не больше %d напоминаний на задачу", MaxReminders)
+	}
+
+	seen := make(map[time.Duration]bool, len(values))
+	res := make(Reminders, 0, len(values))
+	for _, value := range values {
+		before, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("неверный интервал напоминания: %s", value)
+		}
+		if before <= 0 {
+			return nil, fmt.Errorf("интервал напоминания должен быть положительным: %s", value)
+		}
+		if before > MaxReminderBefore {
+			return nil, fmt.Errorf("напоминание не может быть раньше, чем за %s: %s", MaxReminderBefore, value)
+		}
+		if seen[before] {
+			continue
+		}
+		seen[before] = true
+		res = append(res, Reminder{Before: before})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Before > res[j].Before })
+	return res, nil
+}
+
+// NextAt - ближайший момент отправки среди неотправленных напоминаний.
+// Хранилища сохраняют его рядом с задачей, чтобы планировщик выбирал
+// задачи по индексу, а не перебирал все со сроком в пределах окна
+func (r Reminders) NextAt(due time.Time) *time.Time {
+	var next *time.Time
+	for _, reminder := range r {
+		at, ok := reminder.NextAt(due)
+		if ok && (next == nil || at.Before(*next)) {
+			next = &at
+		}
+	}
+	return next
+}
+
+// Reset снимает отметки об отправке, например после переноса срока
+func (r Reminders) Reset() {
+	for i := range r {
+		r[i].SentAt = nil
+		r[i].ClaimedAt = nil
+	}
+}
+
+// Clone копирует напоминания вместе с отметками об отправке
+func (r Reminders) Clone() Reminders {
+	if r == nil {
+		return nil
+	}
+	res := make(Reminders, len(r))
+	for i, reminder := range r {
+		res[i] = reminder
+		if reminder.SentAt != nil {
+			sentAt := *reminder.SentAt
+			res[i].SentAt = &sentAt
+		}
+		if reminder.ClaimedAt != nil {
+			claimedAt := *reminder.ClaimedAt
+			res[i].ClaimedAt = &claimedAt
+		}
+	}
+	return res
+}
+
+func (r Reminders) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (r *Reminders) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("неподдерживаемый тип колонки reminders")
+	}
+
+	var res Reminders
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("разбор напоминаний: %w", err)
+	}
+	if len(res) == 0 {
+		res = nil
+	}
+	*r = res
+	return nil
+}
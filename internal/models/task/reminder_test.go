@@ -0,0 +1,67 @@
+package task_test
+
+import (
+	"encoding/json"
+	"taskTracker/internal/models/task"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseReminders тестирует разбор интервалов напоминаний
+func TestParseReminders(t *testing.T) {
+	reminders, err := task.ParseReminders([]string{"1h", "24h", "1h"})
+	require.NoError(t, err)
+	require.Len(t, reminders, 2)
+	assert.Equal(t, 24*time.Hour, reminders[0].Before)
+	assert.Equal(t, time.Hour, reminders[1].Before)
+
+	for _, bad := range [][]string{{"soon"}, {"-1h"}, {"0s"}, {"1000h"}} {
+		_, err := task.ParseReminders(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// TestReminders_ValueScan тестирует сохранение напоминаний в колонку БД
+func TestReminders_ValueScan(t *testing.T) {
+	sentAt := time.Date(2030, time.January, 1, 10, 0, 0, 0, time.UTC)
+	reminders := task.Reminders{{Before: time.Hour, SentAt: &sentAt}, {Before: 30 * time.Minute}}
+
+	value, err := reminders.Value()
+	require.NoError(t, err)
+
+	var scanned task.Reminders
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, reminders, scanned)
+
+	data, err := json.Marshal(reminders[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"before":"30m0s"}`, string(data))
+
+	var empty task.Reminders
+	require.NoError(t, empty.Scan([]byte("[]")))
+	assert.Nil(t, empty)
+}
+
+// TestReminders_NextAt тестирует ближайший момент отправки с учётом аренды
+func TestReminders_NextAt(t *testing.T) {
+	due := time.Date(2030, time.January, 2, 10, 0, 0, 0, time.UTC)
+	sentAt := due.Add(-48 * time.Hour)
+	claimedAt := due.Add(-2 * time.Hour)
+
+	reminders := task.Reminders{
+		{Before: 48 * time.Hour, SentAt: &sentAt},
+		{Before: 3 * time.Hour, ClaimedAt: &claimedAt},
+		{Before: time.Hour},
+	}
+	// забранное напоминание вернётся, когда истечёт аренда
+	assert.Equal(t, claimedAt.Add(task.ReminderLease), *reminders.NextAt(due))
+
+	reminders[1].ClaimedAt = nil
+	assert.Equal(t, due.Add(-3*time.Hour), *reminders.NextAt(due))
+
+	assert.Nil(t, task.Reminders{{Before: time.Hour, SentAt: &sentAt}}.NextAt(due))
+	assert.Nil(t, task.Reminders(nil).NextAt(due))
+}
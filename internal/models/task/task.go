@@ -17,6 +17,50 @@ type Task struct {
 	Version     int        `db:"version" json:"version"`
 	Flag        Flag       `json:"flag" db:"flag"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at,omitempty"`
+	RRule       string     `json:"rrule,omitempty" db:"rrule"`
+	Reminders   Reminders  `json:"reminders,omitempty" db:"reminders"`
+	// Rank - позиция на доске, строки сравниваются лексикографически.
+	// Пусто, пока задачу не перемещали
+	Rank string `json:"rank,omitempty" db:"rank"`
+	// OriginalEstimate и RemainingEstimate - исходная и оставшаяся оценка в
+	// единицах трекера (часы или story points). Без RemainingEstimate
+	// остаток равен исходной оценке
+	OriginalEstimate  *float64 `json:"original_estimate,omitempty" db:"original_estimate"`
+	RemainingEstimate *float64 `json:"remaining_estimate,omitempty" db:"remaining_estimate"`
+	// StartedAt - первый переход в in progress, CompletedAt - переход в done.
+	// Из них считаются lead time и cycle time
+	StartedAt   *time.Time `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	// TimeSpent - суммарное записанное время. В таблице задач не хранится,
+	// заполняется из worklog при чтении
+	TimeSpent time.Duration `json:"-" db:"-"`
+}
+
+// Remaining - оставшаяся работа: у закрытой задачи ноль, без оценки тоже ноль
+func (t *Task) Remaining() float64 {
+	switch {
+	case t.Status.Closed():
+		return 0
+	case t.RemainingEstimate != nil:
+		return *t.RemainingEstimate
+	case t.OriginalEstimate != nil:
+		return *t.OriginalEstimate
+	}
+	return 0
+}
+
+// TrackProgress отмечает начало работы и выполнение по текущему статусу.
+// Переоткрытая задача теряет время выполнения, но не время начала
+func (t *Task) TrackProgress(now time.Time) {
+	if t.Status == StatusInProgress && t.StartedAt == nil {
+		t.StartedAt = &now
+	}
+	switch {
+	case t.Status == StatusDone && t.CompletedAt == nil:
+		t.CompletedAt = &now
+	case t.Status != StatusDone:
+		t.CompletedAt = nil
+	}
 }
 
 type Status string
@@ -26,7 +70,35 @@ const StatusNew Status = "new"
 const StatusDone Status = "done"
 const StatusInProgress Status = "in progress"
 const StatusOverdue Status = "overdue"
+const StatusBlocked Status = "blocked"
+const StatusInReview Status = "in review"
+const StatusCancelled Status = "cancelled"
+
+// Statuses - все допустимые статусы. Workflow выбирает из них, но не вводит новые
+var Statuses = []Status{StatusNew, StatusInProgress, StatusBlocked, StatusInReview, StatusDone, StatusCancelled, StatusOverdue}
+
+// Closed - работа по задаче закончена: такая задача не становится просроченной
+func (s Status) Closed() bool {
+	return s == StatusDone || s == StatusCancelled
+}
 
 const FlagDeleted Flag = "deleted"
 const FlagArchived Flag = "archived"
 const FlagActive Flag = "active"
+
+// Counts - число задач по флагу и статусу. Overdue - активные невыполненные
+// задачи с прошедшим сроком, даже если статус overdue ещё не проставлен
+type Counts struct {
+	ByFlagStatus map[Flag]map[Status]int
+	Overdue      int
+}
+
+func (c *Counts) Add(flag Flag, status Status, n int) {
+	if c.ByFlagStatus == nil {
+		c.ByFlagStatus = make(map[Flag]map[Status]int)
+	}
+	if c.ByFlagStatus[flag] == nil {
+		c.ByFlagStatus[flag] = make(map[Status]int)
+	}
+	c.ByFlagStatus[flag][status] += n
+}
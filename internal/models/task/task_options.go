@@ -34,4 +34,34 @@ func WithFlag (flag Flag) TaskOption{
 	return func(task *Task){
 		task.Flag = flag
 	}
-}
\ No newline at end of file
+}
+
+func WithRRule(rule string) TaskOption {
+	return func(task *Task) {
+		task.RRule = rule
+	}
+}
+
+func WithReminders(reminders Reminders) TaskOption {
+	return func(task *Task) {
+		task.Reminders = reminders
+	}
+}
+
+func WithRank(rank string) TaskOption {
+	return func(task *Task) {
+		task.Rank = rank
+	}
+}
+
+func WithOriginalEstimate(estimate float64) TaskOption {
+	return func(task *Task) {
+		task.OriginalEstimate = &estimate
+	}
+}
+
+func WithRemainingEstimate(estimate float64) TaskOption {
+	return func(task *Task) {
+		task.RemainingEstimate = &estimate
+	}
+}
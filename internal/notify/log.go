@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"taskTracker/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// Log пишет напоминания в лог приложения
+type Log struct{}
+
+func NewLog() Log {
+	return Log{}
+}
+
+func (Log) Notify(ctx context.Context, n Notification) error {
+	logger.Info("Напоминание о сроке задачи",
+		zap.String("task_id", n.TaskID.String()),
+		zap.String("title", n.Title),
+		zap.Time("due_time", n.DueTime),
+		zap.Duration("before", n.Before),
+		zap.String("key", n.Key()))
+	return nil
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification - напоминание о приближающемся сроке задачи
+type Notification struct {
+	TaskID  uuid.UUID     `json:"task_id"`
+	Title   string        `json:"title"`
+	DueTime time.Time     `json:"due_time"`
+	Before  time.Duration `json:"-"`
+}
+
+// Key - ключ идемпотентности: одно и то же напоминание о том же сроке
+// всегда получает один ключ. Планировщик доставляет напоминания не менее
+// одного раза, и получатель может отбросить повтор по этому ключу
+func (n Notification) Key() string {
+	return fmt.Sprintf("%s-%d-%d", n.TaskID, int64(n.Before/time.Second), n.DueTime.Unix())
+}
+
+func (n Notification) Subject() string {
+	return fmt.Sprintf("Напоминание: %q", n.Title)
+}
+
+func (n Notification) Text() string {
+	return fmt.Sprintf("Срок задачи %q (%s) наступает %s, осталось %s",
+		n.Title, n.TaskID, n.DueTime.Format(time.RFC3339), n.Before)
+}
+
+// Notifier доставляет напоминания. Реализации должны уважать ctx
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Multi рассылает напоминание всем получателям и собирает их ошибки
+type Multi []Notifier
+
+func (m Multi) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,184 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"taskTracker/internal/notify"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNotification() notify.Notification {
+	return notify.Notification{
+		TaskID:  uuid.New(),
+		Title:   "Отчёт",
+		DueTime: time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC),
+		Before:  time.Hour,
+	}
+}
+
+// smtpStub - минимальный SMTP-сервер, который принимает одно письмо
+type smtpStub struct {
+	listener net.Listener
+	from     string
+	rcpt     []string
+	data     string
+	done     chan struct{}
+}
+
+func startSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	stub := &smtpStub{listener: listener, done: make(chan struct{})}
+	go stub.serve()
+	return stub
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP stub")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.rcpt = append(s.rcpt, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case upper == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// TestSMTP_Notify тестирует отправку письма на локальный SMTP-сервер
+func TestSMTP_Notify(t *testing.T) {
+	stub := startSMTPStub(t)
+
+	notifier, err := notify.NewSMTP(notify.SMTPConfig{
+		Addr: stub.listener.Addr().String(),
+		From: "tracker@example.com",
+		To:   []string{"team@example.com", "lead@example.com"},
+	})
+	require.NoError(t, err)
+
+	n := newNotification()
+	require.NoError(t, notifier.Notify(context.Background(), n))
+	<-stub.done
+
+	assert.Equal(t, "tracker@example.com", stub.from)
+	assert.Equal(t, []string{"team@example.com", "lead@example.com"}, stub.rcpt)
+	assert.Contains(t, stub.data, "Subject: =?utf-8?q?")
+	assert.Contains(t, stub.data, n.TaskID.String())
+	assert.Contains(t, stub.data, "Message-ID: <"+n.Key()+"@tasktracker>")
+}
+
+// TestSMTP_InvalidConfig тестирует проверку обязательных параметров
+func TestSMTP_InvalidConfig(t *testing.T) {
+	_, err := notify.NewSMTP(notify.SMTPConfig{Addr: "localhost:25"})
+	assert.Error(t, err)
+}
+
+// TestWebhook_Notify тестирует доставку напоминания вебхуком
+func TestWebhook_Notify(t *testing.T) {
+	var payload map[string]any
+	var key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		key = r.Header.Get("Idempotency-Key")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier, err := notify.NewWebhook(server.URL, time.Second)
+	require.NoError(t, err)
+
+	n := newNotification()
+	require.NoError(t, notifier.Notify(context.Background(), n))
+
+	assert.Equal(t, "task.reminder", payload["event"])
+	assert.Equal(t, n.TaskID.String(), payload["task_id"])
+	assert.Equal(t, "1h0m0s", payload["before"])
+	assert.Equal(t, n.Key(), key)
+	assert.Equal(t, n.Key(), payload["idempotency_key"])
+}
+
+// TestWebhook_ErrorStatus тестирует ошибку при неуспешном ответе
+func TestWebhook_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier, err := notify.NewWebhook(server.URL, time.Second)
+	require.NoError(t, err)
+	assert.Error(t, notifier.Notify(context.Background(), newNotification()))
+}
+
+type notifierFunc func(context.Context, notify.Notification) error
+
+func (f notifierFunc) Notify(ctx context.Context, n notify.Notification) error {
+	return f(ctx, n)
+}
+
+// TestMulti_Notify тестирует рассылку всем получателям несмотря на ошибки
+func TestMulti_Notify(t *testing.T) {
+	calls := 0
+	ok := notifierFunc(func(context.Context, notify.Notification) error { calls++; return nil })
+	failing := notifierFunc(func(context.Context, notify.Notification) error { calls++; return errors.New("down") })
+
+	err := notify.Multi{failing, ok}.Notify(context.Background(), newNotification())
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook отправляет напоминание POST-запросом с JSON-телом на заданный URL
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Event string `json:"event"`
+	Notification
+	Before string `json:"before"`
+	SentAt string `json:"sent_at"`
+	Key    string `json:"idempotency_key"`
+}
+
+func NewWebhook(url string, timeout time.Duration) (*Webhook, error) {
+	if url == "" {
+		return nil, errors.New("не задан URL вебхука напоминаний")
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Webhook{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:        "task.reminder",
+		Notification: n,
+		Before:       n.Before.String(),
+		SentAt:       time.Now().UTC().Format(time.RFC3339),
+		Key:          n.Key(),
+	})
+	if err != nil {
+		return fmt.Errorf("сериализация напоминания: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("создание запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", n.Key())
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("отправка вебхука: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("вебхук ответил статусом %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+)
+
+//go:embed docs.html
+var docsPage string
+
+// DocsHandler отдаёт страницу Swagger UI для документа по адресу specURL.
+// Скрипты и стили UI загружаются браузером с CDN
+func DocsHandler(specURL string) http.HandlerFunc {
+	page := []byte(strings.ReplaceAll(docsPage, "{{SPEC_URL}}", specURL))
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(page)
+	}
+}
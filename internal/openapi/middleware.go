@@ -0,0 +1,241 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// maxValidatedBody - тела больше не проверяются, их отклонит обработчик
+const maxValidatedBody = 1 << 20
+
+type ValidatorOptions struct {
+	// Requests - отклонять запросы, не соответствующие документу, с 400
+	Requests bool
+	// Responses - проверять ответы JSON и писать расхождения в лог.
+	// Ответ клиенту при этом не меняется
+	Responses bool
+}
+
+// Middleware проверяет запросы и ответы по документу. Запросы к путям,
+// которых нет в документе, пропускаются без проверки
+func (s *Spec) Middleware(opts ValidatorOptions) func(http.Handler) http.Handler {
+	routes := s.routes()
+
+	return func(next http.Handler) http.Handler {
+		if !opts.Requests && !opts.Responses {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			segments := splitPath(r.URL.Path)
+			var (
+				rt     route
+				params map[string]string
+				found  bool
+			)
+			for _, candidate := range routes {
+				if params, found = candidate.match(r.Method, segments); found {
+					rt = candidate
+					break
+				}
+			}
+			if !found {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if opts.Requests {
+				if err := s.validateRequest(r, rt.operation, params); err != nil {
+					var validationErr *ValidationError
+					if !errors.As(err, &validationErr) {
+						validationErr = &ValidationError{Field: "body", Reason: err.Error()}
+					}
+					problem.Write(w, r, service.NewValidationError(validationErr.Field, validationErr.Reason),
+						zap.String("operation", rt.operation.OperationID),
+						zap.String("client_ip", r.RemoteAddr))
+					return
+				}
+			}
+
+			if !opts.Responses || !bufferable(rt.operation) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if err := s.validateResponse(rt.operation, rec); err != nil {
+				logger.Error("HTTP: Ответ не соответствует OpenAPI", err,
+					zap.String("operation", rt.operation.OperationID),
+					zap.Int("status", rec.status))
+			}
+			rec.flush()
+		})
+	}
+}
+
+// isJSON - тело, которое проверяется по схеме: JSON или problem+json
+func isJSON(contentType string) bool {
+	return contentType == ContentJSON || contentType == ContentProblem
+}
+
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *Spec) validateRequest(r *http.Request, op *Operation, pathParams map[string]string) error {
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var (
+			raw     string
+			present bool
+		)
+		switch p.In {
+		case "path":
+			raw, present = pathParams[p.Name]
+		case "query":
+			present = query.Has(p.Name)
+			raw = query.Get(p.Name)
+		case "header":
+			raw = r.Header.Get(p.Name)
+			present = raw != ""
+		}
+
+		field := p.In + "." + p.Name
+		if !present {
+			if p.Required {
+				return invalid(field, "обязательный параметр")
+			}
+			continue
+		}
+		if err := s.Validate(p.Schema, parseParam(s.resolve(p.Schema), raw), field); err != nil {
+			return err
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+	media, ok := op.RequestBody.Content[ContentJSON]
+	if !ok {
+		return nil
+	}
+	// неверный Content-Type обработчик отклоняет сам, с 415
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != ContentJSON {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBody+1))
+	if err != nil {
+		return invalid("body", "не удалось прочитать тело запроса")
+	}
+	// обработчик читает тело заново
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), r.Body))
+	if len(data) > maxValidatedBody {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		if op.RequestBody.Required {
+			return invalid("body", "тело запроса обязательно")
+		}
+		return nil
+	}
+
+	value, err := decodeJSON(data)
+	if err != nil {
+		return invalid("body", "неверный JSON: %s", err.Error())
+	}
+	return s.Validate(media.Schema, value, "body")
+}
+
+// bufferable - ответы операции можно задержать до проверки. Потоки
+// (text/event-stream) и переключение протокола (101) пропускаются как есть
+func bufferable(op *Operation) bool {
+	hasJSON := false
+	for code, response := range op.Responses {
+		if code[0] == '1' {
+			return false
+		}
+		for contentType := range response.Content {
+			if !isJSON(contentType) {
+				return false
+			}
+			hasJSON = true
+		}
+	}
+	return hasJSON
+}
+
+func (s *Spec) validateResponse(op *Operation, rec *recorder) error {
+	response := op.response(rec.status)
+	if response == nil {
+		return invalid("status", "код ответа %d не описан", rec.status)
+	}
+
+	expected := ""
+	for contentType := range response.Content {
+		if isJSON(contentType) {
+			expected = contentType
+		}
+	}
+	if expected == "" {
+		if rec.body.Len() > 0 && len(response.Content) == 0 {
+			return invalid("body", "у ответа %d не должно быть тела", rec.status)
+		}
+		return nil
+	}
+	media := response.Content[expected]
+
+	mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if mediaType != expected {
+		return invalid("content-type", "ожидается %s, получено %q", expected, rec.Header().Get("Content-Type"))
+	}
+
+	value, err := decodeJSON(rec.body.Bytes())
+	if err != nil {
+		return invalid("body", "неверный JSON: %s", err.Error())
+	}
+	return s.Validate(media.Schema, value, "body")
+}
+
+// recorder задерживает ответ до проверки
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(b)
+}
+
+func (r *recorder) flush() {
+	r.ResponseWriter.WriteHeader(r.status)
+	r.ResponseWriter.Write(r.body.Bytes())
+}
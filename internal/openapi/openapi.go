@@ -0,0 +1,291 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version   = "3.1.0"
+	refPrefix = "#/components/schemas/"
+
+	ContentJSON    = "application/json"
+	ContentProblem = "application/problem+json"
+)
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// PathItem - операции пути по HTTP-методам в нижнем регистре
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// PathParam - обязательный параметр пути
+func PathParam(name string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
+
+// QueryParam - необязательный параметр строки запроса
+func QueryParam(name string, schema *Schema, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// HeaderParam - необязательный заголовок запроса
+func HeaderParam(name string, schema *Schema, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: schema}
+}
+
+// JSONBody - обязательное тело запроса в JSON
+func JSONBody(schema *Schema) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{ContentJSON: {Schema: schema}},
+	}
+}
+
+// JSONResponse - ответ с телом в JSON
+func JSONResponse(description string, schema *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{ContentJSON: {Schema: schema}},
+	}
+}
+
+// ProblemResponse - ошибка в формате RFC 7807
+func ProblemResponse(description string, schema *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{ContentProblem: {Schema: schema}},
+	}
+}
+
+// ContentResponse - ответ с телом другого типа (text/event-stream, text/html)
+func ContentResponse(description, contentType string, schema *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{contentType: {Schema: schema}},
+	}
+}
+
+// EmptyResponse - ответ без тела
+func EmptyResponse(description string) *Response {
+	return &Response{Description: description}
+}
+
+// Spec собирает документ: схемы тел строятся по типам Go, пути
+// добавляются по одному вместе с маршрутами
+type Spec struct {
+	doc *Document
+	gen *generator
+	// defaults добавляются в каждую операцию, если в ней нет такого кода ответа
+	defaults map[string]*Response
+}
+
+func New(title, version string) *Spec {
+	gen := newGenerator()
+	return &Spec{
+		doc: &Document{
+			OpenAPI:    Version,
+			Info:       Info{Title: title, Version: version},
+			Paths:      make(map[string]*PathItem),
+			Components: Components{Schemas: gen.schemas},
+		},
+		gen:      gen,
+		defaults: make(map[string]*Response),
+	}
+}
+
+// Schema возвращает схему типа значения v. Именованные структуры
+// регистрируются в components и возвращаются ссылкой
+func (s *Spec) Schema(v any) *Schema {
+	return s.gen.schema(reflect.TypeOf(v))
+}
+
+// Define задаёт схему типа значения v вместо построенной по полям,
+// например для типов со своим MarshalJSON. С непустым name схема
+// попадает в components
+func (s *Spec) Define(v any, name string, schema *Schema) {
+	t := reflect.TypeOf(v)
+	s.gen.overrides[t] = schema
+	if name != "" {
+		s.gen.names[t] = name
+		s.gen.schemas[name] = schema
+	}
+}
+
+// Default задаёт ответ, который добавляется ко всем операциям
+// (например, default с телом ошибки)
+func (s *Spec) Default(code string, response *Response) {
+	s.defaults[code] = response
+}
+
+// Add добавляет операцию. Путь - в синтаксисе OpenAPI: /tasks/{id}
+func (s *Spec) Add(method, path string, op Operation) {
+	item, ok := s.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		s.doc.Paths[path] = item
+	}
+
+	if op.Responses == nil {
+		op.Responses = make(map[string]*Response)
+	}
+	for code, response := range s.defaults {
+		if _, ok := op.Responses[code]; !ok {
+			op.Responses[code] = response
+		}
+	}
+	(*item)[strings.ToLower(method)] = &op
+}
+
+func (s *Spec) Document() *Document {
+	return s.doc
+}
+
+// Handler отдаёт документ в JSON
+func (s *Spec) Handler() http.HandlerFunc {
+	body, err := json.MarshalIndent(s.doc, "", "  ")
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", ContentJSON)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+}
+
+// route - операция документа с разобранным шаблоном пути
+type route struct {
+	method    string
+	path      string
+	segments  []string
+	operation *Operation
+}
+
+// routes возвращает операции в порядке сопоставления: пути без
+// параметров раньше, чтобы /tasks/archived не совпал с /tasks/{id}
+func (s *Spec) routes() []route {
+	var res []route
+	for path, item := range s.doc.Paths {
+		for method, op := range *item {
+			res = append(res, route{
+				method:    strings.ToUpper(method),
+				path:      path,
+				segments:  splitPath(path),
+				operation: op,
+			})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		pi, pj := paramCount(res[i].segments), paramCount(res[j].segments)
+		if pi != pj {
+			return pi < pj
+		}
+		return res[i].path < res[j].path
+	})
+	return res
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func paramCount(segments []string) int {
+	n := 0
+	for _, s := range segments {
+		if isParam(s) {
+			n++
+		}
+	}
+	return n
+}
+
+// match сопоставляет путь запроса с шаблоном и возвращает параметры пути
+func (rt route) match(method string, segments []string) (map[string]string, bool) {
+	if rt.method != method || len(rt.segments) != len(segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range rt.segments {
+		if isParam(segment) {
+			params[segment[1:len(segment)-1]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// response ищет описание ответа по коду, затем по маске 4XX и default
+func (op *Operation) response(status int) *Response {
+	code := strconv.Itoa(status)
+	if r, ok := op.Responses[code]; ok {
+		return r
+	}
+	if r, ok := op.Responses[code[:1]+"XX"]; ok {
+		return r
+	}
+	return op.Responses["default"]
+}
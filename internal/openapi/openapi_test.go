@@ -0,0 +1,171 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/openapi"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+type item struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `json:"name"`
+	Tags     []string   `json:"tags"`
+	Note     *string    `json:"note,omitempty"`
+	Due      time.Time  `json:"due"`
+	Children []item     `json:"children,omitempty"`
+	Seen     *time.Time `json:"seen"`
+	internal string
+}
+
+// TestSpec_Schema тестирует построение схем по типам Go
+func TestSpec_Schema(t *testing.T) {
+	spec := openapi.New("test", "1")
+
+	ref := spec.Schema(item{})
+	assert.Equal(t, "#/components/schemas/item", ref.Ref)
+
+	schema := spec.Document().Components.Schemas["item"]
+	require.NotNil(t, schema)
+	assert.ElementsMatch(t, []string{"id", "name", "tags", "due", "seen"}, schema.Required)
+	assert.Equal(t, "uuid", schema.Properties["id"].Format)
+	assert.Equal(t, openapi.SchemaType{"array", "null"}, schema.Properties["tags"].Type)
+	assert.Equal(t, openapi.SchemaType{"string", "null"}, schema.Properties["seen"].Type)
+	assert.Equal(t, "#/components/schemas/item", schema.Properties["children"].Items.Ref)
+	assert.NotContains(t, schema.Properties, "internal")
+
+	data, err := json.Marshal(schema.Properties["name"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"string"}`, string(data))
+}
+
+func newServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *observer.ObservedLogs) {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = zap.NewNop() })
+
+	spec := openapi.New("test", "1")
+	spec.Add(http.MethodPost, "/items/{id}", openapi.Operation{
+		OperationID: "postItem",
+		Parameters: []openapi.Parameter{
+			openapi.PathParam("id", openapi.UUID()),
+			openapi.QueryParam("limit", openapi.Integer().WithMinimum(1), ""),
+		},
+		RequestBody: openapi.JSONBody(spec.Schema(item{})),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.JSONResponse("ok", spec.Schema(item{})),
+		},
+	})
+	spec.Add(http.MethodGet, "/stream", openapi.Operation{
+		OperationID: "stream",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.ContentResponse("поток", "text/event-stream", openapi.String()),
+		},
+	})
+
+	server := httptest.NewServer(spec.Middleware(openapi.ValidatorOptions{Requests: true, Responses: true})(handler))
+	t.Cleanup(server.Close)
+	return server, logs
+}
+
+func post(t *testing.T, url, body string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp, decoded
+}
+
+// TestMiddleware_Requests тестирует отклонение запросов, не соответствующих документу
+func TestMiddleware_Requests(t *testing.T) {
+	var received string
+	server, _ := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]any
+		json.NewDecoder(r.Body).Decode(&v)
+		received, _ = v["name"].(string)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	valid := `{"id":"` + uuid.NewString() + `","name":"a","tags":null,"due":"2030-01-01T00:00:00Z","seen":null}`
+	url := server.URL + "/items/" + uuid.NewString()
+
+	tests := []struct {
+		name  string
+		url   string
+		body  string
+		field string
+	}{
+		{name: "bad path param", url: server.URL + "/items/42", body: valid, field: "path.id"},
+		{name: "bad query param", url: url + "?limit=0", body: valid, field: "query.limit"},
+		{name: "missing field", url: url, body: `{"name":"a"}`, field: "body.id"},
+		{name: "wrong type", url: url, body: strings.Replace(valid, `"a"`, `1`, 1), field: "body.name"},
+		{name: "bad format", url: url, body: strings.Replace(valid, "2030-01-01T00:00:00Z", "завтра", 1), field: "body.due"},
+		{name: "broken json", url: url, body: `{`, field: "body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := post(t, tt.url, tt.body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Equal(t, "VALIDATION_ERROR", body["code"])
+			assert.Equal(t, tt.field, body["field"])
+		})
+	}
+
+	t.Run("valid request reaches handler with body", func(t *testing.T) {
+		resp, _ := post(t, url+"?limit=5", valid)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "a", received)
+	})
+}
+
+// TestMiddleware_Responses тестирует журналирование ответов, не соответствующих документу
+func TestMiddleware_Responses(t *testing.T) {
+	server, logs := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: 1\n\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name":"без id"}`))
+	})
+
+	valid := `{"id":"` + uuid.NewString() + `","name":"a","tags":[],"due":"2030-01-01T00:00:00Z","seen":null}`
+	resp, body := post(t, server.URL+"/items/"+uuid.NewString(), valid)
+
+	// клиент получает ответ без изменений
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "без id", body["name"])
+	require.Equal(t, 1, logs.FilterMessage("HTTP: Ответ не соответствует OpenAPI").Len())
+
+	// потоковые ответы не буферизуются и не проверяются
+	streamResp, err := http.Get(server.URL + "/stream")
+	require.NoError(t, err)
+	streamResp.Body.Close()
+	assert.Equal(t, "text/event-stream", streamResp.Header.Get("Content-Type"))
+	assert.Equal(t, 1, logs.FilterMessage("HTTP: Ответ не соответствует OpenAPI").Len())
+}
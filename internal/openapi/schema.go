@@ -0,0 +1,261 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaType - type из JSON Schema. Один тип сериализуется строкой,
+// несколько (например, ["string", "null"]) - массивом
+type SchemaType []string
+
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+func (t SchemaType) has(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema - подмножество JSON Schema 2020-12, которого достаточно для API
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 SchemaType         `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+func String() *Schema   { return &Schema{Type: SchemaType{"string"}} }
+func Integer() *Schema  { return &Schema{Type: SchemaType{"integer"}} }
+func Number() *Schema   { return &Schema{Type: SchemaType{"number"}} }
+func Boolean() *Schema  { return &Schema{Type: SchemaType{"boolean"}} }
+func UUID() *Schema     { return &Schema{Type: SchemaType{"string"}, Format: "uuid"} }
+func DateTime() *Schema { return &Schema{Type: SchemaType{"string"}, Format: "date-time"} }
+
+// Any - любое значение JSON
+func Any() *Schema { return &Schema{} }
+
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: SchemaType{"array"}, Items: items}
+}
+
+// Object - объект с перечисленными свойствами; required - обязательные из них
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: SchemaType{"object"}, Properties: properties, Required: required}
+}
+
+// Enum - строка с допустимыми значениями
+func Enum(values ...string) *Schema {
+	s := String()
+	for _, v := range values {
+		s.Enum = append(s.Enum, v)
+	}
+	return s
+}
+
+// WithMinimum задаёт нижнюю границу числа
+func (s *Schema) WithMinimum(min float64) *Schema {
+	s.Minimum = &min
+	return s
+}
+
+func (s *Schema) WithDescription(description string) *Schema {
+	s.Description = description
+	return s
+}
+
+// nullable добавляет null к допустимым типам. $ref и пустая схема
+// остаются как есть: ссылки на структуры в API встречаются только в
+// необязательных полях, а пустая схема и так допускает null
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" || len(s.Type) == 0 {
+		return s
+	}
+	res := *s
+	res.Type = append(SchemaType{}, s.Type...)
+	if !res.Type.has("null") {
+		res.Type = append(res.Type, "null")
+	}
+	return &res
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+)
+
+// generator строит схемы по типам Go по тем же правилам, по которым их
+// сериализует encoding/json. Именованные структуры попадают в components
+type generator struct {
+	schemas   map[string]*Schema
+	names     map[reflect.Type]string
+	overrides map[reflect.Type]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas:   make(map[string]*Schema),
+		names:     make(map[reflect.Type]string),
+		overrides: make(map[reflect.Type]*Schema),
+	}
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if s, ok := g.overrides[t]; ok {
+		if name, ok := g.names[t]; ok {
+			return &Schema{Ref: refPrefix + name}
+		}
+		res := *s
+		return &res
+	}
+
+	switch t {
+	case timeType:
+		return DateTime()
+	case uuidType:
+		return UUID()
+	case durationType:
+		return Integer().WithDescription("длительность в наносекундах")
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schema(t.Elem()))
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Integer()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer().WithMinimum(0)
+	case reflect.Float32, reflect.Float64:
+		return Number()
+	case reflect.Slice, reflect.Array:
+		return ArrayOf(g.schema(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: SchemaType{"object"}, AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return Any()
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	name := t.Name()
+	if name != "" {
+		if _, ok := g.names[t]; ok {
+			return &Schema{Ref: refPrefix + g.names[t]}
+		}
+		name = g.uniqueName(t)
+		g.names[t] = name
+		// регистрируем до обхода полей, чтобы рекурсивные типы ссылались на себя
+		g.schemas[name] = &Schema{}
+	}
+
+	s := Object(map[string]*Schema{})
+	g.fields(t, s)
+
+	if name == "" {
+		return s
+	}
+	*g.schemas[name] = *s
+	return &Schema{Ref: refPrefix + name}
+}
+
+// fields добавляет поля структуры, встроенные структуры раскрываются
+func (g *generator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, omitempty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.fields(embedded, s)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schema(field.Type)
+		switch field.Type.Kind() {
+		case reflect.Slice, reflect.Map:
+			// nil-срез и nil-map без omitempty сериализуются в null
+			if !omitempty {
+				fieldSchema = nullable(fieldSchema)
+			}
+		}
+
+		s.Properties[name] = fieldSchema
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func (g *generator) uniqueName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := g.schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+func jsonName(field reflect.StructField) (name string, omitempty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty, false
+}
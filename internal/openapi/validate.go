@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ValidationError - значение не соответствует схеме. Field - путь
+// до поля (body.reminders[0], query.page)
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+func invalid(field, format string, args ...any) *ValidationError {
+	return &ValidationError{Field: field, Reason: fmt.Sprintf(format, args...)}
+}
+
+// resolve раскрывает $ref на components
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, refPrefix)]
+	}
+	return schema
+}
+
+// Validate проверяет значение, разобранное json.Decoder с UseNumber
+func (s *Spec) Validate(schema *Schema, value any, field string) error {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	if len(schema.Type) > 0 {
+		if !matchesType(schema.Type, value) {
+			return invalid(field, "ожидается %s, получено %s", strings.Join(schema.Type, " или "), typeName(value))
+		}
+	}
+	if value == nil {
+		return nil
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		return invalid(field, "недопустимое значение %v", value)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return checkFormat(schema.Format, v, field)
+
+	case json.Number:
+		if schema.Minimum != nil {
+			n, err := v.Float64()
+			if err == nil && n < *schema.Minimum {
+				return invalid(field, "значение меньше %v", *schema.Minimum)
+			}
+		}
+
+	case []any:
+		for i, item := range v {
+			if err := s.Validate(schema.Items, item, fmt.Sprintf("%s[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				return invalid(join(field, name), "обязательное поле")
+			}
+		}
+		for name, item := range v {
+			if prop, ok := schema.Properties[name]; ok {
+				if err := s.Validate(prop, item, join(field, name)); err != nil {
+					return err
+				}
+				continue
+			}
+			// лишние поля допускаются, как и при разборе в обработчиках
+			if schema.AdditionalProperties != nil {
+				if err := s.Validate(schema.AdditionalProperties, item, join(field, name)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func typeName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if isInteger(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func isInteger(n json.Number) bool {
+	if _, err := n.Int64(); err == nil {
+		return true
+	}
+	f, err := n.Float64()
+	return err == nil && f == float64(int64(f))
+}
+
+func matchesType(types SchemaType, value any) bool {
+	actual := typeName(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, v := range enum {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkFormat(format, value, field string) error {
+	switch format {
+	case "uuid":
+		if _, err := uuid.Parse(value); err != nil {
+			return invalid(field, "ожидается UUID")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return invalid(field, "ожидается дата в формате RFC 3339")
+		}
+	}
+	return nil
+}
+
+// parseParam приводит строковое значение параметра к типу схемы
+func parseParam(schema *Schema, raw string) any {
+	if schema == nil {
+		return raw
+	}
+	switch {
+	case schema.Type.has("integer"), schema.Type.has("number"):
+		n := json.Number(raw)
+		if _, err := n.Float64(); err != nil {
+			return raw
+		}
+		return n
+	case schema.Type.has("boolean"):
+		switch raw {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	return raw
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"taskTracker/internal/events"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Memory - outbox в памяти процесса для inmemory-хранилища.
+// Append вызывается под блокировкой хранилища вместе с мутацией,
+// а опубликованные записи удаляются сразу
+type Memory struct {
+	mtx     sync.Mutex
+	seq     int64
+	pending []Record
+
+	// onPublished сохраняет отметку о публикации до удаления записей.
+	// Вызывается без m.mtx: хранилище берёт свою блокировку раньше outbox
+	onPublished func([]uuid.UUID) error
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Append(pending ...events.Event) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, e := range pending {
+		m.seq++
+		m.pending = append(m.pending, Record{Seq: m.seq, Event: e})
+	}
+}
+
+// OnPublished задаёт хук, который хранилище с журналом использует, чтобы
+// после перезапуска не публиковать события повторно. Ошибка хука оставляет
+// записи неопубликованными
+func (m *Memory) OnPublished(hook func(ids []uuid.UUID) error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.onPublished = hook
+}
+
+// Events - неопубликованные события в порядке записи
+func (m *Memory) Events() []events.Event {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	res := make([]events.Event, len(m.pending))
+	for i, record := range m.pending {
+		res[i] = record.Event
+	}
+	return res
+}
+
+func (m *Memory) Len() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return len(m.pending)
+}
+
+func (m *Memory) OutboxBacklog(ctx context.Context) (int64, error) {
+	return int64(m.Len()), nil
+}
+
+func (m *Memory) ProcessOutbox(ctx context.Context, limit int, handle func([]Record) []int64) error {
+	m.mtx.Lock()
+	n := min(limit, len(m.pending))
+	batch := append([]Record(nil), m.pending[:n]...)
+	m.mtx.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	published := make(map[int64]bool)
+	for _, seq := range handle(batch) {
+		published[seq] = true
+	}
+
+	m.mtx.Lock()
+	hook := m.onPublished
+	m.mtx.Unlock()
+	if hook != nil && len(published) > 0 {
+		ids := make([]uuid.UUID, 0, len(published))
+		for _, record := range batch {
+			if published[record.Seq] {
+				ids = append(ids, record.Event.ID)
+			}
+		}
+		if err := hook(ids); err != nil {
+			return fmt.Errorf("отметка публикации: %w", err)
+		}
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	// пока партия публиковалась, в конец могли дописать новые записи
+	kept := m.pending[:0]
+	for _, record := range m.pending {
+		if !published[record.Seq] {
+			kept = append(kept, record)
+		}
+	}
+	m.pending = kept
+	return nil
+}
+
+func (m *Memory) CleanupOutbox(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
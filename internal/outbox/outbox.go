@@ -0,0 +1,178 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Record - событие, сохранённое вместе с мутацией задачи.
+// Seq монотонно растёт в порядке записи
+type Record struct {
+	Seq   int64
+	Event events.Event
+}
+
+// Store - хранилище outbox, которое пишется в одной транзакции с задачами
+type Store interface {
+	// ProcessOutbox передаёт handle неопубликованные записи в порядке Seq
+	// и отмечает опубликованными те, чьи Seq вернул handle
+	ProcessOutbox(ctx context.Context, limit int, handle func([]Record) []int64) error
+	// CleanupOutbox удаляет опубликованные записи старше before
+	CleanupOutbox(ctx context.Context, before time.Time) (int64, error)
+}
+
+// BacklogCounter - хранилище, которое умеет считать неопубликованные записи
+type BacklogCounter interface {
+	OutboxBacklog(ctx context.Context) (int64, error)
+}
+
+type RelayOptions struct {
+	Interval  time.Duration
+	BatchSize int
+	Retention time.Duration
+}
+
+// Relay переносит события из outbox в Publisher.
+//
+// Доставка at-least-once: запись отмечается опубликованной только после
+// успешного Publish, поэтому падение между ними приводит к повтору.
+// Порядок внутри одной задачи сохраняется: после первой неудачи остальные
+// события этой задачи откладываются до следующего тика. Неудача - это и
+// ошибка любого подписчика events.Bus: шина возвращает их все вместе
+type Relay struct {
+	store     Store
+	publisher events.Publisher
+	opts      RelayOptions
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewRelay(store Store, publisher events.Publisher, opts RelayOptions) *Relay {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 24 * time.Hour
+	}
+
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		opts:      opts,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+func (r *Relay) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.opts.Interval)
+		defer ticker.Stop()
+
+		lastCleanup := time.Now()
+		for {
+			select {
+			case <-r.stop:
+				// выгружаем то, что успели записать до остановки
+				r.drain()
+				return
+			case <-ticker.C:
+			}
+
+			r.drain()
+
+			if time.Since(lastCleanup) >= r.opts.Retention/24 {
+				r.cleanup()
+				lastCleanup = time.Now()
+			}
+		}
+	}()
+}
+
+// Stop публикует оставшиеся события и дожидается остановки
+func (r *Relay) Stop() {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+}
+
+// drain публикует партии, пока они заполняются целиком
+func (r *Relay) drain() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		start := time.Now()
+		published, fetched, err := r.Tick(ctx)
+		metrics.ObserveWorkerRun("outbox", start, published, err)
+		cancel()
+
+		if err != nil {
+			logger.Error("Outbox: Ошибка публикации событий", err)
+			return
+		}
+		if published > 0 {
+			logger.Log(zap.DebugLevel, "Outbox: События опубликованы", zap.Int("count", published))
+		}
+		if fetched < r.opts.BatchSize || published == 0 {
+			return
+		}
+	}
+}
+
+func (r *Relay) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	removed, err := r.store.CleanupOutbox(ctx, time.Now().Add(-r.opts.Retention))
+	if err != nil {
+		logger.Error("Outbox: Ошибка очистки опубликованных событий", err)
+		return
+	}
+	if removed > 0 {
+		logger.Info("Outbox: Удалены опубликованные события", zap.Int64("count", removed))
+	}
+}
+
+// Tick обрабатывает одну партию и возвращает число опубликованных
+// и прочитанных записей
+func (r *Relay) Tick(ctx context.Context) (published, fetched int, err error) {
+	err = r.store.ProcessOutbox(ctx, r.opts.BatchSize, func(records []Record) []int64 {
+		fetched = len(records)
+		blocked := make(map[uuid.UUID]bool)
+		done := make([]int64, 0, len(records))
+
+		for _, record := range records {
+			if blocked[record.Event.TaskID] {
+				continue
+			}
+			if err := r.publisher.Publish(ctx, record.Event); err != nil {
+				logger.Warn("Outbox: Не удалось опубликовать событие, повтор на следующем тике",
+					zap.Int64("seq", record.Seq),
+					zap.String("event", string(record.Event.Type)),
+					zap.String("task_id", record.Event.TaskID.String()),
+					zap.Error(err))
+				blocked[record.Event.TaskID] = true
+				continue
+			}
+			done = append(done, record.Seq)
+		}
+		published = len(done)
+		return done
+	})
+	if err != nil {
+		return 0, fetched, err
+	}
+	return published, fetched, nil
+}
@@ -0,0 +1,227 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/outbox"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// recorder запоминает опубликованные события и может отказывать
+// в публикации событий выбранных задач
+type recorder struct {
+	mtx    sync.Mutex
+	events []events.Event
+	fail   map[uuid.UUID]bool
+}
+
+func newRecorder() *recorder {
+	return &recorder{fail: make(map[uuid.UUID]bool)}
+}
+
+func (r *recorder) Publish(ctx context.Context, e events.Event) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.fail[e.TaskID] {
+		return errors.New("подписчик недоступен")
+	}
+	r.events = append(r.events, e)
+	return nil
+}
+
+func (r *recorder) setFail(id uuid.UUID, fail bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.fail[id] = fail
+}
+
+func (r *recorder) types(id uuid.UUID) []events.Type {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var res []events.Type
+	for _, e := range r.events {
+		if e.TaskID == id {
+			res = append(res, e.Type)
+		}
+	}
+	return res
+}
+
+func newEvent(eventType events.Type, id uuid.UUID) events.Event {
+	return events.New(eventType, &task.Task{UUID: id, Title: "Задача"})
+}
+
+// TestRelay_PublishesInOrder тестирует публикацию событий в порядке записи
+func TestRelay_PublishesInOrder(t *testing.T) {
+	store := outbox.NewMemory()
+	pub := newRecorder()
+	relay := outbox.NewRelay(store, pub, outbox.RelayOptions{BatchSize: 2})
+
+	id := uuid.New()
+	store.Append(
+		newEvent(events.TaskCreated, id),
+		newEvent(events.TaskUpdated, id),
+		newEvent(events.TaskArchived, id),
+	)
+
+	published, fetched, err := relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, 2, fetched)
+
+	published, _, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	assert.Equal(t, []events.Type{events.TaskCreated, events.TaskUpdated, events.TaskArchived}, pub.types(id))
+	assert.Zero(t, store.Len())
+}
+
+// TestRelay_BlocksTaskAfterFailure тестирует, что неудача откладывает
+// последующие события той же задачи, не задерживая остальные
+func TestRelay_BlocksTaskAfterFailure(t *testing.T) {
+	store := outbox.NewMemory()
+	pub := newRecorder()
+	relay := outbox.NewRelay(store, pub, outbox.RelayOptions{})
+
+	failing, healthy := uuid.New(), uuid.New()
+	store.Append(
+		newEvent(events.TaskCreated, failing),
+		newEvent(events.TaskCreated, healthy),
+		newEvent(events.TaskUpdated, failing),
+	)
+
+	pub.setFail(failing, true)
+	published, fetched, err := relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 3, fetched)
+	assert.Equal(t, []events.Type{events.TaskCreated}, pub.types(healthy))
+	assert.Empty(t, pub.types(failing))
+	assert.Equal(t, 2, store.Len())
+
+	// после восстановления подписчика события доходят в исходном порядке
+	pub.setFail(failing, false)
+	published, _, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []events.Type{events.TaskCreated, events.TaskUpdated}, pub.types(failing))
+	assert.Zero(t, store.Len())
+}
+
+// TestRelay_BusHandlerFailure тестирует, что ошибка подписчика шины
+// оставляет событие в outbox, а повтор доходит только до упавшего подписчика
+func TestRelay_BusHandlerFailure(t *testing.T) {
+	store := outbox.NewMemory()
+	bus := events.NewBus()
+	relay := outbox.NewRelay(store, bus, outbox.RelayOptions{})
+
+	var stream, journal int
+	failing := true
+	bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		stream++
+		return nil
+	})
+	bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		journal++
+		if failing {
+			return errors.New("журнал недоступен")
+		}
+		return nil
+	})
+
+	store.Append(newEvent(events.TaskCreated, uuid.New()))
+
+	published, _, err := relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, published)
+	assert.Equal(t, 1, store.Len())
+
+	failing = false
+	published, _, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Zero(t, store.Len())
+	assert.Equal(t, 1, stream)
+	assert.Equal(t, 2, journal)
+}
+
+// TestRelay_StopDrains тестирует публикацию оставшихся событий при остановке
+func TestRelay_StopDrains(t *testing.T) {
+	store := outbox.NewMemory()
+	pub := newRecorder()
+	relay := outbox.NewRelay(store, pub, outbox.RelayOptions{Interval: time.Hour, BatchSize: 1})
+
+	id := uuid.New()
+	store.Append(newEvent(events.TaskCreated, id), newEvent(events.TaskDeleted, id))
+
+	relay.Start()
+	relay.Stop()
+
+	assert.Equal(t, []events.Type{events.TaskCreated, events.TaskDeleted}, pub.types(id))
+	assert.Zero(t, store.Len())
+}
+
+// TestOutbox_InmemoryRepository тестирует запись событий сервиса
+// в outbox inmemory-хранилища
+func TestOutbox_InmemoryRepository(t *testing.T) {
+	ctx := context.Background()
+	storage := inmemory.NewTaskStorage()
+	store := storage.EnableOutbox()
+	svc := service.NewTaskService(storage, "inmemory")
+
+	created, err := svc.CreateTask(ctx, "Задача", "", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = svc.ArchiveTask(ctx, created.UUID)
+	require.NoError(t, err)
+	_, err = svc.UnarchiveTask(ctx, created.UUID)
+	require.NoError(t, err)
+
+	// без публикатора сервис ничего не отправляет сам, всё лежит в outbox
+	assert.Equal(t, 3, store.Len())
+
+	pub := newRecorder()
+	published, _, err := outbox.NewRelay(store, pub, outbox.RelayOptions{}).Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.Equal(t, []events.Type{events.TaskCreated, events.TaskArchived, events.TaskUnarchived}, pub.types(created.UUID))
+
+	// снапшот задачи в событии соответствует состоянию после мутации
+	pub.mtx.Lock()
+	defer pub.mtx.Unlock()
+	require.NotNil(t, pub.events[1].Task)
+	assert.Equal(t, task.FlagArchived, pub.events[1].Task.Flag)
+}
+
+// TestOutbox_FailedMutationRecordsNothing тестирует, что неудачная
+// мутация не оставляет событий в outbox
+func TestOutbox_FailedMutationRecordsNothing(t *testing.T) {
+	ctx := context.Background()
+	storage := inmemory.NewTaskStorage()
+	store := storage.EnableOutbox()
+	svc := service.NewTaskService(storage, "inmemory")
+
+	_, err := svc.ArchiveTask(ctx, uuid.New())
+	require.Error(t, err)
+	assert.Zero(t, store.Len())
+}
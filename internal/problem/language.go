@@ -0,0 +1,60 @@
+package problem
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage - язык, если Accept-Language не задан или не поддерживается
+const DefaultLanguage = "ru"
+
+// Languages - поддерживаемые языки ответов
+var Languages = []string{"ru", "en"}
+
+// Language выбирает язык ответа по Accept-Language с учётом q-весов.
+// en-US и en-GB считаются en
+func Language(r *http.Request) string {
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		for _, lang := range Languages {
+			if primary == lang && q > bestQ {
+				best, bestQ = lang, q
+			}
+		}
+	}
+	return best
+}
+
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// render подставляет details в шаблон. Если какого-то поля нет,
+// возвращает пустую строку: лучше общий текст, чем «{task_id}» в ответе
+func render(template string, details map[string]any) string {
+	missing := false
+	rendered := placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		value, ok := details[match[1:len(match)-1]]
+		if !ok {
+			missing = true
+			return match
+		}
+		return fmt.Sprint(value)
+	})
+	if missing {
+		return ""
+	}
+	return rendered
+}
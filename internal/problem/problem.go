@@ -0,0 +1,121 @@
+// Package problem - ответы HTTP API с ошибками в формате RFC 7807
+// (application/problem+json). Любая ошибка проходит через Write: бизнес-ошибка
+// отдаётся со статусом и текстами своего кода, остальные - как 500 без подробностей
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ContentType = "application/problem+json"
+
+	// TypePrefix - префикс type: urn:task-tracker:problem:not-found
+	TypePrefix = "urn:task-tracker:problem:"
+)
+
+// Коды ошибок HTTP-слоя. Коды бизнес-ошибок - в service
+const (
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeRouteNotFound        = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeTimeout              = "TIMEOUT"
+	CodeUnavailable          = "UNAVAILABLE"
+	CodeInternal             = "INTERNAL_ERROR"
+)
+
+// Problem - тело ответа. Code дублирует type коротким кодом для клиентов,
+// Extensions - details бизнес-ошибки, выводятся на верхнем уровне
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       string
+	Extensions map[string]any
+}
+
+// reserved - поля, которые details не может перезаписать
+var reserved = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true, "code": true}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	body := make(map[string]any, len(p.Extensions)+6)
+	for key, value := range p.Extensions {
+		if !reserved[key] {
+			body[key] = value
+		}
+	}
+	body["type"] = p.Type
+	body["title"] = p.Title
+	body["status"] = p.Status
+	body["code"] = p.Code
+	if p.Detail != "" {
+		body["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		body["instance"] = p.Instance
+	}
+	return json.Marshal(body)
+}
+
+// New собирает ответ на ошибку на языке lang
+func New(err error, lang string) Problem {
+	var businessErr *service.BusinessError
+	if !errors.As(err, &businessErr) {
+		businessErr = &service.BusinessError{Code: CodeInternal}
+	}
+
+	msg := message(lang, businessErr.Code)
+	detail := render(msg.Detail, businessErr.Details)
+	if detail == "" && lang == DefaultLanguage {
+		// тексты бизнес-ошибок пишутся в сервисе на русском
+		detail = businessErr.Message
+	}
+	if detail == "" {
+		detail = msg.Title
+	}
+
+	return Problem{
+		Type:       TypePrefix + strings.ReplaceAll(strings.ToLower(businessErr.Code), "_", "-"),
+		Title:      msg.Title,
+		Status:     Status(businessErr.Code),
+		Detail:     detail,
+		Code:       businessErr.Code,
+		Extensions: maps.Clone(businessErr.Details),
+	}
+}
+
+// Write отвечает ошибкой на языке из Accept-Language. instance - ID запроса
+// из заголовка X-Request-ID, который middleware.RequestID ставит до обработчика
+func Write(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	lang := Language(r)
+	p := New(err, lang)
+	p.Instance = w.Header().Get("X-Request-ID")
+
+	if p.Code == CodeInternal {
+		logger.ErrorCtx(r.Context(), "HTTP: Внутренняя ошибка", err, fields...)
+	} else {
+		logger.WarnCtx(r.Context(), "HTTP: Ошибка запроса", append(fields,
+			zap.String("error_code", p.Code),
+			zap.Int("http_status", p.Status),
+			zap.Any("details", p.Extensions))...)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
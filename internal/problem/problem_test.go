@@ -0,0 +1,102 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/problem"
+	"taskTracker/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// TestLanguage тестирует выбор языка по Accept-Language
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "ru"},
+		{"en", "en"},
+		{"en-US,en;q=0.9", "en"},
+		{"de-DE, en;q=0.5", "en"},
+		{"en;q=0.3, ru;q=0.8", "ru"},
+		{"fr, de", "ru"},
+		{"en;q=abc", "ru"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Language", tt.header)
+			assert.Equal(t, tt.want, problem.Language(r))
+		})
+	}
+}
+
+// TestNew тестирует тексты ошибки на разных языках
+func TestNew(t *testing.T) {
+	err := service.NewBusinessError(service.CodeAlreadyArchived, "Задача уже в архиве",
+		service.ToDetail("task_id", "42"))
+
+	ru := problem.New(err, "ru")
+	assert.Equal(t, "urn:task-tracker:problem:already-archived", ru.Type)
+	assert.Equal(t, http.StatusConflict, ru.Status)
+	assert.Equal(t, "Задача уже в архиве", ru.Detail)
+	assert.Equal(t, map[string]any{"task_id": "42"}, ru.Extensions)
+
+	en := problem.New(err, "en")
+	assert.Equal(t, "Task already archived", en.Title)
+	assert.Equal(t, "Task 42 is already archived", en.Detail)
+
+	// без task_id шаблон не подставить - остаётся заголовок
+	en = problem.New(service.NewBusinessError(service.CodeAlreadyArchived, "Задача уже в архиве"), "en")
+	assert.Equal(t, "Task already archived", en.Detail)
+
+	// незарегистрированный код - 400
+	assert.Equal(t, http.StatusBadRequest, problem.New(service.NewBusinessError("SOMETHING", "текст"), "ru").Status)
+
+	internal := problem.New(errors.New("connection refused"), "en")
+	assert.Equal(t, problem.CodeInternal, internal.Code)
+	assert.Equal(t, http.StatusInternalServerError, internal.Status)
+	assert.NotContains(t, internal.Detail, "connection refused")
+}
+
+// TestWrite тестирует заголовки и тело ответа
+func TestWrite(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/tasks/42", nil)
+	r.Header.Set("Accept-Language", "en-US")
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Request-ID", "req-1")
+
+	problem.Write(w, r, service.NewBusinessError(service.CodeNotFound, "Задача не найдена",
+		service.ToDetail("id", "42"),
+		service.ToDetail("code", "перезаписать нельзя")))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{
+		"type":     "urn:task-tracker:problem:not-found",
+		"title":    "Not found",
+		"status":   float64(http.StatusNotFound),
+		"detail":   "Resource 42 was not found",
+		"instance": "req-1",
+		"code":     service.CodeNotFound,
+		"id":       "42",
+	}, body)
+}
@@ -0,0 +1,172 @@
+package problem
+
+import (
+	"net/http"
+	"sync"
+	"taskTracker/internal/service"
+)
+
+// Message - тексты ошибки на одном языке. В Detail подставляются details
+// бизнес-ошибки: {task_id}. Пустой Detail в языке по умолчанию - текст
+// самой бизнес-ошибки
+type Message struct {
+	Title  string
+	Detail string
+}
+
+// Definition - код ошибки: HTTP-статус и тексты по языкам
+type Definition struct {
+	Status   int
+	Messages map[string]Message
+}
+
+var (
+	mtx      sync.RWMutex
+	registry = map[string]Definition{}
+)
+
+// Register добавляет код ошибки или заменяет существующий
+func Register(code string, def Definition) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	registry[code] = def
+}
+
+func lookup(code string) (Definition, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	def, ok := registry[code]
+	return def, ok
+}
+
+// Status - HTTP-статус кода. Незарегистрированный код - 400
+func Status(code string) int {
+	if def, ok := lookup(code); ok {
+		return def.Status
+	}
+	return http.StatusBadRequest
+}
+
+func message(lang, code string) Message {
+	def, ok := lookup(code)
+	if !ok {
+		def, _ = lookup(CodeBadRequest)
+	}
+	if msg, ok := def.Messages[lang]; ok {
+		return msg
+	}
+	return def.Messages[DefaultLanguage]
+}
+
+func init() {
+	codes := map[string]Definition{
+		service.CodeNotFound: {http.StatusNotFound, map[string]Message{
+			"ru": {Title: "Не найдено"},
+			"en": {Title: "Not found", Detail: "Resource {id} was not found"},
+		}},
+		service.CodeValidation: {http.StatusBadRequest, map[string]Message{
+			"ru": {Title: "Ошибка валидации"},
+			"en": {Title: "Validation error", Detail: "Invalid value of field '{field}'"},
+		}},
+		service.CodeNotRecurring: {http.StatusBadRequest, map[string]Message{
+			"ru": {Title: "Задача не повторяется"},
+			"en": {Title: "Task is not recurring", Detail: "Task {task_id} has no recurrence rule"},
+		}},
+		service.CodeAlreadyArchived: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Задача уже в архиве"},
+			"en": {Title: "Task already archived", Detail: "Task {task_id} is already archived"},
+		}},
+		service.CodeNotArchived: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Задача не в архиве"},
+			"en": {Title: "Task not archived", Detail: "Task {task_id} is already active"},
+		}},
+		service.CodeAlreadyDeleted: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Задача уже удалена"},
+			"en": {Title: "Task already deleted", Detail: "Task {task_id} is already deleted"},
+		}},
+		service.CodeNotDeleted: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Задача не удалена"},
+			"en": {Title: "Task not deleted", Detail: "Task {task_id} is not deleted, current flag: '{current_flag}'"},
+		}},
+		service.CodeInvalidFlag: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Недопустимый флаг задачи"},
+			"en": {Title: "Invalid task flag", Detail: "Operation is not allowed for task {task_id} with flag '{current_flag}'"},
+		}},
+		service.CodeInProgress: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Задача в работе"},
+			"en": {Title: "Task in progress", Detail: "Task {task_id} is in progress and cannot be deleted"},
+		}},
+		service.CodeVersionConflict: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Конфликт версий"},
+			"en": {Title: "Version conflict", Detail: "Task {task_id} was modified concurrently, reload it and try again"},
+		}},
+		service.CodeInvalidTransition: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Недопустимый переход статуса"},
+			"en": {Title: "Invalid status transition", Detail: "Task {task_id} cannot move from '{from}' to '{to}'"},
+		}},
+		service.CodeWIPLimitExceeded: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Превышен WIP-лимит колонки"},
+			"en": {Title: "WIP limit exceeded", Detail: "Column '{column}' of board '{board}' already holds {wip_limit} tasks"},
+		}},
+		service.CodeTaskClosed: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Задача закрыта"},
+			"en": {Title: "Task is closed", Detail: "Task is in status '{status}', no more work can be logged by timer"},
+		}},
+		service.CodeTimerRunning: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Таймер уже запущен"},
+			"en": {Title: "Timer already running", Detail: "Timer of user '{user}' for this task is already running"},
+		}},
+		service.CodeTimerNotRunning: {http.StatusConflict, map[string]Message{
+			"ru": {Title: "Таймер не запущен"},
+			"en": {Title: "Timer not running", Detail: "User '{user}' has no running timer for this task"},
+		}},
+		service.CodeTaskDeleted: {http.StatusGone, map[string]Message{
+			"ru": {Title: "Задача удалена"},
+			"en": {Title: "Task deleted", Detail: "Task {task_id} is deleted"},
+		}},
+		service.CodeRestoreExpired: {http.StatusGone, map[string]Message{
+			"ru": {Title: "Срок восстановления истёк"},
+			"en": {Title: "Restore period expired", Detail: "Task {task_id} was deleted more than 30 days ago and cannot be restored"},
+		}},
+
+		CodeBadRequest: {http.StatusBadRequest, map[string]Message{
+			"ru": {Title: "Неверный запрос"},
+			"en": {Title: "Bad request", Detail: "The request could not be parsed"},
+		}},
+		CodeUnsupportedMediaType: {http.StatusUnsupportedMediaType, map[string]Message{
+			"ru": {Title: "Неподдерживаемый тип содержимого"},
+			"en": {Title: "Unsupported media type", Detail: "Content-Type must be {expected}"},
+		}},
+		CodeUnauthorized: {http.StatusUnauthorized, map[string]Message{
+			"ru": {Title: "Требуется авторизация"},
+			"en": {Title: "Unauthorized", Detail: "Missing or invalid Bearer token"},
+		}},
+		CodeRouteNotFound: {http.StatusNotFound, map[string]Message{
+			"ru": {Title: "Маршрут не найден"},
+			"en": {Title: "Route not found", Detail: "No route for {method} {path}"},
+		}},
+		CodeMethodNotAllowed: {http.StatusMethodNotAllowed, map[string]Message{
+			"ru": {Title: "Метод не поддерживается"},
+			"en": {Title: "Method not allowed", Detail: "Method {method} is not allowed for {path}"},
+		}},
+		CodeRateLimited: {http.StatusTooManyRequests, map[string]Message{
+			"ru": {Title: "Слишком много запросов"},
+			"en": {Title: "Too many requests", Detail: "Rate limit exceeded, retry in {retry_after} s"},
+		}},
+		CodeTimeout: {http.StatusGatewayTimeout, map[string]Message{
+			"ru": {Title: "Таймаут запроса"},
+			"en": {Title: "Request timeout", Detail: "The request took too long to process"},
+		}},
+		CodeUnavailable: {http.StatusServiceUnavailable, map[string]Message{
+			"ru": {Title: "Сервис недоступен"},
+			"en": {Title: "Service unavailable", Detail: "The service is starting or shutting down"},
+		}},
+		CodeInternal: {http.StatusInternalServerError, map[string]Message{
+			"ru": {Title: "Внутренняя ошибка сервера", Detail: "Внутренняя ошибка сервера"},
+			"en": {Title: "Internal server error", Detail: "Internal server error"},
+		}},
+	}
+	for code, def := range codes {
+		Register(code, def)
+	}
+}
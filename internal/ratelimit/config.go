@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config - политики лимитера, файл RATELIMIT_CONFIG:
+//
+//	trusted_proxies: [10.0.0.0/8]
+//	default: {algorithm: sliding_window, limit: 100, window: 1m}
+//	routes:
+//	  - {name: create-task, methods: [POST], route: /tasks, algorithm: token_bucket, limit: 10, window: 1m, burst: 5}
+//	principals:
+//	  - {name: ci, tokens: [ci-secret], networks: [192.168.0.0/16], algorithm: token_bucket, limit: 1000, window: 1m}
+type Config struct {
+	// TrustedProxies - адреса и подсети прокси, которым доверяется X-Forwarded-For
+	TrustedProxies []string    `yaml:"trusted_proxies"`
+	Default        Policy      `yaml:"default"`
+	Routes         []Route     `yaml:"routes"`
+	Principals     []Principal `yaml:"principals"`
+}
+
+// Route - отдельный лимит на маршрут chi (/tasks/{id}), сверх общего.
+// Пустой Methods - все методы
+type Route struct {
+	Policy  `yaml:",inline"`
+	Methods []string `yaml:"methods"`
+	Route   string   `yaml:"route"`
+}
+
+// Principal - известный клиент: узнаётся по Bearer-токену или подсети,
+// получает свою общую политику вместо Default. Все его запросы делят один счётчик
+type Principal struct {
+	Policy   `yaml:",inline"`
+	Tokens   []string `yaml:"tokens"`
+	Networks []string `yaml:"networks"`
+}
+
+// LoadConfig читает политики из YAML. Поля default, не заданные в файле,
+// берутся из defaults
+func LoadConfig(path string, defaults Policy) (Config, error) {
+	cfg := Config{Default: defaults}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("чтение %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("разбор %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// compiled - конфиг, разобранный для быстрого сопоставления запросов
+type compiled struct {
+	proxies    []netip.Prefix
+	global     Policy
+	routes     []Route
+	principals []compiledPrincipal
+}
+
+type compiledPrincipal struct {
+	policy   Policy
+	tokens   map[string]bool
+	networks []netip.Prefix
+}
+
+func compile(cfg Config) (*compiled, error) {
+	c := &compiled{global: withDefaults(cfg.Default, "default", Policy{})}
+	if err := c.global.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, proxy := range cfg.TrustedProxies {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted_proxies: %w", err)
+		}
+		c.proxies = append(c.proxies, prefix)
+	}
+
+	for i, route := range cfg.Routes {
+		route.Policy = withDefaults(route.Policy, fmt.Sprintf("route-%d", i+1), c.global)
+		if err := route.Validate(); err != nil {
+			return nil, err
+		}
+		if route.Route == "" {
+			return nil, fmt.Errorf("политика %s: не задан route", route.Name)
+		}
+		route.Route = normalizeRoute(route.Route)
+		for j, method := range route.Methods {
+			route.Methods[j] = strings.ToUpper(method)
+		}
+		c.routes = append(c.routes, route)
+	}
+
+	for i, principal := range cfg.Principals {
+		p := compiledPrincipal{
+			policy: withDefaults(principal.Policy, fmt.Sprintf("principal-%d", i+1), c.global),
+			tokens: make(map[string]bool),
+		}
+		if err := p.policy.Validate(); err != nil {
+			return nil, err
+		}
+		for _, token := range principal.Tokens {
+			p.tokens[token] = true
+		}
+		for _, network := range principal.Networks {
+			prefix, err := parsePrefix(network)
+			if err != nil {
+				return nil, fmt.Errorf("политика %s: %w", p.policy.Name, err)
+			}
+			p.networks = append(p.networks, prefix)
+		}
+		c.principals = append(c.principals, p)
+	}
+	return c, nil
+}
+
+// withDefaults дополняет политику алгоритмом, лимитом и окном из base
+func withDefaults(p Policy, name string, base Policy) Policy {
+	if p.Name == "" {
+		p.Name = name
+	}
+	if p.Algorithm == "" {
+		p.Algorithm = base.Algorithm
+	}
+	if p.Limit == 0 {
+		p.Limit = base.Limit
+	}
+	if p.Window == 0 {
+		p.Window = base.Window
+	}
+	return p
+}
+
+// parsePrefix принимает подсеть или одиночный адрес
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+func normalizeRoute(route string) string {
+	if route != "/" {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return route
+}
@@ -0,0 +1,224 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"taskTracker/internal/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Request - то, по чему лимитер выбирает политики и ключи
+type Request struct {
+	IP     string
+	Token  string
+	Method string
+	// Route - шаблон маршрута chi: /tasks/{id}
+	Route string
+}
+
+type Options struct {
+	Store Store
+	// IdleTimeout - через сколько простоя ключ удаляется из хранилища
+	IdleTimeout time.Duration
+	// EvictInterval - период удаления простаивающих ключей
+	EvictInterval time.Duration
+}
+
+type Limiter struct {
+	cfg  *compiled
+	opts Options
+	now  func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func New(cfg Config, opts Options) (*Limiter, error) {
+	c, err := compile(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Limiter{
+		cfg:  c,
+		opts: opts,
+		now:  time.Now,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
+
+// Allow проверяет запрос по всем подходящим политикам. Возвращает отказ первой
+// отклонившей политики, а если прошли все - решение с наименьшим остатком.
+// При ошибке хранилища запрос пропускается: лимитер не должен ронять API
+func (l *Limiter) Allow(ctx context.Context, req Request) Decision {
+	now := l.now()
+	var result Decision
+	for i, c := range l.checks(req) {
+		d, err := l.opts.Store.TakeRateLimit(ctx, c.key, c.policy, now)
+		if err != nil {
+			logger.Warn("RateLimit: Хранилище недоступно, запрос пропущен",
+				zap.String("policy", c.policy.Name), zap.Error(err))
+			d = Decision{Allowed: true, Policy: c.policy, Remaining: c.policy.Limit}
+		}
+		if !d.Allowed {
+			return d
+		}
+		if i == 0 || d.Remaining < result.Remaining {
+			result = d
+		}
+	}
+	return result
+}
+
+// Policies - все политики, которые применяются к запросу, для RateLimit-Policy
+func (l *Limiter) Policies(req Request) []Policy {
+	checks := l.checks(req)
+	policies := make([]Policy, len(checks))
+	for i, c := range checks {
+		policies[i] = c.policy
+	}
+	return policies
+}
+
+type check struct {
+	key    string
+	policy Policy
+}
+
+// checks - общая политика клиента и политики подходящих маршрутов
+// с ключами счётчиков
+func (l *Limiter) checks(req Request) []check {
+	global, principal := l.principal(req)
+	checks := []check{{key: global.Name + ":" + principal, policy: global}}
+	for _, route := range l.cfg.routes {
+		if route.matches(req) {
+			checks = append(checks, check{key: route.Name + ":" + principal, policy: route.Policy})
+		}
+	}
+	return checks
+}
+
+// Principal - имя известного клиента из principals или пустая строка
+func (l *Limiter) Principal(req Request) string {
+	if p := l.known(req); p != nil {
+		return p.policy.Name
+	}
+	return ""
+}
+
+// principal - общая политика и ключ клиента. Неизвестный токен не даёт
+// отдельного счётчика, иначе лимит обходился бы случайными токенами
+func (l *Limiter) principal(req Request) (Policy, string) {
+	if p := l.known(req); p != nil {
+		return p.policy, "principal:" + p.policy.Name
+	}
+	return l.cfg.global, "ip:" + req.IP
+}
+
+// known ищет клиента по токену или подсети
+func (l *Limiter) known(req Request) *compiledPrincipal {
+	addr, _ := netip.ParseAddr(req.IP)
+	for i := range l.cfg.principals {
+		p := &l.cfg.principals[i]
+		if req.Token != "" && p.tokens[req.Token] {
+			return p
+		}
+		if addr.IsValid() && slices.ContainsFunc(p.networks, func(n netip.Prefix) bool { return n.Contains(addr.Unmap()) }) {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r Route) matches(req Request) bool {
+	if normalizeRoute(req.Route) != r.Route {
+		return false
+	}
+	return len(r.Methods) == 0 || slices.Contains(r.Methods, req.Method)
+}
+
+// ClientIP - адрес клиента. X-Forwarded-For учитывается, только если запрос
+// пришёл от доверенного прокси: цепочка читается справа налево до первого
+// адреса не из доверенных
+func (l *Limiter) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !l.trusted(host) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		if _, err := netip.ParseAddr(hop); err != nil {
+			// мусор в заголовке: дальше цепочке доверять нельзя
+			return host
+		}
+		host = hop
+		if !l.trusted(hop) {
+			return hop
+		}
+	}
+	return host
+}
+
+func (l *Limiter) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	return slices.ContainsFunc(l.cfg.proxies, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
+
+// Start запускает периодическое удаление простаивающих ключей
+func (l *Limiter) Start() {
+	go func() {
+		defer close(l.done)
+
+		ticker := time.NewTicker(l.opts.EvictInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				l.evict()
+			}
+		}
+	}()
+}
+
+// Stop дожидается остановки удаления ключей
+func (l *Limiter) Stop() {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+}
+
+func (l *Limiter) evict() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	evicted, err := l.opts.Store.EvictRateLimits(ctx, l.now().Add(-l.opts.IdleTimeout))
+	if err != nil {
+		logger.Error("RateLimit: Ошибка удаления простаивающих ключей", err)
+		return
+	}
+	if evicted > 0 {
+		logger.Log(zap.DebugLevel, "RateLimit: Удалены простаивающие ключи", zap.Int64("count", evicted))
+	}
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore - счётчики в памяти процесса. Число ключей ограничено MaxKeys:
+// при переполнении вытесняется ключ, к которому дольше всех не обращались
+type MemoryStore struct {
+	maxKeys int
+
+	mtx   sync.Mutex
+	items map[string]*list.Element
+	// order - от недавних к давним, поэтому простаивающие ключи всегда в хвосте
+	order *list.List
+}
+
+type memoryEntry struct {
+	key      string
+	state    State
+	lastSeen time.Time
+}
+
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	return &MemoryStore{
+		maxKeys: maxKeys,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (s *MemoryStore) TakeRateLimit(ctx context.Context, key string, p Policy, now time.Time) (Decision, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	el, ok := s.items[key]
+	if ok {
+		s.order.MoveToFront(el)
+	} else {
+		if s.maxKeys > 0 && s.order.Len() >= s.maxKeys {
+			s.remove(s.order.Back())
+		}
+		el = s.order.PushFront(&memoryEntry{key: key})
+		s.items[key] = el
+	}
+
+	entry := el.Value.(*memoryEntry)
+	entry.lastSeen = now
+	return Take(&entry.state, p, now), nil
+}
+
+func (s *MemoryStore) EvictRateLimits(ctx context.Context, before time.Time) (int64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var evicted int64
+	for el := s.order.Back(); el != nil && el.Value.(*memoryEntry).lastSeen.Before(before); el = s.order.Back() {
+		s.remove(el)
+		evicted++
+	}
+	return evicted, nil
+}
+
+func (s *MemoryStore) Len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.items, el.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,161 @@
+// Package ratelimit - ограничение частоты запросов. Лимитер проверяет запрос
+// по набору политик: общей (своя для известного клиента - principal)
+// и политикам маршрутов. Запрос проходит, только если его пропустили все.
+//
+// Состояние счётчиков лежит в Store: в памяти процесса (MemoryStore) или
+// в PostgreSQL, чтобы лимиты были общими для всех реплик
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Алгоритмы
+const (
+	// TokenBucket - ведро на Burst токенов, пополняется со скоростью Limit за Window.
+	// Допускает короткие всплески
+	TokenBucket = "token_bucket"
+	// SlidingWindow - не больше Limit запросов за любое окно Window
+	// (взвешенная сумма текущего и предыдущего окна)
+	SlidingWindow = "sliding_window"
+)
+
+type Policy struct {
+	Name      string        `yaml:"name"`
+	Algorithm string        `yaml:"algorithm"`
+	Limit     int           `yaml:"limit"`
+	Window    time.Duration `yaml:"window"`
+	// Burst - ёмкость ведра для token_bucket, по умолчанию Limit
+	Burst int `yaml:"burst"`
+}
+
+func (p Policy) Validate() error {
+	switch p.Algorithm {
+	case TokenBucket, SlidingWindow:
+	default:
+		return fmt.Errorf("политика %s: неизвестный алгоритм %q", p.Name, p.Algorithm)
+	}
+	if p.Limit <= 0 {
+		return fmt.Errorf("политика %s: limit должен быть больше нуля", p.Name)
+	}
+	if p.Window <= 0 {
+		return fmt.Errorf("политика %s: window должен быть больше нуля", p.Name)
+	}
+	if p.Burst < 0 {
+		return fmt.Errorf("политика %s: burst не может быть отрицательным", p.Name)
+	}
+	return nil
+}
+
+func (p Policy) capacity() float64 {
+	if p.Algorithm == TokenBucket && p.Burst > 0 {
+		return float64(p.Burst)
+	}
+	return float64(p.Limit)
+}
+
+// Header - описание политики для заголовка RateLimit-Policy: 100;w=60
+func (p Policy) Header() string {
+	return fmt.Sprintf("%d;w=%d", p.Limit, int(math.Ceil(p.Window.Seconds())))
+}
+
+// Decision - результат проверки одной политики
+type Decision struct {
+	Allowed   bool
+	Policy    Policy
+	Remaining int
+	// Reset - через сколько лимит восстановится полностью
+	Reset time.Duration
+	// RetryAfter - через сколько пройдёт следующий запрос, если этот отклонён
+	RetryAfter time.Duration
+}
+
+// State - состояние счётчика одного ключа. Для token_bucket Value - токены
+// в ведре, Stamp - время последнего пополнения. Для sliding_window Value -
+// запросы текущего окна, Prev - предыдущего, Stamp - начало текущего окна
+type State struct {
+	Value float64
+	Prev  float64
+	Stamp time.Time
+}
+
+// Take применяет запрос к состоянию по алгоритму политики.
+// Хранилища вызывают его под своей блокировкой ключа
+func Take(state *State, p Policy, now time.Time) Decision {
+	if p.Algorithm == SlidingWindow {
+		return takeWindow(state, p, now)
+	}
+	return takeBucket(state, p, now)
+}
+
+func takeBucket(state *State, p Policy, now time.Time) Decision {
+	capacity := p.capacity()
+	rate := float64(p.Limit) / p.Window.Seconds() // токенов в секунду
+
+	if state.Stamp.IsZero() {
+		state.Value = capacity
+	} else if elapsed := now.Sub(state.Stamp).Seconds(); elapsed > 0 {
+		state.Value = math.Min(capacity, state.Value+elapsed*rate)
+	}
+	state.Stamp = now
+
+	d := Decision{Policy: p}
+	if state.Value >= 1 {
+		state.Value--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = seconds((1 - state.Value) / rate)
+	}
+	d.Remaining = int(state.Value)
+	d.Reset = seconds((capacity - state.Value) / rate)
+	return d
+}
+
+func takeWindow(state *State, p Policy, now time.Time) Decision {
+	start := now.Truncate(p.Window)
+	if !state.Stamp.Equal(start) {
+		if start.Sub(state.Stamp) == p.Window {
+			state.Prev = state.Value
+		} else {
+			state.Prev = 0
+		}
+		state.Value = 0
+		state.Stamp = start
+	}
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(p.Window)
+	estimated := state.Prev*weight + state.Value
+	limit := float64(p.Limit)
+
+	d := Decision{Policy: p, Reset: p.Window - elapsed}
+	if estimated+1 <= limit {
+		state.Value++
+		d.Allowed = true
+		d.Remaining = int(limit - estimated - 1)
+		return d
+	}
+
+	// когда вес предыдущего окна упадёт настолько, что запрос поместится
+	d.RetryAfter = p.Window - elapsed
+	if free := limit - state.Value - 1; free >= 0 && state.Prev > 0 {
+		wait := time.Duration((1-free/state.Prev)*float64(p.Window)) - elapsed
+		d.RetryAfter = max(wait, time.Millisecond)
+	}
+	return d
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Store хранит состояние счётчиков. Реализация сама обеспечивает
+// атомарность чтения-изменения-записи одного ключа
+type Store interface {
+	TakeRateLimit(ctx context.Context, key string, p Policy, now time.Time) (Decision, error)
+	// EvictRateLimits удаляет ключи, к которым не обращались с before
+	EvictRateLimits(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,246 @@
+package ratelimit_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/middleware"
+	"taskTracker/internal/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// TestTake_TokenBucket тестирует всплеск до burst и пополнение ведра
+func TestTake_TokenBucket(t *testing.T) {
+	policy := ratelimit.Policy{Name: "tb", Algorithm: ratelimit.TokenBucket, Limit: 60, Window: time.Minute, Burst: 3}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var state ratelimit.State
+
+	for i := 0; i < 3; i++ {
+		d := ratelimit.Take(&state, policy, now)
+		require.True(t, d.Allowed, "запрос %d", i+1)
+		assert.Equal(t, 2-i, d.Remaining)
+	}
+
+	d := ratelimit.Take(&state, policy, now)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, time.Second, d.RetryAfter, "токен в секунду")
+	assert.Equal(t, 3*time.Second, d.Reset)
+
+	d = ratelimit.Take(&state, policy, now.Add(time.Second))
+	assert.True(t, d.Allowed)
+
+	d = ratelimit.Take(&state, policy, now.Add(time.Hour))
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 2, d.Remaining, "ведро не переполняется сверх burst")
+}
+
+// TestTake_SlidingWindow тестирует учёт предыдущего окна
+func TestTake_SlidingWindow(t *testing.T) {
+	policy := ratelimit.Policy{Name: "sw", Algorithm: ratelimit.SlidingWindow, Limit: 10, Window: time.Minute}
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var state ratelimit.State
+
+	for i := 0; i < 10; i++ {
+		require.True(t, ratelimit.Take(&state, policy, start.Add(50*time.Second)).Allowed)
+	}
+	d := ratelimit.Take(&state, policy, start.Add(55*time.Second))
+	assert.False(t, d.Allowed)
+	assert.Equal(t, 5*time.Second, d.Reset)
+
+	// через 15 секунд нового окна вес предыдущего 0.75: 7.5 из 10 заняты
+	next := start.Add(75 * time.Second)
+	for i := 0; i < 2; i++ {
+		require.True(t, ratelimit.Take(&state, policy, next).Allowed, "запрос %d", i+1)
+	}
+	d = ratelimit.Take(&state, policy, next)
+	require.False(t, d.Allowed)
+	assert.Equal(t, 3*time.Second, d.RetryAfter, "вес предыдущего окна должен упасть до 0.7")
+
+	// окно спустя два периода предыдущее не учитывает
+	d = ratelimit.Take(&state, policy, start.Add(3*time.Minute))
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 9, d.Remaining)
+}
+
+// TestMemoryStore тестирует ограничение числа ключей и удаление простаивающих
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := ratelimit.NewMemoryStore(3)
+	policy := ratelimit.Policy{Name: "p", Algorithm: ratelimit.TokenBucket, Limit: 1, Window: time.Hour}
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		_, err := store.TakeRateLimit(ctx, fmt.Sprintf("key-%d", i), policy, now.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, store.Len(), "давние ключи вытеснены")
+
+	// key-0 вытеснен, поэтому снова получает полное ведро
+	d, _ := store.TakeRateLimit(ctx, "key-0", policy, now.Add(5*time.Second))
+	assert.True(t, d.Allowed)
+	d, _ = store.TakeRateLimit(ctx, "key-4", policy, now.Add(6*time.Second))
+	assert.False(t, d.Allowed, "недавний ключ сохранил состояние")
+
+	evicted, err := store.EvictRateLimits(ctx, now.Add(5*time.Second))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, evicted, "простаивал только key-3")
+	assert.Equal(t, 2, store.Len())
+}
+
+// TestLoadConfig тестирует разбор YAML с политиками маршрутов и клиентов
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+trusted_proxies: [10.0.0.0/8]
+default:
+  limit: 5
+routes:
+  - name: create-task
+    methods: [post]
+    route: /tasks/
+    algorithm: token_bucket
+    limit: 1
+    window: 1h
+principals:
+  - name: ci
+    tokens: [ci-secret]
+    limit: 1000
+`), 0o644))
+
+	cfg, err := ratelimit.LoadConfig(path, ratelimit.Policy{Algorithm: ratelimit.SlidingWindow, Limit: 100, Window: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.Default.Limit)
+	assert.Equal(t, time.Minute, cfg.Default.Window, "незаданное поле берётся из переменных окружения")
+	require.Len(t, cfg.Routes, 1)
+	assert.Equal(t, time.Hour, cfg.Routes[0].Window)
+
+	_, err = ratelimit.New(cfg, ratelimit.Options{Store: ratelimit.NewMemoryStore(10)})
+	require.NoError(t, err)
+
+	cfg.Routes[0].Algorithm = "leaky"
+	_, err = ratelimit.New(cfg, ratelimit.Options{Store: ratelimit.NewMemoryStore(10)})
+	assert.Error(t, err)
+}
+
+// TestLimiter_ClientIP тестирует X-Forwarded-For только от доверенных прокси
+func TestLimiter_ClientIP(t *testing.T) {
+	limiter, err := ratelimit.New(ratelimit.Config{
+		TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"},
+		Default:        ratelimit.Policy{Algorithm: ratelimit.SlidingWindow, Limit: 1, Window: time.Minute},
+	}, ratelimit.Options{Store: ratelimit.NewMemoryStore(10)})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{"без прокси", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"подделка от клиента", "203.0.113.7:5000", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"доверенный прокси", "10.1.1.1:5000", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"цепочка прокси", "10.1.1.1:5000", []string{"1.2.3.4, 203.0.113.7, 192.168.1.1"}, "203.0.113.7"},
+		{"несколько заголовков", "10.1.1.1:5000", []string{"1.2.3.4", "203.0.113.7"}, "203.0.113.7"},
+		{"мусор в цепочке", "10.1.1.1:5000", []string{"203.0.113.7, garbage"}, "10.1.1.1"},
+		{"только прокси", "10.1.1.1:5000", []string{"10.2.2.2"}, "10.2.2.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			assert.Equal(t, tt.want, limiter.ClientIP(req))
+		})
+	}
+}
+
+// TestRateLimiter тестирует политики маршрутов и клиентов в middleware и заголовки ответа
+func TestRateLimiter(t *testing.T) {
+	limiter, err := ratelimit.New(ratelimit.Config{
+		Default: ratelimit.Policy{Algorithm: ratelimit.SlidingWindow, Limit: 5, Window: time.Minute},
+		Routes: []ratelimit.Route{{
+			Policy:  ratelimit.Policy{Name: "create-task", Algorithm: ratelimit.TokenBucket, Limit: 2, Window: time.Hour},
+			Methods: []string{http.MethodPost},
+			Route:   "/tasks",
+		}},
+		Principals: []ratelimit.Principal{{
+			Policy: ratelimit.Policy{Name: "ci", Limit: 100},
+			Tokens: []string{"ci-secret"},
+		}},
+	}, ratelimit.Options{Store: ratelimit.NewMemoryStore(100)})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RateLimiter(limiter))
+	r.Route("/tasks", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	do := func(method, ip, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/tasks", nil)
+		req.RemoteAddr = ip + ":1234"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "203.0.113.1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "5", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "4", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "5;w=60", rec.Header().Get("RateLimit-Policy"))
+
+	// отдельный лимит на создание задач
+	for i := 0; i < 2; i++ {
+		rec = do(http.MethodPost, "203.0.113.1", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, "5;w=60, 2;w=3600", rec.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	rec = do(http.MethodPost, "203.0.113.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1800", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), `"retry_after":1800`)
+
+	// отклонённый маршрутом запрос тоже учтён в общем лимите: пятый проходит, шестой нет
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "203.0.113.1", "").Code)
+	rec = do(http.MethodGet, "203.0.113.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.Positive(t, retryAfter)
+
+	// другой IP и неизвестный токен - свои счётчики по IP
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "203.0.113.2", "random").Code)
+
+	// известный клиент получает свою политику, общую для всех его адресов
+	for i := 0; i < 10; i++ {
+		rec = do(http.MethodGet, fmt.Sprintf("198.51.100.%d", i), "ci-secret")
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "90", rec.Header().Get("RateLimit-Remaining"))
+}
@@ -0,0 +1,225 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/notify"
+	"taskTracker/internal/repository"
+	"taskTracker/internal/service"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Scheduler периодически ищет задачи с наступившими напоминаниями и рассылает их.
+//
+// Доставка идёт не менее одного раза. Перед отправкой планировщик забирает
+// напоминания, сохраняя ClaimedAt через обычный Update с оптимистичной
+// блокировкой: если другой экземпляр успел раньше, Update вернёт конфликт
+// версий и задача будет пропущена. После доставки сохраняется SentAt.
+// Если доставка не удалась или процесс упал до отметки, аренда истечёт
+// через task.ReminderLease и напоминание будет отправлено снова. Повтор
+// возможен, поэтому каждое уведомление несёт notify.Notification.Key
+type Scheduler struct {
+	repo     service.TaskRepository
+	notifier notify.Notifier
+	interval time.Duration
+	batch    int
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewScheduler(repo service.TaskRepository, notifier notify.Notifier, interval time.Duration, batch int) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		notifier: notifier,
+		interval: interval,
+		batch:    batch,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.runTick()
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop дожидается завершения текущего тика
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+}
+
+func (s *Scheduler) runTick() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+	defer cancel()
+
+	start := time.Now()
+	sent, err := s.Tick(ctx)
+	metrics.ObserveWorkerRun("reminder", start, sent, err)
+	if err != nil {
+		logger.Error("Reminder: Ошибка обработки напоминаний", err)
+		return
+	}
+	if sent > 0 {
+		logger.Info("Reminder: Напоминания отправлены", zap.Int("count", sent))
+	}
+}
+
+// сколько раз повторять отметку о доставке при конфликте версий
+const confirmAttempts = 3
+
+// Tick выбирает задачи с наступившими напоминаниями пачками по batch,
+// самые давние первыми, пока они не закончатся, и возвращает число
+// доставленных напоминаний
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	sent := 0
+	for {
+		tasks, err := s.repo.GetTasksWithDueReminders(ctx, now, s.batch)
+		if err != nil {
+			return sent, fmt.Errorf("выборка задач для напоминаний: %w", err)
+		}
+
+		// забранная задача уходит из выборки, поэтому следующая пачка - новые
+		// задачи. Если не удалось забрать ни одной, повторится то же самое
+		progressed := false
+		for _, t := range tasks {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			n, claimed := s.process(ctx, t, now)
+			sent += n
+			progressed = progressed || claimed
+		}
+		if len(tasks) < s.batch || !progressed {
+			return sent, nil
+		}
+	}
+}
+
+// process доставляет наступившие напоминания задачи. Возвращает число
+// доставленных и признак того, что задача записана и ушла из выборки
+func (s *Scheduler) process(ctx context.Context, t *task.Task, now time.Time) (int, bool) {
+	// просроченным задачам напоминать уже поздно
+	if !now.Before(t.DueTime) {
+		return 0, false
+	}
+
+	// работаем с копией: inmemory-хранилище отдаёт общий указатель
+	claimed := *t
+	claimed.Reminders = t.Reminders.Clone()
+
+	var pending []int
+	for i, r := range claimed.Reminders {
+		if at, ok := r.NextAt(claimed.DueTime); ok && !now.Before(at) {
+			pending = append(pending, i)
+			claimedAt := now
+			claimed.Reminders[i].ClaimedAt = &claimedAt
+		}
+	}
+
+	// если забирать нечего, момент отправки в хранилище устарел, например
+	// после миграции, и запись задачи просто пересчитает его
+	if err := s.repo.Update(ctx, &claimed); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			logger.Info("Reminder: Задача изменена параллельно, напоминание отложено",
+				zap.String("task_id", t.UUID.String()))
+			return 0, false
+		}
+		logger.Error("Reminder: Не удалось забрать напоминание", err,
+			zap.String("task_id", t.UUID.String()))
+		return 0, false
+	}
+	if len(pending) == 0 {
+		return 0, true
+	}
+
+	var delivered []time.Duration
+	for _, i := range pending {
+		r := claimed.Reminders[i]
+		err := s.notifier.Notify(ctx, notify.Notification{
+			TaskID:  claimed.UUID,
+			Title:   claimed.Title,
+			DueTime: claimed.DueTime,
+			Before:  r.Before,
+		})
+		if err != nil {
+			// аренда остаётся: напоминание повторится, когда она истечёт
+			logger.Error("Reminder: Не удалось доставить напоминание", err,
+				zap.String("task_id", claimed.UUID.String()),
+				zap.Duration("before", r.Before))
+			continue
+		}
+		delivered = append(delivered, r.Before)
+	}
+
+	if len(delivered) > 0 {
+		s.confirm(ctx, &claimed, delivered, now)
+	}
+	return len(delivered), true
+}
+
+// confirm отмечает доставленные напоминания. Пока шла доставка, задачу могли
+// изменить, поэтому при конфликте версий отметка переносится на свежую копию:
+// на напоминания с тем же интервалом, которые всё ещё забраны этим тиком
+func (s *Scheduler) confirm(ctx context.Context, t *task.Task, delivered []time.Duration, claimedAt time.Time) {
+	for attempt := 1; ; attempt++ {
+		sentAt := time.Now()
+		marked := false
+		for i, r := range t.Reminders {
+			if r.SentAt == nil && r.ClaimedAt != nil && r.ClaimedAt.Equal(claimedAt) && slices.Contains(delivered, r.Before) {
+				t.Reminders[i].SentAt = &sentAt
+				t.Reminders[i].ClaimedAt = nil
+				marked = true
+			}
+		}
+		// срок перенесли или напоминания заменили - отмечать нечего
+		if !marked {
+			return
+		}
+
+		err := s.repo.Update(ctx, t)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, repository.ErrVersionConflict) || attempt == confirmAttempts {
+			logger.Error("Reminder: Не удалось отметить доставку, напоминание может повториться", err,
+				zap.String("task_id", t.UUID.String()))
+			return
+		}
+
+		fresh, err := s.repo.GetByID(ctx, t.UUID)
+		if err != nil {
+			logger.Error("Reminder: Не удалось перечитать задачу для отметки доставки", err,
+				zap.String("task_id", t.UUID.String()))
+			return
+		}
+		copied := *fresh
+		copied.Reminders = fresh.Reminders.Clone()
+		t = &copied
+	}
+}
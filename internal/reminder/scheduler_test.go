@@ -0,0 +1,242 @@
+package reminder_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/notify"
+	"taskTracker/internal/reminder"
+	"taskTracker/internal/repository/task/inmemory"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// recorder запоминает доставленные напоминания и может имитировать сбой
+type recorder struct {
+	mtx  sync.Mutex
+	sent []notify.Notification
+	err  error
+}
+
+func (r *recorder) Notify(ctx context.Context, n notify.Notification) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func newTask(due time.Duration, before ...time.Duration) *task.Task {
+	reminders := make(task.Reminders, len(before))
+	for i, b := range before {
+		reminders[i] = task.Reminder{Before: b}
+	}
+	return &task.Task{
+		UUID:      uuid.New(),
+		Title:     "reminded",
+		Status:    task.StatusNew,
+		DueTime:   time.Now().Add(due),
+		Flag:      task.FlagActive,
+		Version:   1,
+		Reminders: reminders,
+	}
+}
+
+// TestScheduler_FiresDueRemindersOnce тестирует однократное срабатывание напоминаний
+func TestScheduler_FiresDueRemindersOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewTaskStorage()
+
+	// за сутки - уже пора, за 10 минут - ещё рано
+	created := newTask(time.Hour, 24*time.Hour, 10*time.Minute)
+	require.NoError(t, repo.Create(ctx, created))
+
+	notifier := &recorder{}
+	scheduler := reminder.NewScheduler(repo, notifier, time.Minute, 100)
+
+	sent, err := scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	sent, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, created.UUID, notifier.sent[0].TaskID)
+	assert.Equal(t, 24*time.Hour, notifier.sent[0].Before)
+
+	got, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.NotNil(t, got.Reminders[0].SentAt)
+	assert.Nil(t, got.Reminders[1].SentAt)
+}
+
+// TestScheduler_SurvivesRestart тестирует, что отметка об отправке переживает перезапуск
+func TestScheduler_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo, err := inmemory.NewPersistentTaskStorage(inmemory.PersistenceOptions{Dir: dir})
+	require.NoError(t, err)
+	created := newTask(time.Hour, 2*time.Hour)
+	require.NoError(t, repo.Create(ctx, created))
+
+	notifier := &recorder{}
+	sent, err := reminder.NewScheduler(repo, notifier, time.Minute, 100).Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.NoError(t, repo.Close())
+
+	reopened, err := inmemory.NewPersistentTaskStorage(inmemory.PersistenceOptions{Dir: dir})
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	sent, err = reminder.NewScheduler(reopened, notifier, time.Minute, 100).Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, notifier.sent, 1)
+}
+
+// expireLease сдвигает аренду напоминаний в прошлое, как будто она истекла
+func expireLease(t *testing.T, repo *inmemory.TaskStorage, id uuid.UUID) {
+	t.Helper()
+	got, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	expired := *got
+	expired.Reminders = got.Reminders.Clone()
+	for i, r := range expired.Reminders {
+		if r.ClaimedAt != nil {
+			claimedAt := r.ClaimedAt.Add(-task.ReminderLease)
+			expired.Reminders[i].ClaimedAt = &claimedAt
+		}
+	}
+	require.NoError(t, repo.Update(context.Background(), &expired))
+}
+
+// TestScheduler_RetriesFailedDelivery тестирует повтор недоставленного
+// напоминания после истечения аренды
+func TestScheduler_RetriesFailedDelivery(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewTaskStorage()
+	created := newTask(time.Hour, 2*time.Hour)
+	require.NoError(t, repo.Create(ctx, created))
+
+	notifier := &recorder{err: errors.New("smtp down")}
+	scheduler := reminder.NewScheduler(repo, notifier, time.Minute, 100)
+
+	sent, err := scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	got, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Nil(t, got.Reminders[0].SentAt)
+	assert.NotNil(t, got.Reminders[0].ClaimedAt)
+
+	// пока аренда не истекла, напоминание не повторяется
+	notifier.err = nil
+	sent, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	expireLease(t, repo, created.UUID)
+	sent, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	got, err = repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.NotNil(t, got.Reminders[0].SentAt)
+	assert.Nil(t, got.Reminders[0].ClaimedAt)
+}
+
+// TestScheduler_RedeliversAfterCrash тестирует повторную доставку с тем же
+// ключом, если процесс упал между отправкой и отметкой о ней
+func TestScheduler_RedeliversAfterCrash(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewTaskStorage()
+	created := newTask(time.Hour, 2*time.Hour)
+	require.NoError(t, repo.Create(ctx, created))
+
+	// напоминание забрано, но отметка о доставке так и не записана
+	claimedAt := time.Now()
+	claimed := *created
+	claimed.Reminders = created.Reminders.Clone()
+	claimed.Reminders[0].ClaimedAt = &claimedAt
+	require.NoError(t, repo.Update(ctx, &claimed))
+
+	notifier := &recorder{}
+	scheduler := reminder.NewScheduler(repo, notifier, time.Minute, 100)
+	sent, err := scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	expireLease(t, repo, created.UUID)
+	sent, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, notify.Notification{
+		TaskID:  created.UUID,
+		Title:   created.Title,
+		DueTime: created.DueTime,
+		Before:  2 * time.Hour,
+	}.Key(), notifier.sent[0].Key())
+}
+
+// TestScheduler_DrainsAllBatches тестирует обработку всех наступивших
+// напоминаний за тик, даже если их больше размера пачки
+func TestScheduler_DrainsAllBatches(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewTaskStorage()
+
+	// задачи со сроком далеко впереди не мешают выбрать наступившие
+	for range 5 {
+		require.NoError(t, repo.Create(ctx, newTask(20*24*time.Hour, time.Hour)))
+	}
+	for range 7 {
+		require.NoError(t, repo.Create(ctx, newTask(time.Hour, 2*time.Hour)))
+	}
+
+	notifier := &recorder{}
+	sent, err := reminder.NewScheduler(repo, notifier, time.Minute, 3).Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 7, sent)
+	assert.Len(t, notifier.sent, 7)
+}
+
+// TestScheduler_SkipsOverdueAndDone тестирует пропуск просроченных и выполненных задач
+func TestScheduler_SkipsOverdueAndDone(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewTaskStorage()
+
+	overdue := newTask(-time.Minute, time.Hour)
+	require.NoError(t, repo.Create(ctx, overdue))
+
+	done := newTask(time.Hour, 2*time.Hour)
+	done.Status = task.StatusDone
+	require.NoError(t, repo.Create(ctx, done))
+
+	notifier := &recorder{}
+	sent, err := reminder.NewScheduler(repo, notifier, time.Minute, 100).Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, notifier.sent)
+}
@@ -0,0 +1,157 @@
+package cache_test
+
+import (
+	"context"
+	"os"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository"
+	"taskTracker/internal/repository/task/cache"
+	"taskTracker/internal/repository/task/inmemory"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// countingRepo считает обращения к GetByID нижележащего хранилища
+type countingRepo struct {
+	*inmemory.TaskStorage
+	gets int
+}
+
+func (r *countingRepo) GetByID(ctx context.Context, id uuid.UUID) (*task.Task, error) {
+	r.gets++
+	got, err := r.TaskStorage.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// inmemory отдаёт общий указатель, а настоящие хранилища - копию
+	copied := *got
+	return &copied, nil
+}
+
+func newCachedRepo(t *testing.T, backend cache.Cache) (*cache.Repository, *countingRepo, *task.Task) {
+	t.Helper()
+	inner := &countingRepo{TaskStorage: inmemory.NewTaskStorage()}
+	created := &task.Task{
+		UUID:    uuid.New(),
+		Title:   "cached",
+		Status:  task.StatusNew,
+		DueTime: time.Now().Add(time.Hour),
+		Version: 1,
+	}
+	require.NoError(t, inner.Create(context.Background(), created))
+	return cache.NewRepository(inner, backend, time.Minute), inner, created
+}
+
+func testReadThrough(t *testing.T, backend cache.Cache) {
+	ctx := context.Background()
+	repo, inner, created := newCachedRepo(t, backend)
+
+	first, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	second, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, inner.gets)
+	assert.Equal(t, first.Title, second.Title)
+
+	// изменения полученной копии не портят кэш
+	second.Title = "mutated by caller"
+	third, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", third.Title)
+
+	stats := repo.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.InDelta(t, 2.0/3.0, stats.HitRatio, 0.001)
+
+	// обновление сбрасывает кэш, следующее чтение видит новую версию
+	third.Title = "updated"
+	require.NoError(t, repo.Update(ctx, third))
+
+	fresh, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", fresh.Title)
+	assert.Equal(t, 2, inner.gets)
+
+	require.NoError(t, repo.DeleteSoft(ctx, fresh))
+	deleted, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, task.FlagDeleted, deleted.Flag)
+	assert.Equal(t, 3, inner.gets)
+
+	require.NoError(t, repo.DeleteFull(ctx, created.UUID))
+	_, err = repo.GetByID(ctx, created.UUID)
+	assert.Equal(t, repository.ErrNotFound, err)
+	assert.Equal(t, uint64(3), repo.Stats().Invalidations)
+}
+
+// TestRepository_LRU тестирует read-through и инвалидацию с локальным LRU
+func TestRepository_LRU(t *testing.T) {
+	testReadThrough(t, cache.NewLRU(100))
+}
+
+// TestRepository_Redis тестирует тот же сценарий с Redis-адаптером
+func TestRepository_Redis(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	testReadThrough(t, cache.NewRedis(client, "test:"))
+	assert.Empty(t, server.Keys(), "все ключи должны быть удалены после DeleteFull")
+}
+
+// TestRepository_RedisUnavailable тестирует работу без кэша при падении Redis
+func TestRepository_RedisUnavailable(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	repo, inner, created := newCachedRepo(t, cache.NewRedis(client, "test:"))
+	server.Close()
+
+	got, err := repo.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", got.Title)
+	assert.Equal(t, 1, inner.gets)
+	assert.NotZero(t, repo.Stats().Errors)
+}
+
+// TestLRU_EvictionAndTTL тестирует вытеснение и истечение записей
+func TestLRU_EvictionAndTTL(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(2)
+
+	require.NoError(t, lru.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, lru.Set(ctx, "b", []byte("2"), 0))
+
+	// "a" становится самым свежим, поэтому вытесняется "b"
+	_, ok, _ := lru.Get(ctx, "a")
+	require.True(t, ok)
+	require.NoError(t, lru.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok, _ = lru.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok, _ = lru.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, lru.Len())
+
+	require.NoError(t, lru.Set(ctx, "short", []byte("x"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, ok, _ = lru.Get(ctx, "short")
+	assert.False(t, ok)
+}
@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Repository - read-through кэш для GetByID поверх любого service.TaskRepository.
+//
+// Задача хранится под ключом task:<uuid>:v<version>, а task:<uuid> указывает
+// на актуальную версию. Мутации удаляют указатель, поэтому следующий GetByID
+// идёт в хранилище. Устаревшее значение, записанное гонкой чтения с обновлением,
+// живёт не дольше TTL, а запись по нему всё равно упрётся в оптимистичную блокировку.
+// Ошибки кэша не пробрасываются: запрос просто уходит в хранилище.
+type Repository struct {
+	service.TaskRepository
+
+	cache Cache
+	ttl   time.Duration
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+	errors        atomic.Uint64
+}
+
+func NewRepository(repo service.TaskRepository, cache Cache, ttl time.Duration) *Repository {
+	return &Repository{
+		TaskRepository: repo,
+		cache:          cache,
+		ttl:            ttl,
+	}
+}
+
+func latestKey(id uuid.UUID) string {
+	return "task:" + id.String()
+}
+
+func versionKey(id uuid.UUID, version int) string {
+	return "task:" + id.String() + ":v" + strconv.Itoa(version)
+}
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*task.Task, error) {
+	if cached, ok := r.lookup(ctx, id); ok {
+		r.hits.Add(1)
+		return cached, nil
+	}
+	r.misses.Add(1)
+
+	got, err := r.TaskRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, got)
+	return got, nil
+}
+
+// GetByIDs берёт из кэша что есть, а остальное - одним запросом к хранилищу
+func (r *Repository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, error) {
+	res := make([]*task.Task, 0, len(ids))
+	var missed []uuid.UUID
+	for _, id := range ids {
+		if cached, ok := r.lookup(ctx, id); ok {
+			r.hits.Add(1)
+			res = append(res, cached)
+			continue
+		}
+		r.misses.Add(1)
+		missed = append(missed, id)
+	}
+	if len(missed) == 0 {
+		return res, nil
+	}
+
+	got, err := r.TaskRepository.GetByIDs(ctx, missed)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range got {
+		r.store(ctx, t)
+	}
+	return append(res, got...), nil
+}
+
+func (r *Repository) lookup(ctx context.Context, id uuid.UUID) (*task.Task, bool) {
+	version, ok, err := r.cache.Get(ctx, latestKey(id))
+	if err != nil {
+		r.cacheError(ctx, "чтение версии", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	v, err := strconv.Atoi(string(version))
+	if err != nil {
+		return nil, false
+	}
+
+	data, ok, err := r.cache.Get(ctx, versionKey(id, v))
+	if err != nil {
+		r.cacheError(ctx, "чтение задачи", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	// каждый раз декодируем заново: сервис меняет полученную задачу на месте
+	cached := &task.Task{}
+	if err := json.Unmarshal(data, cached); err != nil {
+		r.cacheError(ctx, "разбор задачи", err)
+		return nil, false
+	}
+	return cached, true
+}
+
+func (r *Repository) store(ctx context.Context, t *task.Task) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		r.cacheError(ctx, "сериализация задачи", err)
+		return
+	}
+
+	if err := r.cache.Set(ctx, versionKey(t.UUID, t.Version), data, r.ttl); err != nil {
+		r.cacheError(ctx, "запись задачи", err)
+		return
+	}
+	if err := r.cache.Set(ctx, latestKey(t.UUID), []byte(strconv.Itoa(t.Version)), r.ttl); err != nil {
+		r.cacheError(ctx, "запись версии", err)
+	}
+}
+
+func (r *Repository) invalidate(ctx context.Context, id uuid.UUID, version int) {
+	r.invalidations.Add(1)
+	if err := r.cache.Delete(ctx, latestKey(id), versionKey(id, version)); err != nil {
+		r.cacheError(ctx, "инвалидация", err)
+	}
+}
+
+func (r *Repository) Update(ctx context.Context, taskToUpdate *task.Task) error {
+	version := taskToUpdate.Version
+	err := r.TaskRepository.Update(ctx, taskToUpdate)
+	// при конфликте версий кэш тоже мог отстать, поэтому сбрасываем в любом случае
+	r.invalidate(ctx, taskToUpdate.UUID, version)
+	return err
+}
+
+func (r *Repository) DeleteSoft(ctx context.Context, taskToDelete *task.Task) error {
+	version := taskToDelete.Version
+	err := r.TaskRepository.DeleteSoft(ctx, taskToDelete)
+	r.invalidate(ctx, taskToDelete.UUID, version)
+	return err
+}
+
+func (r *Repository) DeleteFull(ctx context.Context, id uuid.UUID) error {
+	err := r.TaskRepository.DeleteFull(ctx, id)
+
+	keys := []string{latestKey(id)}
+	if version, ok, getErr := r.cache.Get(ctx, latestKey(id)); getErr == nil && ok {
+		if v, convErr := strconv.Atoi(string(version)); convErr == nil {
+			keys = append(keys, versionKey(id, v))
+		}
+	}
+
+	r.invalidations.Add(1)
+	if delErr := r.cache.Delete(ctx, keys...); delErr != nil {
+		r.cacheError(ctx, "инвалидация", delErr)
+	}
+	return err
+}
+
+func (r *Repository) Stats() Stats {
+	stats := Stats{
+		Hits:          r.hits.Load(),
+		Misses:        r.misses.Load(),
+		Invalidations: r.invalidations.Load(),
+		Errors:        r.errors.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+func (r *Repository) cacheError(ctx context.Context, op string, err error) {
+	r.errors.Add(1)
+	logger.WarnCtx(ctx, "Cache: Ошибка кэша, запрос уйдёт в хранилище",
+		zap.String("operation", op),
+		zap.Error(err))
+}
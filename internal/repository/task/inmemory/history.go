@@ -0,0 +1,73 @@
+package inmemory
+
+import (
+	"context"
+	"taskTracker/internal/events"
+	"taskTracker/internal/history"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// История задач, как и учёт времени, пишется в журнал хранилища
+
+func (s *TaskStorage) AppendHistory(ctx context.Context, r history.Record) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.journal(logRecord{Op: opHistory, History: &r}, func() error {
+		return s.history.AppendHistory(ctx, r)
+	})
+}
+
+func (s *TaskStorage) HistoryState(ctx context.Context, at time.Time) ([]history.Record, error) {
+	return s.history.HistoryState(ctx, at)
+}
+
+func (s *TaskStorage) ListHistory(ctx context.Context, from, to time.Time) ([]history.Record, error) {
+	return s.history.ListHistory(ctx, from, to)
+}
+
+// backfillHistory заносит в историю текущее состояние задач, у которых её нет,
+// как миграция task_history в PostgreSQL. Задачи с неопубликованными событиями
+// пропускаются: история получит их из outbox
+func (s *TaskStorage) backfillHistory() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	known := make(map[uuid.UUID]struct{})
+	for _, r := range s.history.Records() {
+		known[r.TaskID] = struct{}{}
+	}
+	for _, e := range s.persister.restored {
+		known[e.TaskID] = struct{}{}
+	}
+
+	now := time.Now().UTC()
+	for _, id := range s.ids {
+		t, ok := s.storage[id]
+		if !ok {
+			continue
+		}
+		if _, ok := known[id]; ok {
+			continue
+		}
+		r := history.Record{
+			EventID:           uuid.New(),
+			TaskID:            id,
+			Event:             events.TaskCreated,
+			Status:            t.Status,
+			Flag:              t.Flag,
+			OriginalEstimate:  t.OriginalEstimate,
+			RemainingEstimate: t.RemainingEstimate,
+			OccurredAt:        now,
+		}
+		err := s.journal(logRecord{Op: opHistory, History: &r}, func() error {
+			return s.history.AppendHistory(context.Background(), r)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
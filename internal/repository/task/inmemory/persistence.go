@@ -0,0 +1,487 @@
+package inmemory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"taskTracker/internal/events"
+	"taskTracker/internal/history"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/worklog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	snapshotFileName = "snapshot.json"
+	walFileName      = "wal.log"
+)
+
+// FsyncPolicy определяет, когда записи журнала сбрасываются на диск
+type FsyncPolicy string
+
+const (
+	FsyncAlways   FsyncPolicy = "always"   // fsync после каждой записи
+	FsyncInterval FsyncPolicy = "interval" // fsync в фоне раз в FsyncInterval
+	FsyncNever    FsyncPolicy = "never"    // сброс на диск остаётся за ОС
+)
+
+type PersistenceOptions struct {
+	Dir              string
+	Fsync            FsyncPolicy
+	FsyncInterval    time.Duration
+	SnapshotInterval time.Duration
+	// после стольких записей в журнале делается снапшот и журнал обрезается
+	CompactThreshold int
+}
+
+type logOp string
+
+const (
+	opCreate     logOp = "create"
+	opUpdate     logOp = "update"
+	opDeleteSoft logOp = "delete_soft"
+	opDeleteFull logOp = "delete_full"
+	// opPublished - релей outbox опубликовал события Published
+	opPublished logOp = "outbox_published"
+	// таймеры и записи времени, Timer и Worklog
+	opTimerStart logOp = "timer_start"
+	opTimerStop  logOp = "timer_stop"
+	opWorklog    logOp = "worklog"
+	// запись истории задачи, History
+	opHistory logOp = "history"
+)
+
+type logRecord struct {
+	Seq  uint64     `json:"seq"`
+	Op   logOp      `json:"op"`
+	ID   uuid.UUID  `json:"id"`
+	Task *task.Task `json:"task,omitempty"`
+	// Events - события мутации для outbox
+	Events    []events.Event  `json:"events,omitempty"`
+	Published []uuid.UUID     `json:"published,omitempty"`
+	Timer     *worklog.Timer  `json:"timer,omitempty"`
+	Worklog   *worklog.Entry  `json:"worklog,omitempty"`
+	History   *history.Record `json:"history,omitempty"`
+}
+
+type snapshot struct {
+	Seq   uint64       `json:"seq"`
+	Tasks []*task.Task `json:"tasks"`
+	// Outbox - неопубликованные события
+	Outbox   []events.Event   `json:"outbox,omitempty"`
+	Timers   []worklog.Timer  `json:"timers,omitempty"`
+	Worklogs []worklog.Entry  `json:"worklogs,omitempty"`
+	History  []history.Record `json:"history,omitempty"`
+}
+
+// persister - журнал упреждающей записи и снапшоты для TaskStorage.
+// Все методы, кроме фоновых циклов, вызываются под s.mtx хранилища.
+type persister struct {
+	opts PersistenceOptions
+
+	fileMtx sync.Mutex // защищает wal от фонового fsync
+	wal     *os.File
+	dirty   bool
+
+	seq           uint64
+	sinceSnapshot int
+
+	// restored - неопубликованные события из снапшота и журнала,
+	// ждут EnableOutbox
+	restored []events.Event
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// NewPersistentTaskStorage восстанавливает хранилище из снапшота и журнала
+// в opts.Dir и дальше пишет каждую мутацию в журнал
+func NewPersistentTaskStorage(opts PersistenceOptions) (*TaskStorage, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("не задана директория для хранения")
+	}
+	if opts.Fsync == "" {
+		opts.Fsync = FsyncAlways
+	}
+	switch opts.Fsync {
+	case FsyncAlways, FsyncInterval, FsyncNever:
+	default:
+		return nil, fmt.Errorf("неизвестная политика fsync: %s", opts.Fsync)
+	}
+	if opts.FsyncInterval <= 0 {
+		opts.FsyncInterval = time.Second
+	}
+	if opts.CompactThreshold <= 0 {
+		opts.CompactThreshold = 10000
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("создание директории хранилища: %w", err)
+	}
+
+	s := NewTaskStorage()
+	p := &persister{
+		opts: opts,
+		stop: make(chan struct{}),
+	}
+
+	if err := p.recover(s); err != nil {
+		return nil, err
+	}
+
+	s.persister = p
+	if err := s.backfillHistory(); err != nil {
+		p.wal.Close()
+		return nil, err
+	}
+	p.startBackground(s)
+
+	logger.Info("Repository: Хранилище в памяти восстановлено с диска",
+		zap.String("dir", opts.Dir),
+		zap.Int("tasks", len(s.storage)),
+		zap.Uint64("seq", p.seq))
+	return s, nil
+}
+
+// recover загружает снапшот, проигрывает поверх него журнал
+// и обрезает недописанный хвост журнала после аварийного завершения
+func (p *persister) recover(s *TaskStorage) error {
+	snap, err := readSnapshot(filepath.Join(p.opts.Dir, snapshotFileName))
+	if err != nil {
+		return err
+	}
+	if snap != nil {
+		for _, t := range snap.Tasks {
+			s.storage[t.UUID] = t
+			s.ids = append(s.ids, t.UUID)
+		}
+		p.seq = snap.Seq
+		p.restored = snap.Outbox
+		for _, t := range snap.Timers {
+			s.worklogs.StartTimer(context.Background(), t)
+		}
+		for _, e := range snap.Worklogs {
+			s.worklogs.AddWorklog(context.Background(), e)
+		}
+		for _, r := range snap.History {
+			s.history.AppendHistory(context.Background(), r)
+		}
+	}
+
+	wal, err := os.OpenFile(filepath.Join(p.opts.Dir, walFileName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("открытие журнала: %w", err)
+	}
+
+	reader := bufio.NewReader(wal)
+	var offset int64
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr == io.EOF && len(line) == 0 {
+			break
+		}
+
+		var rec logRecord
+		if readErr != nil || json.Unmarshal(bytes.TrimSpace(line), &rec) != nil {
+			logger.Warn("Repository: Повреждённый хвост журнала, обрезаем",
+				zap.Int64("offset", offset))
+			if err := wal.Truncate(offset); err != nil {
+				wal.Close()
+				return fmt.Errorf("обрезка журнала: %w", err)
+			}
+			break
+		}
+		offset += int64(len(line))
+
+		// записи, уже попавшие в снапшот, пропускаем
+		if rec.Seq <= p.seq {
+			continue
+		}
+		s.apply(rec)
+		p.replayOutbox(rec)
+		p.seq = rec.Seq
+		p.sinceSnapshot++
+	}
+
+	if _, err := wal.Seek(offset, io.SeekStart); err != nil {
+		wal.Close()
+		return fmt.Errorf("позиционирование журнала: %w", err)
+	}
+	p.wal = wal
+	return nil
+}
+
+func readSnapshot(path string) (*snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("чтение снапшота: %w", err)
+	}
+
+	snap := &snapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("разбор снапшота: %w", err)
+	}
+	return snap, nil
+}
+
+// apply повторяет мутацию хранилища так же, как её выполнили бы методы TaskStorage
+func (s *TaskStorage) apply(rec logRecord) {
+	switch rec.Op {
+	case opCreate:
+		if _, ok := s.storage[rec.ID]; !ok {
+			s.ids = append(s.ids, rec.ID)
+		}
+		s.storage[rec.ID] = rec.Task
+	case opUpdate, opDeleteSoft:
+		s.storage[rec.ID] = rec.Task
+	case opDeleteFull:
+		s.removeLocked(rec.ID)
+	case opTimerStart:
+		s.worklogs.StartTimer(context.Background(), *rec.Timer)
+	case opTimerStop:
+		s.worklogs.StopTimer(context.Background(), rec.Timer.TaskID, rec.Timer.User)
+	case opWorklog:
+		s.worklogs.AddWorklog(context.Background(), *rec.Worklog)
+	case opHistory:
+		s.history.AppendHistory(context.Background(), *rec.History)
+	}
+}
+
+// replayOutbox восстанавливает неопубликованные события по записи журнала
+func (p *persister) replayOutbox(rec logRecord) {
+	p.restored = append(p.restored, rec.Events...)
+	if len(rec.Published) == 0 {
+		return
+	}
+
+	published := make(map[uuid.UUID]bool, len(rec.Published))
+	for _, id := range rec.Published {
+		published[id] = true
+	}
+	kept := p.restored[:0]
+	for _, e := range p.restored {
+		if !published[e.ID] {
+			kept = append(kept, e)
+		}
+	}
+	p.restored = kept
+}
+
+// append пишет запись в журнал до применения мутации. Вызывается под s.mtx.Lock
+func (p *persister) append(rec logRecord) error {
+	rec.Seq = p.seq + 1
+	if rec.Task != nil {
+		copied := *rec.Task
+		rec.Task = &copied
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("сериализация записи журнала: %w", err)
+	}
+	data = append(data, '\n')
+
+	p.fileMtx.Lock()
+	_, err = p.wal.Write(data)
+	if err == nil && p.opts.Fsync == FsyncAlways {
+		err = p.wal.Sync()
+	}
+	if err == nil {
+		p.dirty = true
+	}
+	p.fileMtx.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("запись журнала: %w", err)
+	}
+
+	p.seq = rec.Seq
+	p.sinceSnapshot++
+	return nil
+}
+
+// compactIfNeeded делает снапшот, когда журнал вырос до порога.
+// Вызывается под s.mtx.Lock после применения мутации к памяти
+func (p *persister) compactIfNeeded(s *TaskStorage) {
+	if p.sinceSnapshot < p.opts.CompactThreshold {
+		return
+	}
+	if err := p.snapshotLocked(s); err != nil {
+		// записи остаются в журнале, поэтому мутации не теряются
+		logger.Error("Repository: Не удалось сжать журнал", err)
+	}
+}
+
+// snapshotLocked атомарно записывает снапшот и обрезает журнал.
+// Вызывается под s.mtx.Lock
+func (p *persister) snapshotLocked(s *TaskStorage) error {
+	snap := snapshot{Seq: p.seq, Tasks: make([]*task.Task, 0, len(s.ids)), Outbox: p.restored}
+	for _, id := range s.ids {
+		if t, ok := s.storage[id]; ok {
+			snap.Tasks = append(snap.Tasks, t)
+		}
+	}
+	if s.outbox != nil {
+		snap.Outbox = s.outbox.Events()
+	}
+	var err error
+	if snap.Timers, err = s.worklogs.ListTimers(context.Background(), uuid.Nil, ""); err != nil {
+		return fmt.Errorf("таймеры для снапшота: %w", err)
+	}
+	if snap.Worklogs, err = s.worklogs.ListWorklogs(context.Background(), worklog.Filter{}); err != nil {
+		return fmt.Errorf("записи времени для снапшота: %w", err)
+	}
+	snap.History = s.history.Records()
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("сериализация снапшота: %w", err)
+	}
+
+	path := filepath.Join(p.opts.Dir, snapshotFileName)
+	tmp := path + ".tmp"
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("замена снапшота: %w", err)
+	}
+	syncDir(p.opts.Dir)
+
+	// если упадём до обрезки, записи журнала с seq <= snap.Seq будут пропущены при восстановлении
+	p.fileMtx.Lock()
+	defer p.fileMtx.Unlock()
+	if err := p.wal.Truncate(0); err != nil {
+		return fmt.Errorf("обрезка журнала: %w", err)
+	}
+	if _, err := p.wal.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("позиционирование журнала: %w", err)
+	}
+	if err := p.wal.Sync(); err != nil {
+		return fmt.Errorf("fsync журнала: %w", err)
+	}
+	p.dirty = false
+	p.sinceSnapshot = 0
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("создание снапшота: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("запись снапшота: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("fsync снапшота: %w", err)
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+func (p *persister) startBackground(s *TaskStorage) {
+	if p.opts.Fsync == FsyncInterval {
+		p.done.Add(1)
+		go func() {
+			defer p.done.Done()
+			ticker := time.NewTicker(p.opts.FsyncInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-p.stop:
+					return
+				case <-ticker.C:
+					p.fileMtx.Lock()
+					if p.dirty {
+						if err := p.wal.Sync(); err != nil {
+							logger.Error("Repository: Ошибка fsync журнала", err)
+						} else {
+							p.dirty = false
+						}
+					}
+					p.fileMtx.Unlock()
+				}
+			}
+		}()
+	}
+
+	if p.opts.SnapshotInterval > 0 {
+		p.done.Add(1)
+		go func() {
+			defer p.done.Done()
+			ticker := time.NewTicker(p.opts.SnapshotInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-p.stop:
+					return
+				case <-ticker.C:
+					if err := s.Snapshot(); err != nil {
+						logger.Error("Repository: Ошибка периодического снапшота", err)
+					}
+				}
+			}
+		}()
+	}
+}
+
+// Snapshot записывает снапшот текущего состояния и сжимает журнал
+func (s *TaskStorage) Snapshot() error {
+	if s.persister == nil {
+		return nil
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.persister.snapshotLocked(s)
+}
+
+// Close останавливает фоновые задачи, пишет финальный снапшот и закрывает журнал
+func (s *TaskStorage) Close() error {
+	if s.persister == nil {
+		return nil
+	}
+	p := s.persister
+	close(p.stop)
+	p.done.Wait()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.persister = nil
+	snapErr := p.snapshotLocked(s)
+	if err := p.wal.Close(); err != nil && snapErr == nil {
+		return fmt.Errorf("закрытие журнала: %w", err)
+	}
+	if snapErr != nil {
+		return snapErr
+	}
+
+	logger.Info("Repository: Финальный снапшот хранилища в памяти записан",
+		zap.String("dir", p.opts.Dir))
+	return nil
+}
@@ -0,0 +1,327 @@
+package inmemory_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"taskTracker/internal/events"
+	"taskTracker/internal/history"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/outbox"
+	"taskTracker/internal/repository"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/worklog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func openPersistent(t *testing.T, dir string, threshold int) *inmemory.TaskStorage {
+	t.Helper()
+	storage, err := inmemory.NewPersistentTaskStorage(inmemory.PersistenceOptions{
+		Dir:              dir,
+		Fsync:            inmemory.FsyncAlways,
+		CompactThreshold: threshold,
+	})
+	require.NoError(t, err)
+	return storage
+}
+
+func newTestTask(title string) *task.Task {
+	return &task.Task{
+		UUID:    uuid.New(),
+		Title:   title,
+		Status:  task.StatusNew,
+		DueTime: time.Now().Add(24 * time.Hour),
+		Version: 1,
+	}
+}
+
+// TestPersistentStorage_RecoverFromLog тестирует восстановление из журнала без снапшота
+func TestPersistentStorage_RecoverFromLog(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage := openPersistent(t, dir, 1000)
+
+	kept := newTestTask("kept")
+	updated := newTestTask("updated")
+	softDeleted := newTestTask("soft deleted")
+	purged := newTestTask("purged")
+	for _, tsk := range []*task.Task{kept, updated, softDeleted, purged} {
+		require.NoError(t, storage.Create(ctx, tsk))
+	}
+
+	updated.Title = "updated twice"
+	require.NoError(t, storage.Update(ctx, updated))
+	require.NoError(t, storage.DeleteSoft(ctx, softDeleted))
+	require.NoError(t, storage.DeleteFull(ctx, purged.UUID))
+
+	// имитируем падение: журнал не закрываем и снапшот не пишем
+	recovered := openPersistent(t, dir, 1000)
+	defer recovered.Close()
+
+	got, err := recovered.GetByID(ctx, updated.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "updated twice", got.Title)
+	assert.Equal(t, 2, got.Version)
+
+	got, err = recovered.GetByID(ctx, softDeleted.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, task.FlagDeleted, got.Flag)
+	assert.NotNil(t, got.DeletedAt)
+
+	_, err = recovered.GetByID(ctx, purged.UUID)
+	assert.Equal(t, repository.ErrNotFound, err)
+
+	all, err := recovered.GetAllWithLimit(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, kept.UUID, all[0].UUID)
+	assert.Equal(t, updated.UUID, all[1].UUID)
+}
+
+// TestPersistentStorage_TornTail тестирует обрезку недописанной записи в конце журнала
+func TestPersistentStorage_TornTail(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage := openPersistent(t, dir, 1000)
+	first := newTestTask("first")
+	require.NoError(t, storage.Create(ctx, first))
+
+	walPath := filepath.Join(dir, "wal.log")
+	f, err := os.OpenFile(walPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":2,"op":"create","id":"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	recovered := openPersistent(t, dir, 1000)
+
+	_, err = recovered.GetByID(ctx, first.UUID)
+	require.NoError(t, err)
+
+	// после обрезки журнал снова пригоден для записи
+	second := newTestTask("second")
+	require.NoError(t, recovered.Create(ctx, second))
+
+	again := openPersistent(t, dir, 1000)
+	defer again.Close()
+	_, err = again.GetByID(ctx, second.UUID)
+	assert.NoError(t, err)
+}
+
+// TestPersistentStorage_Compaction тестирует снапшот и обрезку журнала по порогу
+func TestPersistentStorage_Compaction(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage := openPersistent(t, dir, 3)
+
+	tasks := make([]*task.Task, 0, 4)
+	for i := 0; i < 4; i++ {
+		tsk := newTestTask("task")
+		require.NoError(t, storage.Create(ctx, tsk))
+		tasks = append(tasks, tsk)
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "snapshot.json"))
+	require.NoError(t, err)
+
+	// в журнале осталась только запись после снапшота
+	data, err := os.ReadFile(filepath.Join(dir, "wal.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), tasks[3].UUID.String())
+	assert.NotContains(t, string(data), tasks[0].UUID.String())
+
+	recovered := openPersistent(t, dir, 3)
+	defer recovered.Close()
+	all, err := recovered.GetAllWithLimit(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+}
+
+// TestPersistentStorage_CloseWritesSnapshot тестирует финальный снапшот при закрытии
+func TestPersistentStorage_CloseWritesSnapshot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage := openPersistent(t, dir, 1000)
+	tsk := newTestTask("snapshotted")
+	require.NoError(t, storage.Create(ctx, tsk))
+	require.NoError(t, storage.Close())
+
+	info, err := os.Stat(filepath.Join(dir, "wal.log"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+
+	recovered := openPersistent(t, dir, 1000)
+	defer recovered.Close()
+	got, err := recovered.GetByID(ctx, tsk.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshotted", got.Title)
+}
+
+// TestPersistentStorage_OutboxSurvivesCrash тестирует, что события
+// неопубликованных мутаций восстанавливаются из журнала и снапшота,
+// а опубликованные не повторяются
+func TestPersistentStorage_OutboxSurvivesCrash(t *testing.T) {
+	dir := t.TempDir()
+
+	storage := openPersistent(t, dir, 1000)
+	box := storage.EnableOutbox()
+
+	published := newTestTask("published")
+	ctx := events.WithPending(context.Background(), events.New(events.TaskCreated, published))
+	require.NoError(t, storage.Create(ctx, published))
+	require.NoError(t, box.ProcessOutbox(context.Background(), 10, func(records []outbox.Record) []int64 {
+		return []int64{records[0].Seq}
+	}))
+
+	lost := newTestTask("lost")
+	ctx = events.WithPending(context.Background(), events.New(events.TaskCreated, lost))
+	require.NoError(t, storage.Create(ctx, lost))
+
+	// имитируем падение до публикации второго события
+	recovered := openPersistent(t, dir, 1000)
+	pending := recovered.EnableOutbox().Events()
+	require.Len(t, pending, 1)
+	assert.Equal(t, lost.UUID, pending[0].TaskID)
+	assert.Equal(t, "lost", pending[0].Task.Title)
+
+	// снапшот при закрытии тоже сохраняет outbox
+	require.NoError(t, recovered.Close())
+	reopened := openPersistent(t, dir, 1000)
+	defer reopened.Close()
+	assert.Len(t, reopened.EnableOutbox().Events(), 1)
+}
+
+// TestPersistentStorage_WorklogsSurviveCrash тестирует восстановление таймеров
+// и записей времени из журнала и снапшота
+func TestPersistentStorage_WorklogsSurviveCrash(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage := openPersistent(t, dir, 1000)
+	var store worklog.Store = storage
+
+	taskID := uuid.New()
+	started := time.Now().Add(-time.Hour).UTC()
+	require.NoError(t, store.StartTimer(ctx, worklog.Timer{TaskID: taskID, User: "alice", StartedAt: started}))
+	require.NoError(t, store.StartTimer(ctx, worklog.Timer{TaskID: taskID, User: "bob", StartedAt: started}))
+	assert.ErrorIs(t, store.StartTimer(ctx, worklog.Timer{TaskID: taskID, User: "bob", StartedAt: started}), worklog.ErrTimerRunning)
+	_, err := store.StopTimer(ctx, taskID, "alice")
+	require.NoError(t, err)
+	_, err = store.StopTimer(ctx, taskID, "alice")
+	assert.ErrorIs(t, err, worklog.ErrNotFound)
+	require.NoError(t, store.AddWorklog(ctx, worklog.Entry{
+		ID:        uuid.New(),
+		TaskID:    taskID,
+		User:      "alice",
+		StartedAt: started,
+		Duration:  time.Hour,
+		Source:    worklog.SourceTimer,
+		CreatedAt: started,
+	}))
+
+	check := func(s *inmemory.TaskStorage) {
+		timers, err := s.ListTimers(ctx, uuid.Nil, "")
+		require.NoError(t, err)
+		require.Len(t, timers, 1)
+		assert.Equal(t, "bob", timers[0].User)
+
+		totals, err := s.WorklogTotals(ctx, []uuid.UUID{taskID})
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, totals[taskID])
+	}
+
+	// имитируем падение: всё восстанавливается из журнала
+	recovered := openPersistent(t, dir, 1000)
+	check(recovered)
+
+	// снапшот при закрытии тоже сохраняет таймеры и записи
+	require.NoError(t, recovered.Close())
+	reopened := openPersistent(t, dir, 1000)
+	defer reopened.Close()
+	check(reopened)
+}
+
+// TestPersistentStorage_HistorySurvivesCrash тестирует восстановление истории задач
+// и заполнение её для задач без истории при открытии
+func TestPersistentStorage_HistorySurvivesCrash(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage := openPersistent(t, dir, 1000)
+	tracked := newTestTask("tracked")
+	untracked := newTestTask("untracked")
+	require.NoError(t, storage.Create(ctx, tracked))
+	require.NoError(t, storage.Create(ctx, untracked))
+
+	pending := newTestTask("pending")
+	box := storage.EnableOutbox()
+	require.NoError(t, storage.Create(events.WithPending(ctx, events.New(events.TaskCreated, pending)), pending))
+	require.Len(t, box.Events(), 1)
+
+	record := history.Record{
+		EventID:    uuid.New(),
+		TaskID:     tracked.UUID,
+		Event:      events.TaskCreated,
+		Status:     task.StatusNew,
+		Flag:       task.FlagActive,
+		OccurredAt: time.Now().Add(-time.Hour).UTC(),
+	}
+	var store history.Store = storage
+	require.NoError(t, store.AppendHistory(ctx, record))
+	require.NoError(t, store.AppendHistory(ctx, record))
+
+	byTask := func(s *inmemory.TaskStorage) map[uuid.UUID][]history.Record {
+		records, err := s.ListHistory(ctx, time.Time{}, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		res := make(map[uuid.UUID][]history.Record)
+		for _, r := range records {
+			res[r.TaskID] = append(res[r.TaskID], r)
+		}
+		return res
+	}
+
+	// имитируем падение: запись восстанавливается из журнала, задача без истории
+	// получает запись с текущим состоянием, а задачу с событием в outbox заполнит outbox
+	recovered := openPersistent(t, dir, 1000)
+	got := byTask(recovered)
+	require.Len(t, got[tracked.UUID], 1)
+	assert.Equal(t, record.EventID, got[tracked.UUID][0].EventID)
+	require.Len(t, got[untracked.UUID], 1)
+	assert.Equal(t, events.TaskCreated, got[untracked.UUID][0].Event)
+	assert.Equal(t, task.StatusNew, got[untracked.UUID][0].Status)
+	assert.Empty(t, got[pending.UUID])
+
+	// заполненная запись сохраняется в снапшоте и не повторяется
+	require.NoError(t, recovered.Close())
+	reopened := openPersistent(t, dir, 1000)
+	defer reopened.Close()
+	assert.Equal(t, got, byTask(reopened))
+}
+
+// TestPersistentStorage_InvalidOptions тестирует проверку параметров
+func TestPersistentStorage_InvalidOptions(t *testing.T) {
+	_, err := inmemory.NewPersistentTaskStorage(inmemory.PersistenceOptions{})
+	assert.Error(t, err)
+
+	_, err = inmemory.NewPersistentTaskStorage(inmemory.PersistenceOptions{
+		Dir:   t.TempDir(),
+		Fsync: "sometimes",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/stats"
+	"time"
+)
+
+// TaskStats считает статистику одним проходом по задачам
+func (s *TaskStorage) TaskStats(ctx context.Context, q stats.Query, now time.Time) (*stats.Aggregates, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	agg := &stats.Aggregates{
+		Created:    make(map[string]int),
+		Completed:  make(map[string]int),
+		TopOverdue: []*task.Task{},
+	}
+	var overdue []*task.Task
+
+	for _, t := range s.storage {
+		agg.Counts.Add(t.Flag, t.Status, 1)
+		if t.Flag == task.FlagDeleted {
+			continue
+		}
+		if t.Flag == task.FlagActive && !t.Status.Closed() && t.DueTime.Before(now) {
+			agg.Counts.Overdue++
+			overdue = append(overdue, t)
+		}
+
+		if inRange(t.CreatedAt, q) {
+			agg.Created[t.CreatedAt.UTC().Format(stats.DayLayout)]++
+		}
+		if t.CompletedAt == nil || !inRange(*t.CompletedAt, q) {
+			continue
+		}
+		agg.Completed[t.CompletedAt.UTC().Format(stats.DayLayout)]++
+		agg.LeadTime = agg.LeadTime.Add(stats.Durations{Sum: t.CompletedAt.Sub(t.CreatedAt), Tasks: 1})
+		if t.StartedAt != nil {
+			agg.CycleTime = agg.CycleTime.Add(stats.Durations{Sum: t.CompletedAt.Sub(*t.StartedAt), Tasks: 1})
+		}
+	}
+
+	slices.SortFunc(overdue, func(a, b *task.Task) int {
+		return a.DueTime.Compare(b.DueTime)
+	})
+	for _, t := range overdue[:min(q.Top, len(overdue))] {
+		// копия: отчёт живёт в кэше дольше, чем задача остаётся неизменной
+		c := *t
+		agg.TopOverdue = append(agg.TopOverdue, &c)
+	}
+	return agg, nil
+}
+
+func inRange(t time.Time, q stats.Query) bool {
+	return !t.Before(q.From) && t.Before(q.To)
+}
@@ -2,10 +2,14 @@ package inmemory
 
 import (
 	"context"
+	"slices"
 	"sync"
-	"taskTracker/internal/logger"
+	"taskTracker/internal/events"
+	"taskTracker/internal/history"
 	"taskTracker/internal/models/task"
+	"taskTracker/internal/outbox"
 	repo "taskTracker/internal/repository"
+	"taskTracker/internal/worklog"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,18 +19,27 @@ type TaskStorage struct {
 	storage map[uuid.UUID]*task.Task
 	mtx     *sync.RWMutex
 	ids     []uuid.UUID
+
+	// nil, если хранилище живёт только в памяти
+	persister *persister
+	// nil, пока не вызван EnableOutbox
+	outbox *outbox.Memory
+	// таймеры, записи времени и история задач; с хранением на диске попадают в журнал
+	worklogs *worklog.MemoryStore
+	history  *history.MemoryStore
 }
 
 func NewTaskStorage() *TaskStorage {
 	return &TaskStorage{
-		storage: make(map[uuid.UUID]*task.Task),
-		mtx:     &sync.RWMutex{},
-		ids:     []uuid.UUID{},
+		storage:  make(map[uuid.UUID]*task.Task),
+		mtx:      &sync.RWMutex{},
+		ids:      []uuid.UUID{},
+		worklogs: worklog.NewMemoryStore(),
+		history:  history.NewMemoryStore(),
 	}
 }
 
 func (s *TaskStorage) HealthCheck(ctx context.Context) error {
-	logger.Info("Repository: Соединение стабильно")
 	return nil
 }
 
@@ -37,9 +50,10 @@ func (s *TaskStorage) Create(ctx context.Context, taskToCreate *task.Task) error
 	taskToCreate.CreatedAt = time.Now()
 	taskToCreate.Flag = task.FlagActive
 
-	s.storage[taskToCreate.UUID] = taskToCreate
-	s.ids = append(s.ids, taskToCreate.UUID)
-	return nil
+	return s.commit(ctx, opCreate, taskToCreate.UUID, taskToCreate, func() {
+		s.storage[taskToCreate.UUID] = taskToCreate
+		s.ids = append(s.ids, taskToCreate.UUID)
+	})
 }
 
 func (s *TaskStorage) Update(ctx context.Context, taskToUpdate *task.Task) error {
@@ -49,7 +63,14 @@ func (s *TaskStorage) Update(ctx context.Context, taskToUpdate *task.Task) error
 	now := time.Now()
 	taskToUpdate.UpdatedAt = &now
 	taskToUpdate.Version++
-	s.storage[taskToUpdate.UUID] = taskToUpdate
+
+	err := s.commit(ctx, opUpdate, taskToUpdate.UUID, taskToUpdate, func() {
+		s.storage[taskToUpdate.UUID] = taskToUpdate
+	})
+	if err != nil {
+		taskToUpdate.Version--
+		return err
+	}
 
 	return nil
 }
@@ -77,11 +98,14 @@ func (s *TaskStorage) DeleteSoft(ctx context.Context, taskToDelete *task.Task) e
 	}
 
 	now := time.Now()
-	taskExisted.UpdatedAt = &now
-	taskExisted.DeletedAt = &now
-	taskExisted.Flag = task.FlagDeleted
+	deleted := *taskExisted
+	deleted.UpdatedAt = &now
+	deleted.DeletedAt = &now
+	deleted.Flag = task.FlagDeleted
 
-	return nil
+	return s.commit(ctx, opDeleteSoft, deleted.UUID, &deleted, func() {
+		*taskExisted = deleted
+	})
 
 }
 
@@ -90,15 +114,108 @@ func (s *TaskStorage) DeleteFull(ctx context.Context, uuid uuid.UUID) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
+	return s.commit(ctx, opDeleteFull, uuid, nil, func() {
+		s.removeLocked(uuid)
+	})
+}
+
+func (s *TaskStorage) removeLocked(uuid uuid.UUID) {
 	delete(s.storage, uuid)
 	for ind, val := range s.ids {
 		if val == uuid {
 			s.ids = append(s.ids[:ind], s.ids[ind+1:]...)
+			break
 		}
 	}
+}
+
+// commit применяет мутацию, предварительно записав её в журнал,
+// если включено хранение на диске. Вызывается под s.mtx.Lock
+// События мутации пишутся в ту же запись журнала, что и сама мутация:
+// после падения не бывает изменения задачи без его события
+func (s *TaskStorage) commit(ctx context.Context, op logOp, id uuid.UUID, t *task.Task, apply func()) error {
+	recorded := s.pendingEvents(ctx, t)
+	if s.persister == nil {
+		apply()
+		s.recordEvents(recorded)
+		return nil
+	}
+
+	if err := s.persister.append(logRecord{Op: op, ID: id, Task: t, Events: recorded}); err != nil {
+		return err
+	}
+	apply()
+	s.recordEvents(recorded)
+	s.persister.compactIfNeeded(s)
 	return nil
 }
 
+// EnableOutbox включает запись событий мутаций в outbox. Без него события
+// из ctx игнорируются, чтобы хранилище без релея не копило их бесконечно.
+// С хранением на диске outbox восстанавливается из журнала и снапшота,
+// а отметки о публикации тоже пишутся в журнал
+func (s *TaskStorage) EnableOutbox() *outbox.Memory {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.outbox != nil {
+		return s.outbox
+	}
+
+	s.outbox = outbox.NewMemory()
+	if s.persister != nil {
+		s.outbox.Append(s.persister.restored...)
+		s.persister.restored = nil
+		s.outbox.OnPublished(s.markPublished)
+	}
+	return s.outbox
+}
+
+// markPublished записывает в журнал, что события дошли до подписчиков
+func (s *TaskStorage) markPublished(ids []uuid.UUID) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.persister == nil {
+		return nil
+	}
+	if err := s.persister.append(logRecord{Op: opPublished, Published: ids}); err != nil {
+		return err
+	}
+	s.persister.compactIfNeeded(s)
+	return nil
+}
+
+// pendingEvents - события мутации со снимком задачи после неё,
+// nil без outbox
+func (s *TaskStorage) pendingEvents(ctx context.Context, t *task.Task) []events.Event {
+	if s.outbox == nil {
+		return nil
+	}
+
+	pending := events.Pending(ctx)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	recorded := make([]events.Event, len(pending))
+	for i, e := range pending {
+		if t != nil {
+			e = e.WithTask(t)
+		}
+		recorded[i] = e
+	}
+	return recorded
+}
+
+// recordEvents вызывается под блокировкой хранилища, поэтому события
+// попадают в outbox в том же порядке, что и мутации
+func (s *TaskStorage) recordEvents(recorded []events.Event) {
+	if len(recorded) > 0 {
+		s.outbox.Append(recorded...)
+	}
+}
+
 // получение задач с флагами active или archived
 func (s *TaskStorage) GetAllWithLimit(ctx context.Context, page, limit int) ([]*task.Task, error) {
 	s.mtx.RLock()
@@ -186,7 +303,7 @@ func (s *TaskStorage) GetTasksDueBefore(ctx context.Context, deadline time.Time,
 		t := s.storage[s.ids[i]]
 
 		if t.Flag == task.FlagActive &&
-			t.Status != task.StatusDone &&
+			!t.Status.Closed() &&
 			t.Status != task.StatusOverdue &&
 			t.DueTime.Before(deadline) {
 
@@ -197,3 +314,79 @@ func (s *TaskStorage) GetTasksDueBefore(ctx context.Context, deadline time.Time,
 
 	return tasks, nil
 }
+
+// GetTasksWithDueReminders отдаёт задачи с наступившим напоминанием,
+// самые давние первыми
+func (s *TaskStorage) GetTasksWithDueReminders(ctx context.Context, now time.Time, limit int) ([]*task.Task, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	type due struct {
+		task *task.Task
+		at   time.Time
+	}
+	var found []due
+	for _, t := range s.storage {
+		if t.Flag != task.FlagActive || t.Status.Closed() || t.Status == task.StatusOverdue || !now.Before(t.DueTime) {
+			continue
+		}
+		if at := t.Reminders.NextAt(t.DueTime); at != nil && !at.After(now) {
+			found = append(found, due{task: t, at: *at})
+		}
+	}
+
+	slices.SortFunc(found, func(a, b due) int {
+		return a.at.Compare(b.at)
+	})
+	tasks := make([]*task.Task, 0, min(limit, len(found)))
+	for _, d := range found[:min(limit, len(found))] {
+		tasks = append(tasks, d.task)
+	}
+	return tasks, nil
+}
+
+// GetActiveByStatuses отдаёт копии: сервис доски подменяет статус
+// просроченных задач на overdue
+func (s *TaskStorage) GetActiveByStatuses(ctx context.Context, statuses []task.Status) ([]*task.Task, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	res := []*task.Task{}
+	for _, id := range s.ids {
+		t := s.storage[id]
+		if t.Flag == task.FlagActive && slices.Contains(statuses, t.Status) {
+			c := *t
+			res = append(res, &c)
+		}
+	}
+	return res, nil
+}
+
+func (s *TaskStorage) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	res := []*task.Task{}
+	for _, id := range ids {
+		if t, ok := s.storage[id]; ok {
+			c := *t
+			res = append(res, &c)
+		}
+	}
+	return res, nil
+}
+
+// CountTasks считает задачи для метрик
+func (s *TaskStorage) CountTasks(ctx context.Context, now time.Time) (task.Counts, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	var counts task.Counts
+	for _, t := range s.storage {
+		counts.Add(t.Flag, t.Status, 1)
+		if t.Flag == task.FlagActive && !t.Status.Closed() && t.DueTime.Before(now) {
+			counts.Overdue++
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,94 @@
+package inmemory
+
+import (
+	"context"
+	"taskTracker/internal/worklog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Таймеры и записи времени живут рядом с задачами и с хранением на диске
+// пишутся в тот же журнал, поэтому переживают перезапуск
+
+func (s *TaskStorage) StartTimer(ctx context.Context, t worklog.Timer) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	running, err := s.worklogs.ListTimers(ctx, t.TaskID, t.User)
+	if err != nil {
+		return err
+	}
+	for _, r := range running {
+		if r.User == t.User {
+			return worklog.ErrTimerRunning
+		}
+	}
+	return s.journal(logRecord{Op: opTimerStart, Timer: &t}, func() error {
+		return s.worklogs.StartTimer(ctx, t)
+	})
+}
+
+func (s *TaskStorage) StopTimer(ctx context.Context, taskID uuid.UUID, user string) (worklog.Timer, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	running, err := s.worklogs.ListTimers(ctx, taskID, user)
+	if err != nil {
+		return worklog.Timer{}, err
+	}
+	var stopped *worklog.Timer
+	for i := range running {
+		if running[i].User == user {
+			stopped = &running[i]
+		}
+	}
+	if stopped == nil {
+		return worklog.Timer{}, worklog.ErrNotFound
+	}
+
+	var res worklog.Timer
+	err = s.journal(logRecord{Op: opTimerStop, Timer: stopped}, func() error {
+		res, err = s.worklogs.StopTimer(ctx, taskID, user)
+		return err
+	})
+	return res, err
+}
+
+func (s *TaskStorage) ListTimers(ctx context.Context, taskID uuid.UUID, user string) ([]worklog.Timer, error) {
+	return s.worklogs.ListTimers(ctx, taskID, user)
+}
+
+func (s *TaskStorage) AddWorklog(ctx context.Context, e worklog.Entry) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.journal(logRecord{Op: opWorklog, Worklog: &e}, func() error {
+		return s.worklogs.AddWorklog(ctx, e)
+	})
+}
+
+func (s *TaskStorage) ListWorklogs(ctx context.Context, f worklog.Filter) ([]worklog.Entry, error) {
+	return s.worklogs.ListWorklogs(ctx, f)
+}
+
+func (s *TaskStorage) WorklogTotals(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID]time.Duration, error) {
+	return s.worklogs.WorklogTotals(ctx, taskIDs)
+}
+
+// journal пишет запись в журнал, если он есть, и затем применяет её в памяти.
+// Вызывается под s.mtx.Lock после проверок, чтобы в журнал не попадали отказы
+func (s *TaskStorage) journal(rec logRecord, apply func() error) error {
+	if s.persister != nil {
+		if err := s.persister.append(rec); err != nil {
+			return err
+		}
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	if s.persister != nil {
+		s.persister.compactIfNeeded(s)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetActiveByStatuses читает колонки доски по индексу idx_tasks_active_status_rank
+func (s *Storage) GetActiveByStatuses(ctx context.Context, statuses []task.Status) ([]*task.Task, error) {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return s.queryTasks(ctx, 0, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE flag = 'active' AND status = ANY($1)
+		ORDER BY status, rank`, names)
+}
+
+// LockBoard берёт сессионную advisory-блокировку доски на отдельном
+// соединении, поэтому перемещения сериализуются между экземплярами сервиса.
+// Соединение занято, пока блокировка не снята
+func (s *Storage) LockBoard(ctx context.Context, board string) (func(), error) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("соединение для блокировки доски: %w", err)
+	}
+
+	key := "board:" + board
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock(hashtext($1))`, key); err != nil {
+		conn.Release()
+		logger.ErrorCtx(ctx, "Repository: Не удалось заблокировать доску", err, zap.String("board", board))
+		return nil, fmt.Errorf("блокировка доски: %w", err)
+	}
+
+	return func() {
+		// блокировку снимаем, даже если контекст запроса уже отменён
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, key); err != nil {
+			logger.Error("Repository: Не удалось снять блокировку доски", err, zap.String("board", board))
+			// с удерживаемой блокировкой соединение в пул не возвращается:
+			// при закрытии сессии Postgres снимет её сам
+			conn.Hijack().Close(ctx)
+			return
+		}
+		conn.Release()
+	}, nil
+}
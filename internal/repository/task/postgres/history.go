@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/history"
+	"taskTracker/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// журнал состояний задач; id записи - id события, поэтому повторная
+// доставка из outbox ничего не меняет
+
+const historyColumns = `id, task_id, event_type, status, flag, original_estimate, remaining_estimate, occurred_at`
+
+func (s *Storage) AppendHistory(ctx context.Context, r history.Record) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO task_history (`+historyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO NOTHING`,
+		r.EventID, r.TaskID, r.Event, r.Status, r.Flag, r.OriginalEstimate, r.RemainingEstimate, r.OccurredAt)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось записать историю задачи", err)
+		return fmt.Errorf("запись истории задачи: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) HistoryState(ctx context.Context, at time.Time) ([]history.Record, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT ON (task_id) `+historyColumns+`
+		FROM task_history
+		WHERE occurred_at < $1
+		ORDER BY task_id, occurred_at DESC`, at)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить состояние задач", err)
+		return nil, fmt.Errorf("получение состояния задач: %w", err)
+	}
+	return scanHistory(rows)
+}
+
+func (s *Storage) ListHistory(ctx context.Context, from, to time.Time) ([]history.Record, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+historyColumns+`
+		FROM task_history
+		WHERE occurred_at >= $1 AND occurred_at < $2
+		ORDER BY occurred_at`, from, to)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить историю задач", err)
+		return nil, fmt.Errorf("получение истории задач: %w", err)
+	}
+	return scanHistory(rows)
+}
+
+func scanHistory(rows pgx.Rows) ([]history.Record, error) {
+	defer rows.Close()
+
+	records := []history.Record{}
+	for rows.Next() {
+		var r history.Record
+		err := rows.Scan(&r.EventID, &r.TaskID, &r.Event, &r.Status, &r.Flag,
+			&r.OriginalEstimate, &r.RemainingEstimate, &r.OccurredAt)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование истории: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
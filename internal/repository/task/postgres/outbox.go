@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/outbox"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ключ advisory-блокировки: одновременно outbox читает только один релей,
+// иначе порядок событий одной задачи между экземплярами не гарантирован
+const outboxLockKey int64 = 0x6f7574626f78
+
+// querier - общее подмножество пула и транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// mutate выполняет мутацию и запись событий из ctx в одной транзакции.
+// t - состояние задачи после мутации (nil при полном удалении).
+// Без событий мутация идёт напрямую через пул.
+//
+// Порядок seq внутри одной задачи совпадает с порядком мутаций: UPDATE
+// блокирует строку задачи до коммита, поэтому следующая мутация
+// вставит свою запись в outbox уже после
+func (s *Storage) mutate(ctx context.Context, t *task.Task, fn func(q querier) error) error {
+	pending := events.Pending(ctx)
+	if len(pending) == 0 {
+		return fn(s.pool)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("начало транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	for _, e := range pending {
+		if t != nil {
+			e = e.WithTask(t)
+		}
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("сериализация события: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `INSERT INTO outbox (event_id, aggregate_id, event_type, payload)
+				VALUES ($1, $2, $3, $4)`,
+			e.ID, e.TaskID, e.Type, payload)
+		if err != nil {
+			return fmt.Errorf("запись события в outbox: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("фиксация транзакции: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) ProcessOutbox(ctx context.Context, limit int, handle func([]outbox.Record) []int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("начало транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxLockKey).Scan(&locked); err != nil {
+		return fmt.Errorf("блокировка outbox: %w", err)
+	}
+	if !locked {
+		// outbox сейчас обрабатывает другой экземпляр
+		return nil
+	}
+
+	rows, err := tx.Query(ctx, `SELECT seq, payload
+				FROM outbox
+				WHERE published_at IS NULL
+				ORDER BY seq
+				LIMIT $1`, limit)
+	if err != nil {
+		return fmt.Errorf("чтение outbox: %w", err)
+	}
+
+	var records []outbox.Record
+	for rows.Next() {
+		var (
+			record  outbox.Record
+			payload []byte
+		)
+		if err := rows.Scan(&record.Seq, &payload); err != nil {
+			rows.Close()
+			return fmt.Errorf("сканирование outbox: %w", err)
+		}
+		if err := json.Unmarshal(payload, &record.Event); err != nil {
+			rows.Close()
+			return fmt.Errorf("разбор события %d: %w", record.Seq, err)
+		}
+		records = append(records, record)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("итерация по outbox: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	published := handle(records)
+	if len(published) > 0 {
+		_, err := tx.Exec(ctx, `UPDATE outbox SET published_at = NOW() WHERE seq = ANY($1)`, published)
+		if err != nil {
+			return fmt.Errorf("отметка публикации: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("фиксация транзакции: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) CleanupOutbox(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < $1`, before)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось очистить outbox", err)
+		return 0, fmt.Errorf("очистка outbox: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *Storage) OutboxBacklog(ctx context.Context) (int64, error) {
+	var pending int64
+	err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox WHERE published_at IS NULL`).Scan(&pending)
+	if err != nil {
+		return 0, fmt.Errorf("подсчёт неопубликованных записей outbox: %w", err)
+	}
+	return pending, nil
+}
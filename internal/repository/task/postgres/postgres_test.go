@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"taskTracker/internal/models/task"
+	"taskTracker/internal/ratelimit"
 	"taskTracker/internal/repository/task/postgres"
 	"testing"
 	"time"
@@ -149,7 +150,106 @@ func (s *PostgresTestSuite) applyTestMigrations() error {
 		updated_at TIMESTAMP,
 		deleted_at TIMESTAMP,
 		version INTEGER NOT NULL DEFAULT 1,
-		flag VARCHAR(50) NOT NULL DEFAULT 'active'
+		flag VARCHAR(50) NOT NULL DEFAULT 'active',
+		rrule TEXT NOT NULL DEFAULT '',
+		reminders JSONB NOT NULL DEFAULT '[]',
+		rank TEXT NOT NULL DEFAULT '',
+		original_estimate DOUBLE PRECISION,
+		remaining_estimate DOUBLE PRECISION,
+		started_at TIMESTAMPTZ,
+		completed_at TIMESTAMPTZ,
+		next_reminder_at TIMESTAMPTZ
+	);
+
+	CREATE TABLE IF NOT EXISTS outbox (
+		seq BIGSERIAL PRIMARY KEY,
+		event_id UUID NOT NULL,
+		aggregate_id UUID NOT NULL,
+		event_type VARCHAR(50) NOT NULL,
+		payload JSONB NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		published_at TIMESTAMPTZ
+	);
+
+	CREATE TABLE IF NOT EXISTS rate_limits (
+		key TEXT PRIMARY KEY,
+		value DOUBLE PRECISION NOT NULL,
+		prev DOUBLE PRECISION NOT NULL,
+		stamp TIMESTAMPTZ,
+		updated_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS worklogs (
+		id UUID PRIMARY KEY,
+		task_id UUID NOT NULL,
+		user_id TEXT NOT NULL,
+		started_at TIMESTAMPTZ NOT NULL,
+		duration_seconds BIGINT NOT NULL,
+		note TEXT NOT NULL DEFAULT '',
+		source VARCHAR(20) NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS timers (
+		task_id UUID NOT NULL,
+		user_id TEXT NOT NULL,
+		started_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (task_id, user_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_history (
+		id UUID PRIMARY KEY,
+		task_id UUID NOT NULL,
+		event_type VARCHAR(50) NOT NULL,
+		status VARCHAR(50) NOT NULL,
+		flag VARCHAR(50) NOT NULL,
+		original_estimate DOUBLE PRECISION,
+		remaining_estimate DOUBLE PRECISION,
+		occurred_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+		id UUID PRIMARY KEY,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events JSONB NOT NULL DEFAULT '[]',
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		seq BIGSERIAL PRIMARY KEY,
+		id UUID NOT NULL,
+		delivery_id UUID NOT NULL,
+		subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event_id UUID NOT NULL,
+		event_type VARCHAR(50) NOT NULL,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		success BOOLEAN NOT NULL,
+		duration_ns BIGINT NOT NULL,
+		attempted_at TIMESTAMPTZ NOT NULL,
+		next_retry_at TIMESTAMPTZ
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		seq BIGSERIAL PRIMARY KEY,
+		delivery_id UUID NOT NULL,
+		subscription_id UUID NOT NULL,
+		event JSONB NOT NULL,
+		attempts INTEGER NOT NULL,
+		last_error TEXT NOT NULL,
+		failed_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_pending (
+		delivery_id UUID PRIMARY KEY,
+		subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event JSONB NOT NULL,
+		attempt INTEGER NOT NULL,
+		next_attempt_at TIMESTAMPTZ NOT NULL
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_tasks_flag ON tasks(flag);
@@ -474,6 +574,26 @@ func (s *PostgresTestSuite) TestStorage_HealthCheck() {
 	require.NoError(s.T(), err)
 }
 
+// TestStorage_RateLimit тестирует общий счётчик лимитера и удаление простаивающих ключей
+func (s *PostgresTestSuite) TestStorage_RateLimit() {
+	ctx := context.Background()
+	policy := ratelimit.Policy{Name: "test", Algorithm: ratelimit.TokenBucket, Limit: 2, Window: time.Minute}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		d, err := s.storage.TakeRateLimit(ctx, "test:ip:10.0.0.1", policy, now)
+		require.NoError(s.T(), err)
+		assert.True(s.T(), d.Allowed)
+	}
+	d, err := s.storage.TakeRateLimit(ctx, "test:ip:10.0.0.1", policy, now)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), d.Allowed)
+
+	evicted, err := s.storage.EvictRateLimits(ctx, now.Add(time.Second))
+	require.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 1, evicted)
+}
+
 // TestStorage_GetStatusedWithLimit тестирует получение задач по статусу
 func (s *PostgresTestSuite) TestStorage_GetStatusedWithLimit() {
 	ctx := context.Background()
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// taskColumns - колонки задачи в порядке полей, которые читает queryTasks
+const taskColumns = `uuid,
+			title,
+			description,
+			status,
+			due_time,
+			created_at,
+			updated_at,
+			deleted_at,
+			version,
+			flag,
+			rrule,
+			reminders,
+			rank,
+			original_estimate,
+			remaining_estimate,
+			started_at,
+			completed_at`
+
+// queryTasks выполняет запрос, выбирающий taskColumns. limit нужен только
+// для порога медленного запроса
+func (s *Storage) queryTasks(ctx context.Context, limit int, query string, args ...any) ([]*task.Task, error) {
+	start := time.Now()
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
+		return nil, fmt.Errorf("получение задач: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*task.Task{}
+	for rows.Next() {
+		t := &task.Task{}
+		err := rows.Scan(
+			&t.UUID,
+			&t.Title,
+			&t.Description,
+			&t.Status,
+			&t.DueTime,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+			&t.DeletedAt,
+			&t.Version,
+			&t.Flag,
+			&t.RRule,
+			&t.Reminders,
+			&t.Rank,
+			&t.OriginalEstimate,
+			&t.RemainingEstimate,
+			&t.StartedAt,
+			&t.CompletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование задачи: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorCtx(ctx, "Repository: Ошибка итерации по строкам", err)
+		return nil, fmt.Errorf("итерация по строкам: %w", err)
+	}
+
+	if time.Since(start) > time.Millisecond*50+time.Millisecond*10*time.Duration(limit) {
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+	}
+	return tasks, nil
+}
+
+func (s *Storage) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, error) {
+	return s.queryTasks(ctx, len(ids), `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE uuid = ANY($1)`, ids)
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/ratelimit"
+	"time"
+)
+
+// TakeRateLimit - счётчик лимитера, общий для всех реплик. Строка ключа
+// блокируется до конца транзакции, поэтому одновременные запросы одного
+// клиента с разных реплик применяются по очереди
+func (s *Storage) TakeRateLimit(ctx context.Context, key string, p ratelimit.Policy, now time.Time) (ratelimit.Decision, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("начало транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limits (key, value, prev, stamp, updated_at)
+		VALUES ($1, 0, 0, NULL, $2)
+		ON CONFLICT (key) DO NOTHING`, key, now)
+	if err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("создание счётчика: %w", err)
+	}
+
+	var state ratelimit.State
+	var stamp *time.Time
+	err = tx.QueryRow(ctx, `SELECT value, prev, stamp FROM rate_limits WHERE key = $1 FOR UPDATE`, key).
+		Scan(&state.Value, &state.Prev, &stamp)
+	if err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("чтение счётчика: %w", err)
+	}
+	if stamp != nil {
+		state.Stamp = *stamp
+	}
+
+	decision := ratelimit.Take(&state, p, now)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE rate_limits SET value = $2, prev = $3, stamp = $4, updated_at = $5
+		WHERE key = $1`, key, state.Value, state.Prev, state.Stamp, now)
+	if err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("запись счётчика: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("фиксация транзакции: %w", err)
+	}
+	return decision, nil
+}
+
+func (s *Storage) EvictRateLimits(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM rate_limits WHERE updated_at < $1`, before)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось удалить простаивающие счётчики лимитера", err)
+		return 0, fmt.Errorf("очистка rate_limits: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"taskTracker/internal/models/task"
+	"time"
+)
+
+// next_reminder_at хранит task.Reminders.NextAt и пересчитывается при
+// каждой записи задачи, поэтому планировщик идёт по частичному индексу
+
+func (s *Storage) GetTasksWithDueReminders(ctx context.Context, now time.Time, limit int) ([]*task.Task, error) {
+	return s.queryTasks(ctx, limit, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE next_reminder_at <= $1
+			AND due_time > $1
+			AND flag = 'active'
+			AND status NOT IN ('done', 'cancelled', 'overdue')
+		ORDER BY next_reminder_at
+		LIMIT $2`, now, limit)
+}
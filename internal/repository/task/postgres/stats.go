@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/stats"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// статистика считается агрегатами на стороне базы: в приложение приходят
+// только строки по дням и короткий список просроченных задач
+
+func (s *Storage) TaskStats(ctx context.Context, q stats.Query, now time.Time) (*stats.Aggregates, error) {
+	start := time.Now()
+
+	counts, err := s.CountTasks(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	agg := &stats.Aggregates{Counts: counts}
+
+	if agg.Created, err = s.createdByDay(ctx, q); err != nil {
+		return nil, err
+	}
+	if err := s.completedByDay(ctx, q, agg); err != nil {
+		return nil, err
+	}
+	if agg.TopOverdue, err = s.topOverdue(ctx, now, q.Top); err != nil {
+		return nil, err
+	}
+
+	if time.Since(start) > 200*time.Millisecond {
+		logger.WarnCtx(ctx, "Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
+	}
+	return agg, nil
+}
+
+func (s *Storage) createdByDay(ctx context.Context, q stats.Query) (map[string]int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD'), COUNT(*)
+		FROM tasks
+		WHERE flag <> 'deleted' AND created_at >= $1 AND created_at < $2
+		GROUP BY 1`, q.From, q.To)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось посчитать созданные задачи", err)
+		return nil, fmt.Errorf("подсчёт созданных задач: %w", err)
+	}
+	defer rows.Close()
+
+	created := make(map[string]int)
+	for rows.Next() {
+		var (
+			day string
+			n   int
+		)
+		if err := rows.Scan(&day, &n); err != nil {
+			return nil, fmt.Errorf("сканирование созданных задач: %w", err)
+		}
+		created[day] = n
+	}
+	return created, rows.Err()
+}
+
+// completedByDay считает выполненные задачи по дням и заодно суммы lead
+// time и cycle time, чтобы не читать те же строки второй раз
+func (s *Storage) completedByDay(ctx context.Context, q stats.Query, agg *stats.Aggregates) error {
+	rows, err := s.pool.Query(ctx, `
+		SELECT to_char(completed_at AT TIME ZONE 'UTC', 'YYYY-MM-DD'),
+			COUNT(*),
+			COALESCE(SUM(EXTRACT(EPOCH FROM completed_at - created_at)), 0)::float8,
+			COUNT(started_at),
+			COALESCE(SUM(EXTRACT(EPOCH FROM completed_at - started_at)), 0)::float8
+		FROM tasks
+		WHERE flag <> 'deleted' AND completed_at >= $1 AND completed_at < $2
+		GROUP BY 1`, q.From, q.To)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось посчитать выполненные задачи", err)
+		return fmt.Errorf("подсчёт выполненных задач: %w", err)
+	}
+	defer rows.Close()
+
+	agg.Completed = make(map[string]int)
+	for rows.Next() {
+		var (
+			day               string
+			completed, cycled int
+			lead, cycle       float64
+		)
+		if err := rows.Scan(&day, &completed, &lead, &cycled, &cycle); err != nil {
+			return fmt.Errorf("сканирование выполненных задач: %w", err)
+		}
+		agg.Completed[day] = completed
+		agg.LeadTime = agg.LeadTime.Add(stats.Durations{Sum: seconds(lead), Tasks: completed})
+		agg.CycleTime = agg.CycleTime.Add(stats.Durations{Sum: seconds(cycle), Tasks: cycled})
+	}
+	return rows.Err()
+}
+
+func (s *Storage) topOverdue(ctx context.Context, now time.Time, limit int) ([]*task.Task, error) {
+	if limit == 0 {
+		return []*task.Task{}, nil
+	}
+	return s.queryTasks(ctx, limit, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE flag = 'active'
+			AND status NOT IN ('done', 'cancelled')
+			AND due_time < $1
+		ORDER BY due_time
+		LIMIT $2`, now, limit)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
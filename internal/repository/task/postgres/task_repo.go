@@ -6,6 +6,7 @@ import (
 	"taskTracker/internal/logger"
 	"taskTracker/internal/models/task"
 	repo "taskTracker/internal/repository"
+	"taskTracker/internal/tracing"
 	"time"
 	"os"
 	"github.com/google/uuid"
@@ -21,27 +22,29 @@ type Storage struct {
 func New(ctx context.Context, connString string) (*Storage, error) {
 	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		logger.Error("Repository: Ошибка загрузки конфига", err)
+		logger.ErrorCtx(ctx, "Repository: Ошибка загрузки конфига", err)
 		return nil, fmt.Errorf("загрузка конфига: %w", err)
 	}
 
 	config.MaxConns = 10
 	config.MinConns = 2
 	config.MaxConnIdleTime = time.Minute * 5
+	// без трассировки спаны запросов уходят в noop-провайдер
+	config.ConnConfig.Tracer = tracing.NewPgxTracer()
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
-		logger.Error("Repository: Ошибка создания пула", err)
+		logger.ErrorCtx(ctx, "Repository: Ошибка создания пула", err)
 		return nil, fmt.Errorf("создание пула: %w", err)
 	}
 
 	err = pool.Ping(ctx)
 	if err != nil {
-		logger.Error("Repository: Неудачная проверка ping", err)
+		logger.ErrorCtx(ctx, "Repository: Неудачная проверка ping", err)
 		return nil, fmt.Errorf("проверка соединения ping: %w", err)
 	}
 
-	logger.Info("Repository: Успешное создание подключения к PostgreSQL")
+	logger.InfoCtx(ctx, "Repository: Успешное создание подключения к PostgreSQL")
 	return &Storage{pool: pool}, nil
 }
 
@@ -53,10 +56,9 @@ func (s *Storage) Close() {
 func (s *Storage) HealthCheck(ctx context.Context) error {
 	err := s.pool.Ping(ctx)
 	if err != nil {
-		logger.Error("Repository: Неудачная проверка ping", err)
+		logger.ErrorCtx(ctx, "Repository: Неудачная проверка ping", err)
 		return fmt.Errorf("проверка соединения ping: %w", err)
 	}
-	logger.Info("Repository: Соединение стабильно")
 	return nil
 }
 
@@ -71,22 +73,24 @@ func (s *Storage) DeleteSoft(ctx context.Context, taskToDelete *task.Task) error
 			WHERE uuid = $2 AND version = $3 
 			RETURNING deleted_at, version`
 
-	err := s.pool.QueryRow(ctx, query, task.FlagDeleted, taskToDelete.UUID, taskToDelete.Version).Scan(&taskToDelete.DeletedAt, &taskToDelete.Version)
+	err := s.mutate(ctx, taskToDelete, func(q querier) error {
+		return q.QueryRow(ctx, query, task.FlagDeleted, taskToDelete.UUID, taskToDelete.Version).Scan(&taskToDelete.DeletedAt, &taskToDelete.Version)
+	})
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			logger.Warn("Конфликт версий при мягком удалении",
+			logger.WarnCtx(ctx, "Конфликт версий при мягком удалении",
 				zap.String("task_id", taskToDelete.UUID.String()),
 				zap.Int("expected_version", taskToDelete.Version))
 			return repo.ErrVersionConflict
 		}
 
-		logger.Error("Repository: Мягкое удаление задачи", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repository: Мягкое удаление задачи", err, zap.Duration("ms", time.Since(start)))
 		return fmt.Errorf("мягкое удаление: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*100 {
-		logger.Warn("Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
 	}
 	return err
 }
@@ -98,15 +102,18 @@ func (s *Storage) DeleteFull(ctx context.Context, uuid uuid.UUID) error {
 	query := `DELETE FROM tasks
 				WHERE uuid = $1`
 
-	_, err := s.pool.Exec(ctx, query, uuid)
+	err := s.mutate(ctx, nil, func(q querier) error {
+		_, err := q.Exec(ctx, query, uuid)
+		return err
+	})
 
 	if err != nil {
-		logger.Error("Repositry: Полное уделание задачи", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repositry: Полное уделание задачи", err, zap.Duration("ms", time.Since(start)))
 		return fmt.Errorf("полное удаление: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*100 {
-		logger.Warn("Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
 	}
 
 	return nil
@@ -122,33 +129,51 @@ func (s *Storage) Update(ctx context.Context, taskToUpdate *task.Task) error {
 				due_time = $4,
 				version = version + 1,
 				updated_at = NOW(),
-				flag = $5
-			WHERE uuid = $6 AND version = $7
+				flag = $5,
+				rrule = $6,
+				reminders = $7,
+				rank = $8,
+				original_estimate = $9,
+				remaining_estimate = $10,
+				started_at = $11,
+				completed_at = $12,
+				next_reminder_at = $13
+			WHERE uuid = $14 AND version = $15
 			RETURNING updated_at, version`
 
-	err := s.pool.QueryRow(ctx, query,
-		taskToUpdate.Title,
-		taskToUpdate.Description,
-		taskToUpdate.Status,
-		taskToUpdate.DueTime,
-		taskToUpdate.Flag,
-		taskToUpdate.UUID,
-		taskToUpdate.Version,
-	).Scan(&taskToUpdate.UpdatedAt, &taskToUpdate.Version)
+	err := s.mutate(ctx, taskToUpdate, func(q querier) error {
+		return q.QueryRow(ctx, query,
+			taskToUpdate.Title,
+			taskToUpdate.Description,
+			taskToUpdate.Status,
+			taskToUpdate.DueTime,
+			taskToUpdate.Flag,
+			taskToUpdate.RRule,
+			taskToUpdate.Reminders,
+			taskToUpdate.Rank,
+			taskToUpdate.OriginalEstimate,
+			taskToUpdate.RemainingEstimate,
+			taskToUpdate.StartedAt,
+			taskToUpdate.CompletedAt,
+			taskToUpdate.Reminders.NextAt(taskToUpdate.DueTime),
+			taskToUpdate.UUID,
+			taskToUpdate.Version,
+		).Scan(&taskToUpdate.UpdatedAt, &taskToUpdate.Version)
+	})
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			logger.Warn("Конфликт версий при обновлении задачи",
+			logger.WarnCtx(ctx, "Конфликт версий при обновлении задачи",
 				zap.String("task_id", taskToUpdate.UUID.String()),
 				zap.Int("expected_version", taskToUpdate.Version))
 			return repo.ErrVersionConflict // Нужно добавить эту ошибку
 		}
-		logger.Error("Repository: Не удалось обновить задачу", err)
+		logger.ErrorCtx(ctx, "Repository: Не удалось обновить задачу", err)
 		return fmt.Errorf("обновление задачи: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*100 {
-		logger.Warn("Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
 	}
 	return err
 }
@@ -157,27 +182,38 @@ func (s *Storage) Create(ctx context.Context, taskToCreate *task.Task) error {
 	start := time.Now()
 
 	query := `INSERT INTO tasks
-				(uuid, title, description, status, due_time, created_at, flag)
-				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				(uuid, title, description, status, due_time, created_at, flag, rrule, reminders, rank,
+				original_estimate, remaining_estimate, started_at, completed_at, next_reminder_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 				RETURNING created_at`
 
-	err := s.pool.QueryRow(ctx, query,
-		taskToCreate.UUID,
-		taskToCreate.Title,
-		taskToCreate.Description,
-		taskToCreate.Status,
-		taskToCreate.DueTime,
-		time.Now(),
-		task.FlagActive,
-	).Scan(&taskToCreate.CreatedAt)
+	err := s.mutate(ctx, taskToCreate, func(q querier) error {
+		return q.QueryRow(ctx, query,
+			taskToCreate.UUID,
+			taskToCreate.Title,
+			taskToCreate.Description,
+			taskToCreate.Status,
+			taskToCreate.DueTime,
+			time.Now(),
+			task.FlagActive,
+			taskToCreate.RRule,
+			taskToCreate.Reminders,
+			taskToCreate.Rank,
+			taskToCreate.OriginalEstimate,
+			taskToCreate.RemainingEstimate,
+			taskToCreate.StartedAt,
+			taskToCreate.CompletedAt,
+			taskToCreate.Reminders.NextAt(taskToCreate.DueTime),
+		).Scan(&taskToCreate.CreatedAt)
+	})
 
 	if err != nil {
-		logger.Error("Repository: Не удалось добавить задачу", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repository: Не удалось добавить задачу", err, zap.Duration("ms", time.Since(start)))
 		return fmt.Errorf("добавление задачи: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*50 {
-		logger.Warn("Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
 	}
 	return nil
 }
@@ -195,7 +231,14 @@ func (s *Storage) GetByID(ctx context.Context, uuid uuid.UUID) (*task.Task, erro
 				updated_at,
 				deleted_at,
 				version,
-				flag 
+				flag,
+				rrule,
+				reminders,
+				rank,
+				original_estimate,
+				remaining_estimate,
+				started_at,
+				completed_at
 				FROM tasks
 				WHERE uuid = $1`
 
@@ -211,15 +254,22 @@ func (s *Storage) GetByID(ctx context.Context, uuid uuid.UUID) (*task.Task, erro
 		&task.DeletedAt,
 		&task.Version,
 		&task.Flag,
+		&task.RRule,
+		&task.Reminders,
+		&task.Rank,
+		&task.OriginalEstimate,
+		&task.RemainingEstimate,
+		&task.StartedAt,
+		&task.CompletedAt,
 	)
 
 	if err != nil {
-		logger.Error("Repository: Не удалось получить задачу", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачу", err, zap.Duration("ms", time.Since(start)))
 		return nil, fmt.Errorf("получение задачи: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*100 {
-		logger.Warn("Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
 	}
 
 	return task, nil
@@ -239,14 +289,21 @@ func (s *Storage) GetAllWithLimit(ctx context.Context, page, limit int) ([]*task
 				updated_at,
 				deleted_at,
 				version,
-				flag
+				flag,
+				rrule,
+				reminders,
+				rank,
+				original_estimate,
+				remaining_estimate,
+				started_at,
+				completed_at
 				FROM tasks
 				WHERE flag != $1
 				LIMIT $2 OFFSET $3`
 
 	rows, err := s.pool.Query(ctx, query, task.FlagDeleted, limit, offset)
 	if err != nil {
-		logger.Error("Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
 		return nil, fmt.Errorf("получение задач: %w", err)
 	}
 
@@ -268,22 +325,29 @@ func (s *Storage) GetAllWithLimit(ctx context.Context, page, limit int) ([]*task
 			&task.DeletedAt,
 			&task.Version,
 			&task.Flag,
+			&task.RRule,
+			&task.Reminders,
+			&task.Rank,
+			&task.OriginalEstimate,
+			&task.RemainingEstimate,
+			&task.StartedAt,
+			&task.CompletedAt,
 		)
 
 		if err != nil {
-			logger.Warn("Repository: Ошибка сканирования задачи", zap.Error(err))
+			logger.WarnCtx(ctx, "Repository: Ошибка сканирования задачи", zap.Error(err))
 		}
 
 		tasks = append(tasks, task)
 	}
 
 	if err := rows.Err(); err != nil {
-		logger.Error("Repository: Ошибка иерации по строкам", err)
+		logger.ErrorCtx(ctx, "Repository: Ошибка иерации по строкам", err)
 		return nil, fmt.Errorf("итерация по строкам: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*50+time.Millisecond*10*time.Duration(limit) {
-		logger.Warn("Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
 	}
 
 	return tasks, nil
@@ -305,14 +369,21 @@ func (s *Storage) GetStatusedWithLimit(ctx context.Context, page, limit int, sta
 				updated_at,
 				deleted_at,
 				version,
-				flag
+				flag,
+				rrule,
+				reminders,
+				rank,
+				original_estimate,
+				remaining_estimate,
+				started_at,
+				completed_at
 				FROM tasks
 				WHERE STATUS = $1
 				LIMIT $2 OFFSET $3`
 
 	rows, err := s.pool.Query(ctx, query, status, limit, offset)
 	if err != nil {
-		logger.Error("Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
 		return nil, fmt.Errorf("получение задач: %w", err)
 	}
 
@@ -333,19 +404,26 @@ func (s *Storage) GetStatusedWithLimit(ctx context.Context, page, limit int, sta
 			&task.DeletedAt,
 			&task.Version,
 			&task.Flag,
+			&task.RRule,
+			&task.Reminders,
+			&task.Rank,
+			&task.OriginalEstimate,
+			&task.RemainingEstimate,
+			&task.StartedAt,
+			&task.CompletedAt,
 		)
 		if err != nil {
-			logger.Warn("Repository: Ошибка сканирования задачи", zap.Error(err))
+			logger.WarnCtx(ctx, "Repository: Ошибка сканирования задачи", zap.Error(err))
 		}
 		tasks = append(tasks, task)
 	}
 	if err := rows.Err(); err != nil {
-		logger.Error("Repository: Ошибка итерации по строкам", err)
+		logger.ErrorCtx(ctx, "Repository: Ошибка итерации по строкам", err)
 		return nil, fmt.Errorf("итерация по строкам: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*50+time.Millisecond*10*time.Duration(limit) {
-		logger.Warn("Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
 	}
 
 	return tasks, nil
@@ -366,14 +444,21 @@ func (s *Storage) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag
 				updated_at,
 				deleted_at,
 				version,
-				flag
+				flag,
+				rrule,
+				reminders,
+				rank,
+				original_estimate,
+				remaining_estimate,
+				started_at,
+				completed_at
 				FROM tasks
 				WHERE flag = $1
 				LIMIT $2 OFFSET $3`
 
 	rows, err := s.pool.Query(ctx, query, flag, limit, offset)
 	if err != nil {
-		logger.Error("Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
 		return nil, fmt.Errorf("получение задач: %w", err)
 	}
 
@@ -394,19 +479,26 @@ func (s *Storage) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag
 			&task.DeletedAt,
 			&task.Version,
 			&task.Flag,
+			&task.RRule,
+			&task.Reminders,
+			&task.Rank,
+			&task.OriginalEstimate,
+			&task.RemainingEstimate,
+			&task.StartedAt,
+			&task.CompletedAt,
 		)
 		if err != nil {
-			logger.Warn("Repository: Ошибка сканирования задачи", zap.Error(err))
+			logger.WarnCtx(ctx, "Repository: Ошибка сканирования задачи", zap.Error(err))
 		}
 		tasks = append(tasks, task)
 	}
 	if err := rows.Err(); err != nil {
-		logger.Error("Repository: Ошибка итерации по строкам", err)
+		logger.ErrorCtx(ctx, "Repository: Ошибка итерации по строкам", err)
 		return nil, fmt.Errorf("итерация по строкам: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*50+time.Millisecond*10*time.Duration(limit) {
-		logger.Warn("Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
 	}
 
 	return tasks, nil
@@ -415,15 +507,33 @@ func (s *Storage) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag
 func (s *Storage) GetTasksDueBefore(ctx context.Context, deadline time.Time, limit int) ([]*task.Task, error){
 	start := time.Now()
 
-	query := `SELECT * FROM tasks 
+	query := `SELECT
+				uuid,
+				title,
+				description,
+				status,
+				due_time,
+				created_at,
+				updated_at,
+				deleted_at,
+				version,
+				flag,
+				rrule,
+				reminders,
+				rank,
+				original_estimate,
+				remaining_estimate,
+				started_at,
+				completed_at
+				FROM tasks
               WHERE flag = 'active' 
-                AND status NOT IN ('done', 'overdue')
+                AND status NOT IN ('done', 'cancelled', 'overdue')
                 AND due_time < $1
               LIMIT $2`
 
 	rows, err := s.pool.Query(ctx, query, deadline, limit)
 	if err != nil{
-		logger.Error("Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
 		return nil, fmt.Errorf("получение задач: %w", err)
 	}
 
@@ -442,88 +552,136 @@ func (s *Storage) GetTasksDueBefore(ctx context.Context, deadline time.Time, lim
 			&task.DeletedAt,
 			&task.Version,
 			&task.Flag,
+			&task.RRule,
+			&task.Reminders,
+			&task.Rank,
+			&task.OriginalEstimate,
+			&task.RemainingEstimate,
+			&task.StartedAt,
+			&task.CompletedAt,
 		)
 
 		if err != nil{
-			logger.Warn("Repository: Ошибка сканирования задачи", zap.Error(err))
+			logger.WarnCtx(ctx, "Repository: Ошибка сканирования задачи", zap.Error(err))
 		}
 
 		tasks = append(tasks, task)
 	}
 	
 	if err := rows.Err(); err != nil{
-		logger.Error("Repository: Ошибка итерации по строкам", err)
+		logger.ErrorCtx(ctx, "Repository: Ошибка итерации по строкам", err)
 		return nil, fmt.Errorf("итерация по строкам: %w", err)
 	}
 
 	if time.Since(start) > time.Millisecond*50+time.Millisecond*10*time.Duration(limit) {
-		logger.Warn("Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
 	}
 	
 	return tasks, nil
 }
 
 
-func (s *Storage)Migrate(ctx context.Context) error {
-	logger.Info("Попытка миграций")
-	
-	initUp, err := os.ReadFile("internal/migrations/001_init.up.sql")
-	if err != nil {
-		logger.Error("failed to read 001_init.up.sql", err)
-		return err
-	}
-	
-	indexesUp, err := os.ReadFile("internal/migrations/002_indexes.up.sql")
-	if err != nil {
-		
-		logger.Error("failed to read 002_indexes.up.sql", err)
-		return err
-	}
-	
-		_, err = s.pool.Exec(ctx,string(initUp))
-	if err != nil {
-		logger.Error("failed to apply 001_init", err)
-		return err 
+// миграции применяются по порядку и откатываются в обратном
+var migrations = []string{
+	"001_init",
+	"002_indexes",
+	"003_recurrence",
+	"004_reminders",
+	"005_outbox",
+	"006_workflow_statuses",
+	"007_task_rank",
+	"008_worklogs",
+	"009_estimates",
+	"010_task_history",
+	"011_task_progress",
+	"012_reminder_schedule",
+	"013_webhooks",
+	"014_rate_limits",
+}
+
+func (s *Storage) Migrate(ctx context.Context) error {
+	logger.InfoCtx(ctx, "Попытка миграций")
+
+	for _, name := range migrations {
+		up, err := os.ReadFile("internal/migrations/" + name + ".up.sql")
+		if err != nil {
+			logger.ErrorCtx(ctx, "failed to read "+name+".up.sql", err)
+			return err
+		}
+
+		_, err = s.pool.Exec(ctx, string(up))
+		if err != nil {
+			logger.ErrorCtx(ctx, "failed to apply "+name, err)
+			return err
+		}
 	}
-	
-	_, err = s.pool.Exec(ctx,string(indexesUp))
-	if err != nil {
-		logger.Error("failed to apply 002_indexes", err)
-		return err
+
+	logger.InfoCtx(ctx, "Христа ради миграции заработали")
+	return nil
+}
+
+func (s *Storage) Down(ctx context.Context) error {
+	logger.InfoCtx(ctx, "Откат миграций")
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		name := migrations[i]
+		down, err := os.ReadFile("internal/migrations/" + name + ".down.sql")
+		if err != nil {
+			logger.ErrorCtx(ctx, "failed to read "+name+".down.sql", err)
+			return err
+		}
+
+		_, err = s.pool.Exec(ctx, string(down))
+		if err != nil {
+			logger.ErrorCtx(ctx, "failed to rollback "+name, err)
+			return err
+		}
 	}
-	
-	logger.Info("Христа ради миграции заработали")
+
+	logger.InfoCtx(ctx, "Migrations rolled back successfully!")
 	return nil
 }
 
+// CountTasks считает задачи для метрик одним запросом
+func (s *Storage) CountTasks(ctx context.Context, now time.Time) (task.Counts, error) {
+	start := time.Now()
 
-func (s *Storage )Down(ctx context.Context) error {
-	logger.Info("Откат миграций")
+	query := `SELECT flag, status, COUNT(*),
+				COUNT(*) FILTER (WHERE flag = 'active' AND status NOT IN ('done', 'cancelled') AND due_time < $1)
+				FROM tasks
+				GROUP BY flag, status`
 
-	indexesDown, err :=	os.ReadFile("internal/migrations/002_indexes.down.sql")
+	var counts task.Counts
+	rows, err := s.pool.Query(ctx, query, now)
 	if err != nil {
-		logger.Error("failed to read 002_indexes.down.sql", err)
-		return err
+		logger.ErrorCtx(ctx, "Repository: Не удалось посчитать задачи", err, zap.Duration("ms", time.Since(start)))
+		return counts, fmt.Errorf("подсчёт задач: %w", err)
 	}
-	
-	initDown, err := os.ReadFile("internal/migrations/001_init.down.sql")
-	if err != nil {
-		logger.Error("failed to read 001_init.down.sql", err)
-		return err
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			flag           task.Flag
+			status         task.Status
+			total, overdue int
+		)
+		if err := rows.Scan(&flag, &status, &total, &overdue); err != nil {
+			return counts, fmt.Errorf("сканирование счётчиков: %w", err)
+		}
+		counts.Add(flag, status, total)
+		counts.Overdue += overdue
 	}
-	
-	_, err = s.pool.Exec(ctx, string(indexesDown))
-	if err != nil {
-		logger.Error("failed to rollback 002_indexes", err)
-		return  err
+	if err := rows.Err(); err != nil {
+		return counts, fmt.Errorf("итерация по строкам: %w", err)
 	}
-	
-	_, err = s.pool.Exec(ctx, string(initDown))
-	if err != nil {
-		logger.Error("failed to rollback 001_init", err)
-		return err
+
+	if time.Since(start) > 100*time.Millisecond {
+		logger.WarnCtx(ctx, "Repository: Медленная операция", zap.Duration("ms", time.Since(start)))
 	}
-	
-	logger.Info("Migrations rolled back successfully!")
-	return nil
-}
\ No newline at end of file
+	return counts, nil
+}
+
+// PoolStat - статистика пула соединений для метрик
+func (s *Storage) PoolStat() *pgxpool.Stat {
+	return s.pool.Stat()
+}
@@ -0,0 +1,323 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/webhook"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// подписки на вебхуки, журнал попыток, dead-letter и отложенные доставки.
+// Попытки и отложенные доставки удаляются вместе с подпиской (ON DELETE CASCADE),
+// dead-letter остаётся для разбора
+
+const subscriptionColumns = `id, url, secret, events, active, created_at, updated_at`
+
+// isForeignKeyViolation - подписки, на которую ссылается запись, уже нет
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+func (s *Storage) CreateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	types, err := json.Marshal(sub.Events)
+	if err != nil {
+		return fmt.Errorf("сериализация событий подписки: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO webhook_subscriptions (`+subscriptionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		sub.ID, sub.URL, sub.Secret, types, sub.Active, sub.CreatedAt, sub.UpdatedAt)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось создать подписку", err)
+		return fmt.Errorf("создание подписки: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) UpdateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	types, err := json.Marshal(sub.Events)
+	if err != nil {
+		return fmt.Errorf("сериализация событий подписки: %w", err)
+	}
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = $2, secret = $3, events = $4, active = $5, updated_at = $6
+		WHERE id = $1`,
+		sub.ID, sub.URL, sub.Secret, types, sub.Active, sub.UpdatedAt)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось обновить подписку", err)
+		return fmt.Errorf("обновление подписки: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Storage) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось удалить подписку", err)
+		return fmt.Errorf("удаление подписки: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Storage) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	sub, err := scanSubscription(s.pool.QueryRow(ctx, `
+		SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, webhook.ErrNotFound
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить подписку", err)
+		return nil, fmt.Errorf("получение подписки: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *Storage) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+subscriptionColumns+` FROM webhook_subscriptions ORDER BY created_at, id`)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить подписки", err)
+		return nil, fmt.Errorf("получение подписок: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*webhook.Subscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование подписки: %w", err)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+	return subscriptions, rows.Err()
+}
+
+func scanSubscription(row pgx.Row) (*webhook.Subscription, error) {
+	var (
+		sub   webhook.Subscription
+		types []byte
+	)
+	err := row.Scan(&sub.ID, &sub.URL, &sub.Secret, &types, &sub.Active, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(types, &sub.Events); err != nil {
+		return nil, fmt.Errorf("разбор событий подписки %s: %w", sub.ID, err)
+	}
+	return &sub, nil
+}
+
+// AddDelivery записывает попытку и оставляет на подписку
+// не больше webhook.MaxDeliveriesPerSubscription последних
+func (s *Storage) AddDelivery(ctx context.Context, d webhook.Delivery) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO webhook_deliveries (id, delivery_id, subscription_id, event_id, event_type, attempt,
+			status_code, error, success, duration_ns, attempted_at, next_retry_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		d.ID, d.DeliveryID, d.SubscriptionID, d.EventID, d.EventType, d.Attempt,
+		d.StatusCode, d.Error, d.Success, int64(d.Duration), d.AttemptedAt, d.NextRetryAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return webhook.ErrNotFound
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось записать попытку доставки", err)
+		return fmt.Errorf("запись попытки доставки: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE subscription_id = $1 AND seq <= (
+			SELECT seq FROM webhook_deliveries
+			WHERE subscription_id = $1
+			ORDER BY seq DESC
+			OFFSET $2 LIMIT 1
+		)`, d.SubscriptionID, webhook.MaxDeliveriesPerSubscription)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось очистить журнал доставок", err)
+		return fmt.Errorf("очистка журнала доставок: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries отдаёт последние попытки, начиная с самой свежей
+func (s *Storage) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]webhook.Delivery, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = webhook.MaxDeliveriesPerSubscription
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, delivery_id, subscription_id, event_id, event_type, attempt,
+			status_code, error, success, duration_ns, attempted_at, next_retry_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY seq DESC
+		LIMIT $2`, subscriptionID, limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить попытки доставки", err)
+		return nil, fmt.Errorf("получение попыток доставки: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []webhook.Delivery{}
+	for rows.Next() {
+		var (
+			d        webhook.Delivery
+			duration int64
+		)
+		err := rows.Scan(&d.ID, &d.DeliveryID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Attempt,
+			&d.StatusCode, &d.Error, &d.Success, &duration, &d.AttemptedAt, &d.NextRetryAt)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование попытки доставки: %w", err)
+		}
+		d.Duration = time.Duration(duration)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// AddDeadLetter записывает событие и оставляет не больше webhook.MaxDeadLetters последних
+func (s *Storage) AddDeadLetter(ctx context.Context, d webhook.DeadLetter) error {
+	payload, err := json.Marshal(d.Event)
+	if err != nil {
+		return fmt.Errorf("сериализация события: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO webhook_dead_letters (delivery_id, subscription_id, event, attempts, last_error, failed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		d.DeliveryID, d.SubscriptionID, payload, d.Attempts, d.LastError, d.FailedAt)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось записать событие в dead-letter", err)
+		return fmt.Errorf("запись в dead-letter: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		DELETE FROM webhook_dead_letters
+		WHERE seq <= (SELECT seq FROM webhook_dead_letters ORDER BY seq DESC OFFSET $1 LIMIT 1)`,
+		webhook.MaxDeadLetters)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось очистить dead-letter", err)
+		return fmt.Errorf("очистка dead-letter: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) ListDeadLetters(ctx context.Context, limit int) ([]webhook.DeadLetter, error) {
+	if limit <= 0 {
+		limit = webhook.MaxDeadLetters
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT delivery_id, subscription_id, event, attempts, last_error, failed_at
+		FROM webhook_dead_letters
+		ORDER BY seq DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить dead-letter", err)
+		return nil, fmt.Errorf("получение dead-letter: %w", err)
+	}
+	defer rows.Close()
+
+	deadLetters := []webhook.DeadLetter{}
+	for rows.Next() {
+		var (
+			d       webhook.DeadLetter
+			payload []byte
+		)
+		if err := rows.Scan(&d.DeliveryID, &d.SubscriptionID, &payload, &d.Attempts, &d.LastError, &d.FailedAt); err != nil {
+			return nil, fmt.Errorf("сканирование dead-letter: %w", err)
+		}
+		if err := json.Unmarshal(payload, &d.Event); err != nil {
+			return nil, fmt.Errorf("разбор события %s: %w", d.DeliveryID, err)
+		}
+		deadLetters = append(deadLetters, d)
+	}
+	return deadLetters, rows.Err()
+}
+
+func (s *Storage) SavePending(ctx context.Context, p webhook.Pending) error {
+	payload, err := json.Marshal(p.Event)
+	if err != nil {
+		return fmt.Errorf("сериализация события: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO webhook_pending (delivery_id, subscription_id, event, attempt, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (delivery_id) DO UPDATE
+		SET attempt = EXCLUDED.attempt, next_attempt_at = EXCLUDED.next_attempt_at`,
+		p.DeliveryID, p.SubscriptionID, payload, p.Attempt, p.NextAttemptAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return webhook.ErrNotFound
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось сохранить отложенную доставку", err)
+		return fmt.Errorf("сохранение отложенной доставки: %w", err)
+	}
+	return nil
+}
+
+// ClaimPending забирает созревшие доставки одним UPDATE; SKIP LOCKED не даёт
+// двум экземплярам взять одну и ту же запись
+func (s *Storage) ClaimPending(ctx context.Context, now, until time.Time, limit int) ([]webhook.Pending, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE webhook_pending SET next_attempt_at = $2
+		WHERE delivery_id IN (
+			SELECT delivery_id FROM webhook_pending
+			WHERE next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING delivery_id, subscription_id, event, attempt, next_attempt_at`, now, until, limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить отложенные доставки", err)
+		return nil, fmt.Errorf("получение отложенных доставок: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []webhook.Pending
+	for rows.Next() {
+		var (
+			p       webhook.Pending
+			payload []byte
+		)
+		if err := rows.Scan(&p.DeliveryID, &p.SubscriptionID, &payload, &p.Attempt, &p.NextAttemptAt); err != nil {
+			return nil, fmt.Errorf("сканирование отложенной доставки: %w", err)
+		}
+		if err := json.Unmarshal(payload, &p.Event); err != nil {
+			return nil, fmt.Errorf("разбор события %s: %w", p.DeliveryID, err)
+		}
+		pending = append(pending, p)
+	}
+	return pending, rows.Err()
+}
+
+func (s *Storage) DeletePending(ctx context.Context, deliveryID uuid.UUID) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM webhook_pending WHERE delivery_id = $1`, deliveryID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось удалить отложенную доставку", err)
+		return fmt.Errorf("удаление отложенной доставки: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/worklog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// таймеры и записи времени; первичный ключ timers не даёт запустить
+// таймер пользователя на задаче дважды, в том числе с разных реплик
+
+func (s *Storage) StartTimer(ctx context.Context, t worklog.Timer) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO timers (task_id, user_id, started_at) VALUES ($1, $2, $3)`,
+		t.TaskID, t.User, t.StartedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return worklog.ErrTimerRunning
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось запустить таймер", err)
+		return fmt.Errorf("запуск таймера: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) StopTimer(ctx context.Context, taskID uuid.UUID, user string) (worklog.Timer, error) {
+	t := worklog.Timer{TaskID: taskID, User: user}
+	err := s.pool.QueryRow(ctx, `
+		DELETE FROM timers WHERE task_id = $1 AND user_id = $2
+		RETURNING started_at`, taskID, user).Scan(&t.StartedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return worklog.Timer{}, worklog.ErrNotFound
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось остановить таймер", err)
+		return worklog.Timer{}, fmt.Errorf("остановка таймера: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Storage) ListTimers(ctx context.Context, taskID uuid.UUID, user string) ([]worklog.Timer, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT task_id, user_id, started_at FROM timers
+		WHERE ($1::uuid IS NULL OR task_id = $1) AND ($2::text = '' OR user_id = $2)
+		ORDER BY started_at`, nullUUID(taskID), user)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить таймеры", err)
+		return nil, fmt.Errorf("получение таймеров: %w", err)
+	}
+	defer rows.Close()
+
+	timers := []worklog.Timer{}
+	for rows.Next() {
+		var t worklog.Timer
+		if err := rows.Scan(&t.TaskID, &t.User, &t.StartedAt); err != nil {
+			return nil, fmt.Errorf("сканирование таймера: %w", err)
+		}
+		timers = append(timers, t)
+	}
+	return timers, rows.Err()
+}
+
+func (s *Storage) AddWorklog(ctx context.Context, e worklog.Entry) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO worklogs (id, task_id, user_id, started_at, duration_seconds, note, source, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		e.ID, e.TaskID, e.User, e.StartedAt, int64(e.Duration/time.Second), e.Note, e.Source, e.CreatedAt)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось добавить запись времени", err)
+		return fmt.Errorf("добавление записи времени: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) ListWorklogs(ctx context.Context, f worklog.Filter) ([]worklog.Entry, error) {
+	start := time.Now()
+
+	var from, to *time.Time
+	if !f.From.IsZero() {
+		from = &f.From
+	}
+	if !f.To.IsZero() {
+		to = &f.To
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, task_id, user_id, started_at, duration_seconds, note, source, created_at
+		FROM worklogs
+		WHERE ($1::uuid IS NULL OR task_id = $1)
+		  AND ($2::text = '' OR user_id = $2)
+		  AND ($3::timestamptz IS NULL OR started_at >= $3)
+		  AND ($4::timestamptz IS NULL OR started_at < $4)
+		ORDER BY started_at`, nullUUID(f.TaskID), f.User, from, to)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить записи времени", err)
+		return nil, fmt.Errorf("получение записей времени: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []worklog.Entry{}
+	for rows.Next() {
+		var e worklog.Entry
+		var seconds int64
+		err := rows.Scan(&e.ID, &e.TaskID, &e.User, &e.StartedAt, &seconds, &e.Note, &e.Source, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование записи времени: %w", err)
+		}
+		e.Duration = time.Duration(seconds) * time.Second
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация по строкам: %w", err)
+	}
+
+	if time.Since(start) > time.Millisecond*100 {
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+	}
+	return entries, nil
+}
+
+func (s *Storage) WorklogTotals(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID]time.Duration, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT task_id, SUM(duration_seconds) FROM worklogs
+		WHERE task_id = ANY($1)
+		GROUP BY task_id`, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("суммирование времени: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[uuid.UUID]time.Duration)
+	for rows.Next() {
+		var id uuid.UUID
+		var seconds int64
+		if err := rows.Scan(&id, &seconds); err != nil {
+			return nil, fmt.Errorf("сканирование суммы времени: %w", err)
+		}
+		totals[id] = time.Duration(seconds) * time.Second
+	}
+	return totals, rows.Err()
+}
+
+func nullUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"taskTracker/internal/history"
+	"taskTracker/internal/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const historyColumns = `id, task_id, event_type, status, flag, original_estimate, remaining_estimate, occurred_at`
+
+func (s *Storage) AppendHistory(ctx context.Context, r history.Record) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO task_history (`+historyColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`,
+		r.EventID.String(), r.TaskID.String(), string(r.Event), string(r.Status), string(r.Flag),
+		r.OriginalEstimate, r.RemainingEstimate, formatTime(r.OccurredAt))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось записать историю задачи", err)
+		return fmt.Errorf("запись истории задачи: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) HistoryState(ctx context.Context, at time.Time) ([]history.Record, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+historyColumns+` FROM (
+			SELECT `+historyColumns+`,
+				ROW_NUMBER() OVER (PARTITION BY task_id ORDER BY occurred_at DESC) AS rn
+			FROM task_history
+			WHERE occurred_at < ?
+		)
+		WHERE rn = 1`, formatTime(at))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить состояние задач", err)
+		return nil, fmt.Errorf("получение состояния задач: %w", err)
+	}
+	return scanHistory(rows)
+}
+
+func (s *Storage) ListHistory(ctx context.Context, from, to time.Time) ([]history.Record, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+historyColumns+`
+		FROM task_history
+		WHERE occurred_at >= ? AND occurred_at < ?
+		ORDER BY occurred_at`, formatTime(from), formatTime(to))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить историю задач", err)
+		return nil, fmt.Errorf("получение истории задач: %w", err)
+	}
+	return scanHistory(rows)
+}
+
+func scanHistory(rows *sql.Rows) ([]history.Record, error) {
+	defer rows.Close()
+
+	records := []history.Record{}
+	for rows.Next() {
+		var (
+			r                      history.Record
+			id, taskID, occurredAt string
+		)
+		err := rows.Scan(&id, &taskID, &r.Event, &r.Status, &r.Flag,
+			&r.OriginalEstimate, &r.RemainingEstimate, &occurredAt)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование истории: %w", err)
+		}
+		if r.EventID, err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("разбор id: %w", err)
+		}
+		if r.TaskID, err = uuid.Parse(taskID); err != nil {
+			return nil, fmt.Errorf("разбор task_id: %w", err)
+		}
+		if r.OccurredAt, err = parseTime(occurredAt); err != nil {
+			return nil, fmt.Errorf("разбор occurred_at: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
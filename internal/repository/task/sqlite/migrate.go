@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"taskTracker/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+//go:embed migrations/*.up.sql
+var migrationsFS embed.FS
+
+// Migrate применяет ещё не применённые миграции из migrations/ по порядку номеров.
+// Каждая миграция выполняется в своей транзакции вместе с записью в schema_migrations
+func (s *Storage) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			applied_at TEXT NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("создание schema_migrations: %w", err)
+	}
+
+	files, err := fs.Glob(migrationsFS, "migrations/*.up.sql")
+	if err != nil {
+		return fmt.Errorf("чтение списка миграций: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		version, err := migrationVersion(file)
+		if err != nil {
+			return err
+		}
+
+		var applied int
+		err = s.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, version).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("проверка миграции %d: %w", version, err)
+		}
+		if applied > 0 {
+			continue
+		}
+
+		body, err := migrationsFS.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("чтение миграции %s: %w", file, err)
+		}
+
+		if err := s.applyMigration(ctx, version, string(body)); err != nil {
+			return fmt.Errorf("применение миграции %s: %w", file, err)
+		}
+		logger.Info("Repository: Применена миграция SQLite", zap.String("file", file))
+	}
+
+	return nil
+}
+
+func (s *Storage) applyMigration(ctx context.Context, version int, body string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+		version, formatTime(nowUTC()))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func migrationVersion(file string) (int, error) {
+	name := strings.TrimPrefix(file, "migrations/")
+	prefix, _, ok := strings.Cut(name, "_")
+	if !ok {
+		return 0, fmt.Errorf("неверное имя миграции: %s", file)
+	}
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("неверный номер миграции %s: %w", file, err)
+	}
+	return version, nil
+}
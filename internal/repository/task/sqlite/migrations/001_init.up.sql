@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tasks (
+    uuid        TEXT PRIMARY KEY,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status      TEXT NOT NULL,
+    due_time    TEXT NOT NULL,
+    created_at  TEXT NOT NULL,
+    updated_at  TEXT,
+    deleted_at  TEXT,
+    version     INTEGER NOT NULL DEFAULT 1,
+    flag        TEXT NOT NULL DEFAULT 'active'
+);
@@ -0,0 +1,13 @@
+CREATE INDEX IF NOT EXISTS idx_tasks_flag ON tasks(flag);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_active_created ON tasks(created_at DESC)
+WHERE flag = 'active';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_overdue ON tasks(due_time, status)
+WHERE flag = 'active' AND status IN ('new', 'in progress');
+
+CREATE INDEX IF NOT EXISTS idx_tasks_archived_created ON tasks(created_at DESC)
+WHERE flag = 'archived';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_created ON tasks(created_at DESC)
+WHERE flag = 'deleted';
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN rrule TEXT NOT NULL DEFAULT '';
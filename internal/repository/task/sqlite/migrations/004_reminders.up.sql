@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN reminders TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,4 @@
+-- позиция задачи на доске: колонка доски выбирается по статусу и сортируется по rank
+ALTER TABLE tasks ADD COLUMN rank TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_tasks_active_status_rank ON tasks(status, rank)
+WHERE flag = 'active';
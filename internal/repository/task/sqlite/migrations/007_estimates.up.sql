@@ -0,0 +1,3 @@
+-- оценки задачи в единицах трекера (ESTIMATE_UNIT): исходная и оставшаяся
+ALTER TABLE tasks ADD COLUMN original_estimate REAL;
+ALTER TABLE tasks ADD COLUMN remaining_estimate REAL;
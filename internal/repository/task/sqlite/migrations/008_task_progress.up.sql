@@ -0,0 +1,5 @@
+-- начало работы и выполнение задачи для lead time и cycle time
+ALTER TABLE tasks ADD COLUMN started_at TEXT;
+ALTER TABLE tasks ADD COLUMN completed_at TEXT;
+CREATE INDEX idx_tasks_completed ON tasks(completed_at)
+WHERE completed_at IS NOT NULL;
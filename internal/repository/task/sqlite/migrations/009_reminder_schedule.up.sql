@@ -0,0 +1,9 @@
+-- ближайший момент отправки напоминания, см. task.Reminders.NextAt
+ALTER TABLE tasks ADD COLUMN next_reminder_at TEXT;
+CREATE INDEX idx_tasks_next_reminder ON tasks(next_reminder_at)
+WHERE next_reminder_at IS NOT NULL;
+-- интервалы хранятся строками Go, поэтому существующим задачам ставится
+-- самая ранняя граница окна: планировщик уточнит момент, когда впервые выберет задачу
+UPDATE tasks SET next_reminder_at = strftime('%Y-%m-%dT%H:%M:%S', due_time, '-30 days') || '.000000000Z'
+WHERE next_reminder_at IS NULL
+AND EXISTS (SELECT 1 FROM json_each(tasks.reminders) WHERE json_extract(value, '$.sent_at') IS NULL);
@@ -0,0 +1,50 @@
+-- подписки на вебхуки и их доставки; попытки и отложенные доставки
+-- удаляются вместе с подпиской, dead-letter остаётся для разбора
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         TEXT PRIMARY KEY,
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    events     TEXT NOT NULL DEFAULT '[]',
+    active     INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL,
+    updated_at TEXT
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    seq             INTEGER PRIMARY KEY AUTOINCREMENT,
+    id              TEXT NOT NULL,
+    delivery_id     TEXT NOT NULL,
+    subscription_id TEXT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    attempt         INTEGER NOT NULL,
+    status_code     INTEGER NOT NULL DEFAULT 0,
+    error           TEXT NOT NULL DEFAULT '',
+    success         INTEGER NOT NULL,
+    duration_ns     INTEGER NOT NULL,
+    attempted_at    TEXT NOT NULL,
+    next_retry_at   TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, seq);
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    seq             INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id     TEXT NOT NULL,
+    subscription_id TEXT NOT NULL,
+    event           TEXT NOT NULL,
+    attempts        INTEGER NOT NULL,
+    last_error      TEXT NOT NULL,
+    failed_at       TEXT NOT NULL
+);
+
+-- доставки, ещё не закончившиеся успехом или dead-letter
+CREATE TABLE IF NOT EXISTS webhook_pending (
+    delivery_id     TEXT PRIMARY KEY,
+    subscription_id TEXT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event           TEXT NOT NULL,
+    attempt         INTEGER NOT NULL,
+    next_attempt_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_pending_next ON webhook_pending(next_attempt_at);
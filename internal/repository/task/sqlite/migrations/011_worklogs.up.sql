@@ -0,0 +1,21 @@
+-- учёт времени: записи о потраченном времени и запущенные таймеры
+CREATE TABLE IF NOT EXISTS worklogs (
+    id               TEXT PRIMARY KEY,
+    task_id          TEXT NOT NULL,
+    user_id          TEXT NOT NULL,
+    started_at       TEXT NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    note             TEXT NOT NULL DEFAULT '',
+    source           TEXT NOT NULL,
+    created_at       TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_worklogs_task ON worklogs(task_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_worklogs_started ON worklogs(started_at);
+
+CREATE TABLE IF NOT EXISTS timers (
+    task_id    TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    PRIMARY KEY (task_id, user_id)
+);
@@ -0,0 +1,26 @@
+-- история задачи: состояние после каждого события, id - id события,
+-- поэтому повторная доставка из outbox не дублирует запись
+CREATE TABLE IF NOT EXISTS task_history (
+    id                 TEXT PRIMARY KEY,
+    task_id            TEXT NOT NULL,
+    event_type         TEXT NOT NULL,
+    status             TEXT NOT NULL,
+    flag               TEXT NOT NULL,
+    original_estimate  REAL,
+    remaining_estimate REAL,
+    occurred_at        TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_history_occurred ON task_history(occurred_at);
+CREATE INDEX IF NOT EXISTS idx_task_history_task ON task_history(task_id, occurred_at);
+
+-- задачи, созданные до журнала, попадают в него с состоянием на момент миграции.
+-- В SQLite нет gen_random_uuid, поэтому UUID v4 собирается из randomblob
+INSERT INTO task_history (id, task_id, event_type, status, flag, original_estimate, remaining_estimate, occurred_at)
+SELECT
+    lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+          substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+    uuid, 'task.created', status, flag, original_estimate, remaining_estimate,
+    strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000000Z'
+FROM tasks
+WHERE NOT EXISTS (SELECT 1 FROM task_history h WHERE h.task_id = tasks.uuid);
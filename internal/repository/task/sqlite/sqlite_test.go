@@ -0,0 +1,614 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"taskTracker/internal/events"
+	"taskTracker/internal/history"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository"
+	"taskTracker/internal/repository/task/sqlite"
+	"taskTracker/internal/stats"
+	"taskTracker/internal/webhook"
+	"taskTracker/internal/worklog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func newStorage(t *testing.T) (*sqlite.Storage, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	storage, err := sqlite.New(context.Background(), path)
+	require.NoError(t, err)
+	t.Cleanup(storage.Close)
+	return storage, path
+}
+
+func newTask(title string, due time.Time) *task.Task {
+	return &task.Task{
+		UUID:    uuid.New(),
+		Title:   title,
+		Status:  task.StatusNew,
+		DueTime: due,
+	}
+}
+
+// TestStorage_CreateAndGet тестирует создание и получение задачи
+func TestStorage_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	due := time.Now().Add(48 * time.Hour).Truncate(time.Microsecond)
+	created := newTask("Test Task", due)
+	created.Description = "Test Description"
+	created.Reminders = task.Reminders{{Before: time.Hour}}
+	task.WithOriginalEstimate(5)(created)
+	require.NoError(t, storage.Create(ctx, created))
+
+	assert.Equal(t, 1, created.Version)
+	assert.Equal(t, task.FlagActive, created.Flag)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	got, err := storage.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Task", got.Title)
+	assert.Equal(t, "Test Description", got.Description)
+	assert.True(t, due.Equal(got.DueTime))
+	assert.Nil(t, got.UpdatedAt)
+	assert.Equal(t, created.Reminders, got.Reminders)
+	require.NotNil(t, got.OriginalEstimate)
+	assert.Equal(t, 5.0, *got.OriginalEstimate)
+	assert.Nil(t, got.RemainingEstimate)
+
+	_, err = storage.GetByID(ctx, uuid.New())
+	assert.Equal(t, repository.ErrNotFound, err)
+}
+
+// TestStorage_UpdateVersionConflict тестирует оптимистичную блокировку
+func TestStorage_UpdateVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	created := newTask("v1", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, created))
+
+	first, err := storage.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	second, err := storage.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+
+	first.Title = "v2"
+	require.NoError(t, storage.Update(ctx, first))
+	assert.Equal(t, 2, first.Version)
+	assert.NotNil(t, first.UpdatedAt)
+
+	second.Title = "stale"
+	err = storage.Update(ctx, second)
+	assert.Equal(t, repository.ErrVersionConflict, err)
+
+	got, err := storage.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got.Title)
+}
+
+// TestStorage_DeleteSoftAndRestore тестирует мягкое удаление и восстановление
+func TestStorage_DeleteSoftAndRestore(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	created := newTask("to delete", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, created))
+
+	require.NoError(t, storage.DeleteSoft(ctx, created))
+	assert.Equal(t, 2, created.Version)
+	require.NotNil(t, created.DeletedAt)
+
+	got, err := storage.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, task.FlagDeleted, got.Flag)
+
+	deleted, err := storage.GetFlaggedWithLimit(ctx, 1, 10, task.FlagDeleted)
+	require.NoError(t, err)
+	assert.Len(t, deleted, 1)
+
+	// устаревшая версия при удалении - конфликт
+	stale := *got
+	stale.Version = 1
+	assert.Equal(t, repository.ErrVersionConflict, storage.DeleteSoft(ctx, &stale))
+
+	got.Flag = task.FlagActive
+	got.DeletedAt = nil
+	require.NoError(t, storage.Update(ctx, got))
+
+	restored, err := storage.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, task.FlagActive, restored.Flag)
+	assert.Nil(t, restored.DeletedAt)
+}
+
+// TestStorage_DeleteFull тестирует полное удаление
+func TestStorage_DeleteFull(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	created := newTask("to purge", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, created))
+	require.NoError(t, storage.DeleteFull(ctx, created.UUID))
+
+	_, err := storage.GetByID(ctx, created.UUID)
+	assert.Equal(t, repository.ErrNotFound, err)
+}
+
+// TestStorage_Lists тестирует выборки с пагинацией и фильтрами
+func TestStorage_Lists(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, storage.Create(ctx, newTask("active", time.Now().Add(time.Hour))))
+	}
+
+	archived := newTask("archived", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, archived))
+	archived.Flag = task.FlagArchived
+	archived.Status = task.StatusDone
+	require.NoError(t, storage.Update(ctx, archived))
+
+	deleted := newTask("deleted", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, deleted))
+	require.NoError(t, storage.DeleteSoft(ctx, deleted))
+
+	page1, err := storage.GetAllWithLimit(ctx, 1, 4)
+	require.NoError(t, err)
+	assert.Len(t, page1, 4)
+	page2, err := storage.GetAllWithLimit(ctx, 2, 4)
+	require.NoError(t, err)
+	assert.Len(t, page2, 2)
+
+	active, err := storage.GetFlaggedWithLimit(ctx, 1, 10, task.FlagActive)
+	require.NoError(t, err)
+	assert.Len(t, active, 5)
+
+	done, err := storage.GetStatusedWithLimit(ctx, 1, 10, task.StatusDone)
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, archived.UUID, done[0].UUID)
+}
+
+// TestStorage_GetTasksDueBefore тестирует выборку задач с истекающим сроком
+func TestStorage_GetTasksDueBefore(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	soon := newTask("soon", time.Now().Add(-time.Minute))
+	require.NoError(t, storage.Create(ctx, soon))
+
+	later := newTask("later", time.Now().Add(72*time.Hour))
+	require.NoError(t, storage.Create(ctx, later))
+
+	doneTask := newTask("done", time.Now().Add(-time.Hour))
+	doneTask.Status = task.StatusDone
+	require.NoError(t, storage.Create(ctx, doneTask))
+
+	tasks, err := storage.GetTasksDueBefore(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, soon.UUID, tasks[0].UUID)
+}
+
+// TestStorage_GetTasksWithDueReminders тестирует выборку задач с наступившими
+// напоминаниями по порядку срабатывания и пересчёт момента при обновлении
+func TestStorage_GetTasksWithDueReminders(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	later := newTask("later", time.Now().Add(2*time.Hour))
+	later.Reminders = task.Reminders{{Before: 3 * time.Hour}}
+	require.NoError(t, storage.Create(ctx, later))
+
+	earlier := newTask("earlier", time.Now().Add(time.Hour))
+	earlier.Reminders = task.Reminders{{Before: 3 * time.Hour}}
+	require.NoError(t, storage.Create(ctx, earlier))
+
+	notYet := newTask("not yet", time.Now().Add(20*24*time.Hour))
+	notYet.Reminders = task.Reminders{{Before: time.Hour}}
+	require.NoError(t, storage.Create(ctx, notYet))
+
+	require.NoError(t, storage.Create(ctx, newTask("no reminders", time.Now().Add(time.Hour))))
+
+	tasks, err := storage.GetTasksWithDueReminders(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, earlier.UUID, tasks[0].UUID)
+	assert.Equal(t, later.UUID, tasks[1].UUID)
+
+	sentAt := time.Now()
+	earlier.Reminders[0].SentAt = &sentAt
+	require.NoError(t, storage.Update(ctx, earlier))
+
+	tasks, err = storage.GetTasksWithDueReminders(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, later.UUID, tasks[0].UUID)
+}
+
+// TestStorage_GetActiveByStatuses тестирует выборку колонок доски без ограничения страницей
+func TestStorage_GetActiveByStatuses(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	for range 3 {
+		require.NoError(t, storage.Create(ctx, newTask("new", time.Now().Add(time.Hour))))
+	}
+	doing := newTask("doing", time.Now().Add(time.Hour))
+	doing.Status = task.StatusInProgress
+	require.NoError(t, storage.Create(ctx, doing))
+
+	done := newTask("done", time.Now().Add(time.Hour))
+	done.Status = task.StatusDone
+	require.NoError(t, storage.Create(ctx, done))
+	require.NoError(t, storage.DeleteSoft(ctx, done))
+
+	tasks, err := storage.GetActiveByStatuses(ctx, []task.Status{task.StatusNew, task.StatusInProgress, task.StatusDone})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 4)
+
+	tasks, err = storage.GetActiveByStatuses(ctx, []task.Status{task.StatusInProgress})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, doing.UUID, tasks[0].UUID)
+}
+
+// TestStorage_GetByIDs тестирует получение задач списком ID
+func TestStorage_GetByIDs(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	first := newTask("first", time.Now().Add(time.Hour))
+	second := newTask("second", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, first))
+	require.NoError(t, storage.Create(ctx, second))
+	require.NoError(t, storage.Create(ctx, newTask("other", time.Now().Add(time.Hour))))
+
+	tasks, err := storage.GetByIDs(ctx, []uuid.UUID{second.UUID, uuid.New(), first.UUID})
+	require.NoError(t, err)
+	titles := []string{}
+	for _, got := range tasks {
+		titles = append(titles, got.Title)
+	}
+	assert.ElementsMatch(t, []string{"first", "second"}, titles)
+
+	tasks, err = storage.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+// TestStorage_Webhooks тестирует подписки, журнал доставок и отложенные доставки
+func TestStorage_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	storage, path := newStorage(t)
+	var store webhook.Store = storage
+
+	sub := &webhook.Subscription{
+		ID:        uuid.New(),
+		URL:       "https://example.com/hook",
+		Secret:    "s3cret",
+		Events:    []events.Type{events.TaskCreated},
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, store.CreateSubscription(ctx, sub))
+
+	now := time.Now()
+	sub.Active = false
+	sub.UpdatedAt = &now
+	require.NoError(t, store.UpdateSubscription(ctx, sub))
+
+	got, err := store.GetSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sub.Events, got.Events)
+	assert.False(t, got.Active)
+	require.NotNil(t, got.UpdatedAt)
+
+	event := events.New(events.TaskCreated, newTask("hooked", now))
+	for attempt := 1; attempt <= webhook.MaxDeliveriesPerSubscription+2; attempt++ {
+		require.NoError(t, store.AddDelivery(ctx, webhook.Delivery{
+			ID:             uuid.New(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Attempt:        attempt,
+			Duration:       time.Millisecond,
+			AttemptedAt:    now,
+		}))
+	}
+	deliveries, err := store.ListDeliveries(ctx, sub.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, webhook.MaxDeliveriesPerSubscription)
+	assert.Equal(t, webhook.MaxDeliveriesPerSubscription+2, deliveries[0].Attempt)
+	assert.Equal(t, time.Millisecond, deliveries[0].Duration)
+
+	due := webhook.Pending{DeliveryID: uuid.New(), SubscriptionID: sub.ID, Event: event, Attempt: 2, NextAttemptAt: now}
+	later := webhook.Pending{DeliveryID: uuid.New(), SubscriptionID: sub.ID, Event: event, Attempt: 1, NextAttemptAt: now.Add(time.Hour)}
+	require.NoError(t, store.SavePending(ctx, due))
+	require.NoError(t, store.SavePending(ctx, later))
+	assert.ErrorIs(t, store.SavePending(ctx, webhook.Pending{DeliveryID: uuid.New(), SubscriptionID: uuid.New(), NextAttemptAt: now}), webhook.ErrNotFound)
+
+	// отложенные доставки переживают повторное открытие базы
+	storage.Close()
+	reopened, err := sqlite.New(ctx, path)
+	require.NoError(t, err)
+	defer reopened.Close()
+	store = reopened
+
+	claimed, err := store.ClaimPending(ctx, now.Add(time.Second), now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, due.DeliveryID, claimed[0].DeliveryID)
+	assert.Equal(t, event.ID, claimed[0].Event.ID)
+	assert.Equal(t, 2, claimed[0].Attempt)
+
+	// взятая доставка не выдаётся повторно до конца аренды
+	claimed, err = store.ClaimPending(ctx, now.Add(time.Second), now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	require.NoError(t, store.AddDeadLetter(ctx, webhook.DeadLetter{
+		DeliveryID: due.DeliveryID, SubscriptionID: sub.ID, Event: event, Attempts: 5, LastError: "502", FailedAt: now,
+	}))
+	require.NoError(t, store.DeletePending(ctx, due.DeliveryID))
+
+	// вместе с подпиской удаляются попытки и отложенные доставки, dead-letter остаётся
+	require.NoError(t, store.DeleteSubscription(ctx, sub.ID))
+	assert.ErrorIs(t, store.DeleteSubscription(ctx, sub.ID), webhook.ErrNotFound)
+	_, err = store.ListDeliveries(ctx, sub.ID, 10)
+	assert.ErrorIs(t, err, webhook.ErrNotFound)
+
+	claimed, err = store.ClaimPending(ctx, now.Add(2*time.Hour), now.Add(3*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	deadLetters, err := store.ListDeadLetters(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, event.ID, deadLetters[0].Event.ID)
+}
+
+// TestStorage_CountTasks тестирует подсчёт задач для метрик
+func TestStorage_CountTasks(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	require.NoError(t, storage.Create(ctx, newTask("later", time.Now().Add(time.Hour))))
+	require.NoError(t, storage.Create(ctx, newTask("late", time.Now().Add(-time.Hour))))
+
+	doneTask := newTask("done", time.Now().Add(-time.Hour))
+	doneTask.Status = task.StatusDone
+	require.NoError(t, storage.Create(ctx, doneTask))
+
+	archived := newTask("archived", time.Now().Add(-time.Hour))
+	require.NoError(t, storage.Create(ctx, archived))
+	archived.Flag = task.FlagArchived
+	require.NoError(t, storage.Update(ctx, archived))
+
+	counts, err := storage.CountTasks(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, counts.ByFlagStatus[task.FlagActive][task.StatusNew])
+	assert.Equal(t, 1, counts.ByFlagStatus[task.FlagActive][task.StatusDone])
+	assert.Equal(t, 1, counts.ByFlagStatus[task.FlagArchived][task.StatusNew])
+	assert.Equal(t, 1, counts.Overdue)
+}
+
+// TestStorage_TaskStats тестирует ряды по дням, lead time и cycle time в SQL
+func TestStorage_TaskStats(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+
+	finished := newTask("finished", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, finished))
+	started, completed := finished.CreatedAt.Add(time.Hour), finished.CreatedAt.Add(3*time.Hour)
+	finished.Status, finished.StartedAt, finished.CompletedAt = task.StatusDone, &started, &completed
+	require.NoError(t, storage.Update(ctx, finished))
+
+	late := newTask("late", time.Now().Add(-2*time.Hour))
+	require.NoError(t, storage.Create(ctx, late))
+	later := newTask("later", time.Now().Add(-time.Hour))
+	require.NoError(t, storage.Create(ctx, later))
+
+	deleted := newTask("deleted", time.Now().Add(-3*time.Hour))
+	require.NoError(t, storage.Create(ctx, deleted))
+	require.NoError(t, storage.DeleteSoft(ctx, deleted))
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	q := stats.Query{From: day.Add(-24 * time.Hour), To: day.Add(48 * time.Hour), Top: 1}
+	agg, err := storage.TaskStats(ctx, q, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, agg.Counts.Overdue)
+	assert.Equal(t, 3, agg.Created[finished.CreatedAt.UTC().Format(stats.DayLayout)])
+	assert.Equal(t, 1, agg.Completed[completed.UTC().Format(stats.DayLayout)])
+	assert.Equal(t, 1, agg.LeadTime.Tasks)
+	assert.InDelta(t, 3*time.Hour, agg.LeadTime.Average(), float64(time.Second))
+	assert.Equal(t, 1, agg.CycleTime.Tasks)
+	assert.InDelta(t, 2*time.Hour, agg.CycleTime.Average(), float64(time.Second))
+	require.Len(t, agg.TopOverdue, 1)
+	assert.Equal(t, late.UUID, agg.TopOverdue[0].UUID)
+
+	got, err := storage.GetByID(ctx, finished.UUID)
+	require.NoError(t, err)
+	require.NotNil(t, got.CompletedAt)
+	assert.True(t, completed.Equal(*got.CompletedAt))
+}
+
+// TestStorage_Worklogs тестирует таймеры и записи времени и их сохранение после переоткрытия базы
+func TestStorage_Worklogs(t *testing.T) {
+	ctx := context.Background()
+	storage, path := newStorage(t)
+	var store worklog.Store = storage
+
+	taskID := uuid.New()
+	started := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.StartTimer(ctx, worklog.Timer{TaskID: taskID, User: "alice", StartedAt: started}))
+	assert.ErrorIs(t, store.StartTimer(ctx, worklog.Timer{TaskID: taskID, User: "alice", StartedAt: started}), worklog.ErrTimerRunning)
+	require.NoError(t, store.StartTimer(ctx, worklog.Timer{TaskID: uuid.New(), User: "bob", StartedAt: started}))
+
+	timers, err := store.ListTimers(ctx, uuid.Nil, "alice")
+	require.NoError(t, err)
+	require.Len(t, timers, 1)
+	assert.Equal(t, taskID, timers[0].TaskID)
+
+	stopped, err := store.StopTimer(ctx, taskID, "alice")
+	require.NoError(t, err)
+	assert.True(t, started.Equal(stopped.StartedAt))
+	_, err = store.StopTimer(ctx, taskID, "alice")
+	assert.ErrorIs(t, err, worklog.ErrNotFound)
+
+	for i, d := range []time.Duration{time.Hour, 30 * time.Minute} {
+		require.NoError(t, store.AddWorklog(ctx, worklog.Entry{
+			ID:        uuid.New(),
+			TaskID:    taskID,
+			User:      "alice",
+			StartedAt: started.AddDate(0, 0, i),
+			Duration:  d,
+			Note:      "work",
+			Source:    worklog.SourceManual,
+			CreatedAt: started,
+		}))
+	}
+
+	entries, err := store.ListWorklogs(ctx, worklog.Filter{User: "alice", From: started.AddDate(0, 0, 1)})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 30*time.Minute, entries[0].Duration)
+	assert.Equal(t, worklog.SourceManual, entries[0].Source)
+
+	storage.Close()
+	reopened, err := sqlite.New(ctx, path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	totals, err := reopened.WorklogTotals(ctx, []uuid.UUID{taskID, uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]time.Duration{taskID: 90 * time.Minute}, totals)
+	timers, err = reopened.ListTimers(ctx, uuid.Nil, "")
+	require.NoError(t, err)
+	require.Len(t, timers, 1)
+	assert.Equal(t, "bob", timers[0].User)
+}
+
+// TestStorage_History тестирует журнал истории: повтор события не дублирует запись,
+// состояние берётся из последней записи задачи до момента
+func TestStorage_History(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newStorage(t)
+	var store history.Store = storage
+
+	taskID := uuid.New()
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	estimate := 5.0
+	created := history.Record{
+		EventID:          uuid.New(),
+		TaskID:           taskID,
+		Event:            events.TaskCreated,
+		Status:           task.StatusNew,
+		Flag:             task.FlagActive,
+		OriginalEstimate: &estimate,
+		OccurredAt:       day.Add(9 * time.Hour),
+	}
+	done := history.Record{
+		EventID:    uuid.New(),
+		TaskID:     taskID,
+		Event:      events.TaskUpdated,
+		Status:     task.StatusDone,
+		Flag:       task.FlagActive,
+		OccurredAt: day.Add(33 * time.Hour),
+	}
+	for _, r := range []history.Record{done, created, created} {
+		require.NoError(t, store.AppendHistory(ctx, r))
+	}
+
+	records, err := store.ListHistory(ctx, day, day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, created.EventID, records[0].EventID)
+	require.NotNil(t, records[0].OriginalEstimate)
+	assert.Equal(t, 5.0, *records[0].OriginalEstimate)
+	assert.Nil(t, records[0].RemainingEstimate)
+	assert.True(t, created.OccurredAt.Equal(records[0].OccurredAt))
+
+	state, err := store.HistoryState(ctx, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+	assert.Equal(t, task.StatusNew, state[0].Status)
+
+	state, err = store.HistoryState(ctx, day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+	assert.Equal(t, task.StatusDone, state[0].Status)
+}
+
+// TestStorage_HistoryBackfill тестирует, что миграция заносит в историю задачи без неё
+func TestStorage_HistoryBackfill(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	storage, err := sqlite.New(ctx, path)
+	require.NoError(t, err)
+	existing := newTask("existing", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, existing))
+	storage.Close()
+
+	// откатываем базу к состоянию до миграции истории
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DROP TABLE task_history; DELETE FROM schema_migrations WHERE version = 12`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	before := time.Now().Add(-time.Second)
+	reopened, err := sqlite.New(ctx, path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	records, err := reopened.ListHistory(ctx, before, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, existing.UUID, records[0].TaskID)
+	assert.Equal(t, events.TaskCreated, records[0].Event)
+	assert.Equal(t, task.StatusNew, records[0].Status)
+	assert.NotEqual(t, uuid.Nil, records[0].EventID)
+}
+
+// TestStorage_ReopenKeepsData тестирует повторное открытие файла и идемпотентность миграций
+func TestStorage_ReopenKeepsData(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	storage, err := sqlite.New(ctx, path)
+	require.NoError(t, err)
+	created := newTask("persistent", time.Now().Add(time.Hour))
+	require.NoError(t, storage.Create(ctx, created))
+	storage.Close()
+
+	reopened, err := sqlite.New(ctx, path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	got, err := reopened.GetByID(ctx, created.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "persistent", got.Title)
+	assert.NoError(t, reopened.HealthCheck(ctx))
+}
@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/stats"
+	"time"
+)
+
+// время хранится строками timeLayout: день - первые десять символов,
+// длительности - разница julianday в сутках
+
+func (s *Storage) TaskStats(ctx context.Context, q stats.Query, now time.Time) (*stats.Aggregates, error) {
+	start := time.Now()
+
+	counts, err := s.CountTasks(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	agg := &stats.Aggregates{Counts: counts}
+
+	if agg.Created, err = s.createdByDay(ctx, q); err != nil {
+		return nil, err
+	}
+	if err := s.completedByDay(ctx, q, agg); err != nil {
+		return nil, err
+	}
+
+	agg.TopOverdue = []*task.Task{}
+	if q.Top > 0 {
+		query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE flag = 'active'
+				AND status NOT IN ('done', 'cancelled')
+				AND due_time < ?
+				ORDER BY due_time
+				LIMIT ?`
+		if agg.TopOverdue, err = s.queryTasks(ctx, q.Top, query, formatTime(now), q.Top); err != nil {
+			return nil, err
+		}
+	}
+
+	logSlow(ctx, start, 200*time.Millisecond)
+	return agg, nil
+}
+
+func (s *Storage) createdByDay(ctx context.Context, q stats.Query) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT substr(created_at, 1, 10), COUNT(*)
+		FROM tasks
+		WHERE flag <> 'deleted' AND created_at >= ? AND created_at < ?
+		GROUP BY 1`, formatTime(q.From), formatTime(q.To))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось посчитать созданные задачи", err)
+		return nil, fmt.Errorf("подсчёт созданных задач: %w", err)
+	}
+	defer rows.Close()
+
+	created := make(map[string]int)
+	for rows.Next() {
+		var (
+			day string
+			n   int
+		)
+		if err := rows.Scan(&day, &n); err != nil {
+			return nil, fmt.Errorf("сканирование созданных задач: %w", err)
+		}
+		created[day] = n
+	}
+	return created, rows.Err()
+}
+
+// completedByDay считает выполненные задачи по дням и заодно суммы lead
+// time и cycle time, чтобы не читать те же строки второй раз
+func (s *Storage) completedByDay(ctx context.Context, q stats.Query, agg *stats.Aggregates) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT substr(completed_at, 1, 10),
+			COUNT(*),
+			COALESCE(SUM(julianday(completed_at) - julianday(created_at)), 0),
+			COUNT(started_at),
+			COALESCE(SUM(julianday(completed_at) - julianday(started_at)), 0)
+		FROM tasks
+		WHERE flag <> 'deleted' AND completed_at >= ? AND completed_at < ?
+		GROUP BY 1`, formatTime(q.From), formatTime(q.To))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось посчитать выполненные задачи", err)
+		return fmt.Errorf("подсчёт выполненных задач: %w", err)
+	}
+	defer rows.Close()
+
+	agg.Completed = make(map[string]int)
+	for rows.Next() {
+		var (
+			day               string
+			completed, cycled int
+			lead, cycle       float64
+		)
+		if err := rows.Scan(&day, &completed, &lead, &cycled, &cycle); err != nil {
+			return fmt.Errorf("сканирование выполненных задач: %w", err)
+		}
+		agg.Completed[day] = completed
+		agg.LeadTime = agg.LeadTime.Add(stats.Durations{Sum: days(lead), Tasks: completed})
+		agg.CycleTime = agg.CycleTime.Add(stats.Durations{Sum: days(cycle), Tasks: cycled})
+	}
+	return rows.Err()
+}
+
+// days переводит дробные сутки julianday в длительность с точностью до миллисекунды
+func days(d float64) time.Duration {
+	return time.Duration(d * float64(24*time.Hour)).Round(time.Millisecond)
+}
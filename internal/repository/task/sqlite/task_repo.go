@@ -0,0 +1,521 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	repo "taskTracker/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	_ "modernc.org/sqlite"
+)
+
+// фиксированная ширина, чтобы строки времени сравнивались в SQL лексикографически
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+const taskColumns = `uuid,
+				title,
+				description,
+				status,
+				due_time,
+				created_at,
+				updated_at,
+				deleted_at,
+				version,
+				flag,
+				rrule,
+				reminders,
+				rank,
+				original_estimate,
+				remaining_estimate,
+				started_at,
+				completed_at`
+
+type Storage struct {
+	db *sql.DB
+}
+
+// New открывает файл базы (создаёт при отсутствии), включает WAL и применяет миграции
+func New(ctx context.Context, path string) (*Storage, error) {
+	dsn := "file:" + path + "?" + url.Values{
+		"_pragma": []string{
+			"journal_mode(WAL)",
+			"busy_timeout(5000)",
+			"synchronous(NORMAL)",
+			"foreign_keys(ON)",
+		},
+		"_txlock": []string{"immediate"},
+	}.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Ошибка открытия SQLite", err)
+		return nil, fmt.Errorf("открытие базы: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		logger.ErrorCtx(ctx, "Repository: Неудачная проверка ping", err)
+		return nil, fmt.Errorf("проверка соединения ping: %w", err)
+	}
+
+	s := &Storage{db: db}
+	if err := s.Migrate(ctx); err != nil {
+		db.Close()
+		logger.ErrorCtx(ctx, "Repository: Ошибка миграций SQLite", err)
+		return nil, fmt.Errorf("миграции: %w", err)
+	}
+
+	logger.InfoCtx(ctx, "Repository: Успешное открытие базы SQLite", zap.String("path", path))
+	return s, nil
+}
+
+func (s *Storage) Close() {
+	s.db.Close()
+	logger.Info("Repository: Закрытие базы SQLite")
+}
+
+func (s *Storage) HealthCheck(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		logger.ErrorCtx(ctx, "Repository: Неудачная проверка ping", err)
+		return fmt.Errorf("проверка соединения ping: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) Create(ctx context.Context, taskToCreate *task.Task) error {
+	start := time.Now()
+
+	createdAt := nowUTC()
+	query := `INSERT INTO tasks
+				(uuid, title, description, status, due_time, created_at, flag, version, rrule, reminders, rank,
+				original_estimate, remaining_estimate, started_at, completed_at, next_reminder_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		taskToCreate.UUID.String(),
+		taskToCreate.Title,
+		taskToCreate.Description,
+		taskToCreate.Status,
+		formatTime(taskToCreate.DueTime),
+		formatTime(createdAt),
+		task.FlagActive,
+		taskToCreate.RRule,
+		taskToCreate.Reminders,
+		taskToCreate.Rank,
+		taskToCreate.OriginalEstimate,
+		taskToCreate.RemainingEstimate,
+		formatNullTime(taskToCreate.StartedAt),
+		formatNullTime(taskToCreate.CompletedAt),
+		formatNullTime(taskToCreate.Reminders.NextAt(taskToCreate.DueTime)),
+	)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось добавить задачу", err, zap.Duration("ms", time.Since(start)))
+		return fmt.Errorf("добавление задачи: %w", err)
+	}
+
+	taskToCreate.CreatedAt = createdAt
+	taskToCreate.Flag = task.FlagActive
+	taskToCreate.Version = 1
+
+	logSlow(ctx, start, 50*time.Millisecond)
+	return nil
+}
+
+func (s *Storage) Update(ctx context.Context, taskToUpdate *task.Task) error {
+	start := time.Now()
+
+	query := `UPDATE tasks
+			SET title = ?,
+				description = ?,
+				status = ?,
+				due_time = ?,
+				version = version + 1,
+				updated_at = ?,
+				flag = ?,
+				deleted_at = ?,
+				rrule = ?,
+				reminders = ?,
+				rank = ?,
+				original_estimate = ?,
+				remaining_estimate = ?,
+				started_at = ?,
+				completed_at = ?,
+				next_reminder_at = ?
+			WHERE uuid = ? AND version = ?
+			RETURNING updated_at, version`
+
+	var updatedAt string
+	var version int
+	err := s.db.QueryRowContext(ctx, query,
+		taskToUpdate.Title,
+		taskToUpdate.Description,
+		taskToUpdate.Status,
+		formatTime(taskToUpdate.DueTime),
+		formatTime(nowUTC()),
+		taskToUpdate.Flag,
+		formatNullTime(taskToUpdate.DeletedAt),
+		taskToUpdate.RRule,
+		taskToUpdate.Reminders,
+		taskToUpdate.Rank,
+		taskToUpdate.OriginalEstimate,
+		taskToUpdate.RemainingEstimate,
+		formatNullTime(taskToUpdate.StartedAt),
+		formatNullTime(taskToUpdate.CompletedAt),
+		formatNullTime(taskToUpdate.Reminders.NextAt(taskToUpdate.DueTime)),
+		taskToUpdate.UUID.String(),
+		taskToUpdate.Version,
+	).Scan(&updatedAt, &version)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.WarnCtx(ctx, "Конфликт версий при обновлении задачи",
+				zap.String("task_id", taskToUpdate.UUID.String()),
+				zap.Int("expected_version", taskToUpdate.Version))
+			return repo.ErrVersionConflict
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось обновить задачу", err)
+		return fmt.Errorf("обновление задачи: %w", err)
+	}
+
+	t, err := parseTime(updatedAt)
+	if err != nil {
+		return fmt.Errorf("разбор updated_at: %w", err)
+	}
+	taskToUpdate.UpdatedAt = &t
+	taskToUpdate.Version = version
+
+	logSlow(ctx, start, 100*time.Millisecond)
+	return nil
+}
+
+// мягкое удаление задачи
+func (s *Storage) DeleteSoft(ctx context.Context, taskToDelete *task.Task) error {
+	start := time.Now()
+
+	now := formatTime(nowUTC())
+	query := `UPDATE tasks
+				SET deleted_at = ?,
+				updated_at = ?,
+				flag = ?,
+				version = version + 1
+			WHERE uuid = ? AND version = ?
+			RETURNING deleted_at, version`
+
+	var deletedAt string
+	var version int
+	err := s.db.QueryRowContext(ctx, query,
+		now, now, task.FlagDeleted, taskToDelete.UUID.String(), taskToDelete.Version,
+	).Scan(&deletedAt, &version)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.WarnCtx(ctx, "Конфликт версий при мягком удалении",
+				zap.String("task_id", taskToDelete.UUID.String()),
+				zap.Int("expected_version", taskToDelete.Version))
+			return repo.ErrVersionConflict
+		}
+
+		logger.ErrorCtx(ctx, "Repository: Мягкое удаление задачи", err, zap.Duration("ms", time.Since(start)))
+		return fmt.Errorf("мягкое удаление: %w", err)
+	}
+
+	t, err := parseTime(deletedAt)
+	if err != nil {
+		return fmt.Errorf("разбор deleted_at: %w", err)
+	}
+	taskToDelete.DeletedAt = &t
+	taskToDelete.Version = version
+
+	logSlow(ctx, start, 100*time.Millisecond)
+	return nil
+}
+
+// полное удаление из БД
+func (s *Storage) DeleteFull(ctx context.Context, uuid uuid.UUID) error {
+	start := time.Now()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM tasks WHERE uuid = ?`, uuid.String())
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Полное удаление задачи", err, zap.Duration("ms", time.Since(start)))
+		return fmt.Errorf("полное удаление: %w", err)
+	}
+
+	logSlow(ctx, start, 100*time.Millisecond)
+	return nil
+}
+
+func (s *Storage) GetByID(ctx context.Context, uuid uuid.UUID) (*task.Task, error) {
+	start := time.Now()
+
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE uuid = ?`
+
+	t, err := scanTask(s.db.QueryRowContext(ctx, query, uuid.String()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repo.ErrNotFound
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачу", err, zap.Duration("ms", time.Since(start)))
+		return nil, fmt.Errorf("получение задачи: %w", err)
+	}
+
+	logSlow(ctx, start, 100*time.Millisecond)
+	return t, nil
+}
+
+// все задачи с флагами active или archived
+func (s *Storage) GetAllWithLimit(ctx context.Context, page, limit int) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE flag != ?
+				ORDER BY created_at
+				LIMIT ? OFFSET ?`
+	return s.queryTasks(ctx, limit, query, task.FlagDeleted, limit, (page-1)*limit)
+}
+
+// получение задач с определённым статусом
+func (s *Storage) GetStatusedWithLimit(ctx context.Context, page, limit int, status task.Status) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE status = ?
+				ORDER BY created_at
+				LIMIT ? OFFSET ?`
+	return s.queryTasks(ctx, limit, query, status, limit, (page-1)*limit)
+}
+
+// получение задач с определённым флагом
+func (s *Storage) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag task.Flag) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE flag = ?
+				ORDER BY created_at
+				LIMIT ? OFFSET ?`
+	return s.queryTasks(ctx, limit, query, flag, limit, (page-1)*limit)
+}
+
+func (s *Storage) GetTasksDueBefore(ctx context.Context, deadline time.Time, limit int) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE flag = 'active'
+				AND status NOT IN ('done', 'cancelled', 'overdue')
+				AND due_time < ?
+				ORDER BY due_time
+				LIMIT ?`
+	return s.queryTasks(ctx, limit, query, formatTime(deadline), limit)
+}
+
+// GetTasksWithDueReminders выбирает задачи по next_reminder_at - моменту
+// task.Reminders.NextAt, который пересчитывается при каждой записи задачи
+func (s *Storage) GetTasksWithDueReminders(ctx context.Context, now time.Time, limit int) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE next_reminder_at <= ?
+				AND due_time > ?
+				AND flag = 'active'
+				AND status NOT IN ('done', 'cancelled', 'overdue')
+				ORDER BY next_reminder_at
+				LIMIT ?`
+	return s.queryTasks(ctx, limit, query, formatTime(now), formatTime(now), limit)
+}
+
+// GetActiveByStatuses читает колонки доски по индексу idx_tasks_active_status_rank
+func (s *Storage) GetActiveByStatuses(ctx context.Context, statuses []task.Status) ([]*task.Task, error) {
+	if len(statuses) == 0 {
+		return []*task.Task{}, nil
+	}
+	args := make([]any, len(statuses))
+	for i, status := range statuses {
+		args[i] = string(status)
+	}
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE flag = 'active'
+				AND status IN (?` + strings.Repeat(", ?", len(statuses)-1) + `)
+				ORDER BY status, rank`
+	return s.queryTasks(ctx, 0, query, args...)
+}
+
+func (s *Storage) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, error) {
+	if len(ids) == 0 {
+		return []*task.Task{}, nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id.String()
+	}
+	query := `SELECT ` + taskColumns + `
+				FROM tasks
+				WHERE uuid IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+	return s.queryTasks(ctx, len(ids), query, args...)
+}
+
+// CountTasks считает задачи для метрик одним запросом
+func (s *Storage) CountTasks(ctx context.Context, now time.Time) (task.Counts, error) {
+	start := time.Now()
+
+	query := `SELECT flag, status, COUNT(*),
+				SUM(CASE WHEN flag = 'active' AND status NOT IN ('done', 'cancelled') AND due_time < ? THEN 1 ELSE 0 END)
+				FROM tasks
+				GROUP BY flag, status`
+
+	var counts task.Counts
+	rows, err := s.db.QueryContext(ctx, query, formatTime(now))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось посчитать задачи", err, zap.Duration("ms", time.Since(start)))
+		return counts, fmt.Errorf("подсчёт задач: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			flag           task.Flag
+			status         task.Status
+			total, overdue int
+		)
+		if err := rows.Scan(&flag, &status, &total, &overdue); err != nil {
+			return counts, fmt.Errorf("сканирование счётчиков: %w", err)
+		}
+		counts.Add(flag, status, total)
+		counts.Overdue += overdue
+	}
+	if err := rows.Err(); err != nil {
+		return counts, fmt.Errorf("итерация по строкам: %w", err)
+	}
+
+	logSlow(ctx, start, 100*time.Millisecond)
+	return counts, nil
+}
+
+func (s *Storage) queryTasks(ctx context.Context, limit int, query string, args ...any) ([]*task.Task, error) {
+	start := time.Now()
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить задачи", err, zap.Duration("ms", time.Since(start)))
+		return nil, fmt.Errorf("получение задач: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*task.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			logger.WarnCtx(ctx, "Repository: Ошибка сканирования задачи", zap.Error(err))
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorCtx(ctx, "Repository: Ошибка итерации по строкам", err)
+		return nil, fmt.Errorf("итерация по строкам: %w", err)
+	}
+
+	logSlow(ctx, start, 50*time.Millisecond+10*time.Millisecond*time.Duration(limit))
+	return tasks, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (*task.Task, error) {
+	var (
+		t                    task.Task
+		id                   string
+		dueTime, createdAt   string
+		updatedAt, deletedAt sql.NullString
+		startedAt, completed sql.NullString
+	)
+
+	err := row.Scan(
+		&id,
+		&t.Title,
+		&t.Description,
+		&t.Status,
+		&dueTime,
+		&createdAt,
+		&updatedAt,
+		&deletedAt,
+		&t.Version,
+		&t.Flag,
+		&t.RRule,
+		&t.Reminders,
+		&t.Rank,
+		&t.OriginalEstimate,
+		&t.RemainingEstimate,
+		&startedAt,
+		&completed,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.UUID, err = uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("разбор uuid: %w", err)
+	}
+	if t.DueTime, err = parseTime(dueTime); err != nil {
+		return nil, fmt.Errorf("разбор due_time: %w", err)
+	}
+	if t.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("разбор created_at: %w", err)
+	}
+	if t.UpdatedAt, err = parseNullTime(updatedAt); err != nil {
+		return nil, fmt.Errorf("разбор updated_at: %w", err)
+	}
+	if t.DeletedAt, err = parseNullTime(deletedAt); err != nil {
+		return nil, fmt.Errorf("разбор deleted_at: %w", err)
+	}
+	if t.StartedAt, err = parseNullTime(startedAt); err != nil {
+		return nil, fmt.Errorf("разбор started_at: %w", err)
+	}
+	if t.CompletedAt, err = parseNullTime(completed); err != nil {
+		return nil, fmt.Errorf("разбор completed_at: %w", err)
+	}
+
+	return &t, nil
+}
+
+func nowUTC() time.Time {
+	return time.Now().UTC()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func formatNullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(*t), Valid: true}
+}
+
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(timeLayout, value)
+}
+
+func parseNullTime(value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	t, err := parseTime(value.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func logSlow(ctx context.Context, start time.Time, threshold time.Duration) {
+	if time.Since(start) > threshold {
+		logger.WarnCtx(ctx, "Repository: Медленный запрос", zap.Duration("ms", time.Since(start)))
+	}
+}
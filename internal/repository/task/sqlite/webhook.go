@@ -0,0 +1,373 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/webhook"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// подписки на вебхуки, журнал попыток, dead-letter и отложенные доставки.
+// Попытки и отложенные доставки удаляются вместе с подпиской (ON DELETE CASCADE),
+// dead-letter остаётся для разбора. Записи о несуществующей подписке
+// не вставляются (INSERT ... SELECT ... WHERE EXISTS), тогда возвращается ErrNotFound
+
+const subscriptionColumns = `id, url, secret, events, active, created_at, updated_at`
+
+func (s *Storage) CreateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	types, err := json.Marshal(sub.Events)
+	if err != nil {
+		return fmt.Errorf("сериализация событий подписки: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (`+subscriptionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		sub.ID.String(), sub.URL, sub.Secret, string(types), sub.Active,
+		formatTime(sub.CreatedAt), formatNullTime(sub.UpdatedAt))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось создать подписку", err)
+		return fmt.Errorf("создание подписки: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) UpdateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	types, err := json.Marshal(sub.Events)
+	if err != nil {
+		return fmt.Errorf("сериализация событий подписки: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = ?, secret = ?, events = ?, active = ?, updated_at = ?
+		WHERE id = ?`,
+		sub.URL, sub.Secret, string(types), sub.Active, formatNullTime(sub.UpdatedAt), sub.ID.String())
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось обновить подписку", err)
+		return fmt.Errorf("обновление подписки: %w", err)
+	}
+	return requireAffected(res)
+}
+
+func (s *Storage) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?`, id.String())
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось удалить подписку", err)
+		return fmt.Errorf("удаление подписки: %w", err)
+	}
+	return requireAffected(res)
+}
+
+func (s *Storage) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	sub, err := scanSubscription(s.db.QueryRowContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = ?`, id.String()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, webhook.ErrNotFound
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить подписку", err)
+		return nil, fmt.Errorf("получение подписки: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *Storage) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM webhook_subscriptions ORDER BY created_at, id`)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить подписки", err)
+		return nil, fmt.Errorf("получение подписок: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*webhook.Subscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование подписки: %w", err)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+	return subscriptions, rows.Err()
+}
+
+func scanSubscription(row rowScanner) (*webhook.Subscription, error) {
+	var (
+		sub                  webhook.Subscription
+		id, types, createdAt string
+		updatedAt            sql.NullString
+	)
+	if err := row.Scan(&id, &sub.URL, &sub.Secret, &types, &sub.Active, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if sub.ID, err = uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("разбор id: %w", err)
+	}
+	if err := json.Unmarshal([]byte(types), &sub.Events); err != nil {
+		return nil, fmt.Errorf("разбор событий подписки %s: %w", id, err)
+	}
+	if sub.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("разбор created_at: %w", err)
+	}
+	if sub.UpdatedAt, err = parseNullTime(updatedAt); err != nil {
+		return nil, fmt.Errorf("разбор updated_at: %w", err)
+	}
+	return &sub, nil
+}
+
+// AddDelivery записывает попытку и оставляет на подписку
+// не больше webhook.MaxDeliveriesPerSubscription последних
+func (s *Storage) AddDelivery(ctx context.Context, d webhook.Delivery) error {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (id, delivery_id, subscription_id, event_id, event_type, attempt,
+			status_code, error, success, duration_ns, attempted_at, next_retry_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE EXISTS (SELECT 1 FROM webhook_subscriptions WHERE id = ?)`,
+		d.ID.String(), d.DeliveryID.String(), d.SubscriptionID.String(), d.EventID.String(), d.EventType, d.Attempt,
+		d.StatusCode, d.Error, d.Success, int64(d.Duration), formatTime(d.AttemptedAt), formatNullTime(d.NextRetryAt),
+		d.SubscriptionID.String())
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось записать попытку доставки", err)
+		return fmt.Errorf("запись попытки доставки: %w", err)
+	}
+	if err := requireAffected(res); err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE subscription_id = ?1 AND seq <= (
+			SELECT seq FROM webhook_deliveries
+			WHERE subscription_id = ?1
+			ORDER BY seq DESC
+			LIMIT 1 OFFSET ?2
+		)`, d.SubscriptionID.String(), webhook.MaxDeliveriesPerSubscription)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось очистить журнал доставок", err)
+		return fmt.Errorf("очистка журнала доставок: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries отдаёт последние попытки, начиная с самой свежей
+func (s *Storage) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]webhook.Delivery, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = webhook.MaxDeliveriesPerSubscription
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, delivery_id, subscription_id, event_id, event_type, attempt,
+			status_code, error, success, duration_ns, attempted_at, next_retry_at
+		FROM webhook_deliveries
+		WHERE subscription_id = ?
+		ORDER BY seq DESC
+		LIMIT ?`, subscriptionID.String(), limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить попытки доставки", err)
+		return nil, fmt.Errorf("получение попыток доставки: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []webhook.Delivery{}
+	for rows.Next() {
+		var (
+			d                                  webhook.Delivery
+			id, deliveryID, subID, eventID, at string
+			nextRetryAt                        sql.NullString
+			duration                           int64
+		)
+		err := rows.Scan(&id, &deliveryID, &subID, &eventID, &d.EventType, &d.Attempt,
+			&d.StatusCode, &d.Error, &d.Success, &duration, &at, &nextRetryAt)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование попытки доставки: %w", err)
+		}
+		if d.ID, err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("разбор id: %w", err)
+		}
+		if d.DeliveryID, err = uuid.Parse(deliveryID); err != nil {
+			return nil, fmt.Errorf("разбор delivery_id: %w", err)
+		}
+		if d.SubscriptionID, err = uuid.Parse(subID); err != nil {
+			return nil, fmt.Errorf("разбор subscription_id: %w", err)
+		}
+		if d.EventID, err = uuid.Parse(eventID); err != nil {
+			return nil, fmt.Errorf("разбор event_id: %w", err)
+		}
+		if d.AttemptedAt, err = parseTime(at); err != nil {
+			return nil, fmt.Errorf("разбор attempted_at: %w", err)
+		}
+		if d.NextRetryAt, err = parseNullTime(nextRetryAt); err != nil {
+			return nil, fmt.Errorf("разбор next_retry_at: %w", err)
+		}
+		d.Duration = time.Duration(duration)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// AddDeadLetter записывает событие и оставляет не больше webhook.MaxDeadLetters последних
+func (s *Storage) AddDeadLetter(ctx context.Context, d webhook.DeadLetter) error {
+	payload, err := json.Marshal(d.Event)
+	if err != nil {
+		return fmt.Errorf("сериализация события: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO webhook_dead_letters (delivery_id, subscription_id, event, attempts, last_error, failed_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		d.DeliveryID.String(), d.SubscriptionID.String(), string(payload), d.Attempts, d.LastError, formatTime(d.FailedAt))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось записать событие в dead-letter", err)
+		return fmt.Errorf("запись в dead-letter: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		DELETE FROM webhook_dead_letters
+		WHERE seq <= (SELECT seq FROM webhook_dead_letters ORDER BY seq DESC LIMIT 1 OFFSET ?)`,
+		webhook.MaxDeadLetters)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось очистить dead-letter", err)
+		return fmt.Errorf("очистка dead-letter: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) ListDeadLetters(ctx context.Context, limit int) ([]webhook.DeadLetter, error) {
+	if limit <= 0 {
+		limit = webhook.MaxDeadLetters
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT delivery_id, subscription_id, event, attempts, last_error, failed_at
+		FROM webhook_dead_letters
+		ORDER BY seq DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить dead-letter", err)
+		return nil, fmt.Errorf("получение dead-letter: %w", err)
+	}
+	defer rows.Close()
+
+	deadLetters := []webhook.DeadLetter{}
+	for rows.Next() {
+		var (
+			d                                  webhook.DeadLetter
+			deliveryID, subID, payload, failed string
+		)
+		if err := rows.Scan(&deliveryID, &subID, &payload, &d.Attempts, &d.LastError, &failed); err != nil {
+			return nil, fmt.Errorf("сканирование dead-letter: %w", err)
+		}
+		if d.DeliveryID, err = uuid.Parse(deliveryID); err != nil {
+			return nil, fmt.Errorf("разбор delivery_id: %w", err)
+		}
+		if d.SubscriptionID, err = uuid.Parse(subID); err != nil {
+			return nil, fmt.Errorf("разбор subscription_id: %w", err)
+		}
+		if err := json.Unmarshal([]byte(payload), &d.Event); err != nil {
+			return nil, fmt.Errorf("разбор события %s: %w", deliveryID, err)
+		}
+		if d.FailedAt, err = parseTime(failed); err != nil {
+			return nil, fmt.Errorf("разбор failed_at: %w", err)
+		}
+		deadLetters = append(deadLetters, d)
+	}
+	return deadLetters, rows.Err()
+}
+
+func (s *Storage) SavePending(ctx context.Context, p webhook.Pending) error {
+	payload, err := json.Marshal(p.Event)
+	if err != nil {
+		return fmt.Errorf("сериализация события: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_pending (delivery_id, subscription_id, event, attempt, next_attempt_at)
+		SELECT ?1, ?2, ?3, ?4, ?5
+		WHERE EXISTS (SELECT 1 FROM webhook_subscriptions WHERE id = ?2)
+		ON CONFLICT (delivery_id) DO UPDATE
+		SET attempt = excluded.attempt, next_attempt_at = excluded.next_attempt_at`,
+		p.DeliveryID.String(), p.SubscriptionID.String(), string(payload), p.Attempt, formatTime(p.NextAttemptAt))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось сохранить отложенную доставку", err)
+		return fmt.Errorf("сохранение отложенной доставки: %w", err)
+	}
+	return requireAffected(res)
+}
+
+// ClaimPending забирает созревшие доставки одним UPDATE: запись
+// в SQLite идёт под блокировкой всей базы, поэтому выборка и перенос атомарны
+func (s *Storage) ClaimPending(ctx context.Context, now, until time.Time, limit int) ([]webhook.Pending, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE webhook_pending SET next_attempt_at = ?2
+		WHERE delivery_id IN (
+			SELECT delivery_id FROM webhook_pending
+			WHERE next_attempt_at <= ?1
+			ORDER BY next_attempt_at
+			LIMIT ?3
+		)
+		RETURNING delivery_id, subscription_id, event, attempt, next_attempt_at`,
+		formatTime(now), formatTime(until), limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить отложенные доставки", err)
+		return nil, fmt.Errorf("получение отложенных доставок: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []webhook.Pending
+	for rows.Next() {
+		var (
+			p                                  webhook.Pending
+			deliveryID, subID, payload, nextAt string
+		)
+		if err := rows.Scan(&deliveryID, &subID, &payload, &p.Attempt, &nextAt); err != nil {
+			return nil, fmt.Errorf("сканирование отложенной доставки: %w", err)
+		}
+		if p.DeliveryID, err = uuid.Parse(deliveryID); err != nil {
+			return nil, fmt.Errorf("разбор delivery_id: %w", err)
+		}
+		if p.SubscriptionID, err = uuid.Parse(subID); err != nil {
+			return nil, fmt.Errorf("разбор subscription_id: %w", err)
+		}
+		if err := json.Unmarshal([]byte(payload), &p.Event); err != nil {
+			return nil, fmt.Errorf("разбор события %s: %w", deliveryID, err)
+		}
+		if p.NextAttemptAt, err = parseTime(nextAt); err != nil {
+			return nil, fmt.Errorf("разбор next_attempt_at: %w", err)
+		}
+		pending = append(pending, p)
+	}
+	return pending, rows.Err()
+}
+
+func (s *Storage) DeletePending(ctx context.Context, deliveryID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM webhook_pending WHERE delivery_id = ?`, deliveryID.String())
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось удалить отложенную доставку", err)
+		return fmt.Errorf("удаление отложенной доставки: %w", err)
+	}
+	return nil
+}
+
+// requireAffected превращает пустой результат в webhook.ErrNotFound
+func requireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("подсчёт изменённых строк: %w", err)
+	}
+	if affected == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/worklog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// таймеры и записи времени; первичный ключ timers не даёт запустить
+// таймер пользователя на задаче дважды
+
+func (s *Storage) StartTimer(ctx context.Context, t worklog.Timer) error {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO timers (task_id, user_id, started_at) VALUES (?, ?, ?)
+		ON CONFLICT (task_id, user_id) DO NOTHING`,
+		t.TaskID.String(), t.User, formatTime(t.StartedAt))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось запустить таймер", err)
+		return fmt.Errorf("запуск таймера: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("подсчёт изменённых строк: %w", err)
+	}
+	if affected == 0 {
+		return worklog.ErrTimerRunning
+	}
+	return nil
+}
+
+func (s *Storage) StopTimer(ctx context.Context, taskID uuid.UUID, user string) (worklog.Timer, error) {
+	var startedAt string
+	err := s.db.QueryRowContext(ctx, `
+		DELETE FROM timers WHERE task_id = ? AND user_id = ?
+		RETURNING started_at`, taskID.String(), user).Scan(&startedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return worklog.Timer{}, worklog.ErrNotFound
+		}
+		logger.ErrorCtx(ctx, "Repository: Не удалось остановить таймер", err)
+		return worklog.Timer{}, fmt.Errorf("остановка таймера: %w", err)
+	}
+
+	t := worklog.Timer{TaskID: taskID, User: user}
+	if t.StartedAt, err = parseTime(startedAt); err != nil {
+		return worklog.Timer{}, fmt.Errorf("разбор started_at: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Storage) ListTimers(ctx context.Context, taskID uuid.UUID, user string) ([]worklog.Timer, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT task_id, user_id, started_at FROM timers
+		WHERE (?1 = '' OR task_id = ?1) AND (?2 = '' OR user_id = ?2)
+		ORDER BY started_at`, uuidFilter(taskID), user)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить таймеры", err)
+		return nil, fmt.Errorf("получение таймеров: %w", err)
+	}
+	defer rows.Close()
+
+	timers := []worklog.Timer{}
+	for rows.Next() {
+		var (
+			t             worklog.Timer
+			id, startedAt string
+		)
+		if err := rows.Scan(&id, &t.User, &startedAt); err != nil {
+			return nil, fmt.Errorf("сканирование таймера: %w", err)
+		}
+		if t.TaskID, err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("разбор task_id: %w", err)
+		}
+		if t.StartedAt, err = parseTime(startedAt); err != nil {
+			return nil, fmt.Errorf("разбор started_at: %w", err)
+		}
+		timers = append(timers, t)
+	}
+	return timers, rows.Err()
+}
+
+func (s *Storage) AddWorklog(ctx context.Context, e worklog.Entry) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO worklogs (id, task_id, user_id, started_at, duration_seconds, note, source, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID.String(), e.TaskID.String(), e.User, formatTime(e.StartedAt), int64(e.Duration/time.Second),
+		e.Note, string(e.Source), formatTime(e.CreatedAt))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось добавить запись времени", err)
+		return fmt.Errorf("добавление записи времени: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) ListWorklogs(ctx context.Context, f worklog.Filter) ([]worklog.Entry, error) {
+	start := time.Now()
+
+	var from, to string
+	if !f.From.IsZero() {
+		from = formatTime(f.From)
+	}
+	if !f.To.IsZero() {
+		to = formatTime(f.To)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, task_id, user_id, started_at, duration_seconds, note, source, created_at
+		FROM worklogs
+		WHERE (?1 = '' OR task_id = ?1)
+		  AND (?2 = '' OR user_id = ?2)
+		  AND (?3 = '' OR started_at >= ?3)
+		  AND (?4 = '' OR started_at < ?4)
+		ORDER BY started_at`, uuidFilter(f.TaskID), f.User, from, to)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Repository: Не удалось получить записи времени", err)
+		return nil, fmt.Errorf("получение записей времени: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []worklog.Entry{}
+	for rows.Next() {
+		var (
+			e                                worklog.Entry
+			id, taskID, startedAt, createdAt string
+			seconds                          int64
+		)
+		err := rows.Scan(&id, &taskID, &e.User, &startedAt, &seconds, &e.Note, &e.Source, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("сканирование записи времени: %w", err)
+		}
+		if e.ID, err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("разбор id: %w", err)
+		}
+		if e.TaskID, err = uuid.Parse(taskID); err != nil {
+			return nil, fmt.Errorf("разбор task_id: %w", err)
+		}
+		if e.StartedAt, err = parseTime(startedAt); err != nil {
+			return nil, fmt.Errorf("разбор started_at: %w", err)
+		}
+		if e.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("разбор created_at: %w", err)
+		}
+		e.Duration = time.Duration(seconds) * time.Second
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация по строкам: %w", err)
+	}
+
+	logSlow(ctx, start, 100*time.Millisecond)
+	return entries, nil
+}
+
+func (s *Storage) WorklogTotals(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID]time.Duration, error) {
+	totals := make(map[uuid.UUID]time.Duration)
+	if len(taskIDs) == 0 {
+		return totals, nil
+	}
+	args := make([]any, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id.String()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT task_id, SUM(duration_seconds) FROM worklogs
+		WHERE task_id IN (?`+strings.Repeat(", ?", len(taskIDs)-1)+`)
+		GROUP BY task_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("суммирование времени: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id      string
+			seconds int64
+		)
+		if err := rows.Scan(&id, &seconds); err != nil {
+			return nil, fmt.Errorf("сканирование суммы времени: %w", err)
+		}
+		taskID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("разбор task_id: %w", err)
+		}
+		totals[taskID] = time.Duration(seconds) * time.Second
+	}
+	return totals, rows.Err()
+}
+
+// uuidFilter - id для условия «пусто - без фильтра»
+func uuidFilter(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
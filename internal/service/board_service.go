@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"taskTracker/internal/board"
+	"taskTracker/internal/models/task"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GET /boards/{id}
+func (s *TaskService) GetBoard(ctx context.Context, id string) (board.Board, []board.ColumnTasks, error) {
+	b, ok := s.Boards.Get(id)
+	if !ok {
+		return board.Board{}, nil, NewNotFound("board", id)
+	}
+
+	var statuses []task.Status
+	for _, col := range b.Columns {
+		statuses = append(statuses, col.Statuses...)
+	}
+	tasks, err := s.boardTasks(ctx, statuses)
+	if err != nil {
+		return board.Board{}, nil, err
+	}
+	return b, b.Group(tasks), nil
+}
+
+// boardTasks - все активные задачи, которые клиент видит в статусах statuses.
+// Просроченная задача хранится со своим статусом, а видна как overdue,
+// поэтому для overdue читаются все незакрытые статусы
+func (s *TaskService) boardTasks(ctx context.Context, statuses []task.Status) ([]*task.Task, error) {
+	stored := slices.Clone(statuses)
+	if slices.Contains(statuses, task.StatusOverdue) {
+		for _, status := range s.Workflows.Statuses() {
+			if !status.Closed() && !slices.Contains(stored, status) {
+				stored = append(stored, status)
+			}
+		}
+	}
+
+	tasks, err := s.Repo.GetActiveByStatuses(ctx, stored)
+	if err != nil {
+		return nil, fmt.Errorf("получение задач доски: %w", err)
+	}
+
+	now := time.Now()
+	visible := make([]*task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		t.Status = effectiveStatus(t, now)
+		if slices.Contains(statuses, t.Status) {
+			visible = append(visible, t)
+		}
+	}
+	return visible, nil
+}
+
+// BoardLocker сериализует попадание задач в колонки одной доски: WIP-лимит
+// проверяется по числу задач в колонке, и без блокировки два параллельных
+// изменения увидят одно и то же число и оба пройдут
+type BoardLocker interface {
+	LockBoard(ctx context.Context, board string) (unlock func(), err error)
+}
+
+// LocalBoardLocker - блокировка досок внутри одного процесса
+type LocalBoardLocker struct {
+	mtx   sync.Mutex
+	locks map[string]chan struct{}
+}
+
+func NewLocalBoardLocker() *LocalBoardLocker {
+	return &LocalBoardLocker{locks: make(map[string]chan struct{})}
+}
+
+func (l *LocalBoardLocker) LockBoard(ctx context.Context, board string) (func(), error) {
+	l.mtx.Lock()
+	lock, ok := l.locks[board]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		l.locks[board] = lock
+	}
+	l.mtx.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type heldBoardsKey struct{}
+
+// lockBoards блокирует доски по порядку id и отмечает их в ctx. Доски,
+// взятые выше по вызову, повторно не блокируются: MoveTask держит свою доску,
+// пока UpdateTask меняет статус, а блокировки не реентерабельны
+func (s *TaskService) lockBoards(ctx context.Context, ids []string) (context.Context, func(), error) {
+	held, _ := ctx.Value(heldBoardsKey{}).([]string)
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+
+	var unlocks []func()
+	unlock := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	locked := slices.Clone(held)
+	for _, id := range ids {
+		if slices.Contains(held, id) {
+			continue
+		}
+		unlockBoard, err := s.BoardLocker.LockBoard(ctx, id)
+		if err != nil {
+			unlock()
+			return ctx, nil, fmt.Errorf("блокировка доски %s: %w", id, err)
+		}
+		unlocks = append(unlocks, unlockBoard)
+		locked = append(locked, id)
+	}
+	return context.WithValue(ctx, heldBoardsKey{}, locked), unlock, nil
+}
+
+// limitedColumn - колонка доски с WIP-лимитом
+type limitedColumn struct {
+	board  string
+	column board.Column
+}
+
+// admit проверяет WIP-лимиты колонок, в которые активная задача t попадает
+// из видимого статуса from, и держит блокировку их досок до unlock, чтобы
+// число задач в колонке не изменилось до записи t. Через admit проходят
+// создание, обновление и перемещение задачи
+func (s *TaskService) admit(ctx context.Context, t *task.Task, from task.Status) (context.Context, func(), error) {
+	to := effectiveStatus(t, time.Now())
+	if t.Flag != task.FlagActive || to == from {
+		return ctx, func() {}, nil
+	}
+
+	var (
+		columns []limitedColumn
+		boards  []string
+	)
+	for _, b := range s.Boards.All() {
+		for _, col := range b.Columns {
+			if col.WIPLimit > 0 && col.Contains(to) && !col.Contains(from) {
+				columns = append(columns, limitedColumn{board: b.ID, column: col})
+				boards = append(boards, b.ID)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return ctx, func() {}, nil
+	}
+
+	ctx, unlock, err := s.lockBoards(ctx, boards)
+	if err != nil {
+		return ctx, nil, err
+	}
+	for _, c := range columns {
+		if err := s.checkWIPLimit(ctx, t.UUID, c); err != nil {
+			unlock()
+			return ctx, nil, err
+		}
+	}
+	return ctx, unlock, nil
+}
+
+// checkWIPLimit - есть ли в колонке место для задачи id. Сама задача не считается
+func (s *TaskService) checkWIPLimit(ctx context.Context, id uuid.UUID, c limitedColumn) error {
+	tasks, err := s.boardTasks(ctx, c.column.Statuses)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, t := range tasks {
+		if t.UUID != id {
+			count++
+		}
+	}
+	if count < c.column.WIPLimit {
+		return nil
+	}
+	return NewBusinessError(
+		CodeWIPLimitExceeded,
+		fmt.Sprintf("В колонке '%s' уже %d задач при лимите %d", c.column.ID, count, c.column.WIPLimit),
+		ToDetail("task_id", id.String()),
+		ToDetail("board", c.board),
+		ToDetail("column", c.column.ID),
+		ToDetail("wip_limit", c.column.WIPLimit),
+	)
+}
+
+// MoveRequest - куда переместить задачу. After - задача, которая окажется
+// прямо над перемещаемой, Before - прямо под ней. Без соседей задача
+// встаёт в конец колонки, без Board используется первая доска конфига
+type MoveRequest struct {
+	Board  string
+	Column string
+	Before *uuid.UUID
+	After  *uuid.UUID
+}
+
+// POST /tasks/{id}/move
+//
+// Задача получает ранг между соседями, остальные ранги не меняются, пока
+// у соседей не совпадут ранги. При переходе в другую колонку статус меняется
+// на первый статус колонки через UpdateTask, то есть с проверкой workflow
+// и WIP-лимита колонки. Перемещения на доске идут по одному, поэтому задачи
+// колонки считаются заново под блокировкой
+func (s *TaskService) MoveTask(ctx context.Context, id uuid.UUID, req MoveRequest) (*task.Task, error) {
+	if req.Board == "" {
+		req.Board = s.Boards.First().ID
+	}
+	b, ok := s.Boards.Get(req.Board)
+	if !ok {
+		return nil, NewNotFound("board", req.Board)
+	}
+	col, ok := b.Column(req.Column)
+	if !ok {
+		return nil, NewValidationError("column", fmt.Sprintf("на доске %s нет колонки '%s'", b.ID, req.Column))
+	}
+	if req.After != nil && req.Before != nil && *req.After == *req.Before {
+		return nil, NewValidationError("before", "after и before указывают на одну и ту же задачу")
+	}
+
+	ctx, unlock, err := s.lockBoards(ctx, []string{b.ID})
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	moving, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if moving.Flag != task.FlagActive {
+		return nil, NewBusinessError(
+			CodeInvalidFlag,
+			fmt.Sprintf("Перемещать можно только активные задачи. Текущий флаг: '%s'", moving.Flag),
+			ToDetail("task_id", id.String()),
+			ToDetail("current_flag", moving.Flag),
+		)
+	}
+
+	tasks, err := s.boardTasks(ctx, col.Statuses)
+	if err != nil {
+		return nil, err
+	}
+	var column []*task.Task
+	for _, t := range tasks {
+		if t.UUID != id {
+			column = append(column, t)
+		}
+	}
+	board.Sort(column)
+
+	var options []task.TaskOption
+	if !col.Contains(moving.Status) {
+		options = append(options, task.WithStatus(col.Statuses[0]))
+	}
+
+	lo, hi, err := neighbours(column, req)
+	if err != nil {
+		return nil, err
+	}
+	if lo != "" && lo == hi {
+		if err := s.untie(ctx, column, lo); err != nil {
+			return nil, err
+		}
+		if lo, hi, err = neighbours(column, req); err != nil {
+			return nil, err
+		}
+	}
+	rank, err := board.Between(lo, hi)
+	if err != nil {
+		return nil, NewValidationError("before", "задача after должна стоять выше задачи before")
+	}
+	options = append(options, task.WithRank(rank))
+
+	return s.UpdateTask(ctx, id, options...)
+}
+
+// neighbours - ранги соседей, между которыми встанет задача. Если указан
+// один сосед, второй берётся рядом с ним, чтобы задача встала вплотную
+func neighbours(column []*task.Task, req MoveRequest) (string, string, error) {
+	find := func(field string, id uuid.UUID) (int, error) {
+		for i, t := range column {
+			if t.UUID == id {
+				return i, nil
+			}
+		}
+		return 0, NewValidationError(field, fmt.Sprintf("задачи %s нет среди активных задач колонки '%s'", id, req.Column))
+	}
+
+	lo, hi := "", ""
+	switch {
+	case req.After != nil && req.Before != nil:
+		i, err := find("after", *req.After)
+		if err != nil {
+			return "", "", err
+		}
+		j, err := find("before", *req.Before)
+		if err != nil {
+			return "", "", err
+		}
+		lo, hi = board.Rank(column[i]), board.Rank(column[j])
+	case req.After != nil:
+		i, err := find("after", *req.After)
+		if err != nil {
+			return "", "", err
+		}
+		lo = board.Rank(column[i])
+		if i+1 < len(column) {
+			hi = board.Rank(column[i+1])
+		}
+	case req.Before != nil:
+		j, err := find("before", *req.Before)
+		if err != nil {
+			return "", "", err
+		}
+		hi = board.Rank(column[j])
+		if j > 0 {
+			lo = board.Rank(column[j-1])
+		}
+	default:
+		if len(column) > 0 {
+			lo = board.Rank(column[len(column)-1])
+		}
+	}
+	return lo, hi, nil
+}
+
+// untie раздаёт задачам колонки с одинаковым рангом rank разные ранги между
+// соседними рангами, сохраняя их порядок. Одинаковые ранги бывают у задач,
+// созданных в одну наносекунду, и у данных, записанных до блокировки досок
+func (s *TaskService) untie(ctx context.Context, column []*task.Task, rank string) error {
+	first := slices.IndexFunc(column, func(t *task.Task) bool { return board.Rank(t) == rank })
+	last := first
+	for last+1 < len(column) && board.Rank(column[last+1]) == rank {
+		last++
+	}
+
+	lo, hi := "", ""
+	if first > 0 {
+		lo = board.Rank(column[first-1])
+	}
+	if last+1 < len(column) {
+		hi = board.Rank(column[last+1])
+	}
+	for i := first; i <= last; i++ {
+		next, err := board.Between(lo, hi)
+		if err != nil {
+			return fmt.Errorf("новый ранг задачи %s: %w", column[i].UUID, err)
+		}
+		updated, err := s.UpdateTask(ctx, column[i].UUID, task.WithRank(next))
+		if err != nil {
+			return err
+		}
+		column[i] = updated
+		lo = next
+	}
+	return nil
+}
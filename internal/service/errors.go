@@ -2,6 +2,28 @@ package service
 
 import "fmt"
 
+// Коды бизнес-ошибок. HTTP-статус и тексты ответа для каждого кода
+// собраны в internal/problem
+const (
+	CodeNotFound          = "NOT_FOUND"
+	CodeValidation        = "VALIDATION_ERROR"
+	CodeNotRecurring      = "NOT_RECURRING"
+	CodeAlreadyArchived   = "ALREADY_ARCHIVED"
+	CodeNotArchived       = "NOT_ARCHIVED"
+	CodeAlreadyDeleted    = "ALREADY_DELETED"
+	CodeNotDeleted        = "NOT_DELETED"
+	CodeInvalidFlag       = "INVALID_FLAG"
+	CodeInProgress        = "IN_PROGRESS"
+	CodeVersionConflict   = "VERSION_CONFLICT"
+	CodeTaskDeleted       = "TASK_DELETED"
+	CodeRestoreExpired    = "RESTORE_EXPIRED"
+	CodeInvalidTransition = "INVALID_TRANSITION"
+	CodeWIPLimitExceeded  = "WIP_LIMIT_EXCEEDED"
+	CodeTaskClosed        = "TASK_CLOSED"
+	CodeTimerRunning      = "TIMER_RUNNING"
+	CodeTimerNotRunning   = "TIMER_NOT_RUNNING"
+)
+
 type BusinessError struct{
 	Code string
 	Message string
@@ -44,7 +66,7 @@ func NewBusinessError(code string, message string, details ...Detail) *BusinessE
 
 func NewNotFound(resource RepoType, id string) *BusinessError {
     return &BusinessError{
-        Code:    "NOT_FOUND",
+        Code:    CodeNotFound,
         Message: fmt.Sprintf("%s %s не найден(а)", resource, id),
         Details: map[string]any{
             "resource": resource,
@@ -55,7 +77,7 @@ func NewNotFound(resource RepoType, id string) *BusinessError {
 
 func NewValidationError(field, reason string) *BusinessError {
     return &BusinessError{
-        Code:    "VALIDATION_ERROR",
+        Code:    CodeValidation,
         Message: fmt.Sprintf("Неверное значение поля '%s': %s", field, reason),
         Details: map[string]any{
             "field":  field,
@@ -3,16 +3,32 @@ package service_test
 import (
 	"context"
 	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"taskTracker/internal/board"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
 	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository"
+	"taskTracker/internal/repository/task/inmemory"
 	"taskTracker/internal/service"
+	"taskTracker/internal/workflow"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
 // MockTaskRepository - мок репозитория
 type MockTaskRepository struct {
 	mock.Mock
@@ -47,6 +63,30 @@ func (m *MockTaskRepository) GetTasksDueBefore(ctx context.Context, dueTime time
 	return args.Get(0).([]*task.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) GetTasksWithDueReminders(ctx context.Context, now time.Time, limit int) ([]*task.Task, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*task.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetActiveByStatuses(ctx context.Context, statuses []task.Status) ([]*task.Task, error) {
+	args := m.Called(ctx, statuses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*task.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*task.Task), args.Error(1)
+}
+
 func (m *MockTaskRepository) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag task.Flag) ([]*task.Task, error) {
 	args := m.Called(ctx, page, limit, flag)
 	if args.Get(0) == nil {
@@ -356,6 +396,372 @@ func TestTaskService_UpdateTask(t *testing.T) {
 	})
 }
 
+// TestTaskService_UpdateTask_Recurring тестирует создание следующего повторения при выполнении
+func TestTaskService_UpdateTask_Recurring(t *testing.T) {
+	ctx := context.Background()
+	taskID := uuid.New()
+	due := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	t.Run("success - done spawns next occurrence", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		existingTask := &task.Task{
+			UUID:    taskID,
+			Title:   "Standup",
+			Status:  task.StatusInProgress,
+			DueTime: due,
+			Flag:    task.FlagActive,
+			Version: 1,
+			RRule:   "FREQ=DAILY;COUNT=3",
+		}
+
+		mockRepo.On("GetByID", mock.Anything, taskID).Return(existingTask, nil)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(t *task.Task) bool {
+			return t.Title == "Standup" && t.DueTime.Equal(due.AddDate(0, 0, 1)) &&
+				t.RRule == "FREQ=DAILY;COUNT=2" && t.Status == task.StatusNew
+		})).Return(nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(t *task.Task) bool {
+			return t.Status == task.StatusDone && t.RRule == ""
+		})).Return(nil)
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		result, err := svc.UpdateTask(ctx, taskID, task.WithStatus(task.StatusDone))
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.RRule)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("version conflict rolls back next occurrence", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		existingTask := &task.Task{
+			UUID:    taskID,
+			Status:  task.StatusInProgress,
+			DueTime: due,
+			Flag:    task.FlagActive,
+			Version: 1,
+			RRule:   "FREQ=WEEKLY",
+		}
+
+		mockRepo.On("GetByID", mock.Anything, taskID).Return(existingTask, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict)
+		mockRepo.On("DeleteFull", mock.Anything, mock.Anything).Return(nil)
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		_, err := svc.UpdateTask(ctx, taskID, task.WithStatus(task.StatusDone))
+
+		var businessErr *service.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "VERSION_CONFLICT", businessErr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error - invalid rrule", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		existingTask := &task.Task{UUID: taskID, Flag: task.FlagActive, DueTime: due, Version: 1}
+		mockRepo.On("GetByID", mock.Anything, taskID).Return(existingTask, nil)
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		_, err := svc.UpdateTask(ctx, taskID, task.WithRRule("FREQ=HOURLY"))
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+// TestTaskService_UpdateTask_Workflow тестирует проверку переходов статуса
+func TestTaskService_UpdateTask_Workflow(t *testing.T) {
+	ctx := context.Background()
+	taskID := uuid.New()
+
+	newTask := func(status task.Status) *task.Task {
+		return &task.Task{
+			UUID:    taskID,
+			Title:   "Workflow",
+			Status:  status,
+			DueTime: time.Now().Add(48 * time.Hour),
+			Flag:    task.FlagActive,
+			Version: 1,
+		}
+	}
+
+	t.Run("allowed transition", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("GetByID", mock.Anything, taskID).Return(newTask(task.StatusNew), nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		result, err := svc.UpdateTask(ctx, taskID, task.WithStatus(task.StatusBlocked))
+
+		assert.NoError(t, err)
+		assert.Equal(t, task.StatusBlocked, result.Status)
+	})
+
+	t.Run("transition not in workflow", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("GetByID", mock.Anything, taskID).Return(newTask(task.StatusDone), nil)
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		_, err := svc.UpdateTask(ctx, taskID, task.WithStatus(task.StatusInProgress))
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeInvalidTransition, businessErr.Code)
+		assert.Equal(t, task.StatusDone, businessErr.Details["from"])
+		assert.Equal(t, []task.Status{task.StatusNew}, businessErr.Details["allowed"])
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("guard fails", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("GetByID", mock.Anything, taskID).Return(newTask(task.StatusInProgress), nil)
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		_, err := svc.UpdateTask(ctx, taskID, task.WithStatus(task.StatusInReview))
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeInvalidTransition, businessErr.Code)
+		assert.Equal(t, "нужно описание задачи", businessErr.Details["reason"])
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("GetByID", mock.Anything, taskID).Return(newTask(task.StatusNew), nil)
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		_, err := svc.UpdateTask(ctx, taskID, task.WithStatus("banana"))
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeValidation, businessErr.Code)
+		assert.Equal(t, "status", businessErr.Details["field"])
+	})
+}
+
+// TestTaskService_UpdateTask_RejectedKeepsStored тестирует, что отклонённое обновление не меняет хранимую задачу
+func TestTaskService_UpdateTask_RejectedKeepsStored(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewTaskStorage()
+	svc := service.NewTaskService(repo, service.InMemoryType)
+
+	created, err := svc.CreateTask(ctx, "original", "", time.Now().Add(72*time.Hour))
+	require.NoError(t, err)
+
+	rejected := map[string][]task.TaskOption{
+		"unknown status":    {task.WithStatus("banana"), task.WithTitle("changed")},
+		"negative estimate": {task.WithOriginalEstimate(-5), task.WithTitle("changed")},
+		"invalid rrule":     {task.WithRRule("FREQ=HOURLY"), task.WithTitle("changed")},
+	}
+	for name, options := range rejected {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.UpdateTask(ctx, created.UUID, options...)
+
+			var businessErr *service.BusinessError
+			require.True(t, errors.As(err, &businessErr))
+			assert.Equal(t, service.CodeValidation, businessErr.Code)
+
+			stored, err := repo.GetByID(ctx, created.UUID)
+			require.NoError(t, err)
+			assert.Equal(t, "original", stored.Title)
+			assert.Equal(t, task.StatusNew, stored.Status)
+			assert.Nil(t, stored.OriginalEstimate)
+			assert.Empty(t, stored.RRule)
+			assert.Equal(t, created.Version, stored.Version)
+		})
+	}
+}
+
+// TestTaskService_MoveTask тестирует перемещение по доске и WIP-лимит
+func TestTaskService_MoveTask(t *testing.T) {
+	ctx := context.Background()
+	boards, err := board.New(board.Config{Boards: []board.Board{{
+		ID: "dev",
+		Columns: []board.Column{
+			{ID: "todo", Statuses: []task.Status{task.StatusNew}},
+			{ID: "doing", Statuses: []task.Status{task.StatusInProgress}, WIPLimit: 1},
+			{ID: "done", Statuses: []task.Status{task.StatusDone}},
+		},
+	}}}, workflow.Default())
+	assert.NoError(t, err)
+
+	created := time.Now().Add(-time.Hour)
+	newTask := func(status task.Status, rank string) *task.Task {
+		created = created.Add(time.Minute)
+		return &task.Task{
+			UUID:      uuid.New(),
+			Status:    status,
+			DueTime:   time.Now().Add(48 * time.Hour),
+			CreatedAt: created,
+			Flag:      task.FlagActive,
+			Version:   1,
+			Rank:      rank,
+		}
+	}
+	first, second, third := newTask(task.StatusNew, "a"), newTask(task.StatusNew, "b"), newTask(task.StatusNew, "")
+	busy := newTask(task.StatusInProgress, "")
+
+	setup := func() *MockTaskRepository {
+		mockRepo := new(MockTaskRepository)
+		for _, tsk := range []*task.Task{first, second, third, busy} {
+			copied := *tsk
+			mockRepo.On("GetByID", mock.Anything, tsk.UUID).Return(&copied, nil)
+		}
+		// лишние статусы отбрасывает сервис
+		mockRepo.On("GetActiveByStatuses", mock.Anything, mock.Anything).
+			Return([]*task.Task{third, busy, second, first}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		return mockRepo
+	}
+
+	t.Run("reorder within column", func(t *testing.T) {
+		mockRepo := setup()
+		svc := service.NewTaskService(mockRepo, service.DBType, service.WithBoards(boards))
+
+		// third без ранга стоит после "b" по времени создания
+		result, err := svc.MoveTask(ctx, third.UUID, service.MoveRequest{Board: "dev", Column: "todo", After: &first.UUID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ai", result.Rank)
+		assert.Equal(t, task.StatusNew, result.Status)
+
+		_, columns, err := svc.GetBoard(ctx, "dev")
+		assert.NoError(t, err)
+		assert.Len(t, columns[0].Tasks, 3)
+	})
+
+	t.Run("wip limit", func(t *testing.T) {
+		mockRepo := setup()
+		svc := service.NewTaskService(mockRepo, service.DBType, service.WithBoards(boards))
+
+		_, err := svc.MoveTask(ctx, first.UUID, service.MoveRequest{Board: "dev", Column: "doing"})
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeWIPLimitExceeded, businessErr.Code)
+		assert.Equal(t, 1, businessErr.Details["wip_limit"])
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("move to another column", func(t *testing.T) {
+		mockRepo := setup()
+		svc := service.NewTaskService(mockRepo, service.DBType, service.WithBoards(boards))
+
+		result, err := svc.MoveTask(ctx, busy.UUID, service.MoveRequest{Board: "dev", Column: "done"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, task.StatusDone, result.Status)
+		assert.Equal(t, "i", result.Rank)
+
+		// смена колонки идёт через workflow: из in progress в new перехода нет
+		svc = service.NewTaskService(setup(), service.DBType, service.WithBoards(boards))
+		_, err = svc.MoveTask(ctx, busy.UUID, service.MoveRequest{Board: "dev", Column: "todo", Before: &first.UUID})
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeInvalidTransition, businessErr.Code)
+	})
+
+	t.Run("neighbour from another column", func(t *testing.T) {
+		mockRepo := setup()
+		svc := service.NewTaskService(mockRepo, service.DBType, service.WithBoards(boards))
+
+		_, err := svc.MoveTask(ctx, first.UUID, service.MoveRequest{Board: "dev", Column: "todo", After: &busy.UUID})
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeValidation, businessErr.Code)
+		assert.Equal(t, "after", businessErr.Details["field"])
+	})
+
+	t.Run("tied ranks", func(t *testing.T) {
+		left, right, moving := newTask(task.StatusNew, "c"), newTask(task.StatusNew, "c"), newTask(task.StatusNew, "a")
+		if left.UUID.String() > right.UUID.String() {
+			left, right = right, left
+		}
+
+		mockRepo := new(MockTaskRepository)
+		for _, tsk := range []*task.Task{left, right, moving} {
+			copied := *tsk
+			mockRepo.On("GetByID", mock.Anything, tsk.UUID).Return(&copied, nil)
+		}
+		mockRepo.On("GetActiveByStatuses", mock.Anything, mock.Anything).
+			Return([]*task.Task{left, right, moving}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		svc := service.NewTaskService(mockRepo, service.DBType, service.WithBoards(boards))
+
+		// между задачами с одним рангом места нет: их ранги разводятся
+		result, err := svc.MoveTask(ctx, moving.UUID, service.MoveRequest{Board: "dev", Column: "todo", After: &left.UUID, Before: &right.UUID})
+
+		assert.NoError(t, err)
+		var ranks []string
+		for _, call := range mockRepo.Calls {
+			if call.Method == "Update" {
+				ranks = append(ranks, call.Arguments.Get(1).(*task.Task).Rank)
+			}
+		}
+		assert.Len(t, ranks, 3)
+		assert.True(t, ranks[0] < result.Rank && result.Rank < ranks[1], ranks)
+	})
+
+	t.Run("same neighbour twice", func(t *testing.T) {
+		svc := service.NewTaskService(setup(), service.DBType, service.WithBoards(boards))
+		_, err := svc.MoveTask(ctx, third.UUID, service.MoveRequest{Board: "dev", Column: "todo", After: &first.UUID, Before: &first.UUID})
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeValidation, businessErr.Code)
+	})
+
+	t.Run("unknown board", func(t *testing.T) {
+		svc := service.NewTaskService(setup(), service.DBType, service.WithBoards(boards))
+		_, _, err := svc.GetBoard(ctx, "ops")
+
+		var businessErr *service.BusinessError
+		assert.True(t, errors.As(err, &businessErr))
+		assert.Equal(t, service.CodeNotFound, businessErr.Code)
+	})
+}
+
+// TestTaskService_PublishesEvents тестирует публикацию событий после успешных операций
+func TestTaskService_PublishesEvents(t *testing.T) {
+	ctx := context.Background()
+	taskID := uuid.New()
+
+	bus := events.NewBus()
+	var published []events.Type
+	bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		published = append(published, e.Type)
+		return nil
+	})
+
+	mockRepo := new(MockTaskRepository)
+	existingTask := &task.Task{
+		UUID:    taskID,
+		Status:  task.StatusNew,
+		DueTime: time.Now().Add(48 * time.Hour),
+		Flag:    task.FlagActive,
+		Version: 1,
+	}
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, taskID).Return(existingTask, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict).Once()
+
+	svc := service.NewTaskService(mockRepo, service.DBType, service.WithPublisher(bus))
+
+	_, err := svc.CreateTask(ctx, "Test", "Description", time.Now().Add(48*time.Hour))
+	assert.NoError(t, err)
+	_, err = svc.ArchiveTask(ctx, taskID)
+	assert.NoError(t, err)
+
+	// неудачная операция не публикует событие
+	_, err = svc.UnarchiveTask(ctx, taskID)
+	assert.Error(t, err)
+
+	assert.Equal(t, []events.Type{events.TaskCreated, events.TaskArchived}, published)
+}
+
 // TestTaskService_GetTaskByID тестирует получение задачи
 func TestTaskService_GetTaskByID(t *testing.T) {
 	ctx := context.Background()
@@ -419,6 +825,53 @@ func TestTaskService_GetTaskByID(t *testing.T) {
 	})
 }
 
+// TestTaskService_GetTasksByIDs тестирует получение нескольких задач одним запросом
+func TestTaskService_GetTasksByIDs(t *testing.T) {
+	ctx := context.Background()
+	active := &task.Task{UUID: uuid.New(), Flag: task.FlagActive, Status: task.StatusNew, DueTime: time.Now().Add(time.Hour)}
+	late := &task.Task{UUID: uuid.New(), Flag: task.FlagActive, Status: task.StatusNew, DueTime: time.Now().Add(-time.Hour)}
+	deleted := &task.Task{UUID: uuid.New(), Flag: task.FlagDeleted, DeletedAt: &time.Time{}}
+	missing := uuid.New()
+
+	t.Run("per-id results", func(t *testing.T) {
+		ids := []uuid.UUID{active.UUID, missing, deleted.UUID, late.UUID}
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("GetByIDs", mock.Anything, ids).Return([]*task.Task{late, deleted, active}, nil).Once()
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		tasks, errs := svc.GetTasksByIDs(ctx, ids)
+
+		require.Len(t, tasks, 4)
+		require.Len(t, errs, 4)
+		assert.NoError(t, errs[0])
+		assert.Equal(t, active.UUID, tasks[0].UUID)
+
+		var businessErr *service.BusinessError
+		require.ErrorAs(t, errs[1], &businessErr)
+		assert.Equal(t, "NOT_FOUND", businessErr.Code)
+		require.ErrorAs(t, errs[2], &businessErr)
+		assert.Equal(t, service.CodeTaskDeleted, businessErr.Code)
+		assert.Nil(t, tasks[2])
+
+		assert.NoError(t, errs[3])
+		assert.Equal(t, task.StatusOverdue, tasks[3].Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ids := []uuid.UUID{active.UUID, late.UUID}
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("GetByIDs", mock.Anything, ids).Return(nil, errors.New("db down"))
+
+		svc := service.NewTaskService(mockRepo, service.DBType)
+		_, errs := svc.GetTasksByIDs(ctx, ids)
+
+		require.Len(t, errs, 2)
+		assert.Error(t, errs[0])
+		assert.Error(t, errs[1])
+	})
+}
+
 // TestTaskService_DeleteTask тестирует удаление задачи
 func TestTaskService_DeleteTask(t *testing.T) {
 	ctx := context.Background()
@@ -759,3 +1212,90 @@ func TestTaskService_EdgeCases(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+// slowBoardRepo задерживает чтение колонки, чтобы параллельные перемещения
+// гарантированно пересеклись
+type slowBoardRepo struct {
+	*inmemory.TaskStorage
+}
+
+func (r slowBoardRepo) GetActiveByStatuses(ctx context.Context, statuses []task.Status) ([]*task.Task, error) {
+	tasks, err := r.TaskStorage.GetActiveByStatuses(ctx, statuses)
+	time.Sleep(10 * time.Millisecond)
+	return tasks, err
+}
+
+// TestTaskService_MoveTaskConcurrentWIP тестирует WIP-лимит при параллельных перемещениях
+func TestTaskService_MoveTaskConcurrentWIP(t *testing.T) {
+	ctx := context.Background()
+	boards, err := board.New(board.Config{Boards: []board.Board{{
+		ID: "dev",
+		Columns: []board.Column{
+			{ID: "todo", Statuses: []task.Status{task.StatusNew}},
+			{ID: "doing", Statuses: []task.Status{task.StatusInProgress}, WIPLimit: 1},
+		},
+	}}}, workflow.Default())
+	require.NoError(t, err)
+
+	repo := slowBoardRepo{inmemory.NewTaskStorage()}
+	svc := service.NewTaskService(repo, service.InMemoryType, service.WithBoards(boards))
+
+	var ids []uuid.UUID
+	for range 8 {
+		created, err := svc.CreateTask(ctx, "task", "", time.Now().Add(72*time.Hour))
+		require.NoError(t, err)
+		ids = append(ids, created.UUID)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		moved atomic.Int32
+	)
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.MoveTask(ctx, id, service.MoveRequest{Column: "doing"}); err == nil {
+				moved.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), moved.Load())
+}
+
+// TestTaskService_CreateTaskWIPLimit тестирует WIP-лимит для задачи, созданной сразу в работе
+func TestTaskService_CreateTaskWIPLimit(t *testing.T) {
+	ctx := context.Background()
+	boards, err := board.New(board.Config{Boards: []board.Board{{
+		ID: "dev",
+		Columns: []board.Column{
+			{ID: "todo", Statuses: []task.Status{task.StatusNew}},
+			{ID: "doing", Statuses: []task.Status{task.StatusInProgress}, WIPLimit: 1},
+		},
+	}}}, workflow.Default())
+	require.NoError(t, err)
+
+	repo := inmemory.NewTaskStorage()
+	svc := service.NewTaskService(repo, service.InMemoryType, service.WithBoards(boards))
+
+	// срок ближе суток - задача сразу в работе
+	urgent, err := svc.CreateTask(ctx, "urgent", "", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusInProgress, urgent.Status)
+
+	_, err = svc.CreateTask(ctx, "urgent too", "", time.Now().Add(time.Hour))
+	var businessErr *service.BusinessError
+	require.True(t, errors.As(err, &businessErr))
+	assert.Equal(t, service.CodeWIPLimitExceeded, businessErr.Code)
+	assert.Equal(t, "doing", businessErr.Details["column"])
+
+	all, err := repo.GetAllWithLimit(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	// в колонку без лимита задача создаётся как обычно
+	_, err = svc.CreateTask(ctx, "later", "", time.Now().Add(72*time.Hour))
+	assert.NoError(t, err)
+}
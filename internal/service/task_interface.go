@@ -14,7 +14,13 @@ type TaskRepository interface {
 	GetStatusedWithLimit(context.Context, int, int, task.Status) ([]*task.Task, error)
 	GetFlaggedWithLimit(context.Context, int, int, task.Flag) ([]*task.Task, error)
 	GetTasksDueBefore(context.Context, time.Time, int) ([]*task.Task, error)
+	// задачи с неотправленным напоминанием, которое пора отправить, по возрастанию момента отправки
+	GetTasksWithDueReminders(context.Context, time.Time, int) ([]*task.Task, error)
+	// все активные задачи с хранимым статусом из списка, для колонок доски
+	GetActiveByStatuses(context.Context, []task.Status) ([]*task.Task, error)
 	GetByID(context.Context, uuid.UUID) (*task.Task, error)
+	// задачи с ID из списка одним запросом; ненайденные ID пропускаются, порядок произвольный
+	GetByIDs(context.Context, []uuid.UUID) ([]*task.Task, error)
 	DeleteSoft(context.Context, *task.Task) error
 	DeleteFull(context.Context, uuid.UUID) error 
 	HealthCheck(context.Context) error
@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"taskTracker/internal/board"
+	"taskTracker/internal/events"
 	"taskTracker/internal/logger"
 	"taskTracker/internal/models/task"
 	"taskTracker/internal/repository"
+	"taskTracker/internal/workflow"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +19,50 @@ import (
 type TaskService struct {
 	Repo     TaskRepository
 	RepoType RepoType
+	Events   events.Publisher
+	// Workflows - workflow по умолчанию и workflow проектов
+	Workflows *workflow.Set
+	Boards    *board.Boards
+	// BoardLocker сериализует перемещения задач на доске
+	BoardLocker BoardLocker
+}
+
+type Option func(*TaskService)
+
+// WithPublisher включает публикацию событий жизненного цикла задач
+func WithPublisher(publisher events.Publisher) Option {
+	return func(s *TaskService) {
+		s.Events = publisher
+	}
+}
+
+// WithWorkflow заменяет workflow по умолчанию одним workflow для всех проектов
+func WithWorkflow(wf *workflow.Workflow) Option {
+	return func(s *TaskService) {
+		s.Workflows = workflow.Single(wf)
+	}
+}
+
+// WithWorkflows заменяет workflow по умолчанию и задаёт workflow проектов
+func WithWorkflows(workflows *workflow.Set) Option {
+	return func(s *TaskService) {
+		s.Workflows = workflows
+	}
+}
+
+// WithBoards заменяет доску по умолчанию
+func WithBoards(boards *board.Boards) Option {
+	return func(s *TaskService) {
+		s.Boards = boards
+	}
+}
+
+// WithBoardLocker заменяет блокировку досок внутри процесса, например на
+// блокировку в БД, общую для всех экземпляров сервиса
+func WithBoardLocker(locker BoardLocker) Option {
+	return func(s *TaskService) {
+		s.BoardLocker = locker
+	}
 }
 
 type RepoType string
@@ -22,11 +70,52 @@ type RepoType string
 const DBType RepoType = "DB"
 const InMemoryType RepoType = "IM"
 
-func NewTaskService(repo TaskRepository, repoType RepoType) TaskService {
-	return TaskService{
+// сколько повторений максимум отдаёт предпросмотр серии
+const maxOccurrencesPreview = 100
+
+func NewTaskService(repo TaskRepository, repoType RepoType, options ...Option) TaskService {
+	s := TaskService{
 		Repo:     repo,
 		RepoType: repoType,
 	}
+	for _, opt := range options {
+		opt(&s)
+	}
+	if s.Workflows == nil {
+		s.Workflows = workflow.Single(workflow.Default())
+	}
+	if s.Boards == nil {
+		s.Boards = board.Default(s.Workflows.Default())
+	}
+	if s.BoardLocker == nil {
+		s.BoardLocker = NewLocalBoardLocker()
+	}
+	return s
+}
+
+// track прикладывает к ctx события мутации. Хранилище с outbox сохранит их
+// в той же транзакции, что и изменение задачи
+func (s *TaskService) track(ctx context.Context, t *task.Task, types ...events.Type) context.Context {
+	pending := make([]events.Event, len(types))
+	for i, eventType := range types {
+		pending[i] = events.New(eventType, t)
+	}
+	return events.WithPending(ctx, pending...)
+}
+
+// publish сообщает подписчикам об уже сохранённом изменении, когда события
+// не доставляются через outbox. Ошибка публикации не откатывает операцию
+func (s *TaskService) publish(ctx context.Context, t *task.Task) {
+	if s.Events == nil {
+		return
+	}
+	for _, e := range events.Pending(ctx) {
+		if err := s.Events.Publish(ctx, e.WithTask(t)); err != nil {
+			logger.ErrorCtx(ctx, "Не удалось опубликовать событие", err,
+				zap.String("event", string(e.Type)),
+				zap.String("task_id", t.UUID.String()))
+		}
+	}
 }
 
 func (s *TaskService) HealthCheck(ctx context.Context) error {
@@ -50,7 +139,7 @@ func (s *TaskService) ArchiveTask(ctx context.Context, id uuid.UUID) (*task.Task
 	// Бизнес-правила архивации
 	if taskToArchive.Flag == task.FlagArchived {
 		return nil, NewBusinessError(
-			"ALREADY_ARCHIVED",
+			CodeAlreadyArchived,
 			"Задача уже находится в архиве",
 			ToDetail("task_id", id.String()),
 			ToDetail("archived_at", taskToArchive.UpdatedAt),
@@ -59,7 +148,7 @@ func (s *TaskService) ArchiveTask(ctx context.Context, id uuid.UUID) (*task.Task
 
 	if taskToArchive.Flag == task.FlagDeleted {
 		return nil, NewBusinessError(
-			"TASK_DELETED",
+			CodeTaskDeleted,
 			"Невозможно архивировать удаленную задачу",
 			ToDetail("task_id", id.String()),
 			ToDetail("deleted_at", taskToArchive.DeletedAt),
@@ -69,7 +158,7 @@ func (s *TaskService) ArchiveTask(ctx context.Context, id uuid.UUID) (*task.Task
 
 	if taskToArchive.Flag != task.FlagActive {
 		return nil, NewBusinessError(
-			"INVALID_FLAG",
+			CodeInvalidFlag,
 			fmt.Sprintf("Невозможно архивировать задачу с флагом '%s'", taskToArchive.Flag),
 			ToDetail("task_id", id.String()),
 			ToDetail("current_flag", taskToArchive.Flag),
@@ -82,10 +171,11 @@ func (s *TaskService) ArchiveTask(ctx context.Context, id uuid.UUID) (*task.Task
 	now := time.Now()
 	taskToArchive.UpdatedAt = &now
 
+	ctx = s.track(ctx, taskToArchive, events.TaskArchived)
 	if err := s.Repo.Update(ctx, taskToArchive); err != nil {
 		if err == repository.ErrVersionConflict {
 			return nil, NewBusinessError(
-				"VERSION_CONFLICT",
+				CodeVersionConflict,
 				"Задача была изменена другим пользователем",
 				ToDetail("task_id", id.String()),
 				ToDetail("suggestion", "Обновите страницу и попробуйте снова"),
@@ -94,6 +184,7 @@ func (s *TaskService) ArchiveTask(ctx context.Context, id uuid.UUID) (*task.Task
 		return nil, fmt.Errorf("обновление задачи при архивации: %w", err)
 	}
 
+	s.publish(ctx, taskToArchive)
 	return taskToArchive, nil
 }
 
@@ -110,7 +201,7 @@ func (s *TaskService) UnarchiveTask(ctx context.Context, id uuid.UUID) (*task.Ta
 	// Бизнес-правила разархивации
 	if taskToUnarchive.Flag == task.FlagActive {
 		return nil, NewBusinessError(
-			"NOT_ARCHIVED",
+			CodeNotArchived,
 			"Задача уже активна",
 			ToDetail("task_id", id.String()),
 		)
@@ -118,7 +209,7 @@ func (s *TaskService) UnarchiveTask(ctx context.Context, id uuid.UUID) (*task.Ta
 
 	if taskToUnarchive.Flag == task.FlagDeleted {
 		return nil, NewBusinessError(
-			"TASK_DELETED",
+			CodeTaskDeleted,
 			"Невозможно разархивировать удаленную задачу",
 			ToDetail("task_id", id.String()),
 			ToDetail("deleted_at", taskToUnarchive.DeletedAt),
@@ -128,7 +219,7 @@ func (s *TaskService) UnarchiveTask(ctx context.Context, id uuid.UUID) (*task.Ta
 
 	if taskToUnarchive.Flag != task.FlagArchived {
 		return nil, NewBusinessError(
-			"INVALID_FLAG",
+			CodeInvalidFlag,
 			fmt.Sprintf("Можно разархивировать только архивные задачи. Текущий флаг: '%s'", taskToUnarchive.Flag),
 			ToDetail("task_id", id.String()),
 			ToDetail("current_flag", taskToUnarchive.Flag),
@@ -141,10 +232,11 @@ func (s *TaskService) UnarchiveTask(ctx context.Context, id uuid.UUID) (*task.Ta
 	now := time.Now()
 	taskToUnarchive.UpdatedAt = &now
 
+	ctx = s.track(ctx, taskToUnarchive, events.TaskUnarchived)
 	if err := s.Repo.Update(ctx, taskToUnarchive); err != nil {
 		if err == repository.ErrVersionConflict {
 			return nil, NewBusinessError(
-				"VERSION_CONFLICT",
+				CodeVersionConflict,
 				"Задача была изменена другим пользователем",
 				ToDetail("task_id", id.String()),
 			)
@@ -152,6 +244,7 @@ func (s *TaskService) UnarchiveTask(ctx context.Context, id uuid.UUID) (*task.Ta
 		return nil, fmt.Errorf("обновление задачи при разархивации: %w", err)
 	}
 
+	s.publish(ctx, taskToUnarchive)
 	return taskToUnarchive, nil
 }
 
@@ -176,7 +269,7 @@ func (s *TaskService) RestoreTask(ctx context.Context, id uuid.UUID) (*task.Task
 
 	if taskToRestore.Flag != task.FlagDeleted {
 		return nil, NewBusinessError(
-			"NOT_DELETED",
+			CodeNotDeleted,
 			fmt.Sprintf("Задача не была удалена. Текущий флаг: '%s'", taskToRestore.Flag),
 			ToDetail("task_id", id.String()),
 			ToDetail("current_flag", taskToRestore.Flag),
@@ -187,7 +280,7 @@ func (s *TaskService) RestoreTask(ctx context.Context, id uuid.UUID) (*task.Task
 		restoreDeadline := taskToRestore.DeletedAt.Add(30 * 24 * time.Hour)
 		if time.Now().After(restoreDeadline) {
 			return nil, NewBusinessError(
-				"RESTORE_EXPIRED",
+				CodeRestoreExpired,
 				"Срок восстановления истек (30 дней)",
 				ToDetail("task_id", id.String()),
 				ToDetail("deleted_at", taskToRestore.DeletedAt),
@@ -201,10 +294,11 @@ func (s *TaskService) RestoreTask(ctx context.Context, id uuid.UUID) (*task.Task
 	now := time.Now()
 	taskToRestore.UpdatedAt = &now
 
+	ctx = s.track(ctx, taskToRestore, events.TaskRestored)
 	if err := s.Repo.Update(ctx, taskToRestore); err != nil {
 		if err == repository.ErrVersionConflict {
 			return nil, NewBusinessError(
-				"VERSION_CONFLICT",
+				CodeVersionConflict,
 				"Задача была изменена в корзине",
 				ToDetail("task_id", id.String()),
 			)
@@ -212,6 +306,7 @@ func (s *TaskService) RestoreTask(ctx context.Context, id uuid.UUID) (*task.Task
 		return nil, fmt.Errorf("восстановление задачи: %w", err)
 	}
 
+	s.publish(ctx, taskToRestore)
 	return taskToRestore, nil
 }
 
@@ -228,7 +323,7 @@ func (s *TaskService) PurgeTask(ctx context.Context, id uuid.UUID) error {
 
 	if taskToPurge.Flag != task.FlagDeleted {
 		return NewBusinessError(
-			"NOT_DELETED",
+			CodeNotDeleted,
 			fmt.Sprintf("Можно полностью удалять только удаленные задачи. Текущий флаг: '%s'", taskToPurge.Flag),
 			ToDetail("task_id", id.String()),
 			ToDetail("current_flag", taskToPurge.Flag),
@@ -237,10 +332,12 @@ func (s *TaskService) PurgeTask(ctx context.Context, id uuid.UUID) error {
 	}
 
 	// Полное удаление
+	ctx = s.track(ctx, taskToPurge, events.TaskPurged)
 	if err := s.Repo.DeleteFull(ctx, id); err != nil {
 		return fmt.Errorf("полное удаление задачи: %w", err)
 	}
 
+	s.publish(ctx, taskToPurge)
 	return nil
 }
 
@@ -256,7 +353,7 @@ func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
 
 	if taskToDelete.Flag == task.FlagDeleted {
 		return NewBusinessError(
-			"ALREADY_DELETED",
+			CodeAlreadyDeleted,
 			"Задача уже удалена",
 			ToDetail("task_id", id.String()),
 			ToDetail("deleted_at", taskToDelete.DeletedAt),
@@ -265,7 +362,7 @@ func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
 
 	if taskToDelete.Status == task.StatusInProgress {
 		return NewBusinessError(
-			"IN_PROGRESS",
+			CodeInProgress,
 			"Нельзя удалять задачу в процессе выполнения",
 			ToDetail("task_id", id.String()),
 			ToDetail("current_status", taskToDelete.Status),
@@ -278,10 +375,11 @@ func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
 	taskToDelete.DeletedAt = &now
 	taskToDelete.UpdatedAt = &now
 
+	ctx = s.track(ctx, taskToDelete, events.TaskDeleted)
 	if err := s.Repo.DeleteSoft(ctx, taskToDelete); err != nil {
 		if err == repository.ErrVersionConflict {
 			return NewBusinessError(
-				"VERSION_CONFLICT",
+				CodeVersionConflict,
 				"Задача была изменена другим пользователем",
 				ToDetail("task_id", id.String()),
 			)
@@ -289,11 +387,12 @@ func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("мягкое удаление задачи: %w", err)
 	}
 
+	s.publish(ctx, taskToDelete)
 	return nil
 }
 
 // POST /tasks
-func (s *TaskService) CreateTask(ctx context.Context, title, description string, dueTime time.Time) (*task.Task, error) {
+func (s *TaskService) CreateTask(ctx context.Context, title, description string, dueTime time.Time, options ...task.TaskOption) (*task.Task, error) {
 	// Бизнес-логика: если дедлайн близко, сразу ставим "в работе"
     now := time.Now()
 	status := task.StatusNew
@@ -314,16 +413,43 @@ func (s *TaskService) CreateTask(ctx context.Context, title, description string,
 		Version:     1,
 	}
 
+	for _, opt := range options {
+		opt(newTask)
+	}
+
+	if !s.workflowFor(newTask).Known(newTask.Status) {
+		return nil, NewValidationError("status", fmt.Sprintf("неизвестный статус '%s'", newTask.Status))
+	}
+
+	if newTask.RRule != "" {
+		if _, err := task.ParseRRule(newTask.RRule); err != nil {
+			return nil, NewValidationError("rrule", err.Error())
+		}
+	}
+
+	if err := validateEstimates(newTask); err != nil {
+		return nil, err
+	}
+	newTask.TrackProgress(newTask.CreatedAt)
+
+	ctx, unlock, err := s.admit(ctx, newTask, "")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ctx = s.track(ctx, newTask, events.TaskCreated)
 	if err := s.Repo.Create(ctx, newTask); err != nil {
 		return nil, fmt.Errorf("создание задачи: %w", err)
 	}
 
+	s.publish(ctx, newTask)
 	return newTask, nil
 }
 
 // PUT /tasks/{id}
 func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, options ...task.TaskOption) (*task.Task, error) {
-	taskToUpdate, err := s.Repo.GetByID(ctx, id)
+	stored, err := s.Repo.GetByID(ctx, id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return nil, NewNotFound(s.RepoType, id.String())
@@ -331,20 +457,58 @@ func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, options ...t
 		return nil, fmt.Errorf("получение задачи: %w", err)
 	}
 
-	if taskToUpdate.Flag != task.FlagActive {
+	if stored.Flag != task.FlagActive {
 		return nil, NewBusinessError(
-			"INVALID_FLAG",
-			fmt.Sprintf("Можно обновлять только активные задачи. Текущий флаг: '%s'", taskToUpdate.Flag),
+			CodeInvalidFlag,
+			fmt.Sprintf("Можно обновлять только активные задачи. Текущий флаг: '%s'", stored.Flag),
 			ToDetail("task_id", id.String()),
-			ToDetail("current_flag", taskToUpdate.Flag),
+			ToDetail("current_flag", stored.Flag),
 		)
 	}
 
+	previousStatus := stored.Status
+	// переход проверяется от статуса, который видит клиент: с истёкшим
+	// сроком это overdue, даже если в хранилище он ещё не проставлен
+	currentStatus := effectiveStatus(stored, time.Now())
+	previousRule := stored.RRule
+	previousDue := stored.DueTime
+	previousReminders := stored.Reminders
+
+	// опции применяются к копии: inmemory отдаёт саму хранимую задачу,
+	// а отклонённое обновление не должно её менять
+	updated := *stored
+	updated.Reminders = stored.Reminders.Clone()
+	taskToUpdate := &updated
+
 	for _, opt := range options {
 		opt(taskToUpdate)
 	}
 
-	if taskToUpdate.Status != task.StatusDone &&
+	// перенос срока заново взводит все напоминания, а при замене набора
+	// уже отправленные напоминания с тем же интервалом не повторяются
+	if !taskToUpdate.DueTime.Equal(previousDue) {
+		taskToUpdate.Reminders.Reset()
+	} else {
+		keepSentReminders(previousReminders, taskToUpdate.Reminders)
+	}
+
+	if taskToUpdate.RRule != "" && taskToUpdate.RRule != previousRule {
+		if _, err := task.ParseRRule(taskToUpdate.RRule); err != nil {
+			return nil, NewValidationError("rrule", err.Error())
+		}
+	}
+
+	if err := validateEstimates(taskToUpdate); err != nil {
+		return nil, err
+	}
+
+	if taskToUpdate.Status != previousStatus {
+		if err := s.checkTransition(currentStatus, taskToUpdate); err != nil {
+			return nil, err
+		}
+	}
+
+	if !taskToUpdate.Status.Closed() &&
 		taskToUpdate.DueTime.Before(time.Now()) {
 		taskToUpdate.Status = task.StatusOverdue
 	}
@@ -356,13 +520,48 @@ func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, options ...t
 		}
 	}
 
+	ctx, unlock, err := s.admit(ctx, taskToUpdate, currentStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Повторяющаяся задача при выполнении порождает следующее повторение
+	var nextTask *task.Task
+	if previousStatus != task.StatusDone &&
+		taskToUpdate.Status == task.StatusDone &&
+		taskToUpdate.RRule != "" {
+		nextTask, err = s.createNextOccurrence(ctx, taskToUpdate)
+		if err != nil {
+			return nil, err
+		}
+		// правило переходит к следующему повторению, чтобы повторное
+		// выполнение этой задачи не породило серию дважды
+		taskToUpdate.RRule = ""
+	}
+
 	now := time.Now()
 	taskToUpdate.UpdatedAt = &now
+	taskToUpdate.TrackProgress(now)
+
+	types := []events.Type{events.TaskUpdated}
+	if taskToUpdate.Flag == task.FlagArchived {
+		types = append(types, events.TaskArchived)
+	}
+	updateCtx := s.track(ctx, taskToUpdate, types...)
 
-	if err := s.Repo.Update(ctx, taskToUpdate); err != nil {
+	if err := s.Repo.Update(updateCtx, taskToUpdate); err != nil {
+		if nextTask != nil {
+			// созданное повторение уже могло попасть в outbox, поэтому откат тоже публикуется
+			purgeCtx := s.track(ctx, nextTask, events.TaskPurged)
+			if delErr := s.Repo.DeleteFull(purgeCtx, nextTask.UUID); delErr != nil {
+				logger.ErrorCtx(ctx, "Не удалось откатить создание следующего повторения", delErr,
+					zap.String("task_id", nextTask.UUID.String()))
+			}
+		}
 		if err == repository.ErrVersionConflict {
 			return nil, NewBusinessError(
-				"VERSION_CONFLICT",
+				CodeVersionConflict,
 				"Задача была изменена другим пользователем",
 				ToDetail("task_id", id.String()),
 			)
@@ -370,9 +569,179 @@ func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, options ...t
 		return nil, fmt.Errorf("обновление задачи: %w", err)
 	}
 
+	s.publish(updateCtx, taskToUpdate)
+	if nextTask != nil {
+		s.publish(s.track(ctx, nextTask, events.TaskCreated), nextTask)
+	}
+
 	return taskToUpdate, nil
 }
 
+func keepSentReminders(previous, current task.Reminders) {
+	for i := range current {
+		if current[i].SentAt != nil {
+			continue
+		}
+		for _, p := range previous {
+			if p.Before == current[i].Before {
+				current[i].SentAt = p.SentAt
+				current[i].ClaimedAt = p.ClaimedAt
+				break
+			}
+		}
+	}
+}
+
+// createNextOccurrence создаёт следующее повторение серии. Пропущенные
+// повторения (если задачу закрыли позже их срока) считаются израсходованными
+func (s *TaskService) createNextOccurrence(ctx context.Context, done *task.Task) (*task.Task, error) {
+	rule, err := task.ParseRRule(done.RRule)
+	if err != nil {
+		return nil, NewValidationError("rrule", err.Error())
+	}
+
+	after := time.Now()
+	if done.DueTime.After(after) {
+		after = done.DueTime
+	}
+
+	nextDue, index, ok := rule.Next(done.DueTime, after)
+	if !ok {
+		// серия закончилась по COUNT или UNTIL
+		return nil, nil
+	}
+	if rule.Count > 0 {
+		rule.Count -= index
+	}
+
+	nextTask := &task.Task{
+		UUID:        uuid.New(),
+		Title:       done.Title,
+		Description: done.Description,
+		Status:      task.StatusNew,
+		DueTime:     nextDue,
+		CreatedAt:   time.Now(),
+		Flag:        task.FlagActive,
+		Version:     1,
+		RRule:       rule.String(),
+		Reminders:   done.Reminders.Clone(),
+		// оставшаяся оценка у нового повторения снова равна исходной
+		OriginalEstimate: done.OriginalEstimate,
+	}
+	nextTask.Reminders.Reset()
+
+	if err := s.Repo.Create(s.track(ctx, nextTask, events.TaskCreated), nextTask); err != nil {
+		return nil, fmt.Errorf("создание следующего повторения: %w", err)
+	}
+
+	logger.InfoCtx(ctx, "Создано следующее повторение задачи",
+		zap.String("task_id", done.UUID.String()),
+		zap.String("next_task_id", nextTask.UUID.String()),
+		zap.Time("due_time", nextDue))
+	return nextTask, nil
+}
+
+// maxEstimate - верхняя граница оценки, защищает отчёты от опечаток
+const maxEstimate = 10000
+
+func validateEstimates(t *task.Task) error {
+	estimates := []struct {
+		field string
+		value *float64
+	}{
+		{"original_estimate", t.OriginalEstimate},
+		{"remaining_estimate", t.RemainingEstimate},
+	}
+	for _, e := range estimates {
+		if e.value != nil && (*e.value < 0 || *e.value > maxEstimate) {
+			return NewValidationError(e.field, fmt.Sprintf("должна быть от 0 до %d", maxEstimate))
+		}
+	}
+	return nil
+}
+
+// GET /tasks/{id}/occurrences
+func (s *TaskService) GetTaskOccurrences(ctx context.Context, id uuid.UUID, from, to time.Time) ([]time.Time, error) {
+	recurring, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurring.RRule == "" {
+		return nil, NewBusinessError(
+			CodeNotRecurring,
+			"Задача не является повторяющейся",
+			ToDetail("task_id", id.String()),
+		)
+	}
+
+	rule, err := task.ParseRRule(recurring.RRule)
+	if err != nil {
+		return nil, NewValidationError("rrule", err.Error())
+	}
+
+	return rule.Between(recurring.DueTime, from, to, maxOccurrencesPreview), nil
+}
+
+// workflowFor - workflow проекта задачи. Поля проекта у задачи пока нет,
+// поэтому ключ пустой и действует workflow по умолчанию
+func (s *TaskService) workflowFor(t *task.Task) *workflow.Workflow {
+	return s.Workflows.For("")
+}
+
+// checkTransition переводит ошибку workflow в бизнес-ошибку
+func (s *TaskService) checkTransition(from task.Status, t *task.Task) error {
+	err := s.workflowFor(t).Check(from, t)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, workflow.ErrUnknownStatus) {
+		return NewValidationError("status", fmt.Sprintf("неизвестный статус '%s'", t.Status))
+	}
+
+	var transitionErr *workflow.TransitionError
+	if !errors.As(err, &transitionErr) {
+		return err
+	}
+	message := fmt.Sprintf("Нельзя перевести задачу из '%s' в '%s'", from, t.Status)
+	details := []Detail{
+		ToDetail("task_id", t.UUID.String()),
+		ToDetail("from", from),
+		ToDetail("to", t.Status),
+		ToDetail("allowed", transitionErr.Allowed),
+	}
+	if transitionErr.Reason != "" {
+		message += ": " + transitionErr.Reason
+		details = append(details, ToDetail("reason", transitionErr.Reason))
+	}
+	return NewBusinessError(CodeInvalidTransition, message, details...)
+}
+
+// effectiveStatus - статус с учётом срока, как его отдаёт GetTaskByID
+func effectiveStatus(t *task.Task, now time.Time) task.Status {
+	if t.Flag == task.FlagActive && !t.Status.Closed() && t.DueTime.Before(now) {
+		return task.StatusOverdue
+	}
+	return t.Status
+}
+
+// GET /tasks/{id}/transitions
+func (s *TaskService) GetTaskTransitions(ctx context.Context, id uuid.UUID) (*task.Task, []workflow.Option, error) {
+	t, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := s.workflowFor(t).Available(t)
+	if t.Flag != task.FlagActive {
+		for i := range options {
+			options[i].Allowed = false
+			options[i].Reason = fmt.Sprintf("статус меняется только у активных задач, текущий флаг: '%s'", t.Flag)
+		}
+	}
+	return t, options, nil
+}
+
 // GET /tasks/{id}
 func (s *TaskService) GetTaskByID(ctx context.Context, id uuid.UUID) (*task.Task, error) {
 	taskGot, err := s.Repo.GetByID(ctx, id)
@@ -383,27 +752,63 @@ func (s *TaskService) GetTaskByID(ctx context.Context, id uuid.UUID) (*task.Task
 		return nil, fmt.Errorf("получение задачи: %w", err)
 	}
 
-	// Бизнес-правило: не отдаем удаленные через основной API
-	if taskGot.Flag == task.FlagDeleted {
-		return nil, NewBusinessError(
-			"TASK_DELETED",
+	if err := checkVisible(taskGot, time.Now()); err != nil {
+		return nil, err
+	}
+	return taskGot, nil
+}
+
+// GetTasksByIDs получает задачи одним запросом к хранилищу.
+// tasks[i] и errs[i] - результат для ids[i], как у GetTaskByID
+func (s *TaskService) GetTasksByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, []error) {
+	tasks := make([]*task.Task, len(ids))
+	errs := make([]error, len(ids))
+
+	got, err := s.Repo.GetByIDs(ctx, ids)
+	if err != nil {
+		for i := range errs {
+			errs[i] = fmt.Errorf("получение задач: %w", err)
+		}
+		return tasks, errs
+	}
+
+	byID := make(map[uuid.UUID]*task.Task, len(got))
+	for _, t := range got {
+		byID[t.UUID] = t
+	}
+
+	now := time.Now()
+	for i, id := range ids {
+		t, ok := byID[id]
+		if !ok {
+			errs[i] = NewNotFound(s.RepoType, id.String())
+			continue
+		}
+		if err := checkVisible(t, now); err != nil {
+			errs[i] = err
+			continue
+		}
+		tasks[i] = t
+	}
+	return tasks, errs
+}
+
+// checkVisible применяет правила выдачи задачи через основной API:
+// удалённые не отдаются, просроченные помечаются автоматически
+func checkVisible(t *task.Task, now time.Time) error {
+	if t.Flag == task.FlagDeleted {
+		return NewBusinessError(
+			CodeTaskDeleted,
 			"Задача была удалена",
-			ToDetail("task_id", id.String()),
-			ToDetail("deleted_at", taskGot.DeletedAt),
+			ToDetail("task_id", t.UUID.String()),
+			ToDetail("deleted_at", t.DeletedAt),
 			ToDetail("can_restore", true),
-			ToDetail("restore_url", fmt.Sprintf("/admin/tasks/%s/restore", id)),
+			ToDetail("restore_url", fmt.Sprintf("/admin/tasks/%s/restore", t.UUID)),
 		)
 	}
 
-	// Автоматически помечаем просроченные
-	if taskGot.Flag == task.FlagActive &&
-		taskGot.Status != task.StatusDone &&
-		taskGot.Status != task.StatusOverdue &&
-		taskGot.DueTime.Before(time.Now()) {
-		taskGot.Status = task.StatusOverdue
-	}
-
-	return taskGot, nil
+	t.Status = effectiveStatus(t, now)
+	return nil
 }
 
 // ТУТ НАДО ДОБАВИТ  ИНДЕКС
@@ -417,7 +822,7 @@ func (s *TaskService) GetOverdueTasks(ctx context.Context, page, limit int) ([]*
 		if t.Status != task.StatusOverdue {
 			t.Status = task.StatusOverdue
 			if err := s.Repo.Update(ctx, t); err != nil {
-				logger.Warn("Не удалось обновить статус задачи как просроченной",
+				logger.WarnCtx(ctx, "Не удалось обновить статус задачи как просроченной",
 					zap.String("task_id", t.UUID.String()),
 					zap.Error(err))
 			}
@@ -454,7 +859,7 @@ func (s *TaskService) GetActiveTasks(ctx context.Context, page, limit int) ([]*t
 
 	now := time.Now()
 	for _, t := range tasks {
-		if t.Status != task.StatusDone &&
+		if !t.Status.Closed() &&
 			t.Status != task.StatusOverdue &&
 			t.DueTime.Before(now) {
 			t.Status = task.StatusOverdue
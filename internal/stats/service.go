@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"taskTracker/internal/service"
+	"time"
+)
+
+// самый длинный интервал рядов и самый длинный список просроченных
+const (
+	maxRange = 366 * DayLength
+	maxTop   = 100
+)
+
+// Service считает статистику в хранилище и держит ответы TTL: дашборды
+// опрашивают /stats часто, а агрегаты по всей таблице дорогие
+type Service struct {
+	source Source
+	ttl    time.Duration
+
+	mtx   sync.Mutex
+	cache map[cacheKey]cached
+}
+
+// cacheKey - запрос без монотонных часов и зоны, чтобы равные запросы совпадали
+type cacheKey struct {
+	from, to int64
+	top      int
+}
+
+type cached struct {
+	report  *Report
+	expires time.Time
+}
+
+// NewService - ttl 0 отключает кэш
+func NewService(source Source, ttl time.Duration) *Service {
+	return &Service{
+		source: source,
+		ttl:    ttl,
+		cache:  make(map[cacheKey]cached),
+	}
+}
+
+// TTL - сколько ответ остаётся актуальным, для Cache-Control
+func (s *Service) TTL() time.Duration {
+	return s.ttl
+}
+
+// GET /stats
+// Интервал расширяется до целых суток UTC
+func (s *Service) Stats(ctx context.Context, q Query) (*Report, error) {
+	q.From, q.To = WholeDays(q.From, q.To)
+	if err := validate(q); err != nil {
+		return nil, err
+	}
+
+	key := cacheKey{from: q.From.Unix(), to: q.To.Unix(), top: q.Top}
+	now := time.Now()
+	if report, ok := s.lookup(key, now); ok {
+		return report, nil
+	}
+
+	agg, err := s.source.TaskStats(ctx, q, now)
+	if err != nil {
+		return nil, fmt.Errorf("подсчёт статистики: %w", err)
+	}
+
+	report := &Report{
+		From:         q.From,
+		To:           q.To,
+		Counts:       agg.Counts,
+		OverdueRatio: OverdueRatio(agg.Counts),
+		Days:         Days(q.From, q.To, agg.Created, agg.Completed),
+		LeadTime:     agg.LeadTime,
+		CycleTime:    agg.CycleTime,
+		TopOverdue:   agg.TopOverdue,
+		GeneratedAt:  now,
+	}
+	s.store(key, report, now)
+	return report, nil
+}
+
+func (s *Service) lookup(key cacheKey, now time.Time) (*Report, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entry, ok := s.cache[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.report, true
+}
+
+func (s *Service) store(key cacheKey, report *Report, now time.Time) {
+	if s.ttl <= 0 {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// устаревшие ответы выбрасываются при записи, чтобы разные интервалы
+	// не копились в памяти
+	for k, entry := range s.cache {
+		if !now.Before(entry.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cached{report: report, expires: now.Add(s.ttl)}
+}
+
+func validate(q Query) error {
+	if !q.To.After(q.From) {
+		return service.NewValidationError("to", "должен быть позже from")
+	}
+	if q.To.Sub(q.From) > maxRange {
+		return service.NewValidationError("to", fmt.Sprintf("интервал статистики не больше %d дней", maxRange/DayLength))
+	}
+	if q.Top < 0 || q.Top > maxTop {
+		return service.NewValidationError("top", fmt.Sprintf("должен быть от 0 до %d", maxTop))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"context"
+	"taskTracker/internal/models/task"
+	"time"
+)
+
+// DayLayout - ключ дня в рядах созданных и выполненных задач, сутки UTC
+const DayLayout = time.DateOnly
+
+// DayLength - сутки; ряды статистики и отчёты истории считаются по суткам UTC
+const DayLength = 24 * time.Hour
+
+// WholeDays расширяет интервал до целых суток UTC: from - вниз, to - вверх
+func WholeDays(from, to time.Time) (time.Time, time.Time) {
+	from, to = from.UTC(), to.UTC()
+	if truncated := to.Truncate(DayLength); truncated.Before(to) {
+		to = truncated.Add(DayLength)
+	}
+	return from.Truncate(DayLength), to
+}
+
+// Query - интервал [From, To) для рядов по дням и размер списка просроченных
+type Query struct {
+	From time.Time
+	To   time.Time
+	Top  int
+}
+
+// Durations - сумма длительностей и число задач, из которых она сложена
+type Durations struct {
+	Sum   time.Duration
+	Tasks int
+}
+
+func (d Durations) Add(other Durations) Durations {
+	return Durations{Sum: d.Sum + other.Sum, Tasks: d.Tasks + other.Tasks}
+}
+
+// Average - средняя длительность, ноль без задач
+func (d Durations) Average() time.Duration {
+	if d.Tasks == 0 {
+		return 0
+	}
+	return d.Sum / time.Duration(d.Tasks)
+}
+
+// Aggregates - то, что хранилище считает за один запрос статистики.
+// Удалённые задачи не учитываются нигде, кроме Counts
+type Aggregates struct {
+	Counts task.Counts
+	// Created и Completed - число задач по дням DayLayout внутри интервала
+	Created   map[string]int
+	Completed map[string]int
+	// LeadTime - от создания до выполнения, CycleTime - от начала работы
+	// до выполнения. Считаются по задачам, выполненным внутри интервала
+	LeadTime  Durations
+	CycleTime Durations
+	// TopOverdue - активные невыполненные задачи с прошедшим сроком,
+	// самые давние первыми
+	TopOverdue []*task.Task
+}
+
+// Source - хранилище, умеющее посчитать статистику на своей стороне
+type Source interface {
+	TaskStats(ctx context.Context, q Query, now time.Time) (*Aggregates, error)
+}
+
+// Day - созданные и выполненные задачи за сутки UTC
+type Day struct {
+	Day       time.Time
+	Created   int
+	Completed int
+}
+
+// Report - ответ GET /stats
+type Report struct {
+	From   time.Time
+	To     time.Time
+	Counts task.Counts
+	// OverdueRatio - доля просроченных среди активных невыполненных задач
+	OverdueRatio float64
+	Days         []Day
+	LeadTime     Durations
+	CycleTime    Durations
+	TopOverdue   []*task.Task
+	GeneratedAt  time.Time
+}
+
+// Days раскладывает счётчики по всем суткам [from, to), включая пустые
+func Days(from, to time.Time, created, completed map[string]int) []Day {
+	days := []Day{}
+	for d := from; d.Before(to); d = d.Add(DayLength) {
+		key := d.Format(DayLayout)
+		days = append(days, Day{Day: d, Created: created[key], Completed: completed[key]})
+	}
+	return days
+}
+
+// OverdueRatio - Overdue относительно активных задач в незакрытых статусах
+func OverdueRatio(counts task.Counts) float64 {
+	open := 0
+	for status, n := range counts.ByFlagStatus[task.FlagActive] {
+		if !status.Closed() {
+			open += n
+		}
+	}
+	if open == 0 {
+		return 0
+	}
+	return float64(counts.Overdue) / float64(open)
+}
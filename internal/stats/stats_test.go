@@ -0,0 +1,166 @@
+package stats_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"taskTracker/internal/stats"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// countingSource отдаёт заданные агрегаты и считает обращения
+type countingSource struct {
+	agg   stats.Aggregates
+	calls int
+	last  stats.Query
+}
+
+func (c *countingSource) TaskStats(ctx context.Context, q stats.Query, now time.Time) (*stats.Aggregates, error) {
+	c.calls++
+	c.last = q
+	agg := c.agg
+	return &agg, nil
+}
+
+func day(s string) time.Time {
+	d, _ := time.Parse(stats.DayLayout, s)
+	return d
+}
+
+// TestService_Report тестирует интервал по суткам, пустые дни и долю просроченных
+func TestService_Report(t *testing.T) {
+	source := &countingSource{}
+	source.agg.Counts.Add(task.FlagActive, task.StatusNew, 3)
+	source.agg.Counts.Add(task.FlagActive, task.StatusDone, 5)
+	source.agg.Counts.Add(task.FlagArchived, task.StatusNew, 2)
+	source.agg.Counts.Overdue = 1
+	source.agg.Created = map[string]int{"2026-03-02": 4}
+	source.agg.Completed = map[string]int{"2026-03-03": 2}
+	source.agg.LeadTime = stats.Durations{Sum: 6 * time.Hour, Tasks: 2}
+
+	svc := stats.NewService(source, time.Minute)
+	report, err := svc.Stats(context.Background(), stats.Query{
+		From: day("2026-03-02").Add(5 * time.Hour),
+		To:   day("2026-03-04").Add(time.Hour),
+		Top:  10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, day("2026-03-02"), source.last.From)
+	assert.Equal(t, day("2026-03-05"), source.last.To)
+	require.Len(t, report.Days, 3)
+	assert.Equal(t, stats.Day{Day: day("2026-03-02"), Created: 4}, report.Days[0])
+	assert.Equal(t, stats.Day{Day: day("2026-03-03"), Completed: 2}, report.Days[1])
+	assert.Equal(t, stats.Day{Day: day("2026-03-04")}, report.Days[2])
+	// закрытые и архивные задачи в знаменатель не входят
+	assert.InDelta(t, 1.0/3, report.OverdueRatio, 1e-9)
+	assert.Equal(t, 3*time.Hour, report.LeadTime.Average())
+	assert.Zero(t, report.CycleTime.Average())
+}
+
+// TestService_Cache тестирует повторное использование ответа в пределах TTL
+func TestService_Cache(t *testing.T) {
+	ctx := context.Background()
+	q := stats.Query{From: time.Now().Add(-24 * time.Hour), To: time.Now(), Top: 5}
+
+	source := &countingSource{}
+	svc := stats.NewService(source, time.Minute)
+	first, err := svc.Stats(ctx, q)
+	require.NoError(t, err)
+	second, err := svc.Stats(ctx, q)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, source.calls)
+
+	// другой размер списка - другой ответ
+	q.Top = 6
+	_, err = svc.Stats(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, 2, source.calls)
+
+	uncached := stats.NewService(source, 0)
+	_, err = uncached.Stats(ctx, q)
+	require.NoError(t, err)
+	_, err = uncached.Stats(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, 4, source.calls)
+}
+
+// TestService_Validation тестирует границы интервала и размера списка
+func TestService_Validation(t *testing.T) {
+	svc := stats.NewService(&countingSource{}, time.Minute)
+	now := time.Now()
+
+	cases := map[string]stats.Query{
+		"to":  {From: now, To: now.Add(-2 * stats.DayLength)},
+		"top": {From: now.Add(-time.Hour), To: now, Top: 101},
+	}
+	for field, q := range cases {
+		_, err := svc.Stats(context.Background(), q)
+		var validation *service.BusinessError
+		require.True(t, errors.As(err, &validation), field)
+		assert.Equal(t, service.CodeValidation, validation.Code)
+		assert.Equal(t, field, validation.Details["field"])
+	}
+
+	_, err := svc.Stats(context.Background(), stats.Query{From: now.Add(-400 * 24 * time.Hour), To: now})
+	assert.Error(t, err)
+}
+
+// TestService_InMemory тестирует статистику поверх хранилища в памяти и
+// отметки начала и выполнения, которые ставит сервис задач
+func TestService_InMemory(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewTaskStorage()
+	tasks := service.NewTaskService(repo, "inmemory")
+
+	done, err := tasks.CreateTask(ctx, "done", "", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = tasks.UpdateTask(ctx, done.UUID, task.WithStatus(task.StatusInProgress))
+	require.NoError(t, err)
+	done, err = tasks.UpdateTask(ctx, done.UUID, task.WithStatus(task.StatusDone))
+	require.NoError(t, err)
+	require.NotNil(t, done.StartedAt)
+	require.NotNil(t, done.CompletedAt)
+
+	late, err := tasks.CreateTask(ctx, "late", "", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	late.DueTime = time.Now().Add(-time.Hour)
+	require.NoError(t, repo.Update(ctx, late))
+
+	report, err := stats.NewService(repo, 0).Stats(ctx, stats.Query{
+		From: time.Now().Add(-24 * time.Hour),
+		To:   time.Now().Add(24 * time.Hour),
+		Top:  10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Counts.Overdue)
+	assert.Equal(t, 1.0, report.OverdueRatio)
+	assert.Equal(t, 1, report.LeadTime.Tasks)
+	assert.Equal(t, 1, report.CycleTime.Tasks)
+	require.Len(t, report.TopOverdue, 1)
+	assert.Equal(t, late.UUID, report.TopOverdue[0].UUID)
+
+	created, completed := 0, 0
+	for _, d := range report.Days {
+		created += d.Created
+		completed += d.Completed
+	}
+	assert.Equal(t, 2, created)
+	assert.Equal(t, 1, completed)
+}
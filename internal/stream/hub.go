@@ -0,0 +1,225 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"taskTracker/internal/events"
+	"time"
+)
+
+// Message - событие с порядковым номером потока, который клиент
+// присылает обратно в Last-Event-ID при переподключении
+type Message struct {
+	ID    uint64
+	Event events.Event
+}
+
+// Filter отбирает события для одного клиента, nil пропускает всё
+type Filter func(events.Event) bool
+
+type HubOptions struct {
+	// ReplaySize - сколько последних событий хранится для переподключений
+	ReplaySize int
+	// ClientBuffer - сколько событий может ждать отправки одному клиенту
+	ClientBuffer int
+}
+
+// Hub раздаёт события шины подключённым клиентам потока.
+//
+// Последние ReplaySize событий хранятся в кольцевом буфере, чтобы клиент
+// после обрыва мог дочитать пропущенное. Клиент, который не успевает
+// забирать события, отключается: его буфер не блокирует шину, а пропуск
+// он восполнит из буфера при переподключении
+type Hub struct {
+	opts HubOptions
+	// epoch отличает номера событий разных запусков процесса
+	epoch string
+
+	mtx     sync.Mutex
+	seq     uint64
+	replay  []Message
+	head    int
+	clients map[*Subscription]struct{}
+	closed  bool
+}
+
+// Subscription - подключение одного клиента
+type Subscription struct {
+	hub *Hub
+	// from - номер последнего события на момент подключения
+	from   uint64
+	filter Filter
+	ch     chan Message
+	once   sync.Once
+}
+
+func NewHub(opts HubOptions) *Hub {
+	if opts.ReplaySize <= 0 {
+		opts.ReplaySize = 1000
+	}
+	if opts.ClientBuffer <= 0 {
+		opts.ClientBuffer = 64
+	}
+
+	return &Hub{
+		opts:    opts,
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		replay:  make([]Message, 0, opts.ReplaySize),
+		clients: make(map[*Subscription]struct{}),
+	}
+}
+
+// Handle подписывается на шину событий
+func (h *Hub) Handle(ctx context.Context, e events.Event) error {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if h.closed {
+		return nil
+	}
+
+	h.seq++
+	msg := Message{ID: h.seq, Event: e}
+	if len(h.replay) < h.opts.ReplaySize {
+		h.replay = append(h.replay, msg)
+	} else {
+		h.replay[h.head] = msg
+		h.head = (h.head + 1) % h.opts.ReplaySize
+	}
+
+	for sub := range h.clients {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			// клиент не успевает, отключаем его, чтобы не держать шину
+			h.removeLocked(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe подключает клиента. Если lastID не ноль, возвращаются события
+// после него из буфера; complete == false, если часть событий уже вытеснена
+// из буфера и клиенту нужно перечитать состояние целиком
+func (h *Hub) Subscribe(lastID uint64, filter Filter) (sub *Subscription, replay []Message, complete bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	sub = &Subscription{
+		hub:    h,
+		from:   h.seq,
+		filter: filter,
+		ch:     make(chan Message, h.opts.ClientBuffer),
+	}
+	if h.closed {
+		sub.once.Do(func() { close(sub.ch) })
+		return sub, nil, true
+	}
+	h.clients[sub] = struct{}{}
+
+	if lastID > h.seq {
+		return sub, nil, false
+	}
+	complete = true
+	if lastID == 0 || lastID == h.seq {
+		return sub, nil, complete
+	}
+
+	ordered := h.orderedLocked()
+	if len(ordered) > 0 && ordered[0].ID > lastID+1 {
+		complete = false
+	}
+	for _, msg := range ordered {
+		if msg.ID <= lastID {
+			continue
+		}
+		if filter != nil && !filter(msg.Event) {
+			continue
+		}
+		replay = append(replay, msg)
+	}
+	return sub, replay, complete
+}
+
+func (h *Hub) orderedLocked() []Message {
+	ordered := make([]Message, 0, len(h.replay))
+	ordered = append(ordered, h.replay[h.head:]...)
+	return append(ordered, h.replay[:h.head]...)
+}
+
+// Epoch - идентификатор запуска, номера событий сравнимы только в его пределах
+func (h *Hub) Epoch() string {
+	return h.epoch
+}
+
+// EventID формирует идентификатор события для Last-Event-ID
+func (h *Hub) EventID(id uint64) string {
+	return h.epoch + "-" + strconv.FormatUint(id, 10)
+}
+
+// ParseEventID разбирает Last-Event-ID. ok == false, если идентификатор
+// выдан другим запуском процесса или повреждён
+func (h *Hub) ParseEventID(value string) (id uint64, ok bool) {
+	epoch, seq, found := strings.Cut(value, "-")
+	if !found || epoch != h.epoch {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// LastID - номер последнего события, отданного хабу
+func (h *Hub) LastID() uint64 {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.seq
+}
+
+// Clients - число подключённых клиентов
+func (h *Hub) Clients() int {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return len(h.clients)
+}
+
+// Close отключает всех клиентов, после него новые события не раздаются.
+// Нужен до остановки сервера: иначе долгие потоки не дадут ему завершиться
+func (h *Hub) Close() {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.closed = true
+	for sub := range h.clients {
+		h.removeLocked(sub)
+	}
+}
+
+func (h *Hub) removeLocked(sub *Subscription) {
+	delete(h.clients, sub)
+	sub.once.Do(func() { close(sub.ch) })
+}
+
+// From - номер последнего события на момент подключения
+func (s *Subscription) From() uint64 {
+	return s.from
+}
+
+// Messages закрывается, когда клиент отключён хабом
+func (s *Subscription) Messages() <-chan Message {
+	return s.ch
+}
+
+// Close отключает клиента, повторный вызов безопасен
+func (s *Subscription) Close() {
+	s.hub.mtx.Lock()
+	defer s.hub.mtx.Unlock()
+	s.hub.removeLocked(s)
+}
@@ -0,0 +1,268 @@
+package stream_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"taskTracker/internal/events"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/middleware"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/stream"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func newEvent(eventType events.Type, flag task.Flag) events.Event {
+	return events.New(eventType, &task.Task{
+		UUID:   uuid.New(),
+		Title:  "Задача",
+		Status: task.StatusNew,
+		Flag:   flag,
+	})
+}
+
+func publish(hub *stream.Hub, n int) []events.Event {
+	published := make([]events.Event, n)
+	for i := range published {
+		published[i] = newEvent(events.TaskCreated, task.FlagActive)
+		hub.Handle(context.Background(), published[i])
+	}
+	return published
+}
+
+// TestHub_Broadcast тестирует раздачу событий клиентам с учётом фильтра
+func TestHub_Broadcast(t *testing.T) {
+	hub := stream.NewHub(stream.HubOptions{})
+
+	all, _, _ := hub.Subscribe(0, nil)
+	defer all.Close()
+	archived, _, _ := hub.Subscribe(0, func(e events.Event) bool {
+		return e.Task.Flag == task.FlagArchived
+	})
+	defer archived.Close()
+
+	hub.Handle(context.Background(), newEvent(events.TaskCreated, task.FlagActive))
+	hub.Handle(context.Background(), newEvent(events.TaskArchived, task.FlagArchived))
+
+	first := <-all.Messages()
+	second := <-all.Messages()
+	assert.Equal(t, uint64(1), first.ID)
+	assert.Equal(t, uint64(2), second.ID)
+
+	msg := <-archived.Messages()
+	assert.Equal(t, events.TaskArchived, msg.Event.Type)
+	assert.Len(t, archived.Messages(), 0)
+}
+
+// TestHub_Replay тестирует дочитывание пропущенных событий из буфера
+func TestHub_Replay(t *testing.T) {
+	hub := stream.NewHub(stream.HubOptions{ReplaySize: 3})
+	published := publish(hub, 5)
+
+	t.Run("events after last id", func(t *testing.T) {
+		sub, replay, complete := hub.Subscribe(3, nil)
+		defer sub.Close()
+
+		assert.True(t, complete)
+		require.Len(t, replay, 2)
+		assert.Equal(t, published[3].ID, replay[0].Event.ID)
+		assert.Equal(t, published[4].ID, replay[1].Event.ID)
+	})
+
+	t.Run("evicted events", func(t *testing.T) {
+		sub, replay, complete := hub.Subscribe(1, nil)
+		defer sub.Close()
+
+		assert.False(t, complete)
+		assert.Len(t, replay, 3)
+	})
+
+	t.Run("unknown future id", func(t *testing.T) {
+		sub, replay, complete := hub.Subscribe(100, nil)
+		defer sub.Close()
+
+		assert.False(t, complete)
+		assert.Empty(t, replay)
+	})
+}
+
+// TestHub_EventID тестирует формат идентификаторов событий
+func TestHub_EventID(t *testing.T) {
+	hub := stream.NewHub(stream.HubOptions{})
+
+	id, ok := hub.ParseEventID(hub.EventID(42))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), id)
+
+	other := stream.NewHub(stream.HubOptions{})
+	_, ok = hub.ParseEventID(other.EventID(42))
+	assert.False(t, ok)
+
+	_, ok = hub.ParseEventID("garbage")
+	assert.False(t, ok)
+}
+
+// TestHub_SlowClient тестирует отключение клиента с переполненным буфером
+func TestHub_SlowClient(t *testing.T) {
+	hub := stream.NewHub(stream.HubOptions{ClientBuffer: 2})
+
+	sub, _, _ := hub.Subscribe(0, nil)
+	publish(hub, 3)
+
+	assert.Equal(t, 0, hub.Clients())
+	<-sub.Messages()
+	<-sub.Messages()
+	_, ok := <-sub.Messages()
+	assert.False(t, ok)
+}
+
+// TestHub_Close тестирует отключение всех клиентов при остановке
+func TestHub_Close(t *testing.T) {
+	hub := stream.NewHub(stream.HubOptions{})
+	sub, _, _ := hub.Subscribe(0, nil)
+
+	hub.Close()
+	_, ok := <-sub.Messages()
+	assert.False(t, ok)
+
+	sub.Close()
+	assert.Equal(t, 0, hub.Clients())
+}
+
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// readEvents читает n событий SSE, пропуская комментарии
+func readEvents(t *testing.T, reader *bufio.Reader, n int) []sseEvent {
+	t.Helper()
+
+	var res []sseEvent
+	current := sseEvent{}
+	for len(res) < n {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "":
+			if current.event != "" {
+				res = append(res, current)
+			}
+			current = sseEvent{}
+		case strings.HasPrefix(line, "id: "):
+			current.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			current.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	return res
+}
+
+func newStreamServer(hub *stream.Hub) *httptest.Server {
+	handler := handlers.NewStreamHandler(hub, 50*time.Millisecond)
+	server := httptest.NewUnstartedServer(middleware.Logging(http.HandlerFunc(handler.GetEventStream)))
+	// поток должен переживать WriteTimeout сервера
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	return server
+}
+
+func connect(t *testing.T, ctx context.Context, url, lastEventID string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+// TestStreamHandler тестирует поток событий через HTTP
+func TestStreamHandler(t *testing.T) {
+	hub := stream.NewHub(stream.HubOptions{})
+	server := newStreamServer(hub)
+	defer server.Close()
+
+	t.Run("live events survive write timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		resp := connect(t, ctx, server.URL+"?flag=archived", "")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		require.Eventually(t, func() bool { return hub.Clients() == 1 }, time.Second, 10*time.Millisecond)
+
+		time.Sleep(200 * time.Millisecond)
+		hub.Handle(context.Background(), newEvent(events.TaskCreated, task.FlagActive))
+		archived := newEvent(events.TaskArchived, task.FlagArchived)
+		hub.Handle(context.Background(), archived)
+
+		received := readEvents(t, bufio.NewReader(resp.Body), 1)
+		assert.Equal(t, string(events.TaskArchived), received[0].event)
+		assert.Contains(t, received[0].data, archived.ID.String())
+
+		cancel()
+		require.Eventually(t, func() bool { return hub.Clients() == 0 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("resume with Last-Event-ID", func(t *testing.T) {
+		last := hub.LastID()
+		published := publish(hub, 2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		resp := connect(t, ctx, server.URL, hub.EventID(last))
+		defer resp.Body.Close()
+
+		received := readEvents(t, bufio.NewReader(resp.Body), 2)
+		assert.Contains(t, received[0].data, published[0].ID.String())
+		assert.Contains(t, received[1].data, published[1].ID.String())
+		assert.Equal(t, hub.EventID(last+2), received[1].id)
+	})
+
+	t.Run("reset for unknown id", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		resp := connect(t, ctx, server.URL, "old-7")
+		defer resp.Body.Close()
+
+		received := readEvents(t, bufio.NewReader(resp.Body), 1)
+		assert.Equal(t, handlers.ResetEvent, received[0].event)
+		assert.Equal(t, hub.EventID(hub.LastID()), received[0].id)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		for _, query := range []string{"?flag=unknown", "?status=unknown", "?types=task.unknown", "?project=x"} {
+			resp := connect(t, context.Background(), server.URL+query, "")
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PgxTracer - pgx.QueryTracer: спан на каждый SQL-запрос с текстом
+// запроса. Значения параметров в атрибуты не попадают
+type PgxTracer struct{}
+
+func NewPgxTracer() *PgxTracer {
+	return &PgxTracer{}
+}
+
+func (t *PgxTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	statement := compactSQL(data.SQL)
+	operation := sqlOperation(statement)
+
+	ctx, _ = tracer().Start(ctx, "db."+strings.ToLower(operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBQueryText(statement),
+			semconv.DBOperationName(operation),
+		))
+	return ctx
+}
+
+func (t *PgxTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(semconv.DBResponseReturnedRows(int(data.CommandTag.RowsAffected())))
+	}
+	recordError(span, data.Err)
+	span.End()
+}
+
+// compactSQL схлопывает отступы многострочных запросов в пробелы
+func compactSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// sqlOperation - первое слово запроса: SELECT, INSERT, ...
+func sqlOperation(statement string) string {
+	operation, _, _ := strings.Cut(statement, " ")
+	return strings.ToUpper(operation)
+}
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Repository открывает спан repository.<метод> на каждую операцию хранилища.
+// Для PostgreSQL запросы внутри операции дополнительно видны через PgxTracer
+type Repository struct {
+	repo    service.TaskRepository
+	backend string
+}
+
+func NewRepository(repo service.TaskRepository, backend string) *Repository {
+	return &Repository{repo: repo, backend: backend}
+}
+
+func (r *Repository) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemNameKey.String(r.backend), semconv.DBOperationName(method))
+	return tracer().Start(ctx, "repository."+method,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...))
+}
+
+func endRows(span trace.Span, rows int, err error) {
+	if err == nil {
+		span.SetAttributes(semconv.DBResponseReturnedRows(rows))
+	}
+	end(span, err)
+}
+
+func (r *Repository) Create(ctx context.Context, t *task.Task) (err error) {
+	ctx, span := r.start(ctx, "Create", taskIDKey.String(t.UUID.String()))
+	defer func() { end(span, err) }()
+	return r.repo.Create(ctx, t)
+}
+
+func (r *Repository) Update(ctx context.Context, t *task.Task) (err error) {
+	ctx, span := r.start(ctx, "Update", taskIDKey.String(t.UUID.String()))
+	defer func() { end(span, err) }()
+	return r.repo.Update(ctx, t)
+}
+
+func (r *Repository) GetAllWithLimit(ctx context.Context, page, limit int) (tasks []*task.Task, err error) {
+	ctx, span := r.start(ctx, "GetAllWithLimit", pageKey.Int(page), limitKey.Int(limit))
+	defer func() { endRows(span, len(tasks), err) }()
+	return r.repo.GetAllWithLimit(ctx, page, limit)
+}
+
+func (r *Repository) GetStatusedWithLimit(ctx context.Context, page, limit int, status task.Status) (tasks []*task.Task, err error) {
+	ctx, span := r.start(ctx, "GetStatusedWithLimit", pageKey.Int(page), limitKey.Int(limit),
+		attribute.String("task.status", string(status)))
+	defer func() { endRows(span, len(tasks), err) }()
+	return r.repo.GetStatusedWithLimit(ctx, page, limit, status)
+}
+
+func (r *Repository) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag task.Flag) (tasks []*task.Task, err error) {
+	ctx, span := r.start(ctx, "GetFlaggedWithLimit", pageKey.Int(page), limitKey.Int(limit),
+		attribute.String("task.flag", string(flag)))
+	defer func() { endRows(span, len(tasks), err) }()
+	return r.repo.GetFlaggedWithLimit(ctx, page, limit, flag)
+}
+
+func (r *Repository) GetTasksDueBefore(ctx context.Context, deadline time.Time, limit int) (tasks []*task.Task, err error) {
+	ctx, span := r.start(ctx, "GetTasksDueBefore", limitKey.Int(limit))
+	defer func() { endRows(span, len(tasks), err) }()
+	return r.repo.GetTasksDueBefore(ctx, deadline, limit)
+}
+
+func (r *Repository) GetTasksWithDueReminders(ctx context.Context, now time.Time, limit int) (tasks []*task.Task, err error) {
+	ctx, span := r.start(ctx, "GetTasksWithDueReminders", limitKey.Int(limit))
+	defer func() { endRows(span, len(tasks), err) }()
+	return r.repo.GetTasksWithDueReminders(ctx, now, limit)
+}
+
+func (r *Repository) GetActiveByStatuses(ctx context.Context, statuses []task.Status) (tasks []*task.Task, err error) {
+	ctx, span := r.start(ctx, "GetActiveByStatuses")
+	defer func() { endRows(span, len(tasks), err) }()
+	return r.repo.GetActiveByStatuses(ctx, statuses)
+}
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (t *task.Task, err error) {
+	ctx, span := r.start(ctx, "GetByID", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return r.repo.GetByID(ctx, id)
+}
+
+func (r *Repository) GetByIDs(ctx context.Context, ids []uuid.UUID) (tasks []*task.Task, err error) {
+	ctx, span := r.start(ctx, "GetByIDs", limitKey.Int(len(ids)))
+	defer func() { endRows(span, len(tasks), err) }()
+	return r.repo.GetByIDs(ctx, ids)
+}
+
+func (r *Repository) DeleteSoft(ctx context.Context, t *task.Task) (err error) {
+	ctx, span := r.start(ctx, "DeleteSoft", taskIDKey.String(t.UUID.String()))
+	defer func() { end(span, err) }()
+	return r.repo.DeleteSoft(ctx, t)
+}
+
+func (r *Repository) DeleteFull(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := r.start(ctx, "DeleteFull", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return r.repo.DeleteFull(ctx, id)
+}
+
+func (r *Repository) HealthCheck(ctx context.Context) (err error) {
+	ctx, span := r.start(ctx, "HealthCheck")
+	defer func() { end(span, err) }()
+	return r.repo.HealthCheck(ctx)
+}
@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"taskTracker/internal/board"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository"
+	"taskTracker/internal/service"
+	"taskTracker/internal/workflow"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	errorCodeKey = attribute.Key("task.error_code")
+	taskIDKey    = attribute.Key("task.id")
+	pageKey      = attribute.Key("task.page")
+	limitKey     = attribute.Key("task.limit")
+	boardIDKey   = attribute.Key("board.id")
+	columnKey    = attribute.Key("board.column")
+)
+
+// businessCode - код ожидаемой ошибки: бизнес-ошибки сервиса
+// и штатные ответы хранилища
+func businessCode(err error) (string, bool) {
+	var businessErr *service.BusinessError
+	switch {
+	case errors.As(err, &businessErr):
+		return businessErr.Code, true
+	case errors.Is(err, repository.ErrNotFound):
+		return "NOT_FOUND", true
+	case errors.Is(err, repository.ErrVersionConflict):
+		return "VERSION_CONFLICT", true
+	}
+	return "", false
+}
+
+// Service открывает спан TaskService.<метод> на каждый вызов сервиса
+type Service struct {
+	svc handlers.Service
+}
+
+func NewService(svc handlers.Service) *Service {
+	return &Service{svc: svc}
+}
+
+func (s *Service) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "TaskService."+method, trace.WithAttributes(attrs...))
+}
+
+func end(span trace.Span, err error) {
+	recordError(span, err)
+	span.End()
+}
+
+func (s *Service) CreateTask(ctx context.Context, title, description string, dueTime time.Time, options ...task.TaskOption) (t *task.Task, err error) {
+	ctx, span := s.start(ctx, "CreateTask")
+	defer func() {
+		if t != nil {
+			span.SetAttributes(taskIDKey.String(t.UUID.String()))
+		}
+		end(span, err)
+	}()
+	return s.svc.CreateTask(ctx, title, description, dueTime, options...)
+}
+
+func (s *Service) GetActiveTasks(ctx context.Context, page, limit int) (tasks []*task.Task, err error) {
+	ctx, span := s.start(ctx, "GetActiveTasks", pageKey.Int(page), limitKey.Int(limit))
+	defer func() { end(span, err) }()
+	return s.svc.GetActiveTasks(ctx, page, limit)
+}
+
+func (s *Service) GetAllTasks(ctx context.Context, page, limit int) (tasks []*task.Task, err error) {
+	ctx, span := s.start(ctx, "GetAllTasks", pageKey.Int(page), limitKey.Int(limit))
+	defer func() { end(span, err) }()
+	return s.svc.GetAllTasks(ctx, page, limit)
+}
+
+func (s *Service) GetArchivedTasks(ctx context.Context, page, limit int) (tasks []*task.Task, err error) {
+	ctx, span := s.start(ctx, "GetArchivedTasks", pageKey.Int(page), limitKey.Int(limit))
+	defer func() { end(span, err) }()
+	return s.svc.GetArchivedTasks(ctx, page, limit)
+}
+
+func (s *Service) GetOverdueTasks(ctx context.Context, page, limit int) (tasks []*task.Task, err error) {
+	ctx, span := s.start(ctx, "GetOverdueTasks", pageKey.Int(page), limitKey.Int(limit))
+	defer func() { end(span, err) }()
+	return s.svc.GetOverdueTasks(ctx, page, limit)
+}
+
+func (s *Service) GetDeletedTasks(ctx context.Context, page, limit int) (tasks []*task.Task, err error) {
+	ctx, span := s.start(ctx, "GetDeletedTasks", pageKey.Int(page), limitKey.Int(limit))
+	defer func() { end(span, err) }()
+	return s.svc.GetDeletedTasks(ctx, page, limit)
+}
+
+func (s *Service) GetTaskByID(ctx context.Context, id uuid.UUID) (t *task.Task, err error) {
+	ctx, span := s.start(ctx, "GetTaskByID", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.GetTaskByID(ctx, id)
+}
+
+func (s *Service) GetTasksByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, []error) {
+	ctx, span := s.start(ctx, "GetTasksByIDs", limitKey.Int(len(ids)))
+	defer span.End()
+	return s.svc.GetTasksByIDs(ctx, ids)
+}
+
+func (s *Service) UpdateTask(ctx context.Context, id uuid.UUID, options ...task.TaskOption) (t *task.Task, err error) {
+	ctx, span := s.start(ctx, "UpdateTask", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.UpdateTask(ctx, id, options...)
+}
+
+func (s *Service) DeleteTask(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteTask", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.DeleteTask(ctx, id)
+}
+
+func (s *Service) ArchiveTask(ctx context.Context, id uuid.UUID) (t *task.Task, err error) {
+	ctx, span := s.start(ctx, "ArchiveTask", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.ArchiveTask(ctx, id)
+}
+
+func (s *Service) UnarchiveTask(ctx context.Context, id uuid.UUID) (t *task.Task, err error) {
+	ctx, span := s.start(ctx, "UnarchiveTask", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.UnarchiveTask(ctx, id)
+}
+
+func (s *Service) RestoreTask(ctx context.Context, id uuid.UUID) (t *task.Task, err error) {
+	ctx, span := s.start(ctx, "RestoreTask", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.RestoreTask(ctx, id)
+}
+
+func (s *Service) PurgeTask(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "PurgeTask", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.PurgeTask(ctx, id)
+}
+
+func (s *Service) GetTaskOccurrences(ctx context.Context, id uuid.UUID, from, to time.Time) (occurrences []time.Time, err error) {
+	ctx, span := s.start(ctx, "GetTaskOccurrences", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.GetTaskOccurrences(ctx, id, from, to)
+}
+
+func (s *Service) GetTaskTransitions(ctx context.Context, id uuid.UUID) (t *task.Task, options []workflow.Option, err error) {
+	ctx, span := s.start(ctx, "GetTaskTransitions", taskIDKey.String(id.String()))
+	defer func() { end(span, err) }()
+	return s.svc.GetTaskTransitions(ctx, id)
+}
+
+func (s *Service) GetBoard(ctx context.Context, id string) (b board.Board, columns []board.ColumnTasks, err error) {
+	ctx, span := s.start(ctx, "GetBoard", boardIDKey.String(id))
+	defer func() { end(span, err) }()
+	return s.svc.GetBoard(ctx, id)
+}
+
+func (s *Service) MoveTask(ctx context.Context, id uuid.UUID, req service.MoveRequest) (t *task.Task, err error) {
+	ctx, span := s.start(ctx, "MoveTask", taskIDKey.String(id.String()),
+		boardIDKey.String(req.Board), columnKey.String(req.Column))
+	defer func() { end(span, err) }()
+	return s.svc.MoveTask(ctx, id, req)
+}
+
+func (s *Service) HealthCheck(ctx context.Context) (err error) {
+	ctx, span := s.start(ctx, "HealthCheck")
+	defer func() { end(span, err) }()
+	return s.svc.HealthCheck(ctx)
+}
@@ -0,0 +1,128 @@
+// Package tracing - распределённая трассировка OpenTelemetry: провайдер
+// с экспортом в OTLP-коллектор или stdout, HTTP-middleware, обёртки
+// сервиса и репозитория и трейсер запросов pgx.
+//
+// Пока Init не вызван, глобальный провайдер - noop: обёртки и трейсер pgx
+// можно ставить всегда, без трассировки они почти ничего не стоят
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const instrumentationName = "taskTracker"
+
+// Экспортёры спанов
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+type Options struct {
+	ServiceName string
+	// Exporter - otlp (gRPC, по умолчанию localhost:4317) или stdout
+	Exporter string
+	Endpoint string
+	Insecure bool
+	// SampleRatio - доля трассируемых корневых запросов, от 0 до 1. Решение
+	// вызывающего сервиса из traceparent соблюдается
+	SampleRatio float64
+}
+
+// Init настраивает глобальный провайдер и W3C-пропагацию (traceparent,
+// baggage). Возвращает функцию, которая отправляет оставшиеся спаны
+// и останавливает провайдер
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) && !errors.Is(err, resource.ErrSchemaURLConflict) {
+		return nil, fmt.Errorf("описание сервиса: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+	switch opts.Exporter {
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{}
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		// соединение устанавливается лениво: недоступный коллектор
+		// не мешает запуску, спаны просто теряются
+		return otlptracegrpc.New(ctx, clientOpts...)
+
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+
+	default:
+		return nil, fmt.Errorf("неизвестный экспортёр трассировки: %s", opts.Exporter)
+	}
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// recordError отмечает спан ошибочным. Ожидаемые бизнес-ошибки
+// (не найдено, конфликт состояния) записываются атрибутом и статус не портят
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	if code, ok := businessCode(err); ok {
+		span.SetAttributes(errorCodeKey.String(code))
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// LogFields - trace_id и span_id текущего спана для строк лога.
+// Пустой список, если запрос не трассируется
+func LogFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	}
+}
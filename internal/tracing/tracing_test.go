@@ -0,0 +1,221 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"taskTracker/internal/handlers"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/middleware"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"taskTracker/internal/tracing"
+	"taskTracker/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+// record ставит глобальный провайдер, который складывает спаны в память
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	rec := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		provider.Shutdown(context.Background())
+	})
+	return rec
+}
+
+// findSpan ищет завершённый спан по имени
+func findSpan(t *testing.T, rec *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	for _, span := range rec.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	require.Failf(t, "спан не найден", "%s", name)
+	return nil
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// newServer - API с трассировкой на всех слоях, как в app
+func newServer(t *testing.T, repo service.TaskRepository) *httptest.Server {
+	t.Helper()
+
+	svc := service.NewTaskService(tracing.NewRepository(repo, "inmemory"), "inmemory")
+	handler := handlers.NewTaskHandler(tracing.NewService(&svc))
+
+	r := chi.NewRouter()
+	r.Use(middleware.Tracing)
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Logging)
+	r.Get("/tasks/{id}", handler.GetTaskByID)
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestTracing_EndToEnd тестирует трассу от клиента через HTTP, сервис и репозиторий
+func TestTracing_EndToEnd(t *testing.T) {
+	rec := record(t)
+	srv := newServer(t, inmemory.NewTaskStorage())
+
+	c, err := client.New(srv.URL, client.WithRetry(client.RetryPolicy{}))
+	require.NoError(t, err)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "caller")
+	_, err = c.GetTask(client.WithRequestID(ctx, "req-42"), uuid.New())
+	parent.End()
+	require.Error(t, err)
+	assert.True(t, client.IsNotFound(err))
+
+	server := findSpan(t, rec, "GET /tasks/{id}")
+	svcSpan := findSpan(t, rec, "TaskService.GetTaskByID")
+	repoSpan := findSpan(t, rec, "repository.GetByID")
+
+	traceID := parent.SpanContext().TraceID()
+	assert.Equal(t, traceID, server.SpanContext().TraceID(), "трасса продолжена из traceparent")
+	assert.Equal(t, parent.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Equal(t, server.SpanContext().SpanID(), svcSpan.Parent().SpanID())
+	assert.Equal(t, svcSpan.SpanContext().SpanID(), repoSpan.Parent().SpanID())
+
+	route, ok := attributeValue(server, "http.route")
+	require.True(t, ok)
+	assert.Equal(t, "/tasks/{id}", route.AsString())
+	requestID, ok := attributeValue(server, "request_id")
+	require.True(t, ok)
+	assert.Equal(t, "req-42", requestID.AsString())
+
+	// не найдено - штатный ответ, а не сбой
+	code, ok := attributeValue(svcSpan, "task.error_code")
+	require.True(t, ok)
+	assert.Equal(t, "NOT_FOUND", code.AsString())
+	assert.NotEqual(t, codes.Error, svcSpan.Status().Code)
+	assert.NotEqual(t, codes.Error, repoSpan.Status().Code)
+}
+
+type failingRepo struct {
+	*inmemory.TaskStorage
+}
+
+func (failingRepo) HealthCheck(context.Context) error { return errors.New("db down") }
+
+// TestRepository_Error тестирует статус Error у спана при сбое хранилища
+func TestRepository_Error(t *testing.T) {
+	rec := record(t)
+	repo := tracing.NewRepository(failingRepo{inmemory.NewTaskStorage()}, "inmemory")
+
+	require.Error(t, repo.HealthCheck(context.Background()))
+	_, err := repo.GetAllWithLimit(context.Background(), 1, 10)
+	require.NoError(t, err)
+
+	span := findSpan(t, rec, "repository.HealthCheck")
+	assert.Equal(t, codes.Error, span.Status().Code)
+	require.Len(t, span.Events(), 1)
+	assert.Equal(t, "exception", span.Events()[0].Name)
+
+	list := findSpan(t, rec, "repository.GetAllWithLimit")
+	system, _ := attributeValue(list, "db.system.name")
+	assert.Equal(t, "inmemory", system.AsString())
+	rows, ok := attributeValue(list, "db.response.returned_rows")
+	require.True(t, ok)
+	assert.EqualValues(t, 0, rows.AsInt64())
+}
+
+// TestPgxTracer тестирует спаны SQL-запросов с текстом запроса
+func TestPgxTracer(t *testing.T) {
+	rec := record(t)
+	tracer := tracing.NewPgxTracer()
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "\n\t\tUPDATE tasks\n\t\tSET title = $1\n\t\tWHERE id = $2",
+		Args: []any{"секрет", uuid.New()},
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 1")})
+
+	span := findSpan(t, rec, "db.update")
+	statement, _ := attributeValue(span, "db.query.text")
+	assert.Equal(t, "UPDATE tasks SET title = $1 WHERE id = $2", statement.AsString())
+	operation, _ := attributeValue(span, "db.operation.name")
+	assert.Equal(t, "UPDATE", operation.AsString())
+	rows, _ := attributeValue(span, "db.response.returned_rows")
+	assert.EqualValues(t, 1, rows.AsInt64())
+	for _, kv := range span.Attributes() {
+		assert.NotContains(t, kv.Value.Emit(), "секрет", "параметры запроса не пишутся в спан")
+	}
+}
+
+// TestLogFields тестирует поля trace_id и span_id для логов
+func TestLogFields(t *testing.T) {
+	record(t)
+
+	assert.Empty(t, tracing.LogFields(context.Background()))
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	fields := tracing.LogFields(ctx)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "trace_id", fields[0].Key)
+	assert.Equal(t, span.SpanContext().TraceID().String(), fields[0].String)
+	assert.Equal(t, "span_id", fields[1].Key)
+	assert.Equal(t, span.SpanContext().SpanID().String(), fields[1].String)
+}
+
+// TestInit тестирует выбор экспортёра
+func TestInit(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	_, err := tracing.Init(context.Background(), tracing.Options{Exporter: "zipkin"})
+	assert.Error(t, err)
+
+	shutdown, err := tracing.Init(context.Background(), tracing.Options{
+		ServiceName: "test",
+		Exporter:    tracing.ExporterOTLP,
+		Endpoint:    "127.0.0.1:1",
+		Insecure:    true,
+		SampleRatio: 1,
+	})
+	require.NoError(t, err, "недоступный коллектор не мешает запуску")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	shutdown(ctx)
+}
@@ -0,0 +1,342 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/metrics"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type DispatcherOptions struct {
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Timeout     time.Duration
+	// PollInterval - как часто забирать из хранилища созревшие повторы
+	PollInterval time.Duration
+	// Lease - на сколько откладывается доставка, взятая в работу.
+	// Если процесс упал до результата, после Lease её заберёт любой экземпляр
+	Lease time.Duration
+}
+
+// Dispatcher доставляет события подписчикам в фоне.
+//
+// Каждая доставка сначала записывается в хранилище как Pending и удаляется
+// только после успеха или dead-letter, поэтому доставка идёт не реже одного раза:
+// повторы переживают остановку и перезапуск, дубликаты получатель отбрасывает
+// по X-TaskTracker-Delivery.
+//
+// Неудачная попытка (сетевая ошибка или ответ не 2xx) повторяется
+// с экспоненциальной задержкой BaseDelay * 2^(attempt-1), но не больше MaxDelay.
+// После MaxAttempts попыток событие попадает в dead-letter.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	opts   DispatcherOptions
+
+	queue    chan Pending
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewDispatcher(store Store, opts DispatcherOptions) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = time.Second
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 5 * time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = time.Minute
+	}
+
+	return &Dispatcher{
+		store:  store,
+		client: &http.Client{Timeout: opts.Timeout},
+		opts:   opts,
+		queue:  make(chan Pending, opts.QueueSize),
+		stop:   make(chan struct{}),
+	}
+}
+
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.opts.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for {
+				select {
+				case <-d.stop:
+					return
+				case p := <-d.queue:
+					d.deliver(p)
+				}
+			}
+		}()
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.opts.PollInterval)
+		defer ticker.Stop()
+
+		// доставки, оставшиеся от прошлого запуска, забираются сразу
+		d.poll()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.poll()
+			}
+		}
+	}()
+}
+
+// Stop дожидается завершения текущих попыток. Недоставленное остаётся
+// в хранилище и уходит после следующего Start
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+	d.wg.Wait()
+}
+
+// Handle записывает доставку события для всех подходящих подписок
+// и сразу ставит её в очередь. Подходит для events.Bus.Subscribe.
+//
+// DeliveryID выводится из события и подписки, поэтому повторная доставка
+// события из outbox не порождает новых доставок
+func (d *Dispatcher) Handle(ctx context.Context, e events.Event) error {
+	subscriptions, err := d.store.ListSubscriptions(ctx)
+	if err != nil {
+		logger.Error("Webhook: Не удалось получить подписки", err)
+		return fmt.Errorf("получение подписок: %w", err)
+	}
+
+	var errs []error
+	for _, s := range subscriptions {
+		if !s.Matches(e.Type) {
+			continue
+		}
+		p := Pending{
+			DeliveryID:     uuid.NewSHA1(s.ID, e.ID[:]),
+			SubscriptionID: s.ID,
+			Event:          e,
+			Attempt:        1,
+			NextAttemptAt:  time.Now().Add(d.opts.Lease),
+		}
+		if err := d.store.SavePending(ctx, p); err != nil {
+			if err == ErrNotFound {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("запись доставки для подписки %s: %w", s.ID, err))
+			continue
+		}
+		d.enqueue(p)
+	}
+	return errors.Join(errs...)
+}
+
+// poll забирает созревшие доставки, сколько помещается в очередь
+func (d *Dispatcher) poll() {
+	free := cap(d.queue) - len(d.queue)
+	if free <= 0 {
+		return
+	}
+
+	now := time.Now()
+	pending, err := d.store.ClaimPending(context.Background(), now, now.Add(d.opts.Lease), free)
+	if err != nil {
+		logger.Error("Webhook: Не удалось получить отложенные доставки", err)
+		return
+	}
+	for _, p := range pending {
+		d.enqueue(p)
+	}
+}
+
+// enqueue не блокируется: если очередь полна, доставка остаётся
+// в хранилище и вернётся через poll после Lease
+func (d *Dispatcher) enqueue(p Pending) {
+	select {
+	case <-d.stop:
+		return
+	default:
+	}
+
+	select {
+	case d.queue <- p:
+	default:
+		logger.Warn("Webhook: Очередь доставки переполнена, доставка отложена",
+			zap.String("subscription_id", p.SubscriptionID.String()),
+			zap.String("event_id", p.Event.ID.String()))
+	}
+}
+
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.opts.BaseDelay
+	for i := 1; i < attempt && delay < d.opts.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.opts.MaxDelay {
+		delay = d.opts.MaxDelay
+	}
+	return delay
+}
+
+func (d *Dispatcher) deliver(p Pending) {
+	ctx := context.Background()
+
+	s, err := d.store.GetSubscription(ctx, p.SubscriptionID)
+	if err != nil {
+		if err != ErrNotFound {
+			// доставка останется в хранилище и вернётся после Lease
+			logger.Error("Webhook: Не удалось получить подписку", err)
+			return
+		}
+		// подписку удалили, пока событие ждало в очереди
+		d.done(ctx, p)
+		return
+	}
+	if !s.Active {
+		d.done(ctx, p)
+		return
+	}
+
+	start := time.Now()
+	statusCode, sendErr := d.send(ctx, s, p)
+	metrics.ObserveWorkerRun("webhook", start, 1, sendErr)
+
+	attempt := Delivery{
+		ID:             uuid.New(),
+		DeliveryID:     p.DeliveryID,
+		SubscriptionID: p.SubscriptionID,
+		EventID:        p.Event.ID,
+		EventType:      p.Event.Type,
+		Attempt:        p.Attempt,
+		StatusCode:     statusCode,
+		Success:        sendErr == nil,
+		Duration:       time.Since(start),
+		AttemptedAt:    start,
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+
+	retry := sendErr != nil && p.Attempt < d.opts.MaxAttempts
+	if retry {
+		next := time.Now().Add(d.backoff(p.Attempt))
+		attempt.NextRetryAt = &next
+	}
+
+	if err := d.store.AddDelivery(ctx, attempt); err != nil && err != ErrNotFound {
+		logger.Error("Webhook: Не удалось сохранить попытку доставки", err)
+	}
+
+	if sendErr == nil {
+		d.done(ctx, p)
+		return
+	}
+
+	logger.Warn("Webhook: Ошибка доставки",
+		zap.String("subscription_id", p.SubscriptionID.String()),
+		zap.String("event", string(p.Event.Type)),
+		zap.Int("attempt", p.Attempt),
+		zap.Error(sendErr))
+
+	if !retry {
+		d.deadLetter(p, sendErr.Error())
+		d.done(ctx, p)
+		return
+	}
+
+	// повтор заберёт poll, когда наступит NextRetryAt
+	p.Attempt++
+	p.NextAttemptAt = *attempt.NextRetryAt
+	if err := d.store.SavePending(ctx, p); err != nil && err != ErrNotFound {
+		logger.Error("Webhook: Не удалось отложить повтор доставки", err)
+	}
+}
+
+// done удаляет доставку из хранилища. При ошибке доставка вернётся
+// после Lease и получатель отбросит её по X-TaskTracker-Delivery
+func (d *Dispatcher) done(ctx context.Context, p Pending) {
+	if err := d.store.DeletePending(ctx, p.DeliveryID); err != nil {
+		logger.Error("Webhook: Не удалось удалить завершённую доставку", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, s *Subscription, p Pending) (int, error) {
+	body, err := json.Marshal(p.Event)
+	if err != nil {
+		return 0, fmt.Errorf("сериализация события: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("создание запроса: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(p.Event.Type))
+	req.Header.Set(DeliveryHeader, p.DeliveryID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, "sha256="+Sign(s.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("получатель ответил статусом %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) deadLetter(p Pending, reason string) {
+	err := d.store.AddDeadLetter(context.Background(), DeadLetter{
+		DeliveryID:     p.DeliveryID,
+		SubscriptionID: p.SubscriptionID,
+		Event:          p.Event,
+		Attempts:       p.Attempt,
+		LastError:      reason,
+		FailedAt:       time.Now(),
+	})
+	if err != nil {
+		logger.Error("Webhook: Не удалось сохранить событие в dead-letter", err)
+	}
+}
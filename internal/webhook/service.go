@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"taskTracker/internal/events"
+	"taskTracker/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const resource service.RepoType = "Подписка"
+
+type SubscriptionOption func(*Subscription)
+
+func WithURL(rawURL string) SubscriptionOption {
+	return func(s *Subscription) {
+		s.URL = rawURL
+	}
+}
+
+func WithSecret(secret string) SubscriptionOption {
+	return func(s *Subscription) {
+		s.Secret = secret
+	}
+}
+
+func WithEvents(types []events.Type) SubscriptionOption {
+	return func(s *Subscription) {
+		s.Events = types
+	}
+}
+
+func WithActive(active bool) SubscriptionOption {
+	return func(s *Subscription) {
+		s.Active = active
+	}
+}
+
+// Service управляет подписками на вебхуки
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// POST /webhooks
+// Если секрет не передан, он генерируется и возвращается только в ответе на создание
+func (s *Service) CreateSubscription(ctx context.Context, rawURL, secret string, types []events.Type) (*Subscription, error) {
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	subscription := &Subscription{
+		ID:        uuid.New(),
+		URL:       rawURL,
+		Secret:    secret,
+		Events:    types,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	if err := validate(subscription); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.CreateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("создание подписки: %w", err)
+	}
+	return subscription, nil
+}
+
+// GET /webhooks
+func (s *Service) ListSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	subscriptions, err := s.store.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("получение подписок: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// GET /webhooks/{id}
+func (s *Service) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	subscription, err := s.store.GetSubscription(ctx, id)
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, service.NewNotFound(resource, id.String())
+		}
+		return nil, fmt.Errorf("получение подписки: %w", err)
+	}
+	return subscription, nil
+}
+
+// PUT /webhooks/{id}
+func (s *Service) UpdateSubscription(ctx context.Context, id uuid.UUID, options ...SubscriptionOption) (*Subscription, error) {
+	subscription, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range options {
+		opt(subscription)
+	}
+	if err := validate(subscription); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription.UpdatedAt = &now
+
+	if err := s.store.UpdateSubscription(ctx, subscription); err != nil {
+		if err == ErrNotFound {
+			return nil, service.NewNotFound(resource, id.String())
+		}
+		return nil, fmt.Errorf("обновление подписки: %w", err)
+	}
+	return subscription, nil
+}
+
+// DELETE /webhooks/{id}
+func (s *Service) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	if err := s.store.DeleteSubscription(ctx, id); err != nil {
+		if err == ErrNotFound {
+			return service.NewNotFound(resource, id.String())
+		}
+		return fmt.Errorf("удаление подписки: %w", err)
+	}
+	return nil
+}
+
+// GET /webhooks/{id}/deliveries
+func (s *Service) ListDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]Delivery, error) {
+	deliveries, err := s.store.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, service.NewNotFound(resource, id.String())
+		}
+		return nil, fmt.Errorf("получение попыток доставки: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GET /webhooks/dead-letters
+func (s *Service) ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	deadLetters, err := s.store.ListDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("получение dead-letter: %w", err)
+	}
+	return deadLetters, nil
+}
+
+func validate(s *Subscription) error {
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return service.NewValidationError("url", "нужен абсолютный http(s) URL")
+	}
+	if s.Secret == "" {
+		return service.NewValidationError("secret", "секрет не может быть пустым")
+	}
+	for _, t := range s.Events {
+		if !t.Valid() {
+			return service.NewValidationError("events", fmt.Sprintf("неизвестное событие: %s", t))
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("генерация секрета: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// сколько последних попыток хранится на подписку и сколько записей в dead-letter
+const MaxDeliveriesPerSubscription = 100
+const MaxDeadLetters = 1000
+
+type Store interface {
+	CreateSubscription(context.Context, *Subscription) error
+	UpdateSubscription(context.Context, *Subscription) error
+	DeleteSubscription(context.Context, uuid.UUID) error
+	GetSubscription(context.Context, uuid.UUID) (*Subscription, error)
+	ListSubscriptions(context.Context) ([]*Subscription, error)
+
+	AddDelivery(context.Context, Delivery) error
+	ListDeliveries(context.Context, uuid.UUID, int) ([]Delivery, error)
+	AddDeadLetter(context.Context, DeadLetter) error
+	ListDeadLetters(context.Context, int) ([]DeadLetter, error)
+
+	// SavePending создаёт или обновляет отложенную доставку по DeliveryID.
+	// ErrNotFound - подписки уже нет
+	SavePending(context.Context, Pending) error
+	// ClaimPending отдаёт до limit доставок с NextAttemptAt <= now и переносит
+	// их NextAttemptAt на until, чтобы другой экземпляр не взял их повторно
+	ClaimPending(ctx context.Context, now, until time.Time, limit int) ([]Pending, error)
+	DeletePending(context.Context, uuid.UUID) error
+}
+
+// MemoryStore хранит подписки, журнал и отложенные доставки в памяти процесса
+type MemoryStore struct {
+	mtx           sync.RWMutex
+	subscriptions map[uuid.UUID]*Subscription
+	order         []uuid.UUID
+	deliveries    map[uuid.UUID][]Delivery
+	deadLetters   []DeadLetter
+	pending       map[uuid.UUID]Pending
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		subscriptions: make(map[uuid.UUID]*Subscription),
+		deliveries:    make(map[uuid.UUID][]Delivery),
+		pending:       make(map[uuid.UUID]Pending),
+	}
+}
+
+func copySubscription(s *Subscription) *Subscription {
+	copied := *s
+	copied.Events = append(copied.Events[:0:0], s.Events...)
+	return &copied
+}
+
+func (m *MemoryStore) CreateSubscription(ctx context.Context, s *Subscription) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.subscriptions[s.ID] = copySubscription(s)
+	m.order = append(m.order, s.ID)
+	return nil
+}
+
+func (m *MemoryStore) UpdateSubscription(ctx context.Context, s *Subscription) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.subscriptions[s.ID]; !ok {
+		return ErrNotFound
+	}
+	m.subscriptions[s.ID] = copySubscription(s)
+	return nil
+}
+
+func (m *MemoryStore) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.subscriptions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.subscriptions, id)
+	delete(m.deliveries, id)
+	for deliveryID, p := range m.pending {
+		if p.SubscriptionID == id {
+			delete(m.pending, deliveryID)
+		}
+	}
+	for i, existing := range m.order {
+		if existing == id {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	s, ok := m.subscriptions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copySubscription(s), nil
+}
+
+func (m *MemoryStore) ListSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	res := make([]*Subscription, 0, len(m.order))
+	for _, id := range m.order {
+		res = append(res, copySubscription(m.subscriptions[id]))
+	}
+	return res, nil
+}
+
+func (m *MemoryStore) AddDelivery(ctx context.Context, d Delivery) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.subscriptions[d.SubscriptionID]; !ok {
+		return ErrNotFound
+	}
+	list := append(m.deliveries[d.SubscriptionID], d)
+	if len(list) > MaxDeliveriesPerSubscription {
+		list = list[len(list)-MaxDeliveriesPerSubscription:]
+	}
+	m.deliveries[d.SubscriptionID] = list
+	return nil
+}
+
+// ListDeliveries отдаёт последние попытки, начиная с самой свежей
+func (m *MemoryStore) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]Delivery, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	if _, ok := m.subscriptions[subscriptionID]; !ok {
+		return nil, ErrNotFound
+	}
+	return latest(m.deliveries[subscriptionID], limit), nil
+}
+
+func (m *MemoryStore) AddDeadLetter(ctx context.Context, d DeadLetter) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.deadLetters = append(m.deadLetters, d)
+	if len(m.deadLetters) > MaxDeadLetters {
+		m.deadLetters = m.deadLetters[len(m.deadLetters)-MaxDeadLetters:]
+	}
+	return nil
+}
+
+func (m *MemoryStore) ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	return latest(m.deadLetters, limit), nil
+}
+
+func (m *MemoryStore) SavePending(ctx context.Context, p Pending) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.subscriptions[p.SubscriptionID]; !ok {
+		return ErrNotFound
+	}
+	m.pending[p.DeliveryID] = p
+	return nil
+}
+
+func (m *MemoryStore) ClaimPending(ctx context.Context, now, until time.Time, limit int) ([]Pending, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	var due []Pending
+	for _, p := range m.pending {
+		if !p.NextAttemptAt.After(now) {
+			due = append(due, p)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].NextAttemptAt = until
+		m.pending[due[i].DeliveryID] = due[i]
+	}
+	return due, nil
+}
+
+func (m *MemoryStore) DeletePending(ctx context.Context, deliveryID uuid.UUID) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.pending, deliveryID)
+	return nil
+}
+
+func latest[T any](items []T, limit int) []T {
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+	res := make([]T, 0, limit)
+	for i := len(items) - 1; i >= 0 && len(res) < limit; i-- {
+		res = append(res, items[i])
+	}
+	return res
+}
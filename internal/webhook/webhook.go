@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"taskTracker/internal/events"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrNotFound = errors.New("подписка не найдена")
+
+const SignatureHeader = "X-TaskTracker-Signature"
+const TimestampHeader = "X-TaskTracker-Timestamp"
+const EventHeader = "X-TaskTracker-Event"
+const DeliveryHeader = "X-TaskTracker-Delivery"
+
+// Subscription - подписка на события задач. Пустой Events означает все события
+type Subscription struct {
+	ID        uuid.UUID     `json:"id"`
+	URL       string        `json:"url"`
+	Secret    string        `json:"-"`
+	Events    []events.Type `json:"events"`
+	Active    bool          `json:"active"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt *time.Time    `json:"updated_at,omitempty"`
+}
+
+func (s *Subscription) Matches(eventType events.Type) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, t := range s.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery - одна попытка доставки. DeliveryID общий для всех повторов
+// одного события, по нему получатель может отбрасывать дубликаты
+type Delivery struct {
+	ID             uuid.UUID     `json:"id"`
+	DeliveryID     uuid.UUID     `json:"delivery_id"`
+	SubscriptionID uuid.UUID     `json:"subscription_id"`
+	EventID        uuid.UUID     `json:"event_id"`
+	EventType      events.Type   `json:"event_type"`
+	Attempt        int           `json:"attempt"`
+	StatusCode     int           `json:"status_code,omitempty"`
+	Error          string        `json:"error,omitempty"`
+	Success        bool          `json:"success"`
+	Duration       time.Duration `json:"duration"`
+	AttemptedAt    time.Time     `json:"attempted_at"`
+	NextRetryAt    *time.Time    `json:"next_retry_at,omitempty"`
+}
+
+// DeadLetter - событие, которое не удалось доставить за все попытки
+type DeadLetter struct {
+	DeliveryID     uuid.UUID    `json:"delivery_id"`
+	SubscriptionID uuid.UUID    `json:"subscription_id"`
+	Event          events.Event `json:"event"`
+	Attempts       int          `json:"attempts"`
+	LastError      string       `json:"last_error"`
+	FailedAt       time.Time    `json:"failed_at"`
+}
+
+// Pending - доставка события подписчику, которая ещё не закончилась успехом
+// или dead-letter. Хранится вместе с подписками, поэтому повторы переживают
+// перезапуск процесса
+type Pending struct {
+	DeliveryID     uuid.UUID    `json:"delivery_id"`
+	SubscriptionID uuid.UUID    `json:"subscription_id"`
+	Event          events.Event `json:"event"`
+	Attempt        int          `json:"attempt"`
+	NextAttemptAt  time.Time    `json:"next_attempt_at"`
+}
+
+// Sign считает подпись тела запроса: hex(HMAC-SHA256(secret, timestamp + "." + body)).
+// Получатель повторяет вычисление и сравнивает с заголовком X-TaskTracker-Signature
+// без префикса "sha256="
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,262 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"taskTracker/internal/webhook"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func newEvent(eventType events.Type) events.Event {
+	return events.New(eventType, &task.Task{UUID: uuid.New(), Title: "hooked", Status: task.StatusNew})
+}
+
+func newDispatcher(t *testing.T, store webhook.Store, maxAttempts int) *webhook.Dispatcher {
+	t.Helper()
+	dispatcher := webhook.NewDispatcher(store, webhook.DispatcherOptions{
+		Workers:      2,
+		MaxAttempts:  maxAttempts,
+		BaseDelay:    5 * time.Millisecond,
+		MaxDelay:     20 * time.Millisecond,
+		Timeout:      time.Second,
+		PollInterval: 5 * time.Millisecond,
+	})
+	dispatcher.Start()
+	t.Cleanup(dispatcher.Stop)
+	return dispatcher
+}
+
+// TestDispatcher_SignedDelivery тестирует подпись и фильтр событий
+func TestDispatcher_SignedDelivery(t *testing.T) {
+	ctx := context.Background()
+	received := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := webhook.NewMemoryStore()
+	svc := webhook.NewService(store)
+	subscription, err := svc.CreateSubscription(ctx, server.URL, "s3cret", []events.Type{events.TaskCreated})
+	require.NoError(t, err)
+
+	dispatcher := newDispatcher(t, store, 3)
+	dispatcher.Handle(ctx, newEvent(events.TaskUpdated))
+	created := newEvent(events.TaskCreated)
+	dispatcher.Handle(ctx, created)
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("вебхук не доставлен")
+	}
+	body := <-bodies
+
+	assert.Equal(t, string(events.TaskCreated), req.Header.Get(webhook.EventHeader))
+	timestamp, err := strconv.ParseInt(req.Header.Get(webhook.TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256="+webhook.Sign("s3cret", timestamp, body), req.Header.Get(webhook.SignatureHeader))
+
+	var payload events.Event
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, created.ID, payload.ID)
+
+	// task.updated не входит в фильтр подписки
+	select {
+	case <-received:
+		t.Fatal("доставлено событие вне фильтра")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.Eventually(t, func() bool {
+		deliveries, _ := svc.ListDeliveries(ctx, subscription.ID, 10)
+		return len(deliveries) == 1 && deliveries[0].Success
+	}, time.Second, 10*time.Millisecond)
+}
+
+// TestDispatcher_RetryAndDeadLetter тестирует повторы и dead-letter
+func TestDispatcher_RetryAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer flaky.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	store := webhook.NewMemoryStore()
+	svc := webhook.NewService(store)
+	flakySub, err := svc.CreateSubscription(ctx, flaky.URL, "", nil)
+	require.NoError(t, err)
+	brokenSub, err := svc.CreateSubscription(ctx, broken.URL, "", nil)
+	require.NoError(t, err)
+
+	dispatcher := newDispatcher(t, store, 3)
+	event := newEvent(events.TaskDeleted)
+	dispatcher.Handle(ctx, event)
+
+	require.Eventually(t, func() bool {
+		deadLetters, _ := svc.ListDeadLetters(ctx, 10)
+		return len(deadLetters) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	deadLetters, err := svc.ListDeadLetters(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, brokenSub.ID, deadLetters[0].SubscriptionID)
+	assert.Equal(t, event.ID, deadLetters[0].Event.ID)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+
+	require.Eventually(t, func() bool {
+		deliveries, _ := svc.ListDeliveries(ctx, flakySub.ID, 10)
+		return len(deliveries) == 3 && deliveries[0].Success
+	}, 2*time.Second, 10*time.Millisecond)
+
+	deliveries, err := svc.ListDeliveries(ctx, flakySub.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.Equal(t, deliveries[0].DeliveryID, deliveries[2].DeliveryID)
+	assert.NotNil(t, deliveries[2].NextRetryAt)
+}
+
+// TestDispatcher_ResumesAfterStop тестирует повтор, отложенный до остановки диспетчера
+func TestDispatcher_ResumesAfterStop(t *testing.T) {
+	ctx := context.Background()
+	var healthy atomic.Bool
+	deliveries := make(chan string, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		deliveries <- r.Header.Get(webhook.DeliveryHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := webhook.NewMemoryStore()
+	svc := webhook.NewService(store)
+	subscription, err := svc.CreateSubscription(ctx, server.URL, "", nil)
+	require.NoError(t, err)
+
+	first := webhook.NewDispatcher(store, webhook.DispatcherOptions{
+		MaxAttempts:  5,
+		BaseDelay:    time.Hour,
+		PollInterval: 5 * time.Millisecond,
+	})
+	first.Start()
+	require.NoError(t, first.Handle(ctx, newEvent(events.TaskCreated)))
+
+	require.Eventually(t, func() bool {
+		attempts, _ := svc.ListDeliveries(ctx, subscription.ID, 10)
+		return len(attempts) == 1
+	}, time.Second, 5*time.Millisecond)
+	first.Stop()
+
+	// повтор через час переживает остановку; until в прошлом делает его созревшим,
+	// и его забирает новый диспетчер
+	now := time.Now().Add(2 * time.Hour)
+	pending, err := store.ClaimPending(ctx, now, time.Time{}, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 2, pending[0].Attempt)
+
+	healthy.Store(true)
+	newDispatcher(t, store, 5)
+
+	var deliveryID string
+	select {
+	case deliveryID = <-deliveries:
+	case <-time.After(2 * time.Second):
+		t.Fatal("отложенная доставка не возобновилась")
+	}
+	assert.Equal(t, pending[0].DeliveryID.String(), deliveryID)
+
+	require.Eventually(t, func() bool {
+		left, _ := store.ClaimPending(ctx, time.Now().Add(24*time.Hour), time.Time{}, 10)
+		return len(left) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+// TestDispatcher_HandleIdempotent тестирует повторную доставку события из outbox
+func TestDispatcher_HandleIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := webhook.NewMemoryStore()
+	svc := webhook.NewService(store)
+	_, err := svc.CreateSubscription(ctx, "https://example.com/hook", "", nil)
+	require.NoError(t, err)
+
+	// диспетчер не запущен, доставки только записываются
+	dispatcher := webhook.NewDispatcher(store, webhook.DispatcherOptions{Lease: time.Millisecond})
+	event := newEvent(events.TaskUpdated)
+	require.NoError(t, dispatcher.Handle(ctx, event))
+	require.NoError(t, dispatcher.Handle(ctx, event))
+
+	pending, err := store.ClaimPending(ctx, time.Now().Add(time.Second), time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, event.ID, pending[0].Event.ID)
+}
+
+// TestService_Validation тестирует проверку параметров подписки
+func TestService_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc := webhook.NewService(webhook.NewMemoryStore())
+
+	_, err := svc.CreateSubscription(ctx, "ftp://example.com", "", nil)
+	assert.Error(t, err)
+
+	_, err = svc.CreateSubscription(ctx, "https://example.com/hook", "", []events.Type{"task.unknown"})
+	assert.Error(t, err)
+
+	created, err := svc.CreateSubscription(ctx, "https://example.com/hook", "", nil)
+	require.NoError(t, err)
+	assert.Len(t, created.Secret, 64)
+
+	updated, err := svc.UpdateSubscription(ctx, created.ID, webhook.WithActive(false))
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+	assert.NotNil(t, updated.UpdatedAt)
+
+	require.NoError(t, svc.DeleteSubscription(ctx, created.ID))
+	_, err = svc.GetSubscription(ctx, created.ID)
+	var businessErr *service.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "NOT_FOUND", businessErr.Code)
+}
@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"taskTracker/internal/models/task"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config - статусы и переходы задач, файл WORKFLOW_CONFIG:
+//
+//	statuses: [new, in progress, blocked, in review, done, cancelled, overdue]
+//	transitions:
+//	  - {name: start, from: [new, blocked], to: in progress}
+//	  - {name: review, from: [in progress], to: in review, guards: [has_description]}
+//	  - {name: complete, from: [in progress, in review], to: done}
+type Config struct {
+	Statuses    []task.Status `yaml:"statuses"`
+	Transitions []Transition  `yaml:"transitions"`
+}
+
+// Transition - разрешённый переход из любого статуса From в To. Все Guards
+// должны выполняться для задачи с уже применёнными изменениями
+type Transition struct {
+	Name   string        `yaml:"name"`
+	From   []task.Status `yaml:"from"`
+	To     task.Status   `yaml:"to"`
+	Guards []string      `yaml:"guards"`
+}
+
+// DefaultConfig - workflow без файла конфигурации
+func DefaultConfig() Config {
+	return Config{
+		Statuses: task.Statuses,
+		Transitions: []Transition{
+			{Name: "start", From: []task.Status{task.StatusNew, task.StatusBlocked, task.StatusInReview, task.StatusOverdue}, To: task.StatusInProgress},
+			{Name: "block", From: []task.Status{task.StatusNew, task.StatusInProgress, task.StatusInReview, task.StatusOverdue}, To: task.StatusBlocked},
+			{Name: "review", From: []task.Status{task.StatusInProgress, task.StatusOverdue}, To: task.StatusInReview, Guards: []string{GuardHasDescription}},
+			{Name: "complete", From: []task.Status{task.StatusNew, task.StatusInProgress, task.StatusInReview, task.StatusOverdue}, To: task.StatusDone},
+			{Name: "cancel", From: []task.Status{task.StatusNew, task.StatusInProgress, task.StatusBlocked, task.StatusInReview, task.StatusOverdue}, To: task.StatusCancelled},
+			{Name: "reopen", From: []task.Status{task.StatusDone, task.StatusCancelled, task.StatusOverdue}, To: task.StatusNew, Guards: []string{GuardDueInFuture}},
+		},
+	}
+}
+
+// FileConfig - файл WORKFLOW_CONFIG: workflow по умолчанию на верхнем
+// уровне и workflow проектов по ключу проекта:
+//
+//	statuses: [...]
+//	transitions: [...]
+//	projects:
+//	  mobile:
+//	    statuses: [new, in progress, done, overdue]
+//	    transitions:
+//	      - {name: start, from: [new], to: in progress}
+//
+// Без statuses и transitions на верхнем уровне по умолчанию действует DefaultConfig
+type FileConfig struct {
+	Config   `yaml:",inline"`
+	Projects map[string]Config `yaml:"projects"`
+}
+
+// LoadConfig читает workflow из YAML
+func LoadConfig(path string) (FileConfig, error) {
+	var cfg FileConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("чтение %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("разбор %s: %w", path, err)
+	}
+	if len(cfg.Statuses) == 0 && len(cfg.Transitions) == 0 {
+		cfg.Config = DefaultConfig()
+	}
+	return cfg, nil
+}
@@ -0,0 +1,50 @@
+package workflow
+
+import (
+	"strings"
+	"sync"
+	"taskTracker/internal/models/task"
+	"time"
+)
+
+// Guard - условие перехода. Возвращает причину отказа или пустую строку,
+// если переход разрешён
+type Guard func(t *task.Task) string
+
+const (
+	GuardHasDescription = "has_description"
+	GuardDueInFuture    = "due_in_future"
+)
+
+var (
+	mtx    sync.RWMutex
+	guards = map[string]Guard{
+		GuardHasDescription: func(t *task.Task) string {
+			if strings.TrimSpace(t.Description) == "" {
+				return "нужно описание задачи"
+			}
+			return ""
+		},
+		GuardDueInFuture: func(t *task.Task) string {
+			if !t.DueTime.After(time.Now()) {
+				return "срок задачи прошёл, перенесите его"
+			}
+			return ""
+		},
+	}
+)
+
+// RegisterGuard добавляет условие, на которое можно сослаться в конфиге.
+// Регистрировать нужно до New
+func RegisterGuard(name string, guard Guard) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	guards[name] = guard
+}
+
+func lookupGuard(name string) (Guard, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	guard, ok := guards[name]
+	return guard, ok
+}
@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"taskTracker/internal/models/task"
+)
+
+// Set - workflow по умолчанию и workflow проектов. Проект без своего
+// workflow и задача без проекта получают workflow по умолчанию
+type Set struct {
+	def      *Workflow
+	projects map[string]*Workflow
+}
+
+// NewSet проверяет workflow по умолчанию и workflow каждого проекта
+func NewSet(cfg FileConfig) (*Set, error) {
+	def, err := New(cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &Set{def: def, projects: make(map[string]*Workflow, len(cfg.Projects))}
+	for project, projectCfg := range cfg.Projects {
+		if project == "" {
+			return nil, errors.New("workflow: пустой ключ проекта")
+		}
+		wf, err := New(projectCfg)
+		if err != nil {
+			return nil, fmt.Errorf("проект %q: %w", project, err)
+		}
+		set.projects[project] = wf
+	}
+	return set, nil
+}
+
+// Single - один workflow для всех проектов
+func Single(wf *Workflow) *Set {
+	return &Set{def: wf}
+}
+
+// Default - workflow задач без проекта и проектов без своего workflow
+func (s *Set) Default() *Workflow {
+	return s.def
+}
+
+// For - workflow проекта. Пустой или неизвестный проект получает workflow по умолчанию
+func (s *Set) For(project string) *Workflow {
+	if wf, ok := s.projects[project]; ok {
+		return wf
+	}
+	return s.def
+}
+
+// Projects - проекты со своим workflow по алфавиту
+func (s *Set) Projects() []string {
+	return slices.Sorted(maps.Keys(s.projects))
+}
+
+// Statuses - статусы всех workflow набора в порядке task.Statuses
+func (s *Set) Statuses() []task.Status {
+	var res []task.Status
+	for _, status := range task.Statuses {
+		if s.known(status) {
+			res = append(res, status)
+		}
+	}
+	return res
+}
+
+func (s *Set) known(status task.Status) bool {
+	if s.def.Known(status) {
+		return true
+	}
+	for _, wf := range s.projects {
+		if wf.Known(status) {
+			return true
+		}
+	}
+	return false
+}
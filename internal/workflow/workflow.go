@@ -0,0 +1,211 @@
+// Package workflow - статусы задач и разрешённые переходы между ними.
+// Переходы с условиями (guards) задаются в YAML, без файла действует DefaultConfig.
+// Проект может объявить свой workflow, остальные проекты получают workflow по умолчанию
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"taskTracker/internal/models/task"
+)
+
+var ErrUnknownStatus = errors.New("неизвестный статус")
+
+// required - статусы, которые сервис ставит сам: при создании, по сроку
+// и при выполнении повторяющейся задачи
+var required = []task.Status{task.StatusNew, task.StatusInProgress, task.StatusDone, task.StatusOverdue}
+
+// TransitionError - перехода нет в workflow или не выполнено его условие
+type TransitionError struct {
+	From task.Status
+	To   task.Status
+	// Reason - почему не выполнено условие. Пусто, если перехода нет вовсе
+	Reason string
+	// Allowed - статусы, в которые ведут переходы из From
+	Allowed []task.Status
+}
+
+func (e *TransitionError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("переход из %q в %q: %s", e.From, e.To, e.Reason)
+	}
+	return fmt.Sprintf("переход из %q в %q не разрешён", e.From, e.To)
+}
+
+// Option - переход из текущего статуса задачи. Allowed false - условие
+// не выполнено, причина в Reason
+type Option struct {
+	Name    string
+	To      task.Status
+	Allowed bool
+	Reason  string
+}
+
+type Workflow struct {
+	statuses    []task.Status
+	transitions []compiledTransition
+}
+
+type compiledTransition struct {
+	Transition
+	guards []Guard
+}
+
+// New проверяет конфиг: статусы объявлены, условия зарегистрированы,
+// в overdue не ведёт ни один переход - его ставит только сервис.
+// Конфиг выбирает статусы из task.Statuses, но не вводит новые: их знают
+// перечисления GraphQL и gRPC, фильтр потока событий и метрики
+func New(cfg Config) (*Workflow, error) {
+	w := &Workflow{}
+	for _, status := range cfg.Statuses {
+		if status == "" {
+			return nil, errors.New("workflow: пустой статус")
+		}
+		if !slices.Contains(task.Statuses, status) {
+			return nil, fmt.Errorf("workflow: статус %q не поддерживается, допустимы: %s", status, joinStatuses(task.Statuses))
+		}
+		if w.Known(status) {
+			return nil, fmt.Errorf("workflow: статус %q объявлен дважды", status)
+		}
+		w.statuses = append(w.statuses, status)
+	}
+	for _, status := range required {
+		if !w.Known(status) {
+			return nil, fmt.Errorf("workflow: статус %q обязателен, его ставит сервис", status)
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, tr := range cfg.Transitions {
+		if tr.Name == "" || names[tr.Name] {
+			return nil, fmt.Errorf("workflow: у перехода в %q пустое или повторное имя %q", tr.To, tr.Name)
+		}
+		names[tr.Name] = true
+
+		if !w.Known(tr.To) {
+			return nil, fmt.Errorf("workflow: переход %s ведёт в необъявленный статус %q", tr.Name, tr.To)
+		}
+		if tr.To == task.StatusOverdue {
+			return nil, fmt.Errorf("workflow: переход %s ведёт в overdue, этот статус ставится только по сроку", tr.Name)
+		}
+		if len(tr.From) == 0 {
+			return nil, fmt.Errorf("workflow: у перехода %s нет исходных статусов", tr.Name)
+		}
+		for _, from := range tr.From {
+			if !w.Known(from) {
+				return nil, fmt.Errorf("workflow: переход %s из необъявленного статуса %q", tr.Name, from)
+			}
+		}
+
+		compiled := compiledTransition{Transition: tr}
+		for _, name := range tr.Guards {
+			guard, ok := lookupGuard(name)
+			if !ok {
+				return nil, fmt.Errorf("workflow: у перехода %s неизвестное условие %q", tr.Name, name)
+			}
+			compiled.guards = append(compiled.guards, guard)
+		}
+		w.transitions = append(w.transitions, compiled)
+	}
+	return w, nil
+}
+
+// Default - workflow из DefaultConfig
+func Default() *Workflow {
+	w, err := New(DefaultConfig())
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Statuses - объявленные статусы в порядке конфига
+func (w *Workflow) Statuses() []task.Status {
+	return slices.Clone(w.statuses)
+}
+
+func (w *Workflow) Known(status task.Status) bool {
+	return slices.Contains(w.statuses, status)
+}
+
+// Check проверяет переход задачи из from в t.Status. Условия проверяются
+// на t, то есть с изменениями того же запроса: перенос срока и переоткрытие
+// задачи можно сделать одним обновлением
+func (w *Workflow) Check(from task.Status, t *task.Task) error {
+	if t.Status == from {
+		return nil
+	}
+	if !w.Known(t.Status) {
+		return fmt.Errorf("%w %q", ErrUnknownStatus, t.Status)
+	}
+
+	var reasons []string
+	for _, tr := range w.from(from) {
+		if tr.To != t.Status {
+			continue
+		}
+		reason := tr.check(t)
+		if reason == "" {
+			return nil
+		}
+		reasons = append(reasons, reason)
+	}
+
+	err := &TransitionError{From: from, To: t.Status, Reason: strings.Join(reasons, "; ")}
+	for _, tr := range w.from(from) {
+		if !slices.Contains(err.Allowed, tr.To) {
+			err.Allowed = append(err.Allowed, tr.To)
+		}
+	}
+	return err
+}
+
+// Available - переходы из текущего статуса задачи с результатом их условий
+func (w *Workflow) Available(t *task.Task) []Option {
+	transitions := w.from(t.Status)
+	options := make([]Option, 0, len(transitions))
+	for _, tr := range transitions {
+		reason := tr.check(t)
+		options = append(options, Option{
+			Name:    tr.Name,
+			To:      tr.To,
+			Allowed: reason == "",
+			Reason:  reason,
+		})
+	}
+	return options
+}
+
+// from - переходы из статуса. Из статуса, которого нет в workflow (его
+// убрали из конфига), разрешён любой переход, чтобы задачи не застряли
+func (w *Workflow) from(status task.Status) []compiledTransition {
+	if !w.Known(status) {
+		return w.transitions
+	}
+	var res []compiledTransition
+	for _, tr := range w.transitions {
+		if slices.Contains(tr.From, status) && tr.To != status {
+			res = append(res, tr)
+		}
+	}
+	return res
+}
+
+func joinStatuses(statuses []task.Status) string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (tr compiledTransition) check(t *task.Task) string {
+	for _, guard := range tr.guards {
+		if reason := guard(t); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
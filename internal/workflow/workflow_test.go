@@ -0,0 +1,159 @@
+package workflow_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/workflow"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = []task.Status{task.StatusNew, task.StatusInProgress, task.StatusDone, task.StatusOverdue}
+
+// TestNew тестирует проверку конфига
+func TestNew(t *testing.T) {
+	_, err := workflow.New(workflow.DefaultConfig())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		cfg  workflow.Config
+		want string
+	}{
+		{"missing required status", workflow.Config{Statuses: []task.Status{task.StatusNew}}, "обязателен"},
+		{"duplicate status", workflow.Config{Statuses: append(base, task.StatusNew)}, "дважды"},
+		{"unsupported status", workflow.Config{Statuses: append(base, "qa")}, "не поддерживается"},
+		{"undeclared target", workflow.Config{Statuses: base, Transitions: []workflow.Transition{
+			{Name: "cancel", From: []task.Status{task.StatusNew}, To: task.StatusCancelled},
+		}}, "необъявленный"},
+		{"transition to overdue", workflow.Config{Statuses: base, Transitions: []workflow.Transition{
+			{Name: "expire", From: []task.Status{task.StatusNew}, To: task.StatusOverdue},
+		}}, "overdue"},
+		{"unknown guard", workflow.Config{Statuses: base, Transitions: []workflow.Transition{
+			{Name: "start", From: []task.Status{task.StatusNew}, To: task.StatusInProgress, Guards: []string{"approved"}},
+		}}, "approved"},
+		{"duplicate name", workflow.Config{Statuses: base, Transitions: []workflow.Transition{
+			{Name: "start", From: []task.Status{task.StatusNew}, To: task.StatusInProgress},
+			{Name: "start", From: []task.Status{task.StatusInProgress}, To: task.StatusDone},
+		}}, "start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := workflow.New(tt.cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// TestWorkflow_Check тестирует переходы и условия
+func TestWorkflow_Check(t *testing.T) {
+	wf := workflow.Default()
+	tsk := &task.Task{Status: task.StatusInReview, DueTime: time.Now().Add(time.Hour)}
+
+	tsk.Description = "готово к проверке"
+	assert.NoError(t, wf.Check(task.StatusInProgress, tsk))
+	assert.NoError(t, wf.Check(task.StatusInReview, tsk), "статус не меняется")
+
+	tsk.Description = ""
+	var transitionErr *workflow.TransitionError
+	require.ErrorAs(t, wf.Check(task.StatusInProgress, tsk), &transitionErr)
+	assert.Equal(t, "нужно описание задачи", transitionErr.Reason)
+
+	tsk.Status = task.StatusInProgress
+	require.ErrorAs(t, wf.Check(task.StatusDone, tsk), &transitionErr)
+	assert.Empty(t, transitionErr.Reason)
+	assert.Equal(t, []task.Status{task.StatusNew}, transitionErr.Allowed)
+
+	tsk.Status = "banana"
+	assert.True(t, errors.Is(wf.Check(task.StatusNew, tsk), workflow.ErrUnknownStatus))
+
+	// из статуса, убранного из конфига, можно уйти куда угодно
+	tsk.Status = task.StatusNew
+	assert.NoError(t, wf.Check("archived-status", tsk))
+}
+
+// TestWorkflow_Available тестирует список переходов из статуса задачи
+func TestWorkflow_Available(t *testing.T) {
+	wf := workflow.Default()
+
+	options := wf.Available(&task.Task{Status: task.StatusDone, DueTime: time.Now().Add(-time.Hour)})
+	require.Len(t, options, 1)
+	assert.Equal(t, workflow.Option{
+		Name:   "reopen",
+		To:     task.StatusNew,
+		Reason: "срок задачи прошёл, перенесите его",
+	}, options[0])
+
+	var targets []task.Status
+	for _, opt := range wf.Available(&task.Task{Status: task.StatusNew}) {
+		targets = append(targets, opt.To)
+	}
+	assert.Equal(t, []task.Status{task.StatusInProgress, task.StatusBlocked, task.StatusDone, task.StatusCancelled}, targets)
+}
+
+// TestLoadConfig тестирует чтение YAML и свои условия
+func TestLoadConfig(t *testing.T) {
+	workflow.RegisterGuard("has_reminders", func(t *task.Task) string {
+		if len(t.Reminders) == 0 {
+			return "нужно напоминание"
+		}
+		return ""
+	})
+
+	path := filepath.Join(t.TempDir(), "workflow.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+statuses: [new, in progress, done, overdue]
+transitions:
+  - {name: start, from: [new], to: in progress, guards: [has_reminders]}
+  - {name: complete, from: [in progress, overdue], to: done}
+`), 0o644))
+
+	cfg, err := workflow.LoadConfig(path)
+	require.NoError(t, err)
+	wf, err := workflow.New(cfg.Config)
+	require.NoError(t, err)
+
+	assert.Equal(t, []task.Status{task.StatusNew, task.StatusInProgress, task.StatusDone, task.StatusOverdue}, wf.Statuses())
+	assert.Error(t, wf.Check(task.StatusNew, &task.Task{Status: task.StatusInProgress}))
+	assert.NoError(t, wf.Check(task.StatusNew, &task.Task{
+		Status:    task.StatusInProgress,
+		Reminders: task.Reminders{{Before: time.Hour}},
+	}))
+}
+
+// TestNewSet тестирует workflow проектов из YAML
+func TestNewSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflow.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+projects:
+  mobile:
+    statuses: [new, in progress, done, overdue]
+    transitions:
+      - {name: start, from: [new], to: in progress}
+      - {name: complete, from: [in progress, overdue], to: done}
+`), 0o644))
+
+	cfg, err := workflow.LoadConfig(path)
+	require.NoError(t, err)
+	set, err := workflow.NewSet(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"mobile"}, set.Projects())
+	assert.Equal(t, workflow.Default().Statuses(), set.Default().Statuses(), "без верхнего уровня действует встроенный workflow")
+	assert.Equal(t, set.Default(), set.For(""))
+	assert.Equal(t, set.Default(), set.For("backend"))
+	assert.Equal(t, base, set.For("mobile").Statuses())
+	assert.False(t, set.For("mobile").Known(task.StatusBlocked))
+	assert.Equal(t, task.Statuses, set.Statuses())
+
+	cfg.Projects["broken"] = workflow.Config{Statuses: []task.Status{task.StatusNew}}
+	_, err = workflow.NewSet(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `проект "broken"`)
+}
@@ -0,0 +1,106 @@
+package worklog
+
+import (
+	"context"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Repository дополняет прочитанные задачи суммарным временем из Store.
+// Ставится поверх кэша, чтобы итоги не устаревали вместе с закэшированной
+// задачей. Ошибка Store не ломает чтение: итог остаётся нулевым
+type Repository struct {
+	service.TaskRepository
+
+	store Store
+}
+
+func NewRepository(repo service.TaskRepository, store Store) *Repository {
+	return &Repository{TaskRepository: repo, store: store}
+}
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*task.Task, error) {
+	t, err := r.TaskRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.fill(ctx, t)[0], nil
+}
+
+func (r *Repository) GetAllWithLimit(ctx context.Context, page, limit int) ([]*task.Task, error) {
+	tasks, err := r.TaskRepository.GetAllWithLimit(ctx, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.fill(ctx, tasks...), nil
+}
+
+func (r *Repository) GetStatusedWithLimit(ctx context.Context, page, limit int, status task.Status) ([]*task.Task, error) {
+	tasks, err := r.TaskRepository.GetStatusedWithLimit(ctx, page, limit, status)
+	if err != nil {
+		return nil, err
+	}
+	return r.fill(ctx, tasks...), nil
+}
+
+func (r *Repository) GetFlaggedWithLimit(ctx context.Context, page, limit int, flag task.Flag) ([]*task.Task, error) {
+	tasks, err := r.TaskRepository.GetFlaggedWithLimit(ctx, page, limit, flag)
+	if err != nil {
+		return nil, err
+	}
+	return r.fill(ctx, tasks...), nil
+}
+
+func (r *Repository) GetTasksDueBefore(ctx context.Context, deadline time.Time, limit int) ([]*task.Task, error) {
+	tasks, err := r.TaskRepository.GetTasksDueBefore(ctx, deadline, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.fill(ctx, tasks...), nil
+}
+
+func (r *Repository) GetActiveByStatuses(ctx context.Context, statuses []task.Status) ([]*task.Task, error) {
+	tasks, err := r.TaskRepository.GetActiveByStatuses(ctx, statuses)
+	if err != nil {
+		return nil, err
+	}
+	return r.fill(ctx, tasks...), nil
+}
+
+func (r *Repository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*task.Task, error) {
+	tasks, err := r.TaskRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return r.fill(ctx, tasks...), nil
+}
+
+// fill возвращает копии задач с итогами: хранилище в памяти и кэш отдают
+// общие указатели, и запись в них была бы гонкой с другими читателями
+func (r *Repository) fill(ctx context.Context, tasks ...*task.Task) []*task.Task {
+	if len(tasks) == 0 {
+		return tasks
+	}
+	ids := make([]uuid.UUID, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.UUID
+	}
+
+	totals, err := r.store.WorklogTotals(ctx, ids)
+	if err != nil {
+		logger.WarnCtx(ctx, "Worklog: Не удалось получить суммарное время задач", zap.Error(err))
+	}
+
+	filled := make([]*task.Task, len(tasks))
+	for i, t := range tasks {
+		c := *t
+		c.TimeSpent = totals[t.UUID]
+		filled[i] = &c
+	}
+	return filled
+}
@@ -0,0 +1,300 @@
+package worklog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/service"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxUserLength = 100
+	maxNoteLength = 1000
+	// одна ручная запись - не больше суток работы
+	maxEntryDuration = 24 * time.Hour
+	// самый длинный интервал отчёта
+	maxReportRange = 366 * 24 * time.Hour
+)
+
+// Tasks - задачи, к которым пишется время
+type Tasks interface {
+	GetTaskByID(context.Context, uuid.UUID) (*task.Task, error)
+}
+
+// Service ведёт таймеры и записи времени
+type Service struct {
+	store Store
+	tasks Tasks
+}
+
+func NewService(store Store, tasks Tasks) *Service {
+	return &Service{store: store, tasks: tasks}
+}
+
+// POST /tasks/{id}/timer/start
+// Таймер запускается только у активной незакрытой задачи. Таймер
+// пользователя на другой задаче останавливается: время не считается дважды
+func (s *Service) StartTimer(ctx context.Context, taskID uuid.UUID, user string) (*Timer, error) {
+	user, err := validateUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.tasks.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Flag != task.FlagActive {
+		return nil, service.NewBusinessError(
+			service.CodeInvalidFlag,
+			fmt.Sprintf("Таймер запускается только у активных задач. Текущий флаг: '%s'", t.Flag),
+			service.ToDetail("task_id", taskID.String()),
+			service.ToDetail("current_flag", t.Flag),
+		)
+	}
+	if t.Status.Closed() {
+		return nil, service.NewBusinessError(
+			service.CodeTaskClosed,
+			fmt.Sprintf("Задача в статусе '%s', работа по ней закончена", t.Status),
+			service.ToDetail("task_id", taskID.String()),
+			service.ToDetail("status", t.Status),
+		)
+	}
+
+	now := time.Now().UTC()
+	running, err := s.store.ListTimers(ctx, uuid.Nil, user)
+	if err != nil {
+		return nil, fmt.Errorf("получение таймеров: %w", err)
+	}
+	for _, other := range running {
+		if other.TaskID == taskID {
+			return nil, service.NewBusinessError(
+				service.CodeTimerRunning,
+				"Таймер этой задачи уже запущен",
+				service.ToDetail("task_id", taskID.String()),
+				service.ToDetail("user", user),
+				service.ToDetail("started_at", other.StartedAt),
+			)
+		}
+	}
+	for _, other := range running {
+		if _, err := s.stop(ctx, other.TaskID, user, now, "остановлен при запуске таймера другой задачи"); err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	timer := Timer{TaskID: taskID, User: user, StartedAt: now}
+	if err := s.store.StartTimer(ctx, timer); err != nil {
+		if errors.Is(err, ErrTimerRunning) {
+			// параллельный запуск того же таймера
+			return nil, service.NewBusinessError(
+				service.CodeTimerRunning,
+				"Таймер этой задачи уже запущен",
+				service.ToDetail("task_id", taskID.String()),
+				service.ToDetail("user", user),
+			)
+		}
+		return nil, fmt.Errorf("запуск таймера: %w", err)
+	}
+	return &timer, nil
+}
+
+// POST /tasks/{id}/timer/stop
+func (s *Service) StopTimer(ctx context.Context, taskID uuid.UUID, user, note string) (*Entry, error) {
+	user, err := validateUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateNote(note); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.stop(ctx, taskID, user, time.Now().UTC(), note)
+	if errors.Is(err, ErrNotFound) {
+		return nil, service.NewBusinessError(
+			service.CodeTimerNotRunning,
+			"Таймер этой задачи не запущен",
+			service.ToDetail("task_id", taskID.String()),
+			service.ToDetail("user", user),
+		)
+	}
+	return entry, err
+}
+
+// stop останавливает таймер и записывает время от запуска до at
+func (s *Service) stop(ctx context.Context, taskID uuid.UUID, user string, at time.Time, note string) (*Entry, error) {
+	timer, err := s.store.StopTimer(ctx, taskID, user)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("остановка таймера: %w", err)
+	}
+
+	entry := Entry{
+		ID:        uuid.New(),
+		TaskID:    taskID,
+		User:      user,
+		StartedAt: timer.StartedAt,
+		Duration:  max(at.Sub(timer.StartedAt), 0).Truncate(time.Second),
+		Note:      note,
+		Source:    SourceTimer,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.store.AddWorklog(ctx, entry); err != nil {
+		return nil, fmt.Errorf("запись времени таймера: %w", err)
+	}
+	return &entry, nil
+}
+
+// POST /tasks/{id}/worklogs
+// Время можно дописать и к архивной задаче, но не к удалённой
+func (s *Service) AddWorklog(ctx context.Context, taskID uuid.UUID, user string, startedAt time.Time, duration time.Duration, note string) (*Entry, error) {
+	user, err := validateUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateNote(note); err != nil {
+		return nil, err
+	}
+	if duration <= 0 || duration > maxEntryDuration {
+		return nil, service.NewValidationError("duration", fmt.Sprintf("должна быть больше нуля и не больше %s", maxEntryDuration))
+	}
+	if startedAt.IsZero() {
+		return nil, service.NewValidationError("started_at", "время начала должно быть задано")
+	}
+	if startedAt.Add(duration).After(time.Now()) {
+		return nil, service.NewValidationError("started_at", "работа не может закончиться в будущем")
+	}
+
+	if _, err := s.tasks.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	entry := Entry{
+		ID:        uuid.New(),
+		TaskID:    taskID,
+		User:      user,
+		StartedAt: startedAt.UTC(),
+		Duration:  duration.Truncate(time.Second),
+		Note:      note,
+		Source:    SourceManual,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.store.AddWorklog(ctx, entry); err != nil {
+		return nil, fmt.Errorf("добавление записи времени: %w", err)
+	}
+	return &entry, nil
+}
+
+// GET /tasks/{id}/worklogs
+func (s *Service) TaskWorklogs(ctx context.Context, taskID uuid.UUID) ([]Entry, []Timer, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, taskID); err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := s.store.ListWorklogs(ctx, Filter{TaskID: taskID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("получение записей времени: %w", err)
+	}
+	timers, err := s.store.ListTimers(ctx, taskID, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("получение таймеров: %w", err)
+	}
+	return entries, timers, nil
+}
+
+// GET /worklogs/report
+func (s *Service) Report(ctx context.Context, filter Filter, groupBy []string) ([]Row, error) {
+	if len(groupBy) == 0 {
+		return nil, service.NewValidationError("group_by", "нужно хотя бы одно измерение: task, user, day")
+	}
+	for _, dim := range groupBy {
+		if !slices.Contains(Dimensions, dim) {
+			return nil, service.NewValidationError("group_by", fmt.Sprintf("неизвестное измерение '%s', допустимы task, user, day", dim))
+		}
+	}
+	if filter.To.Sub(filter.From) > maxReportRange {
+		return nil, service.NewValidationError("to", fmt.Sprintf("интервал отчёта не больше %d дней", maxReportRange/(24*time.Hour)))
+	}
+
+	entries, err := s.store.ListWorklogs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("получение записей времени: %w", err)
+	}
+	return Aggregate(entries, groupBy), nil
+}
+
+// Handle останавливает таймеры задачи, которую архивировали, удалили или
+// закрыли. Время считается до момента события. Ошибки возвращаются шине,
+// и релей outbox доставит событие повторно. Повтор безопасен: таймеры,
+// остановленные в прошлый раз, уже не найдутся
+func (s *Service) Handle(ctx context.Context, e events.Event) error {
+	var reason string
+	switch {
+	case e.Type == events.TaskArchived:
+		reason = "задача архивирована"
+	case e.Type == events.TaskDeleted || e.Type == events.TaskPurged:
+		reason = "задача удалена"
+	case e.Type == events.TaskUpdated && e.Task != nil && e.Task.Status.Closed():
+		reason = fmt.Sprintf("задача переведена в '%s'", e.Task.Status)
+	default:
+		return nil
+	}
+
+	timers, err := s.store.ListTimers(ctx, e.TaskID, "")
+	if err != nil {
+		logger.ErrorCtx(ctx, "Worklog: Не удалось получить таймеры задачи", err,
+			zap.String("task_id", e.TaskID.String()))
+		return fmt.Errorf("получение таймеров задачи: %w", err)
+	}
+
+	var errs []error
+	for _, timer := range timers {
+		_, err := s.stop(ctx, timer.TaskID, timer.User, e.OccurredAt, "остановлен автоматически: "+reason)
+		if errors.Is(err, ErrNotFound) {
+			// таймер успели остановить вручную
+			continue
+		}
+		if err != nil {
+			logger.ErrorCtx(ctx, "Worklog: Не удалось остановить таймер", err,
+				zap.String("task_id", timer.TaskID.String()),
+				zap.String("user", timer.User))
+			errs = append(errs, fmt.Errorf("остановка таймера %s: %w", timer.User, err))
+			continue
+		}
+		logger.InfoCtx(ctx, "Worklog: Таймер остановлен автоматически",
+			zap.String("task_id", timer.TaskID.String()),
+			zap.String("user", timer.User),
+			zap.String("event", string(e.Type)))
+	}
+	return errors.Join(errs...)
+}
+
+func validateUser(user string) (string, error) {
+	user = strings.TrimSpace(user)
+	if user == "" {
+		return "", service.NewValidationError("user", "пользователь должен быть задан")
+	}
+	if utf8.RuneCountInString(user) > maxUserLength {
+		return "", service.NewValidationError("user", fmt.Sprintf("не длиннее %d символов", maxUserLength))
+	}
+	return user, nil
+}
+
+func validateNote(note string) error {
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return service.NewValidationError("note", fmt.Sprintf("не длиннее %d символов", maxNoteLength))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package worklog
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store - таймеры и записи времени. StopTimer удаляет таймер и возвращает
+// его, чтобы один таймер нельзя было остановить дважды
+type Store interface {
+	StartTimer(context.Context, Timer) error
+	StopTimer(ctx context.Context, taskID uuid.UUID, user string) (Timer, error)
+	ListTimers(ctx context.Context, taskID uuid.UUID, user string) ([]Timer, error)
+
+	AddWorklog(context.Context, Entry) error
+	ListWorklogs(context.Context, Filter) ([]Entry, error)
+	// WorklogTotals - суммарное время по задачам. Задач без записей в ответе нет
+	WorklogTotals(context.Context, []uuid.UUID) (map[uuid.UUID]time.Duration, error)
+}
+
+type timerKey struct {
+	taskID uuid.UUID
+	user   string
+}
+
+// MemoryStore хранит таймеры и записи в памяти процесса
+type MemoryStore struct {
+	mtx     sync.RWMutex
+	timers  map[timerKey]Timer
+	entries []Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{timers: make(map[timerKey]Timer)}
+}
+
+func (m *MemoryStore) StartTimer(ctx context.Context, t Timer) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	key := timerKey{t.TaskID, t.User}
+	if _, ok := m.timers[key]; ok {
+		return ErrTimerRunning
+	}
+	m.timers[key] = t
+	return nil
+}
+
+func (m *MemoryStore) StopTimer(ctx context.Context, taskID uuid.UUID, user string) (Timer, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	key := timerKey{taskID, user}
+	t, ok := m.timers[key]
+	if !ok {
+		return Timer{}, ErrNotFound
+	}
+	delete(m.timers, key)
+	return t, nil
+}
+
+// ListTimers - таймеры задачи или пользователя; uuid.Nil и пустой user не фильтруют
+func (m *MemoryStore) ListTimers(ctx context.Context, taskID uuid.UUID, user string) ([]Timer, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	res := []Timer{}
+	for _, t := range m.timers {
+		if (taskID == uuid.Nil || t.TaskID == taskID) && (user == "" || t.User == user) {
+			res = append(res, t)
+		}
+	}
+	slices.SortFunc(res, func(a, b Timer) int { return a.StartedAt.Compare(b.StartedAt) })
+	return res, nil
+}
+
+func (m *MemoryStore) AddWorklog(ctx context.Context, e Entry) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+func (m *MemoryStore) ListWorklogs(ctx context.Context, f Filter) ([]Entry, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	res := []Entry{}
+	for _, e := range m.entries {
+		if f.Match(e) {
+			res = append(res, e)
+		}
+	}
+	slices.SortStableFunc(res, func(a, b Entry) int { return a.StartedAt.Compare(b.StartedAt) })
+	return res, nil
+}
+
+func (m *MemoryStore) WorklogTotals(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID]time.Duration, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	res := make(map[uuid.UUID]time.Duration)
+	for _, e := range m.entries {
+		if slices.Contains(taskIDs, e.TaskID) {
+			res[e.TaskID] += e.Duration
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,119 @@
+// Package worklog - учёт времени по задачам: таймеры пользователей и записи
+// о потраченном времени, из которых строятся итоги задач и отчёты
+package worklog
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound     = errors.New("таймер не найден")
+	ErrTimerRunning = errors.New("таймер уже запущен")
+)
+
+type Source string
+
+const (
+	SourceTimer  Source = "timer"
+	SourceManual Source = "manual"
+)
+
+// Entry - потраченное время. Запись таймера появляется при его остановке
+type Entry struct {
+	ID        uuid.UUID
+	TaskID    uuid.UUID
+	User      string
+	StartedAt time.Time
+	Duration  time.Duration
+	Note      string
+	Source    Source
+	CreatedAt time.Time
+}
+
+// Timer - запущенный таймер. У пользователя не больше одного таймера
+type Timer struct {
+	TaskID    uuid.UUID
+	User      string
+	StartedAt time.Time
+}
+
+// Filter - отбор записей. Пустые поля не ограничивают выборку, интервал
+// [From, To) - по времени начала работы
+type Filter struct {
+	TaskID uuid.UUID
+	User   string
+	From   time.Time
+	To     time.Time
+}
+
+func (f Filter) Match(e Entry) bool {
+	return (f.TaskID == uuid.Nil || e.TaskID == f.TaskID) &&
+		(f.User == "" || e.User == f.User) &&
+		(f.From.IsZero() || !e.StartedAt.Before(f.From)) &&
+		(f.To.IsZero() || e.StartedAt.Before(f.To))
+}
+
+// Измерения отчёта
+const (
+	ByTask = "task"
+	ByUser = "user"
+	ByDay  = "day"
+)
+
+var Dimensions = []string{ByTask, ByUser, ByDay}
+
+// Row - строка отчёта. Заполнены только поля измерений группировки
+type Row struct {
+	TaskID   uuid.UUID
+	User     string
+	Day      string
+	Duration time.Duration
+	Entries  int
+}
+
+// Aggregate суммирует записи по измерениям groupBy. Запись целиком относится
+// к дню своего начала в UTC. Строки упорядочены по измерениям
+func Aggregate(entries []Entry, groupBy []string) []Row {
+	rows := make(map[Row]*Row)
+	for _, e := range entries {
+		var key Row
+		for _, dim := range groupBy {
+			switch dim {
+			case ByTask:
+				key.TaskID = e.TaskID
+			case ByUser:
+				key.User = e.User
+			case ByDay:
+				key.Day = e.StartedAt.UTC().Format(time.DateOnly)
+			}
+		}
+		row, ok := rows[key]
+		if !ok {
+			copied := key
+			row = &copied
+			rows[key] = row
+		}
+		row.Duration += e.Duration
+		row.Entries++
+	}
+
+	res := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, *row)
+	}
+	slices.SortFunc(res, func(a, b Row) int {
+		if c := strings.Compare(a.Day, b.Day); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.User, b.User); c != 0 {
+			return c
+		}
+		return strings.Compare(a.TaskID.String(), b.TaskID.String())
+	})
+	return res
+}
@@ -0,0 +1,316 @@
+package worklog_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"taskTracker/internal/events"
+	"taskTracker/internal/logger"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository/task/inmemory"
+	"taskTracker/internal/service"
+	"taskTracker/internal/worklog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+type fakeTasks map[uuid.UUID]*task.Task
+
+func (f fakeTasks) GetTaskByID(ctx context.Context, id uuid.UUID) (*task.Task, error) {
+	t, ok := f[id]
+	if !ok {
+		return nil, service.NewNotFound("inmemory", id.String())
+	}
+	return t, nil
+}
+
+func (f fakeTasks) add(status task.Status, flag task.Flag) *task.Task {
+	t := &task.Task{UUID: uuid.New(), Status: status, Flag: flag, CreatedAt: time.Now()}
+	f[t.UUID] = t
+	return t
+}
+
+func businessCode(t *testing.T, err error) string {
+	t.Helper()
+	var be *service.BusinessError
+	require.True(t, errors.As(err, &be), "ожидалась бизнес-ошибка, получено %v", err)
+	return be.Code
+}
+
+// TestService_Timers тестирует запуск и остановку таймеров
+func TestService_Timers(t *testing.T) {
+	ctx := context.Background()
+	tasks := fakeTasks{}
+	store := worklog.NewMemoryStore()
+	svc := worklog.NewService(store, tasks)
+
+	first := tasks.add(task.StatusInProgress, task.FlagActive)
+	second := tasks.add(task.StatusNew, task.FlagActive)
+
+	_, err := svc.StartTimer(ctx, first.UUID, "  ")
+	assert.Equal(t, service.CodeValidation, businessCode(t, err))
+
+	timer, err := svc.StartTimer(ctx, first.UUID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, first.UUID, timer.TaskID)
+
+	_, err = svc.StartTimer(ctx, first.UUID, "alice")
+	assert.Equal(t, service.CodeTimerRunning, businessCode(t, err))
+
+	// у другого пользователя свой таймер
+	_, err = svc.StartTimer(ctx, first.UUID, "bob")
+	require.NoError(t, err)
+
+	// запуск на другой задаче останавливает предыдущий таймер
+	_, err = svc.StartTimer(ctx, second.UUID, "alice")
+	require.NoError(t, err)
+	entries, timers, err := svc.TaskWorklogs(ctx, first.UUID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "alice", entries[0].User)
+	assert.Equal(t, worklog.SourceTimer, entries[0].Source)
+	require.Len(t, timers, 1)
+	assert.Equal(t, "bob", timers[0].User)
+
+	entry, err := svc.StopTimer(ctx, second.UUID, "alice", "готово")
+	require.NoError(t, err)
+	assert.Equal(t, "готово", entry.Note)
+	assert.GreaterOrEqual(t, entry.Duration, time.Duration(0))
+
+	_, err = svc.StopTimer(ctx, second.UUID, "alice", "")
+	assert.Equal(t, service.CodeTimerNotRunning, businessCode(t, err))
+
+	closed := tasks.add(task.StatusDone, task.FlagActive)
+	_, err = svc.StartTimer(ctx, closed.UUID, "alice")
+	assert.Equal(t, service.CodeTaskClosed, businessCode(t, err))
+
+	archived := tasks.add(task.StatusNew, task.FlagArchived)
+	_, err = svc.StartTimer(ctx, archived.UUID, "alice")
+	assert.Equal(t, service.CodeInvalidFlag, businessCode(t, err))
+
+	_, err = svc.StartTimer(ctx, uuid.New(), "alice")
+	assert.Equal(t, service.CodeNotFound, businessCode(t, err))
+}
+
+// TestService_AddWorklog тестирует ручные записи времени
+func TestService_AddWorklog(t *testing.T) {
+	ctx := context.Background()
+	tasks := fakeTasks{}
+	svc := worklog.NewService(worklog.NewMemoryStore(), tasks)
+	tsk := tasks.add(task.StatusDone, task.FlagArchived)
+	startedAt := time.Now().Add(-3 * time.Hour)
+
+	tests := []struct {
+		name      string
+		startedAt time.Time
+		duration  time.Duration
+		field     string
+	}{
+		{"zero duration", startedAt, 0, "duration"},
+		{"more than a day", startedAt.Add(-48 * time.Hour), 25 * time.Hour, "duration"},
+		{"no start", time.Time{}, time.Hour, "started_at"},
+		{"ends in future", startedAt, 4 * time.Hour, "started_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.AddWorklog(ctx, tsk.UUID, "alice", tt.startedAt, tt.duration, "")
+			require.Equal(t, service.CodeValidation, businessCode(t, err))
+			assert.Equal(t, tt.field, err.(*service.BusinessError).Details["field"])
+		})
+	}
+
+	// закрытой и архивной задаче время дописать можно
+	entry, err := svc.AddWorklog(ctx, tsk.UUID, "alice", startedAt, 90*time.Minute+500*time.Millisecond, "созвон")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, entry.Duration)
+	assert.Equal(t, worklog.SourceManual, entry.Source)
+
+	_, err = svc.AddWorklog(ctx, uuid.New(), "alice", startedAt, time.Hour, "")
+	assert.Equal(t, service.CodeNotFound, businessCode(t, err))
+}
+
+// TestService_Handle тестирует автоматическую остановку таймеров по событиям задачи
+func TestService_Handle(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   events.Type
+		status  task.Status
+		stopped bool
+	}{
+		{"archived", events.TaskArchived, task.StatusInProgress, true},
+		{"deleted", events.TaskDeleted, task.StatusInProgress, true},
+		{"purged", events.TaskPurged, task.StatusInProgress, true},
+		{"closed", events.TaskUpdated, task.StatusDone, true},
+		{"updated", events.TaskUpdated, task.StatusBlocked, false},
+		{"created", events.TaskCreated, task.StatusNew, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tasks := fakeTasks{}
+			svc := worklog.NewService(worklog.NewMemoryStore(), tasks)
+			tsk := tasks.add(task.StatusInProgress, task.FlagActive)
+
+			timer, err := svc.StartTimer(ctx, tsk.UUID, "alice")
+			require.NoError(t, err)
+
+			snapshot := *tsk
+			snapshot.Status = tt.status
+			require.NoError(t, svc.Handle(ctx, events.Event{
+				Type:       tt.event,
+				TaskID:     tsk.UUID,
+				OccurredAt: timer.StartedAt.Add(time.Hour),
+				Task:       &snapshot,
+			}))
+
+			entries, timers, err := svc.TaskWorklogs(ctx, tsk.UUID)
+			require.NoError(t, err)
+			if !tt.stopped {
+				assert.Empty(t, entries)
+				assert.Len(t, timers, 1)
+				return
+			}
+			assert.Empty(t, timers)
+			require.Len(t, entries, 1)
+			// время считается до момента события
+			assert.Equal(t, time.Hour, entries[0].Duration)
+			assert.Contains(t, entries[0].Note, "остановлен автоматически")
+		})
+	}
+}
+
+// brokenStore не отдаёт таймеры, как недоступная база
+type brokenStore struct {
+	*worklog.MemoryStore
+}
+
+func (brokenStore) ListTimers(ctx context.Context, taskID uuid.UUID, user string) ([]worklog.Timer, error) {
+	return nil, errors.New("connection refused")
+}
+
+// TestService_HandleError тестирует возврат ошибки шине для повторной доставки события
+func TestService_HandleError(t *testing.T) {
+	tasks := fakeTasks{}
+	svc := worklog.NewService(brokenStore{worklog.NewMemoryStore()}, tasks)
+	tsk := tasks.add(task.StatusInProgress, task.FlagActive)
+
+	err := svc.Handle(context.Background(), events.Event{Type: events.TaskArchived, TaskID: tsk.UUID, OccurredAt: time.Now()})
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+// TestService_Report тестирует отчёт с группировкой по задаче, пользователю и дню
+func TestService_Report(t *testing.T) {
+	ctx := context.Background()
+	tasks := fakeTasks{}
+	svc := worklog.NewService(worklog.NewMemoryStore(), tasks)
+	first := tasks.add(task.StatusInProgress, task.FlagActive)
+	second := tasks.add(task.StatusInProgress, task.FlagActive)
+
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(-48 * time.Hour)
+	for _, w := range []struct {
+		task     uuid.UUID
+		user     string
+		offset   time.Duration
+		duration time.Duration
+	}{
+		{first.UUID, "alice", 9 * time.Hour, time.Hour},
+		{first.UUID, "alice", 14 * time.Hour, 30 * time.Minute},
+		{first.UUID, "bob", 10 * time.Hour, 2 * time.Hour},
+		{second.UUID, "alice", 33 * time.Hour, time.Hour},
+	} {
+		_, err := svc.AddWorklog(ctx, w.task, w.user, day.Add(w.offset), w.duration, "")
+		require.NoError(t, err)
+	}
+	filter := worklog.Filter{From: day, To: day.Add(48 * time.Hour)}
+
+	rows, err := svc.Report(ctx, filter, []string{worklog.ByUser})
+	require.NoError(t, err)
+	assert.Equal(t, []worklog.Row{
+		{User: "alice", Duration: 150 * time.Minute, Entries: 3},
+		{User: "bob", Duration: 2 * time.Hour, Entries: 1},
+	}, rows)
+
+	rows, err = svc.Report(ctx, filter, []string{worklog.ByDay, worklog.ByTask})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, day.Format(time.DateOnly), rows[0].Day)
+	assert.Equal(t, first.UUID, rows[0].TaskID)
+	assert.Equal(t, 210*time.Minute, rows[0].Duration)
+	assert.Equal(t, second.UUID, rows[1].TaskID)
+
+	filter.User = "bob"
+	rows, err = svc.Report(ctx, filter, worklog.Dimensions)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, worklog.Row{TaskID: first.UUID, User: "bob", Day: day.Format(time.DateOnly), Duration: 2 * time.Hour, Entries: 1}, rows[0])
+
+	_, err = svc.Report(ctx, filter, []string{"project"})
+	assert.Equal(t, service.CodeValidation, businessCode(t, err))
+	_, err = svc.Report(ctx, worklog.Filter{From: day, To: day.Add(400 * 24 * time.Hour)}, worklog.Dimensions)
+	assert.Equal(t, service.CodeValidation, businessCode(t, err))
+}
+
+// TestRepository_TimeSpent тестирует итоги времени в прочитанных задачах
+func TestRepository_TimeSpent(t *testing.T) {
+	ctx := context.Background()
+	store := worklog.NewMemoryStore()
+	repo := worklog.NewRepository(inmemory.NewTaskStorage(), store)
+
+	tracked := &task.Task{UUID: uuid.New(), Title: "с временем", Status: task.StatusNew, Flag: task.FlagActive,
+		CreatedAt: time.Now(), DueTime: time.Now().Add(time.Hour)}
+	untracked := &task.Task{UUID: uuid.New(), Title: "без времени", Status: task.StatusNew, Flag: task.FlagActive,
+		CreatedAt: time.Now(), DueTime: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.Create(ctx, tracked))
+	require.NoError(t, repo.Create(ctx, untracked))
+
+	for _, d := range []time.Duration{time.Hour, 20 * time.Minute} {
+		require.NoError(t, store.AddWorklog(ctx, worklog.Entry{ID: uuid.New(), TaskID: tracked.UUID, User: "alice", Duration: d}))
+	}
+
+	got, err := repo.GetByID(ctx, tracked.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, 80*time.Minute, got.TimeSpent)
+
+	list, err := repo.GetFlaggedWithLimit(ctx, 1, 10, task.FlagActive)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, tsk := range list {
+		if tsk.UUID == tracked.UUID {
+			assert.Equal(t, 80*time.Minute, tsk.TimeSpent)
+		} else {
+			assert.Zero(t, tsk.TimeSpent)
+		}
+	}
+}
+
+// TestRepository_TimeSpentCopies тестирует, что итоги пишутся в копию, а не в задачу хранилища
+func TestRepository_TimeSpentCopies(t *testing.T) {
+	ctx := context.Background()
+	storage := inmemory.NewTaskStorage()
+	store := worklog.NewMemoryStore()
+	repo := worklog.NewRepository(storage, store)
+
+	tracked := &task.Task{UUID: uuid.New(), Title: "с временем", Status: task.StatusNew, Flag: task.FlagActive,
+		CreatedAt: time.Now(), DueTime: time.Now().Add(time.Hour)}
+	require.NoError(t, storage.Create(ctx, tracked))
+	require.NoError(t, store.AddWorklog(ctx, worklog.Entry{ID: uuid.New(), TaskID: tracked.UUID, User: "alice", Duration: time.Hour}))
+
+	got, err := repo.GetByID(ctx, tracked.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, got.TimeSpent)
+
+	stored, err := storage.GetByID(ctx, tracked.UUID)
+	require.NoError(t, err)
+	assert.NotSame(t, stored, got)
+	assert.Zero(t, stored.TimeSpent)
+}
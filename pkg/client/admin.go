@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HealthStatus - ответ GET /health. Error заполнен, если сервис нездоров
+type HealthStatus struct {
+	Status    string `json:"status"`
+	Service   string `json:"service"`
+	Timestamp string `json:"timestamp"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (h *HealthStatus) Healthy() bool {
+	return h.Status == "healthy"
+}
+
+// Health проверяет состояние сервиса. Без повторов: нужен ответ
+// на момент вызова. Нездоровый сервис (503) - не ошибка, а HealthStatus
+// с Healthy() == false
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	var res HealthStatus
+	err := c.doWithRetry(ctx, RetryPolicy{}, http.MethodGet, "/health", nil, nil, &res)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable {
+		// тело 503 - отчёт о здоровье, а не problem+json: его поля попадают в Details
+		message, _ := apiErr.Details["error"].(string)
+		if message == "" {
+			message = apiErr.Message
+		}
+		return &HealthStatus{Status: "unhealthy", Error: message}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CacheStats возвращает счётчики кэша задач. Если кэш выключен, сервер
+// отвечает 404
+func (c *Client) CacheStats(ctx context.Context) (*CacheStats, error) {
+	var res CacheStats
+	if err := c.do(ctx, http.MethodGet, "/admin/cache/stats", nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GraphQLError - ошибка из списка errors ответа GraphQL. Code - код
+// BusinessError или ограничений запроса (MAX_DEPTH_EXCEEDED, ...)
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+func (e GraphQLError) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// GraphQLErrors - ошибки выполнения запроса. Данные, которые удалось
+// получить, всё равно раскладываются в out
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+}
+
+// GraphQL выполняет запрос к /graphql и раскладывает data в out.
+// Ошибки выполнения возвращаются как GraphQLErrors
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	var res graphQLResponse
+	request := graphQLRequest{Query: query, Variables: variables}
+	if err := c.do(ctx, http.MethodPost, "/graphql", nil, request, &res); err != nil {
+		return err
+	}
+
+	if out != nil && len(res.Data) > 0 && string(res.Data) != "null" {
+		if err := json.Unmarshal(res.Data, out); err != nil {
+			return fmt.Errorf("разбор ответа graphql: %w", err)
+		}
+	}
+	if len(res.Errors) > 0 {
+		return res.Errors
+	}
+	return nil
+}
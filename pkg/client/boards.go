@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// Board возвращает доску с активными задачами по колонкам
+func (c *Client) Board(ctx context.Context, id string) (*BoardResponse, error) {
+	var res BoardResponse
+	if err := c.do(ctx, http.MethodGet, "/boards/"+url.PathEscape(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// MoveTask перемещает задачу в колонку доски между соседями After и Before.
+// Переход в колонку с занятым WIP-лимитом вернёт ошибку CodeWIPLimitExceeded
+func (c *Client) MoveTask(ctx context.Context, id uuid.UUID, request MoveTaskRequest) (*TaskResponse, error) {
+	return c.task(ctx, http.MethodPost, taskPath(id)+"/move", request)
+}
@@ -0,0 +1,234 @@
+// Package client - HTTP-клиент TaskTracker API. Тела запросов и ответов -
+// те же типы dto, что использует сервер (см. types.go)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+
+	// RequestIDHeader - заголовок, по которому сервер связывает логи запроса
+	RequestIDHeader = "X-Request-ID"
+)
+
+// RetryPolicy - повторы запросов, отклонённых сервером до обработки:
+// 429 (лимит запросов) и 503 (сервис недоступен). Пауза растёт от BaseDelay
+// вдвое на каждой попытке до MaxDelay; если сервер прислал retry_after
+// или Retry-After, ждём столько, сколько он просит. Если просит дольше
+// MaxDelay - не ждём и возвращаем ошибку
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy - три повтора с паузой от 200мс до 30с
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  200 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	userAgent  string
+	language   string
+	retry      RetryPolicy
+}
+
+type Option func(*Client)
+
+// WithHTTPClient заменяет http.Client, например для своего транспорта
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken добавляет заголовок Authorization: Bearer к каждому запросу
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLanguage задаёт язык текстов ошибок сервера (Accept-Language): ru или en
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+// WithRetry задаёт политику повторов, RetryPolicy{} отключает повторы
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New создаёт клиент для сервера по адресу baseURL (http://localhost:8080)
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("неверный адрес сервера: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("неверный адрес сервера %q: нужна схема http или https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		userAgent:  "tasktracker-go-client",
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID возвращает контекст, запросы с которым уйдут с заголовком
+// X-Request-ID = id. Так ID входящего запроса сервиса попадает в логи
+// трекера. Без него клиент генерирует ID сам, один на все повторы запроса
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func requestIDFrom(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return uuid.NewString()
+}
+
+// do выполняет запрос с повторами и раскладывает ответ в out. Ответ
+// с кодом 4xx/5xx возвращается как *Error
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	return c.doWithRetry(ctx, c.retry, method, path, query, body, out)
+}
+
+func (c *Client) doWithRetry(ctx context.Context, policy RetryPolicy, method, path string, query url.Values, body, out any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("сериализация запроса: %w", err)
+		}
+	}
+	requestID := requestIDFrom(ctx)
+
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, requestID, method, path, query, data, out)
+		delay, retry := policy.delay(attempt, err)
+		if !retry {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay решает, повторять ли запрос после попытки attempt (с нуля), и сколько ждать
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	apiErr, ok := err.(*Error)
+	if !ok || attempt >= p.MaxRetries {
+		return 0, false
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	if apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, apiErr.RetryAfter <= p.MaxDelay
+	}
+
+	d := p.BaseDelay << attempt
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	// джиттер до четверти паузы, чтобы клиенты не повторяли запросы разом
+	if quarter := int64(d / 4); quarter > 0 {
+		d += time.Duration(rand.Int64N(quarter))
+	}
+	return d, true
+}
+
+func (c *Client) send(ctx context.Context, requestID, method, path string, query url.Values, data []byte, out any) error {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set(RequestIDHeader, requestID)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	// traceparent из ctx: спаны сервера продолжают трассу вызывающего кода
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp, requestID)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("разбор ответа %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"taskTracker/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, handler http.HandlerFunc, opts ...client.Option) *client.Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(srv.URL, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+var fastRetry = client.WithRetry(client.RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  time.Millisecond,
+	MaxDelay:   2 * time.Second,
+})
+
+// TestClient_Errors тестирует восстановление типизированной ошибки из тела ответа
+func TestClient_Errors(t *testing.T) {
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tasks/archived":
+			writeJSON(w, http.StatusBadRequest, map[string]any{
+				"type": "urn:task-tracker:problem:validation-error", "title": "Ошибка валидации", "status": 400,
+				"code": client.CodeValidation, "detail": "параметр page должен быть положительным числом", "field": "page",
+			})
+		default:
+			w.Header().Set(client.RequestIDHeader, "req-1")
+			writeJSON(w, http.StatusConflict, map[string]any{
+				"type":     "urn:task-tracker:problem:already-archived",
+				"title":    "Задача уже в архиве",
+				"status":   409,
+				"code":     client.CodeAlreadyArchived,
+				"detail":   "задача уже в архиве",
+				"instance": "req-2",
+				"task_id":  "42",
+			})
+		}
+	})
+
+	_, err := c.ArchiveTask(context.Background(), uuid.New())
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, client.CodeAlreadyArchived, apiErr.Code)
+	assert.Equal(t, "задача уже в архиве", apiErr.Message)
+	assert.Equal(t, "42", apiErr.Details["task_id"])
+	assert.Equal(t, map[string]any{"task_id": "42"}, apiErr.Details)
+	assert.Equal(t, "req-2", apiErr.RequestID, "instance важнее заголовка")
+	assert.True(t, client.IsConflict(err))
+	assert.False(t, client.IsNotFound(err))
+
+	_, err = c.ListTasks(context.Background(), client.ListOptions{View: client.ViewArchived})
+	assert.Equal(t, client.CodeValidation, client.ErrorCode(err))
+	assert.True(t, client.IsValidation(err))
+	assert.Contains(t, err.Error(), "параметр page")
+}
+
+// TestClient_Retry тестирует повторы на 429/503 и один X-Request-ID на все попытки
+func TestClient_Retry(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		attempts   int
+		requestIDs []string
+	)
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		n := attempts
+		requestIDs = append(requestIDs, r.Header.Get(client.RequestIDHeader))
+		mu.Unlock()
+
+		switch n {
+		case 1:
+			writeJSON(w, http.StatusTooManyRequests, map[string]any{
+				"type":        "urn:task-tracker:problem:rate-limited",
+				"title":       "Слишком много запросов",
+				"status":      429,
+				"code":        client.CodeRateLimited,
+				"detail":      "Слишком много запросов. Попробуйте позже.",
+				"retry_after": 1,
+			})
+		case 2:
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "перегружен"})
+		default:
+			writeJSON(w, http.StatusOK, []client.TaskResponse{})
+		}
+	}, fastRetry)
+
+	ctx := client.WithRequestID(context.Background(), "incoming-42")
+	start := time.Now()
+	tasks, err := c.ListTasks(ctx, client.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	assert.Equal(t, 3, attempts)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "retry_after должен соблюдаться")
+	assert.Equal(t, []string{"incoming-42", "incoming-42", "incoming-42"}, requestIDs)
+}
+
+// TestClient_RetryLimits тестирует отказ от повторов
+func TestClient_RetryLimits(t *testing.T) {
+	var attempts int
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch r.URL.Path {
+		case "/tasks":
+			// сервер просит ждать дольше MaxDelay
+			writeJSON(w, http.StatusTooManyRequests, map[string]any{
+				"code": client.CodeRateLimited, "detail": "лимит", "retry_after": 60,
+			})
+		case "/tasks/all":
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "перегружен"})
+		default:
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "внутренняя ошибка сервера"})
+		}
+	}, fastRetry)
+	ctx := context.Background()
+
+	_, err := c.ListTasks(ctx, client.ListOptions{})
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, client.CodeRateLimited, apiErr.Code)
+	assert.Equal(t, time.Minute, apiErr.RetryAfter)
+	assert.NotEmpty(t, apiErr.RequestID)
+	assert.Equal(t, 1, attempts)
+
+	attempts = 0
+	_, err = c.ListTasks(ctx, client.ListOptions{View: client.ViewAll})
+	assert.Equal(t, client.CodeUnavailable, client.ErrorCode(err))
+	assert.Equal(t, 4, attempts, "первая попытка и три повтора")
+
+	attempts = 0
+	_, err = c.GetTask(ctx, uuid.New())
+	assert.Equal(t, client.CodeInternal, client.ErrorCode(err))
+	assert.Equal(t, 1, attempts, "500 не повторяется")
+}
+
+// TestClient_Tasks тестирует обход страниц итератором
+func TestClient_Tasks(t *testing.T) {
+	const total = 7
+	var pages []int
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		pages = append(pages, page)
+
+		res := []client.TaskResponse{}
+		for i := (page - 1) * limit; i < min(page*limit, total); i++ {
+			res = append(res, client.TaskResponse{Title: strconv.Itoa(i)})
+		}
+		writeJSON(w, http.StatusOK, res)
+	})
+
+	var titles []string
+	for task, err := range c.Tasks(context.Background(), client.ListOptions{View: client.ViewOverdue, Limit: 3}) {
+		require.NoError(t, err)
+		titles = append(titles, task.Title)
+	}
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6"}, titles)
+	assert.Equal(t, []int{1, 2, 3}, pages)
+
+	// досрочный выход не запрашивает следующую страницу
+	pages = nil
+	for range c.Tasks(context.Background(), client.ListOptions{Limit: 3}) {
+		break
+	}
+	assert.Equal(t, []int{1}, pages)
+}
+
+// TestClient_GraphQL тестирует разбор data и ошибок GraphQL
+func TestClient_GraphQL(t *testing.T) {
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"data": map[string]any{"task": map[string]any{"id": request.Variables["id"]}},
+			"errors": []map[string]any{{
+				"message":    "задача не найдена",
+				"path":       []string{"task", "occurrences"},
+				"extensions": map[string]any{"code": client.CodeNotFound},
+			}},
+		})
+	})
+
+	var out struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	err := c.GraphQL(context.Background(), `query($id: ID!) { task(id: $id) { id } }`, map[string]any{"id": "42"}, &out)
+
+	var gqlErrs client.GraphQLErrors
+	require.True(t, errors.As(err, &gqlErrs))
+	require.Len(t, gqlErrs, 1)
+	assert.Equal(t, client.CodeNotFound, gqlErrs[0].Code())
+	assert.Equal(t, "42", out.Task.ID)
+}
+
+// TestClient_Health тестирует, что нездоровый сервис - не ошибка
+func TestClient_Health(t *testing.T) {
+	var attempts int
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"status": "unhealthy", "error": "база недоступна", "service": "task-tracker",
+		})
+	}, fastRetry)
+
+	health, err := c.Health(context.Background())
+	require.NoError(t, err)
+	assert.False(t, health.Healthy())
+	assert.Equal(t, "база недоступна", health.Error)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Коды бизнес-ошибок сервера (service.BusinessError)
+const (
+	CodeNotFound          = "NOT_FOUND"
+	CodeValidation        = "VALIDATION_ERROR"
+	CodeNotRecurring      = "NOT_RECURRING"
+	CodeAlreadyArchived   = "ALREADY_ARCHIVED"
+	CodeNotArchived       = "NOT_ARCHIVED"
+	CodeAlreadyDeleted    = "ALREADY_DELETED"
+	CodeNotDeleted        = "NOT_DELETED"
+	CodeInvalidFlag       = "INVALID_FLAG"
+	CodeInProgress        = "IN_PROGRESS"
+	CodeVersionConflict   = "VERSION_CONFLICT"
+	CodeTaskDeleted       = "TASK_DELETED"
+	CodeRestoreExpired    = "RESTORE_EXPIRED"
+	CodeInvalidTransition = "INVALID_TRANSITION"
+	CodeWIPLimitExceeded  = "WIP_LIMIT_EXCEEDED"
+	CodeTaskClosed        = "TASK_CLOSED"
+	CodeTimerRunning      = "TIMER_RUNNING"
+	CodeTimerNotRunning   = "TIMER_NOT_RUNNING"
+)
+
+// Коды ошибок HTTP-слоя сервера
+const (
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeRouteNotFound        = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeTimeout              = "TIMEOUT"
+	CodeInternal             = "INTERNAL_ERROR"
+	CodeUnavailable          = "UNAVAILABLE"
+)
+
+// Error - ответ сервера с ошибкой. Для бизнес-ошибок Code - код
+// BusinessError (NOT_FOUND, ALREADY_ARCHIVED, ...), Details - её details
+type Error struct {
+	StatusCode int
+	Code       string
+	// Message - detail ответа на языке из WithLanguage
+	Message string
+	Details map[string]any
+	// RequestID - X-Request-ID запроса, по нему ищут запрос в логах сервера
+	RequestID string
+	// RetryAfter - через сколько сервер разрешает повторить запрос (429, 503)
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// problemFields - стандартные поля problem+json, остальные поля - details ошибки
+var problemFields = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true, "code": true}
+
+// newError разбирает ответ application/problem+json (RFC 7807). Если тело
+// не problem+json, например ответ прокси, код берётся по статусу
+func newError(resp *http.Response, requestID string) *Error {
+	e := &Error{StatusCode: resp.StatusCode, RequestID: requestID}
+	if id := resp.Header.Get(RequestIDHeader); id != "" {
+		e.RequestID = id
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var body map[string]any
+	json.Unmarshal(data, &body)
+
+	e.Code, _ = body["code"].(string)
+	if e.Code == "" {
+		e.Code = codeFromStatus(resp.StatusCode)
+	}
+	e.Message, _ = body["detail"].(string)
+	if e.Message == "" {
+		e.Message, _ = body["title"].(string)
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	if instance, _ := body["instance"].(string); instance != "" {
+		e.RequestID = instance
+	}
+	for key, value := range body {
+		if problemFields[key] {
+			continue
+		}
+		if e.Details == nil {
+			e.Details = make(map[string]any)
+		}
+		e.Details[key] = value
+	}
+
+	// лимитер кладёт retry_after в секундах в тело и в заголовок Retry-After
+	if secs, ok := body["retry_after"].(float64); ok && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+	if e.RetryAfter <= 0 {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			e.RetryAfter = time.Duration(secs) * time.Second
+		}
+	}
+	return e
+}
+
+func codeFromStatus(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return CodeRateLimited
+	case status == http.StatusUnauthorized:
+		return CodeUnauthorized
+	case status == http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case status >= http.StatusInternalServerError:
+		return CodeInternal
+	default:
+		return CodeBadRequest
+	}
+}
+
+// ErrorCode возвращает код ошибки сервера или пустую строку,
+// если err - не ответ сервера (сеть, таймаут)
+func ErrorCode(err error) string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
+
+func IsNotFound(err error) bool {
+	return ErrorCode(err) == CodeNotFound
+}
+
+// IsConflict - операция не подходит к текущему состоянию задачи
+func IsConflict(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+func IsValidation(err error) bool {
+	code := ErrorCode(err)
+	return code == CodeValidation || code == CodeNotRecurring || code == CodeBadRequest
+}
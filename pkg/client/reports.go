@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Burndown возвращает серии burndown и burnup по дням. Нулевой to - две
+// недели от from
+func (c *Client) Burndown(ctx context.Context, from, to time.Time) (*BurndownResponse, error) {
+	var res BurndownResponse
+	if err := c.do(ctx, http.MethodGet, "/reports/burndown", reportQuery(from, to), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Velocity возвращает выполненную работу по неделям. Нулевой to -
+// двенадцать недель от from
+func (c *Client) Velocity(ctx context.Context, from, to time.Time) (*VelocityResponse, error) {
+	var res VelocityResponse
+	if err := c.do(ctx, http.MethodGet, "/reports/velocity", reportQuery(from, to), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func reportQuery(from, to time.Time) url.Values {
+	query := url.Values{}
+	query.Set("from", from.Format(time.RFC3339))
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.RFC3339))
+	}
+	return query
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// StatsOptions - параметры GET /stats. Нулевые поля - значения сервера:
+// последние 30 дней и десять просроченных задач
+type StatsOptions struct {
+	From time.Time
+	To   time.Time
+	Top  int
+}
+
+// Stats возвращает сводку по задачам. Сервер кэширует ответ на STATS_CACHE_TTL
+func (c *Client) Stats(ctx context.Context, opts StatsOptions) (*StatsResponse, error) {
+	query := url.Values{}
+	if !opts.From.IsZero() {
+		query.Set("from", opts.From.Format(time.RFC3339))
+	}
+	if !opts.To.IsZero() {
+		query.Set("to", opts.To.Format(time.RFC3339))
+	}
+	if opts.Top > 0 {
+		query.Set("top", strconv.Itoa(opts.Top))
+	}
+
+	var res StatsResponse
+	if err := c.do(ctx, http.MethodGet, "/stats", query, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// View - какой список задач запрашивать
+type View string
+
+const (
+	ViewActive   View = "active"
+	ViewAll      View = "all"
+	ViewArchived View = "archived"
+	ViewOverdue  View = "overdue"
+	ViewDeleted  View = "deleted"
+)
+
+func (v View) path() string {
+	switch v {
+	case ViewAll:
+		return "/tasks/all"
+	case ViewArchived:
+		return "/tasks/archived"
+	case ViewOverdue:
+		return "/tasks/overdue"
+	case ViewDeleted:
+		return "/admin/tasks/deleted"
+	default:
+		return "/tasks"
+	}
+}
+
+type ListOptions struct {
+	View View
+	// Page и Limit по умолчанию - 1 и 50, как на сервере
+	Page  int
+	Limit int
+}
+
+const defaultLimit = 50
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return query
+}
+
+func taskPath(id uuid.UUID) string {
+	return "/tasks/" + id.String()
+}
+
+// ListTasks возвращает одну страницу задач
+func (c *Client) ListTasks(ctx context.Context, opts ListOptions) ([]TaskResponse, error) {
+	var tasks []TaskResponse
+	if err := c.do(ctx, http.MethodGet, opts.View.path(), opts.query(), nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Tasks обходит все страницы списка, начиная с opts.Page. Следующая страница
+// запрашивается, когда закончилась текущая; обход заканчивается на неполной
+// странице или на первой ошибке:
+//
+//	for t, err := range c.Tasks(ctx, client.ListOptions{View: client.ViewOverdue}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Tasks(ctx context.Context, opts ListOptions) iter.Seq2[TaskResponse, error] {
+	return func(yield func(TaskResponse, error) bool) {
+		if opts.Page <= 0 {
+			opts.Page = 1
+		}
+		if opts.Limit <= 0 {
+			opts.Limit = defaultLimit
+		}
+
+		for {
+			tasks, err := c.ListTasks(ctx, opts)
+			if err != nil {
+				yield(TaskResponse{}, err)
+				return
+			}
+			for _, t := range tasks {
+				if !yield(t, nil) {
+					return
+				}
+			}
+			if len(tasks) < opts.Limit {
+				return
+			}
+			opts.Page++
+		}
+	}
+}
+
+func (c *Client) CreateTask(ctx context.Context, request CreateTaskRequest) (*TaskResponse, error) {
+	return c.task(ctx, http.MethodPost, "/tasks", request)
+}
+
+func (c *Client) GetTask(ctx context.Context, id uuid.UUID) (*TaskResponse, error) {
+	return c.task(ctx, http.MethodGet, taskPath(id), nil)
+}
+
+// UpdateTask меняет только заданные (не nil) поля запроса
+func (c *Client) UpdateTask(ctx context.Context, id uuid.UUID, request UpdateTaskRequest) (*TaskResponse, error) {
+	return c.task(ctx, http.MethodPut, taskPath(id), request)
+}
+
+func (c *Client) ArchiveTask(ctx context.Context, id uuid.UUID) (*TaskResponse, error) {
+	return c.task(ctx, http.MethodPost, taskPath(id)+"/archive", nil)
+}
+
+func (c *Client) UnarchiveTask(ctx context.Context, id uuid.UUID) (*TaskResponse, error) {
+	return c.task(ctx, http.MethodPost, taskPath(id)+"/unarchive", nil)
+}
+
+// TaskOccurrences возвращает сроки повторений задачи в [from, to].
+// Нулевые from и to - значения сервера: от текущего момента на 90 дней
+func (c *Client) TaskOccurrences(ctx context.Context, id uuid.UUID, from, to time.Time) (*OccurrencesResponse, error) {
+	query := url.Values{}
+	if !from.IsZero() {
+		query.Set("from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.RFC3339))
+	}
+
+	var res OccurrencesResponse
+	if err := c.do(ctx, http.MethodGet, taskPath(id)+"/occurrences", query, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// TaskTransitions возвращает переходы workflow из текущего статуса задачи
+func (c *Client) TaskTransitions(ctx context.Context, id uuid.UUID) (*TransitionsResponse, error) {
+	var res TransitionsResponse
+	if err := c.do(ctx, http.MethodGet, taskPath(id)+"/transitions", nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteTask - мягкое удаление, задачу можно восстановить через RestoreTask
+func (c *Client) DeleteTask(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, taskPath(id), nil, nil, nil)
+}
+
+func (c *Client) RestoreTask(ctx context.Context, id uuid.UUID) (*TaskResponse, error) {
+	return c.task(ctx, http.MethodPost, "/admin/tasks/"+id.String()+"/restore", nil)
+}
+
+// PurgeTask удаляет задачу безвозвратно
+func (c *Client) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/admin/tasks/"+id.String()+"/purge", nil, nil, nil)
+}
+
+func (c *Client) task(ctx context.Context, method, path string, body any) (*TaskResponse, error) {
+	var t TaskResponse
+	if err := c.do(ctx, method, path, nil, body, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
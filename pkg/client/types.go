@@ -0,0 +1,62 @@
+package client
+
+import (
+	"taskTracker/internal/events"
+	"taskTracker/internal/handlers/dto"
+	"taskTracker/internal/models/task"
+	"taskTracker/internal/repository/task/cache"
+	"taskTracker/internal/webhook"
+)
+
+// Псевдонимы типов сервера: пакеты internal нельзя импортировать
+// из других модулей, а через client - можно, и тела остаются общими
+type (
+	CreateTaskRequest   = dto.CreateTaskRequest
+	UpdateTaskRequest   = dto.UpdateTaskRequest
+	TaskResponse        = dto.TaskResponse
+	OccurrencesResponse = dto.OccurrencesResponse
+	TransitionsResponse = dto.TransitionsResponse
+	Transition          = dto.Transition
+	BoardResponse       = dto.BoardResponse
+	ColumnResponse      = dto.ColumnResponse
+	MoveTaskRequest     = dto.MoveTaskRequest
+	TaskStatus          = task.Status
+	Reminder            = task.Reminder
+
+	TimerRequest          = dto.TimerRequest
+	TimerResponse         = dto.TimerResponse
+	CreateWorklogRequest  = dto.CreateWorklogRequest
+	WorklogResponse       = dto.WorklogResponse
+	TaskWorklogsResponse  = dto.TaskWorklogsResponse
+	WorklogReportResponse = dto.WorklogReportResponse
+	WorklogReportRow      = dto.WorklogReportRow
+
+	BurndownResponse = dto.BurndownResponse
+	BurndownPoint    = dto.BurndownPoint
+	VelocityResponse = dto.VelocityResponse
+	VelocityWeek     = dto.VelocityWeek
+
+	StatsResponse = dto.StatsResponse
+	StatsDay      = dto.StatsDay
+	DurationStats = dto.DurationStats
+
+	CreateWebhookRequest = dto.CreateWebhookRequest
+	UpdateWebhookRequest = dto.UpdateWebhookRequest
+	WebhookResponse      = dto.WebhookResponse
+	Delivery             = webhook.Delivery
+	DeadLetter           = webhook.DeadLetter
+	Event                = events.Event
+	EventType            = events.Type
+
+	CacheStats = cache.Stats
+)
+
+const (
+	StatusNew        = task.StatusNew
+	StatusInProgress = task.StatusInProgress
+	StatusBlocked    = task.StatusBlocked
+	StatusInReview   = task.StatusInReview
+	StatusDone       = task.StatusDone
+	StatusCancelled  = task.StatusCancelled
+	StatusOverdue    = task.StatusOverdue
+)
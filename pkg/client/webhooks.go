@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+func webhookPath(id uuid.UUID) string {
+	return "/webhooks/" + id.String()
+}
+
+func limitQuery(limit int) url.Values {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	return query
+}
+
+// CreateWebhook создаёт подписку. Secret в ответе заполнен только здесь:
+// если он не задан в запросе, его генерирует сервер
+func (c *Client) CreateWebhook(ctx context.Context, request CreateWebhookRequest) (*WebhookResponse, error) {
+	return c.webhook(ctx, http.MethodPost, "/webhooks", request)
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]WebhookResponse, error) {
+	var res []WebhookResponse
+	if err := c.do(ctx, http.MethodGet, "/webhooks", nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id uuid.UUID) (*WebhookResponse, error) {
+	return c.webhook(ctx, http.MethodGet, webhookPath(id), nil)
+}
+
+// UpdateWebhook меняет только заданные (не nil) поля запроса
+func (c *Client) UpdateWebhook(ctx context.Context, id uuid.UUID, request UpdateWebhookRequest) (*WebhookResponse, error) {
+	return c.webhook(ctx, http.MethodPut, webhookPath(id), request)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, webhookPath(id), nil, nil, nil)
+}
+
+// WebhookDeliveries возвращает последние limit попыток доставки, 0 - значение сервера
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]Delivery, error) {
+	var res []Delivery
+	if err := c.do(ctx, http.MethodGet, webhookPath(id)+"/deliveries", limitQuery(limit), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeadLetters возвращает события, которые не удалось доставить за все попытки
+func (c *Client) DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	var res []DeadLetter
+	if err := c.do(ctx, http.MethodGet, "/webhooks/dead-letters", limitQuery(limit), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) webhook(ctx context.Context, method, path string, body any) (*WebhookResponse, error) {
+	var res WebhookResponse
+	if err := c.do(ctx, method, path, nil, body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StartTimer запускает таймер пользователя на задаче. Таймер того же
+// пользователя на другой задаче сервер останавливает сам
+func (c *Client) StartTimer(ctx context.Context, id uuid.UUID, user string) (*TimerResponse, error) {
+	var res TimerResponse
+	if err := c.do(ctx, http.MethodPost, taskPath(id)+"/timer/start", nil, TimerRequest{User: user}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// StopTimer останавливает таймер и возвращает получившуюся запись времени
+func (c *Client) StopTimer(ctx context.Context, id uuid.UUID, user, note string) (*WorklogResponse, error) {
+	var res WorklogResponse
+	if err := c.do(ctx, http.MethodPost, taskPath(id)+"/timer/stop", nil, TimerRequest{User: user, Note: note}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// AddWorklog записывает время вручную
+func (c *Client) AddWorklog(ctx context.Context, id uuid.UUID, request CreateWorklogRequest) (*WorklogResponse, error) {
+	var res WorklogResponse
+	if err := c.do(ctx, http.MethodPost, taskPath(id)+"/worklogs", nil, request, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) TaskWorklogs(ctx context.Context, id uuid.UUID) (*TaskWorklogsResponse, error) {
+	var res TaskWorklogsResponse
+	if err := c.do(ctx, http.MethodGet, taskPath(id)+"/worklogs", nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ReportOptions - параметры отчёта по времени. Нулевой To - from + 7 дней,
+// пустой GroupBy - группировка по задаче, пользователю и дню
+type ReportOptions struct {
+	From    time.Time
+	To      time.Time
+	GroupBy []string
+	User    string
+	TaskID  uuid.UUID
+}
+
+func (c *Client) WorklogReport(ctx context.Context, opts ReportOptions) (*WorklogReportResponse, error) {
+	query := url.Values{}
+	query.Set("from", opts.From.Format(time.RFC3339))
+	if !opts.To.IsZero() {
+		query.Set("to", opts.To.Format(time.RFC3339))
+	}
+	if len(opts.GroupBy) > 0 {
+		query.Set("group_by", strings.Join(opts.GroupBy, ","))
+	}
+	if opts.User != "" {
+		query.Set("user", opts.User)
+	}
+	if opts.TaskID != uuid.Nil {
+		query.Set("task_id", opts.TaskID.String())
+	}
+
+	var res WorklogReportResponse
+	if err := c.do(ctx, http.MethodGet, "/worklogs/report", query, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}